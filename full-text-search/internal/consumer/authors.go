@@ -0,0 +1,37 @@
+package consumer
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+
+	common "github.com/bakurvik/mylib-common"
+	"github.com/bakurvik/mylib/full-text-search/internal/database"
+	"github.com/google/uuid"
+	"github.com/segmentio/kafka-go"
+)
+
+func HandleAuthorMessage(db *sql.DB) MessageHandler {
+	return func(ctx context.Context, msg kafka.Message) error {
+		authorMessage := common.AuthorMessage{}
+		err := json.Unmarshal(msg.Value, &authorMessage)
+		if err != nil {
+			return invalidMessage(err)
+		}
+		authorID, err := uuid.Parse(authorMessage.ID)
+		if err != nil {
+			return invalidMessage(err)
+		}
+
+		queries := database.New(db)
+		switch authorMessage.Action {
+		case actionCreated, actionUpdated:
+			return queries.UpsertAuthor(ctx, database.UpsertAuthorParams{ID: authorID, FullName: authorMessage.FullName})
+		case actionDeleted:
+			return queries.DeleteAuthor(ctx, authorID)
+		default:
+			return invalidMessage(fmt.Errorf("unknown author action: %v", authorMessage.Action))
+		}
+	}
+}
@@ -0,0 +1,37 @@
+package consumer
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+
+	"github.com/bakurvik/mylib/full-text-search/internal/database"
+	"github.com/bakurvik/mylib/shared/messages"
+	"github.com/google/uuid"
+	"github.com/segmentio/kafka-go"
+)
+
+func HandleBookMessage(db *sql.DB) MessageHandler {
+	return func(ctx context.Context, msg kafka.Message) error {
+		bookMessage := messages.BookMessage{}
+		err := json.Unmarshal(msg.Value, &bookMessage)
+		if err != nil {
+			return invalidMessage(err)
+		}
+		bookID, err := uuid.Parse(bookMessage.ID)
+		if err != nil {
+			return invalidMessage(err)
+		}
+
+		queries := database.New(db)
+		switch bookMessage.Action {
+		case actionCreated, actionUpdated:
+			return queries.UpsertBook(ctx, database.UpsertBookParams{ID: bookID, Title: bookMessage.Title, Authors: bookMessage.Authors})
+		case actionDeleted:
+			return queries.DeleteBook(ctx, bookID)
+		default:
+			return invalidMessage(fmt.Errorf("unknown book action: %v", bookMessage.Action))
+		}
+	}
+}
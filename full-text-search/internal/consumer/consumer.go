@@ -0,0 +1,90 @@
+package consumer
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"time"
+
+	"github.com/segmentio/kafka-go"
+)
+
+// retryDelay is the first pause after a failed fetch or a failed handling of a message,
+// it doubles with every failure in a row up to maxRetryDelay.
+var (
+	retryDelay    = time.Second
+	maxRetryDelay = 30 * time.Second
+)
+
+// errInvalidMessage marks handler errors that retrying can't fix, e.g. malformed JSON. Such messages are skipped.
+var errInvalidMessage = errors.New("invalid message")
+
+func invalidMessage(err error) error {
+	return fmt.Errorf("%w: %v", errInvalidMessage, err)
+}
+
+type KafkaReader interface {
+	FetchMessage(ctx context.Context) (kafka.Message, error)
+	CommitMessages(ctx context.Context, msgs ...kafka.Message) error
+}
+
+type MessageHandler func(ctx context.Context, msg kafka.Message) error
+
+// handle calls handler until it succeeds or reports an invalid message and returns false if ctx is cancelled before that.
+// Other errors, e.g. of DB, are retried with backoff, because the message is committed afterwards
+// and the index would miss the change otherwise.
+func handle(ctx context.Context, handler MessageHandler, msg kafka.Message) bool {
+	delay := retryDelay
+	for {
+		err := handler(ctx, msg)
+		if err == nil {
+			return true
+		}
+		if errors.Is(err, errInvalidMessage) {
+			log.Printf("Skipped message from topic %v: %v", msg.Topic, err)
+			return true
+		}
+		log.Printf("Failed to handle message from topic %v, retrying in %v: %v", msg.Topic, delay, err)
+		select {
+		case <-ctx.Done():
+			return false
+		case <-time.After(delay):
+		}
+		delay = min(delay*2, maxRetryDelay)
+	}
+}
+
+// Consume reads messages until the reader is closed or ctx is cancelled. Failed fetches are retried with backoff,
+// so a broker outage pauses indexing instead of stopping it.
+// A message is committed after it is handled or skipped as invalid, so that one malformed message does not block the topic.
+func Consume(ctx context.Context, reader KafkaReader, handler MessageHandler) {
+	delay := retryDelay
+	for {
+		msg, err := reader.FetchMessage(ctx)
+		if errors.Is(err, io.EOF) || errors.Is(err, context.Canceled) || ctx.Err() != nil {
+			return
+		}
+		if err != nil {
+			log.Printf("Failed to fetch message, retrying in %v: %v", delay, err)
+			select {
+			case <-ctx.Done():
+				return
+			case <-time.After(delay):
+			}
+			delay = min(delay*2, maxRetryDelay)
+			continue
+		}
+		delay = retryDelay
+
+		if !handle(ctx, handler, msg) {
+			return
+		}
+
+		err = reader.CommitMessages(ctx, msg)
+		if err != nil {
+			log.Print("Failed to commit message: ", err)
+		}
+	}
+}
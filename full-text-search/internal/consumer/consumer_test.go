@@ -0,0 +1,128 @@
+package consumer
+
+import (
+	"context"
+	"errors"
+	"io"
+	"testing"
+	"time"
+
+	"github.com/segmentio/kafka-go"
+	"github.com/stretchr/testify/assert"
+)
+
+type fakeKafkaReader struct {
+	messages    []kafka.Message
+	committed   []kafka.Message
+	fetchErrors int
+}
+
+func (r *fakeKafkaReader) FetchMessage(ctx context.Context) (kafka.Message, error) {
+	if r.fetchErrors > 0 {
+		r.fetchErrors--
+		return kafka.Message{}, errors.New("broker is unavailable")
+	}
+	if len(r.messages) == 0 {
+		return kafka.Message{}, io.EOF
+	}
+	msg := r.messages[0]
+	r.messages = r.messages[1:]
+	return msg, nil
+}
+
+func (r *fakeKafkaReader) CommitMessages(ctx context.Context, msgs ...kafka.Message) error {
+	r.committed = append(r.committed, msgs...)
+	return nil
+}
+
+func TestConsume(t *testing.T) {
+	retryDelay = time.Millisecond
+	type testCase struct {
+		name              string
+		messages          []kafka.Message
+		fetchErrors       int
+		handlerErrors     []error
+		expectedHandled   []string
+		expectedCommitted []string
+	}
+	testCases := []testCase{
+		{
+			name:              "success",
+			messages:          []kafka.Message{{Key: []byte("1")}, {Key: []byte("2")}},
+			expectedHandled:   []string{"1", "2"},
+			expectedCommitted: []string{"1", "2"},
+		},
+		{
+			name:              "invalid_message",
+			messages:          []kafka.Message{{Key: []byte("1")}, {Key: []byte("2")}},
+			handlerErrors:     []error{invalidMessage(errors.New("unknown action"))},
+			expectedHandled:   []string{"1", "2"},
+			expectedCommitted: []string{"1", "2"},
+		},
+		{
+			name:              "db_error_retried",
+			messages:          []kafka.Message{{Key: []byte("1")}, {Key: []byte("2")}},
+			handlerErrors:     []error{errors.New("db is unavailable"), errors.New("db is unavailable")},
+			expectedHandled:   []string{"1", "1", "1", "2"},
+			expectedCommitted: []string{"1", "2"},
+		},
+		{
+			name:              "fetch_errors",
+			messages:          []kafka.Message{{Key: []byte("1")}},
+			fetchErrors:       3,
+			expectedHandled:   []string{"1"},
+			expectedCommitted: []string{"1"},
+		},
+		{
+			name:              "no_messages",
+			messages:          []kafka.Message{},
+			expectedHandled:   []string{},
+			expectedCommitted: []string{},
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			reader := fakeKafkaReader{messages: tc.messages, fetchErrors: tc.fetchErrors}
+			handled := make([]string, 0)
+			handlerErrors := tc.handlerErrors
+			Consume(context.Background(), &reader, func(ctx context.Context, msg kafka.Message) error {
+				handled = append(handled, string(msg.Key))
+				if len(handlerErrors) == 0 {
+					return nil
+				}
+				err := handlerErrors[0]
+				handlerErrors = handlerErrors[1:]
+				return err
+			})
+
+			committed := make([]string, 0)
+			for _, msg := range reader.committed {
+				committed = append(committed, string(msg.Key))
+			}
+			assert.Equal(t, handled, tc.expectedHandled)
+			assert.Equal(t, committed, tc.expectedCommitted)
+		})
+	}
+}
+
+func TestConsumeCancelledWhileRetrying(t *testing.T) {
+	retryDelay = time.Millisecond
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	reader := fakeKafkaReader{fetchErrors: 1 << 30}
+
+	Consume(ctx, &reader, func(ctx context.Context, msg kafka.Message) error { return nil })
+
+	assert.Empty(t, reader.committed)
+}
+
+func TestConsumeCancelledWhileRetryingHandler(t *testing.T) {
+	retryDelay = time.Millisecond
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	reader := fakeKafkaReader{messages: []kafka.Message{{Key: []byte("1")}}}
+
+	Consume(ctx, &reader, func(ctx context.Context, msg kafka.Message) error { return errors.New("db is unavailable") })
+
+	assert.Empty(t, reader.committed)
+}
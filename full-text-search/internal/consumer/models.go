@@ -0,0 +1,7 @@
+package consumer
+
+const (
+	actionCreated = "created"
+	actionUpdated = "updated"
+	actionDeleted = "deleted"
+)
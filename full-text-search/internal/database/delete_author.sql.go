@@ -0,0 +1,21 @@
+// Code generated by sqlc. DO NOT EDIT.
+// versions:
+//   sqlc v1.28.0
+// source: delete_author.sql
+
+package database
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+)
+
+const deleteAuthor = `-- name: DeleteAuthor :exec
+DELETE FROM authors WHERE id = $1
+`
+
+func (q *Queries) DeleteAuthor(ctx context.Context, id uuid.UUID) error {
+	_, err := q.db.ExecContext(ctx, deleteAuthor, id)
+	return err
+}
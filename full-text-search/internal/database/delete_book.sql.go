@@ -0,0 +1,21 @@
+// Code generated by sqlc. DO NOT EDIT.
+// versions:
+//   sqlc v1.28.0
+// source: delete_book.sql
+
+package database
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+)
+
+const deleteBook = `-- name: DeleteBook :exec
+DELETE FROM books WHERE id = $1
+`
+
+func (q *Queries) DeleteBook(ctx context.Context, id uuid.UUID) error {
+	_, err := q.db.ExecContext(ctx, deleteBook, id)
+	return err
+}
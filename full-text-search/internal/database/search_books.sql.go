@@ -0,0 +1,60 @@
+// Code generated by sqlc. DO NOT EDIT.
+// versions:
+//   sqlc v1.28.0
+// source: search_books.sql
+
+package database
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+)
+
+const searchBooks = `-- name: SearchBooks :many
+SELECT id, title, authors, ts_rank(tsv, plainto_tsquery('english', $1)) AS rank
+FROM books WHERE tsv @@ plainto_tsquery('english', $1)
+ORDER BY rank DESC
+LIMIT $2
+`
+
+type SearchBooksParams struct {
+	PlaintoTsquery string
+	Limit          int32
+}
+
+type SearchBooksRow struct {
+	ID      uuid.UUID
+	Title   string
+	Authors []string
+	Rank    float32
+}
+
+func (q *Queries) SearchBooks(ctx context.Context, arg SearchBooksParams) ([]SearchBooksRow, error) {
+	rows, err := q.db.QueryContext(ctx, searchBooks, arg.PlaintoTsquery, arg.Limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []SearchBooksRow
+	for rows.Next() {
+		var i SearchBooksRow
+		if err := rows.Scan(
+			&i.ID,
+			&i.Title,
+			pq.Array(&i.Authors),
+			&i.Rank,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
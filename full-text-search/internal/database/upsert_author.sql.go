@@ -0,0 +1,30 @@
+// Code generated by sqlc. DO NOT EDIT.
+// versions:
+//   sqlc v1.28.0
+// source: upsert_author.sql
+
+package database
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+)
+
+const upsertAuthor = `-- name: UpsertAuthor :exec
+INSERT INTO authors (id, full_name, created_at, updated_at)
+VALUES (
+    $1, $2, NOW(), NOW()
+)
+ON CONFLICT (id) DO UPDATE SET full_name = EXCLUDED.full_name, updated_at = NOW()
+`
+
+type UpsertAuthorParams struct {
+	ID       uuid.UUID
+	FullName string
+}
+
+func (q *Queries) UpsertAuthor(ctx context.Context, arg UpsertAuthorParams) error {
+	_, err := q.db.ExecContext(ctx, upsertAuthor, arg.ID, arg.FullName)
+	return err
+}
@@ -0,0 +1,32 @@
+// Code generated by sqlc. DO NOT EDIT.
+// versions:
+//   sqlc v1.28.0
+// source: upsert_book.sql
+
+package database
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+)
+
+const upsertBook = `-- name: UpsertBook :exec
+INSERT INTO books (id, title, authors, created_at, updated_at)
+VALUES (
+    $1, $2, COALESCE($3::TEXT[], '{}'), NOW(), NOW()
+)
+ON CONFLICT (id) DO UPDATE SET title = EXCLUDED.title, authors = EXCLUDED.authors, updated_at = NOW()
+`
+
+type UpsertBookParams struct {
+	ID      uuid.UUID
+	Title   string
+	Authors []string
+}
+
+func (q *Queries) UpsertBook(ctx context.Context, arg UpsertBookParams) error {
+	_, err := q.db.ExecContext(ctx, upsertBook, arg.ID, arg.Title, pq.Array(arg.Authors))
+	return err
+}
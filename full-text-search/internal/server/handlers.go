@@ -0,0 +1,64 @@
+package server
+
+import (
+	"net/http"
+
+	common "github.com/bakurvik/mylib-common"
+	"github.com/bakurvik/mylib/full-text-search/internal/database"
+)
+
+// @Summary Ping the server
+// @Description  Checks server health. Returns 200 OK if server is up.
+// @Tags Health
+// @Accept json
+// @Produce json
+// @Success 200 {string} string
+// @Router /ping [get]
+func (cfg *ApiConfig) HandlePing(w http.ResponseWriter, r *http.Request) {
+	w.WriteHeader(http.StatusOK)
+}
+
+// @Summary Search
+// @Description Searches indexed authors and books. Books are matched by title and authors' names. Uses postgres full text search
+// @Tags Search
+// @Accept json
+// @Produce json
+// @Param q query string true "Search text"
+// @Success 200 {object} ResponseSearch "Found authors and books"
+// @Failure 400 {object} ErrorResponse "Empty search text"
+// @Failure 500 {object} ErrorResponse
+// @Router /api/search [get]
+func (cfg *ApiConfig) HandleGetApiSearch(w http.ResponseWriter, r *http.Request) {
+	if cfg.DB == nil {
+		common.RespondWithError(w, http.StatusInternalServerError, "DB error")
+		return
+	}
+
+	searchText := r.URL.Query().Get("q")
+	if searchText == "" {
+		common.RespondWithError(w, http.StatusBadRequest, "Empty search text")
+		return
+	}
+
+	queries := database.New(cfg.DB)
+	authors, dbErr := queries.SearchAuthors(r.Context(), database.SearchAuthorsParams{PlaintoTsquery: searchText, Limit: int32(cfg.MaxSearchLimit)})
+	if dbErr != nil {
+		common.RespondWithError(w, http.StatusInternalServerError, dbErr.Error())
+		return
+	}
+
+	books, dbErr := queries.SearchBooks(r.Context(), database.SearchBooksParams{PlaintoTsquery: searchText, Limit: int32(cfg.MaxSearchLimit)})
+	if dbErr != nil {
+		common.RespondWithError(w, http.StatusInternalServerError, dbErr.Error())
+		return
+	}
+
+	response := ResponseSearch{Authors: make([]ResponseAuthor, 0, len(authors)), Books: make([]ResponseBook, 0, len(books))}
+	for _, author := range authors {
+		response.Authors = append(response.Authors, ResponseAuthor{ID: author.ID.String(), FullName: author.FullName})
+	}
+	for _, book := range books {
+		response.Books = append(response.Books, ResponseBook{ID: book.ID.String(), Title: book.Title, Authors: book.Authors})
+	}
+	common.RespondWithJSON(w, http.StatusOK, response, nil)
+}
@@ -0,0 +1,21 @@
+package server
+
+type ResponseAuthor struct {
+	ID       string `json:"id"`
+	FullName string `json:"full_name"`
+}
+
+type ResponseBook struct {
+	ID      string   `json:"id"`
+	Title   string   `json:"title"`
+	Authors []string `json:"authors"`
+}
+
+type ResponseSearch struct {
+	Authors []ResponseAuthor `json:"authors"`
+	Books   []ResponseBook   `json:"books"`
+}
+
+type ErrorResponse struct {
+	Error string `json:"error"`
+}
@@ -0,0 +1,29 @@
+package server
+
+import (
+	"database/sql"
+	"net/http"
+
+	httpSwagger "github.com/swaggo/http-swagger"
+)
+
+const (
+	ApiSearchPath = "/api/search"
+	PingPath      = "/ping"
+)
+
+type ApiConfig struct {
+	DB             *sql.DB
+	MaxSearchLimit int
+}
+
+func Handle(sm *http.ServeMux, apiCfg *ApiConfig) {
+	// Ping
+	sm.HandleFunc("GET "+PingPath, apiCfg.HandlePing)
+
+	// Search
+	sm.HandleFunc("GET "+ApiSearchPath, apiCfg.HandleGetApiSearch)
+
+	// Swagger
+	sm.Handle("/swagger/", httpSwagger.WrapHandler)
+}
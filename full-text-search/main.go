@@ -1,5 +1,78 @@
 package main
 
+import (
+	"context"
+	"log"
+	"net/http"
+	"os"
+	"strconv"
+
+	common "github.com/bakurvik/mylib-common"
+	"github.com/bakurvik/mylib/full-text-search/internal/consumer"
+	"github.com/bakurvik/mylib/full-text-search/internal/server"
+	"github.com/segmentio/kafka-go"
+
+	_ "github.com/bakurvik/mylib/full-text-search/docs"
+
+	_ "github.com/lib/pq"
+)
+
+// @title Full Text Search Service API
+// @version 1.0
+// @description API for searching authors and books.
+
+// @license.name MIT
+// @license.url https://opensource.org/licenses/MIT
+
+// @host localhost:8080
+// @BasePath /
+
+const (
+	defaultMaxSearchLimit = 10
+	kafkaGroupID          = "full-text-search"
+)
+
+func getLimit(varName string, defaultValue int) int {
+	limit, err := strconv.Atoi(os.Getenv(varName))
+	if err != nil {
+		log.Printf("Invalid limit %v value: %v", varName, os.Getenv(varName))
+		return defaultValue
+	}
+	return limit
+}
+
 func main() {
+	db, err := common.SetupDB("./.env")
+	if err != nil {
+		log.Fatal("Failed setup db ", err)
+	}
+
+	authorsKafkaReader := kafka.NewReader(kafka.ReaderConfig{
+		Brokers: []string{"localhost:9092"},
+		GroupID: kafkaGroupID,
+		Topic:   "authors",
+	})
+	defer authorsKafkaReader.Close()
+	go consumer.Consume(context.Background(), authorsKafkaReader, consumer.HandleAuthorMessage(db))
+
+	booksKafkaReader := kafka.NewReader(kafka.ReaderConfig{
+		Brokers: []string{"localhost:9092"},
+		GroupID: kafkaGroupID,
+		Topic:   "books",
+	})
+	defer booksKafkaReader.Close()
+	go consumer.Consume(context.Background(), booksKafkaReader, consumer.HandleBookMessage(db))
+
+	sm := http.NewServeMux()
+	apiCfg := server.ApiConfig{DB: db, MaxSearchLimit: getLimit("MAX_SEARCH_LIMIT", defaultMaxSearchLimit)}
+	server.Handle(sm, &apiCfg)
 
+	s := http.Server{
+		Addr:    ":8080",
+		Handler: common.CORSMiddleware(common.LoggingMiddleware(sm)),
+	}
+	serverErr := s.ListenAndServe()
+	if serverErr != nil {
+		log.Fatal("Failed starting server: ", serverErr)
+	}
 }
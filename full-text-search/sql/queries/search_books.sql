@@ -0,0 +1,5 @@
+-- name: SearchBooks :many
+SELECT id, title, authors, ts_rank(tsv, plainto_tsquery('english', $1)) AS rank
+FROM books WHERE tsv @@ plainto_tsquery('english', $1)
+ORDER BY rank DESC
+LIMIT $2;
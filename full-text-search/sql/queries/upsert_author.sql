@@ -0,0 +1,6 @@
+-- name: UpsertAuthor :exec
+INSERT INTO authors (id, full_name, created_at, updated_at)
+VALUES (
+    $1, $2, NOW(), NOW()
+)
+ON CONFLICT (id) DO UPDATE SET full_name = EXCLUDED.full_name, updated_at = NOW();
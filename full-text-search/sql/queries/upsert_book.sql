@@ -0,0 +1,6 @@
+-- name: UpsertBook :exec
+INSERT INTO books (id, title, authors, created_at, updated_at)
+VALUES (
+    $1, $2, COALESCE(@authors::TEXT[], '{}'), NOW(), NOW()
+)
+ON CONFLICT (id) DO UPDATE SET title = EXCLUDED.title, authors = EXCLUDED.authors, updated_at = NOW();
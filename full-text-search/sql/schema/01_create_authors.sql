@@ -0,0 +1,30 @@
+-- +goose Up
+CREATE TABLE IF NOT EXISTS authors(
+    id UUID PRIMARY KEY,
+    full_name TEXT NOT NULL,
+    tsv tsvector,
+    created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMP NOT NULL DEFAULT NOW()
+);
+
+-- +goose StatementBegin
+CREATE FUNCTION authors_tsv_trigger() RETURNS trigger AS $$
+BEGIN
+  NEW.tsv := to_tsvector('english', NEW.full_name);
+  RETURN NEW;
+END;
+$$ LANGUAGE plpgsql;
+-- +goose StatementEnd
+
+CREATE TRIGGER trigger_authors_tsv
+BEFORE INSERT OR UPDATE ON authors
+FOR EACH ROW EXECUTE FUNCTION authors_tsv_trigger();
+
+CREATE INDEX idx_authors_tsv ON authors USING GIN(tsv);
+
+-- +goose Down
+DROP TRIGGER IF EXISTS trigger_authors_tsv ON authors;
+
+DROP FUNCTION IF EXISTS authors_tsv_trigger();
+
+DROP TABLE IF EXISTS authors;
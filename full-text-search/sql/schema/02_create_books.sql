@@ -0,0 +1,33 @@
+-- +goose Up
+CREATE TABLE IF NOT EXISTS books(
+    id UUID PRIMARY KEY,
+    title TEXT NOT NULL,
+    authors TEXT[] NOT NULL DEFAULT '{}',
+    tsv tsvector,
+    created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMP NOT NULL DEFAULT NOW()
+);
+
+-- +goose StatementBegin
+CREATE FUNCTION books_tsv_trigger() RETURNS trigger AS $$
+BEGIN
+  NEW.tsv :=
+    setweight(to_tsvector('english', NEW.title), 'A') ||
+    setweight(to_tsvector('english', array_to_string(NEW.authors, ' ')), 'B');
+  RETURN NEW;
+END;
+$$ LANGUAGE plpgsql;
+-- +goose StatementEnd
+
+CREATE TRIGGER trigger_books_tsv
+BEFORE INSERT OR UPDATE ON books
+FOR EACH ROW EXECUTE FUNCTION books_tsv_trigger();
+
+CREATE INDEX idx_books_tsv ON books USING GIN(tsv);
+
+-- +goose Down
+DROP TRIGGER IF EXISTS trigger_books_tsv ON books;
+
+DROP FUNCTION IF EXISTS books_tsv_trigger();
+
+DROP TABLE IF EXISTS books;
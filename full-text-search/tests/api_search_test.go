@@ -0,0 +1,346 @@
+package tests
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"log"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+
+	common "github.com/bakurvik/mylib-common"
+	"github.com/bakurvik/mylib/full-text-search/internal/consumer"
+	"github.com/bakurvik/mylib/full-text-search/internal/server"
+	"github.com/bakurvik/mylib/shared/messages"
+	"github.com/google/uuid"
+	"github.com/segmentio/kafka-go"
+
+	"github.com/lib/pq"
+	"github.com/stretchr/testify/assert"
+)
+
+const (
+	selectAuthors = "SELECT id, full_name FROM authors ORDER BY full_name"
+	insertAuthor  = "INSERT INTO authors(id, full_name) VALUES ($1, $2)"
+	selectBooks   = "SELECT id, title, authors FROM books ORDER BY title"
+	insertBook    = "INSERT INTO books(id, title, authors) VALUES ($1, $2, $3)"
+)
+
+type author struct {
+	id       uuid.UUID
+	fullName string
+}
+
+type book struct {
+	id      uuid.UUID
+	title   string
+	authors []string
+}
+
+func addDBAuthors(db *sql.DB, authors []author) {
+	for _, author := range authors {
+		_, err := db.Exec(insertAuthor, author.id, author.fullName)
+		if err != nil {
+			log.Print("Failed to add author to db: ", err)
+		}
+	}
+}
+
+func getDBAuthors(t *testing.T, db *sql.DB) []author {
+	rows, err := db.Query(selectAuthors)
+	if err != nil {
+		t.Fatalf("Error while selecting authors: %v", err)
+	}
+	defer common.CloseRows(rows)
+	authors := make([]author, 0)
+
+	for rows.Next() {
+		a := author{}
+		err := rows.Scan(&a.id, &a.fullName)
+		if err != nil {
+			log.Fatal("Error scanning row:", err)
+		}
+		authors = append(authors, a)
+	}
+
+	if err := rows.Err(); err != nil {
+		log.Fatal("Error reading rows:", err)
+	}
+	return authors
+}
+
+func addDBBooks(db *sql.DB, books []book) {
+	for _, book := range books {
+		_, err := db.Exec(insertBook, book.id, book.title, pq.Array(book.authors))
+		if err != nil {
+			log.Print("Failed to add book to db: ", err)
+		}
+	}
+}
+
+func getDBBooks(t *testing.T, db *sql.DB) []book {
+	rows, err := db.Query(selectBooks)
+	if err != nil {
+		t.Fatalf("Error while selecting books: %v", err)
+	}
+	defer common.CloseRows(rows)
+	books := make([]book, 0)
+
+	for rows.Next() {
+		b := book{}
+		err := rows.Scan(&b.id, &b.title, pq.Array(&b.authors))
+		if err != nil {
+			log.Fatal("Error scanning row:", err)
+		}
+		books = append(books, b)
+	}
+
+	if err := rows.Err(); err != nil {
+		log.Fatal("Error reading rows:", err)
+	}
+	return books
+}
+
+func setupTestServer(db *sql.DB) *httptest.Server {
+	apiCfg := server.ApiConfig{DB: db, MaxSearchLimit: 10}
+	sm := http.NewServeMux()
+	server.Handle(sm, &apiCfg)
+	return httptest.NewServer(sm)
+}
+
+func makeAuthorMessage(t *testing.T, message common.AuthorMessage) kafka.Message {
+	value, err := json.Marshal(message)
+	assert.NoError(t, err)
+	return kafka.Message{Key: []byte(message.ID), Value: value}
+}
+
+func makeBookMessage(t *testing.T, message messages.BookMessage) kafka.Message {
+	value, err := json.Marshal(message)
+	assert.NoError(t, err)
+	return kafka.Message{Key: []byte(message.ID), Value: value}
+}
+
+func TestPing_Success(t *testing.T) {
+	db, err := common.SetupDBByURL("../.env", "TEST_DB_URL")
+	assert.NoError(t, err)
+	defer common.CloseDB(db)
+
+	s := setupTestServer(db)
+	defer s.Close()
+
+	response, err := http.Get(s.URL + server.PingPath)
+	assert.NoError(t, err)
+	defer common.CloseResponseBody(response)
+	assert.Equal(t, http.StatusOK, response.StatusCode)
+}
+
+func TestHandleAuthorMessage(t *testing.T) {
+	authorID := uuid.New()
+	type testCase struct {
+		name            string
+		dbAuthors       []author
+		message         common.AuthorMessage
+		hasError        bool
+		expectedAuthors []author
+	}
+	testCases := []testCase{
+		{
+			name:            "created",
+			dbAuthors:       []author{},
+			message:         common.AuthorMessage{ID: authorID.String(), FullName: "Leo Tolstoy", Action: "created"},
+			hasError:        false,
+			expectedAuthors: []author{{id: authorID, fullName: "Leo Tolstoy"}},
+		},
+		{
+			name:            "created_twice",
+			dbAuthors:       []author{{id: authorID, fullName: "Leo Tolstoy"}},
+			message:         common.AuthorMessage{ID: authorID.String(), FullName: "Lev Tolstoy", Action: "created"},
+			hasError:        false,
+			expectedAuthors: []author{{id: authorID, fullName: "Lev Tolstoy"}},
+		},
+		{
+			name:            "updated",
+			dbAuthors:       []author{{id: authorID, fullName: "Leo Tolstoy"}},
+			message:         common.AuthorMessage{ID: authorID.String(), FullName: "Lev Tolstoy", Action: "updated"},
+			hasError:        false,
+			expectedAuthors: []author{{id: authorID, fullName: "Lev Tolstoy"}},
+		},
+		{
+			name:            "deleted",
+			dbAuthors:       []author{{id: authorID, fullName: "Leo Tolstoy"}},
+			message:         common.AuthorMessage{ID: authorID.String(), Action: "deleted"},
+			hasError:        false,
+			expectedAuthors: []author{},
+		},
+		{
+			name:            "invalid_id",
+			dbAuthors:       []author{},
+			message:         common.AuthorMessage{ID: "invalid_id", FullName: "Leo Tolstoy", Action: "created"},
+			hasError:        true,
+			expectedAuthors: []author{},
+		},
+		{
+			name:            "unknown_action",
+			dbAuthors:       []author{},
+			message:         common.AuthorMessage{ID: authorID.String(), FullName: "Leo Tolstoy", Action: "unknown"},
+			hasError:        true,
+			expectedAuthors: []author{},
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			db, err := common.SetupDBByURL("../.env", "TEST_DB_URL")
+			assert.NoError(t, err)
+			defer common.CloseDB(db)
+			cleanupDB(db)
+			addDBAuthors(db, tc.dbAuthors)
+
+			err = consumer.HandleAuthorMessage(db)(context.Background(), makeAuthorMessage(t, tc.message))
+			assert.Equal(t, err != nil, tc.hasError)
+			assert.Equal(t, getDBAuthors(t, db), tc.expectedAuthors)
+		})
+	}
+}
+
+func TestHandleBookMessage(t *testing.T) {
+	bookID := uuid.New()
+	type testCase struct {
+		name          string
+		dbBooks       []book
+		message       messages.BookMessage
+		hasError      bool
+		expectedBooks []book
+	}
+	testCases := []testCase{
+		{
+			name:          "created",
+			dbBooks:       []book{},
+			message:       messages.BookMessage{ID: bookID.String(), Title: "War and Peace", Authors: []string{"Leo Tolstoy"}, Action: "created"},
+			hasError:      false,
+			expectedBooks: []book{{id: bookID, title: "War and Peace", authors: []string{"Leo Tolstoy"}}},
+		},
+		{
+			name:          "created_without_authors",
+			dbBooks:       []book{},
+			message:       messages.BookMessage{ID: bookID.String(), Title: "War and Peace", Action: "created"},
+			hasError:      false,
+			expectedBooks: []book{{id: bookID, title: "War and Peace", authors: []string{}}},
+		},
+		{
+			name:          "updated",
+			dbBooks:       []book{{id: bookID, title: "War and Peace", authors: []string{"Leo Tolstoy"}}},
+			message:       messages.BookMessage{ID: bookID.String(), Title: "Anna Karenina", Authors: []string{"Leo Tolstoy"}, Action: "updated"},
+			hasError:      false,
+			expectedBooks: []book{{id: bookID, title: "Anna Karenina", authors: []string{"Leo Tolstoy"}}},
+		},
+		{
+			name:          "deleted",
+			dbBooks:       []book{{id: bookID, title: "War and Peace", authors: []string{"Leo Tolstoy"}}},
+			message:       messages.BookMessage{ID: bookID.String(), Action: "deleted"},
+			hasError:      false,
+			expectedBooks: []book{},
+		},
+		{
+			name:          "invalid_id",
+			dbBooks:       []book{},
+			message:       messages.BookMessage{ID: "invalid_id", Title: "War and Peace", Action: "created"},
+			hasError:      true,
+			expectedBooks: []book{},
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			db, err := common.SetupDBByURL("../.env", "TEST_DB_URL")
+			assert.NoError(t, err)
+			defer common.CloseDB(db)
+			cleanupDB(db)
+			addDBBooks(db, tc.dbBooks)
+
+			err = consumer.HandleBookMessage(db)(context.Background(), makeBookMessage(t, tc.message))
+			assert.Equal(t, err != nil, tc.hasError)
+			assert.Equal(t, getDBBooks(t, db), tc.expectedBooks)
+		})
+	}
+}
+
+func TestSearch(t *testing.T) {
+	authorID1 := uuid.New()
+	authorID2 := uuid.New()
+	bookID1 := uuid.New()
+	bookID2 := uuid.New()
+	dbBooks := []book{
+		{id: bookID1, title: "War and Peace", authors: []string{"Leo Tolstoy"}},
+		{id: bookID2, title: "Crime and Punishment", authors: []string{"Fyodor Dostoevsky"}},
+	}
+	type testCase struct {
+		name               string
+		dbAuthors          []author
+		searchText         string
+		expectedStatusCode int
+		expectedResponse   server.ResponseSearch
+	}
+	testCases := []testCase{
+		{
+			name:               "found",
+			dbAuthors:          []author{{id: authorID1, fullName: "Leo Tolstoy"}, {id: authorID2, fullName: "Fyodor Dostoevsky"}},
+			searchText:         "tolstoy",
+			expectedStatusCode: http.StatusOK,
+			expectedResponse: server.ResponseSearch{
+				Authors: []server.ResponseAuthor{{ID: authorID1.String(), FullName: "Leo Tolstoy"}},
+				Books:   []server.ResponseBook{{ID: bookID1.String(), Title: "War and Peace", Authors: []string{"Leo Tolstoy"}}},
+			},
+		},
+		{
+			name:               "book_title",
+			dbAuthors:          []author{{id: authorID1, fullName: "Leo Tolstoy"}},
+			searchText:         "punishment",
+			expectedStatusCode: http.StatusOK,
+			expectedResponse: server.ResponseSearch{
+				Authors: []server.ResponseAuthor{},
+				Books:   []server.ResponseBook{{ID: bookID2.String(), Title: "Crime and Punishment", Authors: []string{"Fyodor Dostoevsky"}}},
+			},
+		},
+		{
+			name:               "not_found",
+			dbAuthors:          []author{{id: authorID1, fullName: "Leo Tolstoy"}},
+			searchText:         "pushkin",
+			expectedStatusCode: http.StatusOK,
+			expectedResponse:   server.ResponseSearch{Authors: []server.ResponseAuthor{}, Books: []server.ResponseBook{}},
+		},
+		{
+			name:               "empty_search_text",
+			dbAuthors:          []author{{id: authorID1, fullName: "Leo Tolstoy"}},
+			searchText:         "",
+			expectedStatusCode: http.StatusBadRequest,
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			db, err := common.SetupDBByURL("../.env", "TEST_DB_URL")
+			assert.NoError(t, err)
+			defer common.CloseDB(db)
+			cleanupDB(db)
+			addDBAuthors(db, tc.dbAuthors)
+			addDBBooks(db, dbBooks)
+
+			s := setupTestServer(db)
+			defer s.Close()
+
+			params := url.Values{}
+			params.Add("q", tc.searchText)
+			response, err := http.Get(s.URL + server.ApiSearchPath + "?" + params.Encode())
+			assert.NoError(t, err)
+			defer common.CloseResponseBody(response)
+			assert.Equal(t, tc.expectedStatusCode, response.StatusCode)
+
+			if response.StatusCode == http.StatusOK {
+				responseBody := server.ResponseSearch{}
+				err = json.NewDecoder(response.Body).Decode(&responseBody)
+				assert.NoError(t, err)
+				assert.Equal(t, responseBody, tc.expectedResponse)
+			}
+		})
+	}
+}
@@ -21,6 +21,11 @@ const docTemplate = `{
     "paths": {
         "/admin/authors/{id}": {
             "delete": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
                 "description": "Deletes an author with requested ID from DB",
                 "consumes": [
                     "application/json"
@@ -54,6 +59,18 @@ const docTemplate = `{
                             "$ref": "#/definitions/server.ErrorResponse"
                         }
                     },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/server.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/server.ErrorResponse"
+                        }
+                    },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {
@@ -65,6 +82,11 @@ const docTemplate = `{
         },
         "/admin/books/{id}": {
             "delete": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
                 "description": "Deletes a book from DB with requested ID",
                 "consumes": [
                     "application/json"
@@ -98,6 +120,24 @@ const docTemplate = `{
                             "$ref": "#/definitions/server.ErrorResponse"
                         }
                     },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/server.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/server.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Book not found",
+                        "schema": {
+                            "$ref": "#/definitions/server.ErrorResponse"
+                        }
+                    },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {
@@ -107,9 +147,14 @@ const docTemplate = `{
                 }
             }
         },
-        "/api/authors": {
+        "/admin/genres": {
             "get": {
-                "description": "Gets all authors from DB",
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Gets all genres from DB sorted by name",
                 "consumes": [
                     "application/json"
                 ],
@@ -117,19 +162,31 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Authors"
+                    "Admin Genres"
                 ],
-                "summary": "Get authors",
+                "summary": "Get genres",
                 "responses": {
                     "200": {
-                        "description": "Author's short info",
+                        "description": "Genres",
                         "schema": {
                             "type": "array",
                             "items": {
-                                "$ref": "#/definitions/server.ResponseAuthorShortInfo"
+                                "$ref": "#/definitions/server.ResponseGenre"
                             }
                         }
                     },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/server.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/server.ErrorResponse"
+                        }
+                    },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {
@@ -139,7 +196,12 @@ const docTemplate = `{
                 }
             },
             "put": {
-                "description": "Updates existing author's info in DB",
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Renames existing genre",
                 "consumes": [
                     "application/json"
                 ],
@@ -147,17 +209,17 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Authors"
+                    "Admin Genres"
                 ],
-                "summary": "Update author",
+                "summary": "Update genre",
                 "parameters": [
                     {
-                        "description": "Author's info",
+                        "description": "Genre's info",
                         "name": "request",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/server.RequestAuthorWithID"
+                            "$ref": "#/definitions/server.RequestGenreWithID"
                         }
                     }
                 ],
@@ -169,13 +231,31 @@ const docTemplate = `{
                         }
                     },
                     "400": {
-                        "description": "Invalid request body or empty full_name",
+                        "description": "Invalid request body or empty name",
+                        "schema": {
+                            "$ref": "#/definitions/server.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/server.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
                         "schema": {
                             "$ref": "#/definitions/server.ErrorResponse"
                         }
                     },
                     "404": {
-                        "description": "Author not found",
+                        "description": "Genre not found",
+                        "schema": {
+                            "$ref": "#/definitions/server.ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Genre already exists",
                         "schema": {
                             "$ref": "#/definitions/server.ErrorResponse"
                         }
@@ -189,7 +269,12 @@ const docTemplate = `{
                 }
             },
             "post": {
-                "description": "Creates new author and stores it in DB",
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Creates new genre and stores it in DB",
                 "consumes": [
                     "application/json"
                 ],
@@ -197,29 +282,47 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Authors"
+                    "Admin Genres"
                 ],
-                "summary": "Create new author",
+                "summary": "Create new genre",
                 "parameters": [
                     {
-                        "description": "Author's info",
+                        "description": "Genre's info",
                         "name": "request",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/server.RequestAuthor"
+                            "$ref": "#/definitions/server.RequestGenre"
                         }
                     }
                 ],
                 "responses": {
                     "201": {
-                        "description": "Created successfully",
+                        "description": "Created genre",
                         "schema": {
-                            "type": "string"
+                            "$ref": "#/definitions/server.ResponseGenre"
                         }
                     },
                     "400": {
-                        "description": "Invalid request body or empty full_name",
+                        "description": "Invalid request body or empty name",
+                        "schema": {
+                            "$ref": "#/definitions/server.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/server.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/server.ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Genre already exists",
                         "schema": {
                             "$ref": "#/definitions/server.ErrorResponse"
                         }
@@ -233,9 +336,14 @@ const docTemplate = `{
                 }
             }
         },
-        "/api/authors/{id}": {
-            "get": {
-                "description": "Gets an author with requested ID from DB",
+        "/admin/genres/{id}": {
+            "delete": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Deletes a genre with requested ID from DB. Books lose this genre",
                 "consumes": [
                     "application/json"
                 ],
@@ -243,33 +351,39 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Authors"
+                    "Admin Genres"
                 ],
-                "summary": "Get author",
+                "summary": "Delete genre",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "Author ID",
+                        "description": "Genre ID",
                         "name": "id",
                         "in": "path",
                         "required": true
                     }
                 ],
                 "responses": {
-                    "200": {
-                        "description": "Author's full info",
+                    "204": {
+                        "description": "Deleted successfully",
                         "schema": {
-                            "$ref": "#/definitions/server.ResponseAuthorFullInfo"
+                            "type": "string"
                         }
                     },
                     "400": {
-                        "description": "Invalid author ID",
+                        "description": "Invalid genre ID",
                         "schema": {
                             "$ref": "#/definitions/server.ErrorResponse"
                         }
                     },
-                    "404": {
-                        "description": "Author not found",
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/server.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
                         "schema": {
                             "$ref": "#/definitions/server.ErrorResponse"
                         }
@@ -283,9 +397,9 @@ const docTemplate = `{
                 }
             }
         },
-        "/api/authors/{id}/books": {
+        "/api/authors": {
             "get": {
-                "description": "Returns a list of books written by the specified author",
+                "description": "Gets a page of authors from DB. Authors are sorted by full name or by creation time (newest first)",
                 "consumes": [
                     "application/json"
                 ],
@@ -295,34 +409,42 @@ const docTemplate = `{
                 "tags": [
                     "Authors"
                 ],
-                "summary": "Get author's books",
+                "summary": "Get authors",
                 "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Page size",
+                        "name": "limit",
+                        "in": "query"
+                    },
                     {
                         "type": "string",
-                        "description": "Author ID",
-                        "name": "id",
-                        "in": "path",
-                        "required": true
+                        "description": "Cursor returned with the previous page",
+                        "name": "cursor",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Sort field: full_name (default) or created_at",
+                        "name": "sort",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Returns only authors created on or after the date (DD.MM.YYYY)",
+                        "name": "created_after",
+                        "in": "query"
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "Author's books",
+                        "description": "Page of authors' short info",
                         "schema": {
-                            "type": "array",
-                            "items": {
-                                "$ref": "#/definitions/server.ResponseBook"
-                            }
+                            "$ref": "#/definitions/server.ResponseAuthorsPage"
                         }
                     },
                     "400": {
-                        "description": "Invalid author ID",
-                        "schema": {
-                            "$ref": "#/definitions/server.ErrorResponse"
-                        }
-                    },
-                    "404": {
-                        "description": "Author not found",
+                        "description": "Invalid query parameters",
                         "schema": {
                             "$ref": "#/definitions/server.ErrorResponse"
                         }
@@ -334,11 +456,14 @@ const docTemplate = `{
                         }
                     }
                 }
-            }
-        },
-        "/api/books": {
+            },
             "put": {
-                "description": "Updates existing book's info in DB",
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Updates existing author's info in DB",
                 "consumes": [
                     "application/json"
                 ],
@@ -346,17 +471,17 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Books"
+                    "Authors"
                 ],
-                "summary": "Update book",
+                "summary": "Update author",
                 "parameters": [
                     {
-                        "description": "Book's info",
+                        "description": "Author's info",
                         "name": "request",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/server.RequestBookWithID"
+                            "$ref": "#/definitions/server.RequestAuthorWithID"
                         }
                     }
                 ],
@@ -368,13 +493,25 @@ const docTemplate = `{
                         }
                     },
                     "400": {
-                        "description": "Invalid request body or empty title",
+                        "description": "Invalid request body, empty full_name or invalid language",
+                        "schema": {
+                            "$ref": "#/definitions/server.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/server.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
                         "schema": {
                             "$ref": "#/definitions/server.ErrorResponse"
                         }
                     },
                     "404": {
-                        "description": "Book not found",
+                        "description": "Author not found",
                         "schema": {
                             "$ref": "#/definitions/server.ErrorResponse"
                         }
@@ -388,7 +525,12 @@ const docTemplate = `{
                 }
             },
             "post": {
-                "description": "Creates new book and stores it in DB",
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Creates new author and stores it in DB",
                 "consumes": [
                     "application/json"
                 ],
@@ -396,17 +538,17 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Books"
+                    "Authors"
                 ],
-                "summary": "Create new book",
+                "summary": "Create new author",
                 "parameters": [
                     {
-                        "description": "Book's info",
+                        "description": "Author's info",
                         "name": "request",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/server.RequestBook"
+                            "$ref": "#/definitions/server.RequestAuthor"
                         }
                     }
                 ],
@@ -418,7 +560,19 @@ const docTemplate = `{
                         }
                     },
                     "400": {
-                        "description": "Invalid request body or empty title",
+                        "description": "Invalid request body, empty full_name or invalid language",
+                        "schema": {
+                            "$ref": "#/definitions/server.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/server.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
                         "schema": {
                             "$ref": "#/definitions/server.ErrorResponse"
                         }
@@ -432,9 +586,9 @@ const docTemplate = `{
                 }
             }
         },
-        "/api/books/search": {
-            "post": {
-                "description": "Gets books full info from DB",
+        "/api/authors/search": {
+            "get": {
+                "description": "Searches authors by name. Uses postgres full text search, fuzzy mode also matches names with typos by trigram similarity, prefix mode matches words by their beginnings",
                 "consumes": [
                     "application/json"
                 ],
@@ -442,32 +596,42 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Books"
+                    "Authors"
                 ],
-                "summary": "Get books",
+                "summary": "Search authors by name",
                 "parameters": [
                     {
-                        "description": "Book ids",
-                        "name": "request",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/server.RequestBookIDs"
-                        }
+                        "type": "string",
+                        "description": "Search text",
+                        "name": "text",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Search mode: exact (default), fuzzy or prefix",
+                        "name": "mode",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Language of search text, two-letter ISO 639-1 code (language of each book or author by default)",
+                        "name": "lang",
+                        "in": "query"
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "Books full info",
+                        "description": "Authors' info",
                         "schema": {
                             "type": "array",
                             "items": {
-                                "$ref": "#/definitions/server.ResponseBookFullInfo"
+                                "$ref": "#/definitions/server.ResponseAuthorShortInfo"
                             }
                         }
                     },
                     "400": {
-                        "description": "Invalid request",
+                        "description": "Empty search text, invalid mode or lang",
                         "schema": {
                             "$ref": "#/definitions/server.ErrorResponse"
                         }
@@ -481,9 +645,9 @@ const docTemplate = `{
                 }
             }
         },
-        "/ping": {
+        "/api/authors/{id}": {
             "get": {
-                "description": "Checks server health. Returns 200 OK if server is up.",
+                "description": "Gets an author with requested ID from DB",
                 "consumes": [
                     "application/json"
                 ],
@@ -491,24 +655,490 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Health"
+                    "Authors"
+                ],
+                "summary": "Get author",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Author ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
                 ],
-                "summary": "Ping the server",
                 "responses": {
                     "200": {
-                        "description": "OK",
+                        "description": "Author's full info",
                         "schema": {
-                            "type": "string"
+                            "$ref": "#/definitions/server.ResponseAuthorFullInfo"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid author ID",
+                        "schema": {
+                            "$ref": "#/definitions/server.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Author not found",
+                        "schema": {
+                            "$ref": "#/definitions/server.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/server.ErrorResponse"
                         }
                     }
                 }
             }
-        }
-    },
-    "definitions": {
-        "server.ErrorResponse": {
-            "type": "object",
-            "properties": {
+        },
+        "/api/authors/{id}/books": {
+            "get": {
+                "description": "Returns a list of books written by the specified author",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Authors"
+                ],
+                "summary": "Get author's books",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Author ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Author's books",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/server.ResponseBook"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid author ID",
+                        "schema": {
+                            "$ref": "#/definitions/server.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Author not found",
+                        "schema": {
+                            "$ref": "#/definitions/server.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/server.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/books": {
+            "get": {
+                "description": "Gets a page of books from DB. Books are sorted by title or by creation time (newest first)",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Books"
+                ],
+                "summary": "Get books",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Page size",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Cursor returned with the previous page",
+                        "name": "cursor",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Sort field: title (default) or created_at",
+                        "name": "sort",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Returns only books of the author with this ID",
+                        "name": "author",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Returns only books created on or after the date (DD.MM.YYYY)",
+                        "name": "created_after",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Page of books' full info",
+                        "schema": {
+                            "$ref": "#/definitions/server.ResponseBooksPage"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid query parameters",
+                        "schema": {
+                            "$ref": "#/definitions/server.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/server.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "put": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Updates existing book's info in DB",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Books"
+                ],
+                "summary": "Update book",
+                "parameters": [
+                    {
+                        "description": "Book's info",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/server.RequestBookWithID"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Updated successfully",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid request body, empty title or invalid metadata",
+                        "schema": {
+                            "$ref": "#/definitions/server.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/server.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/server.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Book not found",
+                        "schema": {
+                            "$ref": "#/definitions/server.ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Book with the same ISBN already exists",
+                        "schema": {
+                            "$ref": "#/definitions/server.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/server.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Creates new book and stores it in DB",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Books"
+                ],
+                "summary": "Create new book",
+                "parameters": [
+                    {
+                        "description": "Book's info",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/server.RequestBook"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created successfully",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid request body, empty title or invalid metadata",
+                        "schema": {
+                            "$ref": "#/definitions/server.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/server.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/server.ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Book with the same ISBN already exists",
+                        "schema": {
+                            "$ref": "#/definitions/server.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/server.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/books/search": {
+            "get": {
+                "description": "Searches books by title (highest weight), author names, description and genres. Uses postgres full text search and returns headline with highlighted matches, fuzzy mode also matches titles with typos by trigram similarity, prefix mode matches words by their beginnings",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Books"
+                ],
+                "summary": "Search books by title, authors and description",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Search text",
+                        "name": "text",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Search mode: exact (default), fuzzy or prefix",
+                        "name": "mode",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Language of search text, two-letter ISO 639-1 code (language of each book or author by default)",
+                        "name": "lang",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Books' full info",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/server.ResponseBookFullInfo"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Empty search text, invalid mode or lang",
+                        "schema": {
+                            "$ref": "#/definitions/server.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/server.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "description": "Gets books full info from DB",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Books"
+                ],
+                "summary": "Get books",
+                "parameters": [
+                    {
+                        "description": "Book ids",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/server.RequestBookIDs"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Books full info",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/server.ResponseBookFullInfo"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid request",
+                        "schema": {
+                            "$ref": "#/definitions/server.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/server.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/books/{id}": {
+            "get": {
+                "description": "Gets a book with requested ID from DB",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Books"
+                ],
+                "summary": "Get book",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Book ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Book's full info",
+                        "schema": {
+                            "$ref": "#/definitions/server.ResponseBookFullInfo"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid book ID",
+                        "schema": {
+                            "$ref": "#/definitions/server.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Book not found",
+                        "schema": {
+                            "$ref": "#/definitions/server.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/server.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/ping": {
+            "get": {
+                "description": "Checks server health. Returns 200 OK if server is up.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Health"
+                ],
+                "summary": "Ping the server",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        }
+    },
+    "definitions": {
+        "server.ErrorResponse": {
+            "type": "object",
+            "properties": {
                 "error": {
                     "type": "string"
                 }
@@ -525,6 +1155,9 @@ const docTemplate = `{
                 },
                 "full_name": {
                     "type": "string"
+                },
+                "language": {
+                    "type": "string"
                 }
             }
         },
@@ -542,6 +1175,9 @@ const docTemplate = `{
                 },
                 "id": {
                     "type": "string"
+                },
+                "language": {
+                    "type": "string"
                 }
             }
         },
@@ -554,6 +1190,27 @@ const docTemplate = `{
                         "type": "string"
                     }
                 },
+                "description": {
+                    "type": "string"
+                },
+                "genres": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "isbn": {
+                    "type": "string"
+                },
+                "language": {
+                    "type": "string"
+                },
+                "page_count": {
+                    "type": "integer"
+                },
+                "publication_year": {
+                    "type": "integer"
+                },
                 "title": {
                     "type": "string"
                 }
@@ -579,14 +1236,54 @@ const docTemplate = `{
                         "type": "string"
                     }
                 },
+                "description": {
+                    "type": "string"
+                },
+                "genres": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
                 "id": {
                     "type": "string"
                 },
+                "isbn": {
+                    "type": "string"
+                },
+                "language": {
+                    "type": "string"
+                },
+                "page_count": {
+                    "type": "integer"
+                },
+                "publication_year": {
+                    "type": "integer"
+                },
                 "title": {
                     "type": "string"
                 }
             }
         },
+        "server.RequestGenre": {
+            "type": "object",
+            "properties": {
+                "name": {
+                    "type": "string"
+                }
+            }
+        },
+        "server.RequestGenreWithID": {
+            "type": "object",
+            "properties": {
+                "id": {
+                    "type": "string"
+                },
+                "name": {
+                    "type": "string"
+                }
+            }
+        },
         "server.ResponseAuthorFullInfo": {
             "type": "object",
             "properties": {
@@ -598,6 +1295,9 @@ const docTemplate = `{
                 },
                 "full_name": {
                     "type": "string"
+                },
+                "language": {
+                    "type": "string"
                 }
             }
         },
@@ -612,6 +1312,20 @@ const docTemplate = `{
                 }
             }
         },
+        "server.ResponseAuthorsPage": {
+            "type": "object",
+            "properties": {
+                "items": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/server.ResponseAuthorShortInfo"
+                    }
+                },
+                "next_cursor": {
+                    "type": "string"
+                }
+            }
+        },
         "server.ResponseBook": {
             "type": "object",
             "properties": {
@@ -632,13 +1346,76 @@ const docTemplate = `{
                         "type": "string"
                     }
                 },
+                "authors_info": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/server.ResponseAuthorShortInfo"
+                    }
+                },
+                "description": {
+                    "type": "string"
+                },
+                "genres": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/server.ResponseGenre"
+                    }
+                },
+                "headline": {
+                    "type": "string"
+                },
                 "id": {
                     "type": "string"
                 },
+                "isbn": {
+                    "type": "string"
+                },
+                "language": {
+                    "type": "string"
+                },
+                "page_count": {
+                    "type": "integer"
+                },
+                "publication_year": {
+                    "type": "integer"
+                },
                 "title": {
                     "type": "string"
                 }
             }
+        },
+        "server.ResponseBooksPage": {
+            "type": "object",
+            "properties": {
+                "items": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/server.ResponseBookFullInfo"
+                    }
+                },
+                "next_cursor": {
+                    "type": "string"
+                }
+            }
+        },
+        "server.ResponseGenre": {
+            "type": "object",
+            "properties": {
+                "id": {
+                    "type": "string"
+                },
+                "name": {
+                    "type": "string"
+                }
+            }
+        }
+    },
+    "securityDefinitions": {
+        "BearerAuth": {
+            "description": "Access token issued by users service: \"Bearer {token}\"",
+            "type": "apiKey",
+            "name": "Authorization",
+            "in": "header"
         }
     }
 }`
@@ -0,0 +1,66 @@
+package auth
+
+import (
+	"errors"
+	"net/http"
+	"strings"
+
+	"github.com/bakurvik/mylib/shared/jwks"
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/google/uuid"
+)
+
+// Issuer is iss claim of access tokens issued by users service.
+const Issuer = "mylib.users"
+
+const (
+	RoleReader = "reader"
+	RoleEditor = "editor"
+	RoleAdmin  = "admin"
+)
+
+// roleLevels orders roles: every role has all permissions of the roles below it.
+var roleLevels = map[string]int{
+	RoleReader: 1,
+	RoleEditor: 2,
+	RoleAdmin:  3,
+}
+
+// Claims are JWT claims of access tokens issued by users service.
+type Claims struct {
+	Role string `json:"role"`
+	jwt.RegisteredClaims
+}
+
+// HasRole reports whether role has permissions of requiredRole. Unknown roles have no permissions.
+func HasRole(role string, requiredRole string) bool {
+	level, ok := roleLevels[role]
+	return ok && level >= roleLevels[requiredRole]
+}
+
+// ValidateJWT checks access token with the public key returned by keyfunc and returns user ID and role from it.
+func ValidateJWT(tokenString string, keyfunc jwt.Keyfunc) (uuid.UUID, string, error) {
+	claims := Claims{}
+	_, err := jwt.ParseWithClaims(tokenString, &claims, keyfunc, jwt.WithValidMethods(jwks.Algorithms), jwt.WithIssuer(Issuer))
+	if err != nil {
+		return uuid.Nil, "", err
+	}
+
+	userID, err := uuid.Parse(claims.Subject)
+	if err != nil {
+		return uuid.Nil, "", err
+	}
+	return userID, claims.Role, nil
+}
+
+func GetBearerToken(headers http.Header) (string, error) {
+	authHeader := headers.Get("Authorization")
+	if authHeader == "" {
+		return "", errors.New("no authorization header")
+	}
+	token, found := strings.CutPrefix(authHeader, "Bearer ")
+	if !found {
+		return "", errors.New("no token in header")
+	}
+	return token, nil
+}
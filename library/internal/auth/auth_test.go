@@ -0,0 +1,106 @@
+package auth
+
+import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+func makeToken(t *testing.T, method jwt.SigningMethod, key interface{}, issuer string, userID uuid.UUID, role string, expiresIn time.Duration) string {
+	claims := Claims{
+		Role: role,
+		RegisteredClaims: jwt.RegisteredClaims{
+			Issuer:    issuer,
+			Subject:   userID.String(),
+			ExpiresAt: jwt.NewNumericDate(time.Now().Add(expiresIn)),
+		},
+	}
+	token, err := jwt.NewWithClaims(method, claims).SignedString(key)
+	assert.NoError(t, err)
+	return token
+}
+
+func TestValidateJWT(t *testing.T) {
+	userID := uuid.New()
+	publicKey, privateKey, _ := ed25519.GenerateKey(rand.Reader)
+	_, anotherPrivateKey, _ := ed25519.GenerateKey(rand.Reader)
+	keyfunc := func(token *jwt.Token) (interface{}, error) { return publicKey, nil }
+
+	type testCase struct {
+		name         string
+		token        string
+		expectedRole string
+		hasError     bool
+	}
+	testCases := []testCase{
+		{
+			name:         "valid",
+			token:        makeToken(t, jwt.SigningMethodEdDSA, privateKey, Issuer, userID, RoleEditor, time.Hour),
+			expectedRole: RoleEditor,
+			hasError:     false,
+		},
+		{
+			name:     "expired",
+			token:    makeToken(t, jwt.SigningMethodEdDSA, privateKey, Issuer, userID, RoleEditor, -time.Hour),
+			hasError: true,
+		},
+		{
+			name:     "invalid_key",
+			token:    makeToken(t, jwt.SigningMethodEdDSA, anotherPrivateKey, Issuer, userID, RoleEditor, time.Hour),
+			hasError: true,
+		},
+		{
+			name:     "invalid_issuer",
+			token:    makeToken(t, jwt.SigningMethodEdDSA, privateKey, "another", userID, RoleEditor, time.Hour),
+			hasError: true,
+		},
+		{
+			name:     "hmac_with_public_key",
+			token:    makeToken(t, jwt.SigningMethodHS256, []byte(publicKey), Issuer, userID, RoleAdmin, time.Hour),
+			hasError: true,
+		},
+		{
+			name:     "unsigned",
+			token:    makeToken(t, jwt.SigningMethodNone, jwt.UnsafeAllowNoneSignatureType, Issuer, userID, RoleAdmin, time.Hour),
+			hasError: true,
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			parsedID, role, err := ValidateJWT(tc.token, keyfunc)
+			assert.Equal(t, err != nil, tc.hasError)
+			if !tc.hasError {
+				assert.Equal(t, parsedID, userID)
+				assert.Equal(t, role, tc.expectedRole)
+			}
+		})
+	}
+}
+
+func TestHasRole(t *testing.T) {
+	type testCase struct {
+		role         string
+		requiredRole string
+		expected     bool
+	}
+	testCases := []testCase{
+		{role: RoleReader, requiredRole: RoleReader, expected: true},
+		{role: RoleReader, requiredRole: RoleEditor, expected: false},
+		{role: RoleEditor, requiredRole: RoleEditor, expected: true},
+		{role: RoleEditor, requiredRole: RoleAdmin, expected: false},
+		{role: RoleAdmin, requiredRole: RoleEditor, expected: true},
+		{role: RoleAdmin, requiredRole: RoleAdmin, expected: true},
+		{role: "", requiredRole: RoleReader, expected: false},
+		{role: "owner", requiredRole: RoleReader, expected: false},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.role+"_"+tc.requiredRole, func(t *testing.T) {
+			assert.Equal(t, HasRole(tc.role, tc.requiredRole), tc.expected)
+		})
+	}
+}
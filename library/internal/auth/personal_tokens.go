@@ -0,0 +1,91 @@
+package auth
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"slices"
+	"strings"
+	"time"
+
+	common "github.com/bakurvik/mylib-common"
+	"github.com/google/uuid"
+)
+
+// PersonalTokenPrefix starts personal access tokens issued by users service. Unlike access tokens they are opaque,
+// so they are checked by users service.
+const PersonalTokenPrefix = "mylib_pat_"
+
+// ScopeLibraryWrite allows a personal access token to change the catalogue within the role of its user.
+const ScopeLibraryWrite = "library:write"
+
+const usersWhoamiPath = "/auth/whoami"
+
+// ErrInvalidToken means that the token is unknown, expired or revoked.
+var ErrInvalidToken = errors.New("invalid token")
+
+func IsPersonalToken(token string) bool {
+	return strings.HasPrefix(token, PersonalTokenPrefix)
+}
+
+// TokenInfo is the user of a personal access token and the scopes the token is limited to.
+type TokenInfo struct {
+	UserID uuid.UUID
+	Role   string
+	Scopes []string
+}
+
+func (info TokenInfo) HasScope(scope string) bool {
+	return slices.Contains(info.Scopes, scope)
+}
+
+// PersonalTokenChecker returns the user of a personal access token or ErrInvalidToken.
+type PersonalTokenChecker interface {
+	CheckPersonalToken(ctx context.Context, token string) (TokenInfo, error)
+}
+
+// UsersPersonalTokenChecker checks personal access tokens with /auth/whoami of users service.
+type UsersPersonalTokenChecker struct {
+	UsersServiceHost string
+	Client           *http.Client
+}
+
+func NewUsersPersonalTokenChecker(usersServiceHost string) *UsersPersonalTokenChecker {
+	return &UsersPersonalTokenChecker{UsersServiceHost: usersServiceHost, Client: &http.Client{Timeout: 5 * time.Second}}
+}
+
+func (c *UsersPersonalTokenChecker) CheckPersonalToken(ctx context.Context, token string) (TokenInfo, error) {
+	request, err := http.NewRequestWithContext(ctx, http.MethodGet, c.UsersServiceHost+usersWhoamiPath, nil)
+	if err != nil {
+		return TokenInfo{}, err
+	}
+	request.Header.Set("Authorization", "Bearer "+token)
+	response, err := c.Client.Do(request)
+	if err != nil {
+		return TokenInfo{}, err
+	}
+	defer common.CloseResponseBody(response)
+	if response.StatusCode == http.StatusUnauthorized {
+		return TokenInfo{}, ErrInvalidToken
+	}
+	if response.StatusCode != http.StatusOK {
+		return TokenInfo{}, fmt.Errorf("users service responded with status %v", response.StatusCode)
+	}
+
+	whoami := struct {
+		ID     string   `json:"user_id"`
+		Role   string   `json:"role"`
+		Scopes []string `json:"scopes"`
+	}{}
+	err = json.NewDecoder(response.Body).Decode(&whoami)
+	if err != nil {
+		return TokenInfo{}, err
+	}
+	userID, err := uuid.Parse(whoami.ID)
+	if err != nil {
+		return TokenInfo{}, err
+	}
+	return TokenInfo{UserID: userID, Role: whoami.Role, Scopes: whoami.Scopes}, nil
+}
@@ -0,0 +1,57 @@
+package auth
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	common "github.com/bakurvik/mylib-common"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestUsersPersonalTokenChecker(t *testing.T) {
+	userID := uuid.New()
+	users := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, r.URL.Path, usersWhoamiPath)
+		switch r.Header.Get("Authorization") {
+		case "Bearer mylib_pat_valid":
+			common.RespondWithJSON(w, http.StatusOK, map[string]any{"user_id": userID.String(), "role": RoleEditor, "scopes": []string{ScopeLibraryWrite}}, nil)
+		case "Bearer mylib_pat_broken":
+			common.RespondWithError(w, http.StatusInternalServerError, "failed")
+		default:
+			common.RespondWithError(w, http.StatusUnauthorized, "invalid personal access token")
+		}
+	}))
+	defer users.Close()
+	checker := NewUsersPersonalTokenChecker(users.URL)
+
+	type testCase struct {
+		name         string
+		token        string
+		expectedInfo TokenInfo
+		expectedErr  error
+		anyErr       bool
+	}
+	testCases := []testCase{
+		{name: "valid", token: "mylib_pat_valid", expectedInfo: TokenInfo{UserID: userID, Role: RoleEditor, Scopes: []string{ScopeLibraryWrite}}},
+		{name: "invalid", token: "mylib_pat_invalid", expectedErr: ErrInvalidToken, anyErr: true},
+		{name: "users_service_error", token: "mylib_pat_broken", anyErr: true},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			info, err := checker.CheckPersonalToken(context.Background(), tc.token)
+			assert.Equal(t, err != nil, tc.anyErr)
+			if tc.expectedErr != nil {
+				assert.ErrorIs(t, err, tc.expectedErr)
+			}
+			assert.Equal(t, info, tc.expectedInfo)
+		})
+	}
+}
+
+func TestIsPersonalToken(t *testing.T) {
+	assert.True(t, IsPersonalToken("mylib_pat_0123"))
+	assert.False(t, IsPersonalToken("eyJhbGciOiJFZERTQSJ9.eyJzdWIiOiIxIn0.c2ln"))
+}
@@ -0,0 +1,28 @@
+// Code generated by sqlc. DO NOT EDIT.
+// versions:
+//   sqlc v1.28.0
+// source: add_book_genres.sql
+
+package database
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+)
+
+const addBookGenres = `-- name: AddBookGenres :exec
+INSERT INTO book_genres (book_id, genre_id)
+SELECT $1, UNNEST($2::UUID[])
+`
+
+type AddBookGenresParams struct {
+	Book   uuid.UUID
+	Genres []uuid.UUID
+}
+
+func (q *Queries) AddBookGenres(ctx context.Context, arg AddBookGenresParams) error {
+	_, err := q.db.ExecContext(ctx, addBookGenres, arg.Book, pq.Array(arg.Genres))
+	return err
+}
@@ -13,9 +13,9 @@ import (
 )
 
 const createAuthor = `-- name: CreateAuthor :one
-INSERT INTO authors (id, full_name, birth_date, death_date, created_at, updated_at)
+INSERT INTO authors (id, full_name, birth_date, death_date, language, created_at, updated_at)
 VALUES (
-    gen_random_uuid(), $1, $2, $3, NOW(), NOW()
+    gen_random_uuid(), $1, $2, $3, $4, NOW(), NOW()
 )
 RETURNING id
 `
@@ -24,10 +24,16 @@ type CreateAuthorParams struct {
 	FullName  string
 	BirthDate sql.NullTime
 	DeathDate sql.NullTime
+	Language  sql.NullString
 }
 
 func (q *Queries) CreateAuthor(ctx context.Context, arg CreateAuthorParams) (uuid.UUID, error) {
-	row := q.db.QueryRowContext(ctx, createAuthor, arg.FullName, arg.BirthDate, arg.DeathDate)
+	row := q.db.QueryRowContext(ctx, createAuthor,
+		arg.FullName,
+		arg.BirthDate,
+		arg.DeathDate,
+		arg.Language,
+	)
 	var id uuid.UUID
 	err := row.Scan(&id)
 	return id, err
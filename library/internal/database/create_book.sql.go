@@ -7,20 +7,37 @@ package database
 
 import (
 	"context"
+	"database/sql"
 
 	"github.com/google/uuid"
 )
 
 const createBook = `-- name: CreateBook :one
-INSERT INTO books (id, title, created_at, updated_at)
+INSERT INTO books (id, title, isbn, publication_year, language, page_count, description, created_at, updated_at)
 VALUES (
-    gen_random_uuid(), $1, NOW(), NOW()
+    gen_random_uuid(), $1, $2, $3, $4, $5, $6, NOW(), NOW()
 )
 RETURNING id
 `
 
-func (q *Queries) CreateBook(ctx context.Context, title string) (uuid.UUID, error) {
-	row := q.db.QueryRowContext(ctx, createBook, title)
+type CreateBookParams struct {
+	Title           string
+	Isbn            sql.NullString
+	PublicationYear sql.NullInt32
+	Language        sql.NullString
+	PageCount       sql.NullInt32
+	Description     sql.NullString
+}
+
+func (q *Queries) CreateBook(ctx context.Context, arg CreateBookParams) (uuid.UUID, error) {
+	row := q.db.QueryRowContext(ctx, createBook,
+		arg.Title,
+		arg.Isbn,
+		arg.PublicationYear,
+		arg.Language,
+		arg.PageCount,
+		arg.Description,
+	)
 	var id uuid.UUID
 	err := row.Scan(&id)
 	return id, err
@@ -0,0 +1,27 @@
+// Code generated by sqlc. DO NOT EDIT.
+// versions:
+//   sqlc v1.28.0
+// source: create_genre.sql
+
+package database
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+)
+
+const createGenre = `-- name: CreateGenre :one
+INSERT INTO genres (id, name, created_at, updated_at)
+VALUES (
+    gen_random_uuid(), $1, NOW(), NOW()
+)
+RETURNING id
+`
+
+func (q *Queries) CreateGenre(ctx context.Context, name string) (uuid.UUID, error) {
+	row := q.db.QueryRowContext(ctx, createGenre, name)
+	var id uuid.UUID
+	err := row.Scan(&id)
+	return id, err
+}
@@ -0,0 +1,26 @@
+// Code generated by sqlc. DO NOT EDIT.
+// versions:
+//   sqlc v1.28.0
+// source: create_outbox_message.sql
+
+package database
+
+import (
+	"context"
+)
+
+const createOutboxMessage = `-- name: CreateOutboxMessage :exec
+INSERT INTO outbox (topic, message_key, payload, created_at)
+VALUES ($1, $2, $3, NOW())
+`
+
+type CreateOutboxMessageParams struct {
+	Topic      string
+	MessageKey string
+	Payload    []byte
+}
+
+func (q *Queries) CreateOutboxMessage(ctx context.Context, arg CreateOutboxMessageParams) error {
+	_, err := q.db.ExecContext(ctx, createOutboxMessage, arg.Topic, arg.MessageKey, arg.Payload)
+	return err
+}
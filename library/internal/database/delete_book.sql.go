@@ -11,11 +11,14 @@ import (
 	"github.com/google/uuid"
 )
 
-const deleteBook = `-- name: DeleteBook :exec
+const deleteBook = `-- name: DeleteBook :execrows
 DELETE FROM books WHERE id = $1
 `
 
-func (q *Queries) DeleteBook(ctx context.Context, id uuid.UUID) error {
-	_, err := q.db.ExecContext(ctx, deleteBook, id)
-	return err
+func (q *Queries) DeleteBook(ctx context.Context, id uuid.UUID) (int64, error) {
+	result, err := q.db.ExecContext(ctx, deleteBook, id)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
 }
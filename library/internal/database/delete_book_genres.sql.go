@@ -0,0 +1,21 @@
+// Code generated by sqlc. DO NOT EDIT.
+// versions:
+//   sqlc v1.28.0
+// source: delete_book_genres.sql
+
+package database
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+)
+
+const deleteBookGenres = `-- name: DeleteBookGenres :exec
+DELETE FROM book_genres WHERE book_id = $1
+`
+
+func (q *Queries) DeleteBookGenres(ctx context.Context, bookID uuid.UUID) error {
+	_, err := q.db.ExecContext(ctx, deleteBookGenres, bookID)
+	return err
+}
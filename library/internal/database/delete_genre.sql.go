@@ -0,0 +1,21 @@
+// Code generated by sqlc. DO NOT EDIT.
+// versions:
+//   sqlc v1.28.0
+// source: delete_genre.sql
+
+package database
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+)
+
+const deleteGenre = `-- name: DeleteGenre :exec
+DELETE FROM genres WHERE id = $1
+`
+
+func (q *Queries) DeleteGenre(ctx context.Context, id uuid.UUID) error {
+	_, err := q.db.ExecContext(ctx, deleteGenre, id)
+	return err
+}
@@ -13,7 +13,7 @@ import (
 )
 
 const getAuthor = `-- name: GetAuthor :one
-SELECT full_name, birth_date, death_date FROM authors
+SELECT full_name, birth_date, death_date, language FROM authors
 WHERE id = $1
 `
 
@@ -21,11 +21,17 @@ type GetAuthorRow struct {
 	FullName  string
 	BirthDate sql.NullTime
 	DeathDate sql.NullTime
+	Language  sql.NullString
 }
 
 func (q *Queries) GetAuthor(ctx context.Context, id uuid.UUID) (GetAuthorRow, error) {
 	row := q.db.QueryRowContext(ctx, getAuthor, id)
 	var i GetAuthorRow
-	err := row.Scan(&i.FullName, &i.BirthDate, &i.DeathDate)
+	err := row.Scan(
+		&i.FullName,
+		&i.BirthDate,
+		&i.DeathDate,
+		&i.Language,
+	)
 	return i, err
 }
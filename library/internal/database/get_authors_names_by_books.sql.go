@@ -1,47 +0,0 @@
-// Code generated by sqlc. DO NOT EDIT.
-// versions:
-//   sqlc v1.28.0
-// source: get_authors_names_by_books.sql
-
-package database
-
-import (
-	"context"
-
-	"github.com/google/uuid"
-	"github.com/lib/pq"
-)
-
-const getAuthorsNamesByBooks = `-- name: GetAuthorsNamesByBooks :many
-SELECT ba.book_id, a.full_name FROM book_authors ba
-JOIN authors a ON ba.author_id = a.id
-WHERE book_id IN (SELECT UNNEST($1::UUID[]))
-`
-
-type GetAuthorsNamesByBooksRow struct {
-	BookID   uuid.UUID
-	FullName string
-}
-
-func (q *Queries) GetAuthorsNamesByBooks(ctx context.Context, dollar_1 []uuid.UUID) ([]GetAuthorsNamesByBooksRow, error) {
-	rows, err := q.db.QueryContext(ctx, getAuthorsNamesByBooks, pq.Array(dollar_1))
-	if err != nil {
-		return nil, err
-	}
-	defer rows.Close()
-	var items []GetAuthorsNamesByBooksRow
-	for rows.Next() {
-		var i GetAuthorsNamesByBooksRow
-		if err := rows.Scan(&i.BookID, &i.FullName); err != nil {
-			return nil, err
-		}
-		items = append(items, i)
-	}
-	if err := rows.Close(); err != nil {
-		return nil, err
-	}
-	if err := rows.Err(); err != nil {
-		return nil, err
-	}
-	return items, nil
-}
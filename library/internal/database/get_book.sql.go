@@ -1,24 +0,0 @@
-// Code generated by sqlc. DO NOT EDIT.
-// versions:
-//   sqlc v1.28.0
-// source: get_book.sql
-
-package database
-
-import (
-	"context"
-
-	"github.com/google/uuid"
-)
-
-const getBook = `-- name: GetBook :one
-SELECT title FROM books
-WHERE id = $1
-`
-
-func (q *Queries) GetBook(ctx context.Context, id uuid.UUID) (string, error) {
-	row := q.db.QueryRowContext(ctx, getBook, id)
-	var title string
-	err := row.Scan(&title)
-	return title, err
-}
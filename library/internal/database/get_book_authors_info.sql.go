@@ -0,0 +1,47 @@
+// Code generated by sqlc. DO NOT EDIT.
+// versions:
+//   sqlc v1.28.0
+// source: get_book_authors_info.sql
+
+package database
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+)
+
+const getBookAuthorsInfo = `-- name: GetBookAuthorsInfo :many
+SELECT a.id, a.full_name FROM book_authors ba
+JOIN authors a ON ba.author_id = a.id
+WHERE ba.book_id = $1
+ORDER BY a.full_name
+`
+
+type GetBookAuthorsInfoRow struct {
+	ID       uuid.UUID
+	FullName string
+}
+
+func (q *Queries) GetBookAuthorsInfo(ctx context.Context, bookID uuid.UUID) ([]GetBookAuthorsInfoRow, error) {
+	rows, err := q.db.QueryContext(ctx, getBookAuthorsInfo, bookID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []GetBookAuthorsInfoRow
+	for rows.Next() {
+		var i GetBookAuthorsInfoRow
+		if err := rows.Scan(&i.ID, &i.FullName); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
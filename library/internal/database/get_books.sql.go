@@ -7,19 +7,25 @@ package database
 
 import (
 	"context"
+	"database/sql"
 
 	"github.com/google/uuid"
 	"github.com/lib/pq"
 )
 
 const getBooks = `-- name: GetBooks :many
-SELECT id, title FROM books
+SELECT id, title, isbn, publication_year, language, page_count, description FROM books
 WHERE id IN (SELECT UNNEST($1::UUID[]))
 `
 
 type GetBooksRow struct {
-	ID    uuid.UUID
-	Title string
+	ID              uuid.UUID
+	Title           string
+	Isbn            sql.NullString
+	PublicationYear sql.NullInt32
+	Language        sql.NullString
+	PageCount       sql.NullInt32
+	Description     sql.NullString
 }
 
 func (q *Queries) GetBooks(ctx context.Context, dollar_1 []uuid.UUID) ([]GetBooksRow, error) {
@@ -31,7 +37,15 @@ func (q *Queries) GetBooks(ctx context.Context, dollar_1 []uuid.UUID) ([]GetBook
 	var items []GetBooksRow
 	for rows.Next() {
 		var i GetBooksRow
-		if err := rows.Scan(&i.ID, &i.Title); err != nil {
+		if err := rows.Scan(
+			&i.ID,
+			&i.Title,
+			&i.Isbn,
+			&i.PublicationYear,
+			&i.Language,
+			&i.PageCount,
+			&i.Description,
+		); err != nil {
 			return nil, err
 		}
 		items = append(items, i)
@@ -0,0 +1,45 @@
+// Code generated by sqlc. DO NOT EDIT.
+// versions:
+//   sqlc v1.28.0
+// source: get_genres.sql
+
+package database
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+)
+
+const getGenres = `-- name: GetGenres :many
+SELECT id, name FROM genres
+ORDER BY name
+`
+
+type GetGenresRow struct {
+	ID   uuid.UUID
+	Name string
+}
+
+func (q *Queries) GetGenres(ctx context.Context) ([]GetGenresRow, error) {
+	rows, err := q.db.QueryContext(ctx, getGenres)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []GetGenresRow
+	for rows.Next() {
+		var i GetGenresRow
+		if err := rows.Scan(&i.ID, &i.Name); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
@@ -0,0 +1,49 @@
+// Code generated by sqlc. DO NOT EDIT.
+// versions:
+//   sqlc v1.28.0
+// source: get_genres_by_books.sql
+
+package database
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+)
+
+const getGenresByBooks = `-- name: GetGenresByBooks :many
+SELECT bg.book_id, g.id, g.name FROM book_genres bg
+JOIN genres g ON bg.genre_id = g.id
+WHERE bg.book_id IN (SELECT UNNEST($1::UUID[]))
+ORDER BY g.name
+`
+
+type GetGenresByBooksRow struct {
+	BookID uuid.UUID
+	ID     uuid.UUID
+	Name   string
+}
+
+func (q *Queries) GetGenresByBooks(ctx context.Context, dollar_1 []uuid.UUID) ([]GetGenresByBooksRow, error) {
+	rows, err := q.db.QueryContext(ctx, getGenresByBooks, pq.Array(dollar_1))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []GetGenresByBooksRow
+	for rows.Next() {
+		var i GetGenresByBooksRow
+		if err := rows.Scan(&i.BookID, &i.ID, &i.Name); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
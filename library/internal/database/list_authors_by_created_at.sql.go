@@ -0,0 +1,63 @@
+// Code generated by sqlc. DO NOT EDIT.
+// versions:
+//   sqlc v1.28.0
+// source: list_authors_by_created_at.sql
+
+package database
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+const listAuthorsByCreatedAt = `-- name: ListAuthorsByCreatedAt :many
+SELECT id, full_name, created_at FROM authors
+WHERE ($1::TIMESTAMP IS NULL OR created_at >= $1::TIMESTAMP)
+    AND ($2::UUID IS NULL OR (created_at, id) < ($3::TIMESTAMP, $2::UUID))
+ORDER BY created_at DESC, id DESC
+LIMIT $4
+`
+
+type ListAuthorsByCreatedAtParams struct {
+	CreatedAfter    sql.NullTime
+	CursorID        uuid.NullUUID
+	CursorCreatedAt sql.NullTime
+	Limit           int32
+}
+
+type ListAuthorsByCreatedAtRow struct {
+	ID        uuid.UUID
+	FullName  string
+	CreatedAt time.Time
+}
+
+func (q *Queries) ListAuthorsByCreatedAt(ctx context.Context, arg ListAuthorsByCreatedAtParams) ([]ListAuthorsByCreatedAtRow, error) {
+	rows, err := q.db.QueryContext(ctx, listAuthorsByCreatedAt,
+		arg.CreatedAfter,
+		arg.CursorID,
+		arg.CursorCreatedAt,
+		arg.Limit,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []ListAuthorsByCreatedAtRow
+	for rows.Next() {
+		var i ListAuthorsByCreatedAtRow
+		if err := rows.Scan(&i.ID, &i.FullName, &i.CreatedAt); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
@@ -0,0 +1,63 @@
+// Code generated by sqlc. DO NOT EDIT.
+// versions:
+//   sqlc v1.28.0
+// source: list_authors_by_name.sql
+
+package database
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+const listAuthorsByName = `-- name: ListAuthorsByName :many
+SELECT id, full_name, created_at FROM authors
+WHERE ($1::TIMESTAMP IS NULL OR created_at >= $1::TIMESTAMP)
+    AND ($2::UUID IS NULL OR (full_name, id) > ($3::TEXT, $2::UUID))
+ORDER BY full_name, id
+LIMIT $4
+`
+
+type ListAuthorsByNameParams struct {
+	CreatedAfter   sql.NullTime
+	CursorID       uuid.NullUUID
+	CursorFullName sql.NullString
+	Limit          int32
+}
+
+type ListAuthorsByNameRow struct {
+	ID        uuid.UUID
+	FullName  string
+	CreatedAt time.Time
+}
+
+func (q *Queries) ListAuthorsByName(ctx context.Context, arg ListAuthorsByNameParams) ([]ListAuthorsByNameRow, error) {
+	rows, err := q.db.QueryContext(ctx, listAuthorsByName,
+		arg.CreatedAfter,
+		arg.CursorID,
+		arg.CursorFullName,
+		arg.Limit,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []ListAuthorsByNameRow
+	for rows.Next() {
+		var i ListAuthorsByNameRow
+		if err := rows.Scan(&i.ID, &i.FullName, &i.CreatedAt); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
@@ -0,0 +1,66 @@
+// Code generated by sqlc. DO NOT EDIT.
+// versions:
+//   sqlc v1.28.0
+// source: list_books_by_created_at.sql
+
+package database
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+const listBooksByCreatedAt = `-- name: ListBooksByCreatedAt :many
+SELECT b.id, b.title, b.created_at FROM books b
+WHERE ($1::UUID IS NULL OR b.id IN (SELECT ba.book_id FROM book_authors ba WHERE ba.author_id = $1::UUID))
+    AND ($2::TIMESTAMP IS NULL OR b.created_at >= $2::TIMESTAMP)
+    AND ($3::UUID IS NULL OR (b.created_at, b.id) < ($4::TIMESTAMP, $3::UUID))
+ORDER BY b.created_at DESC, b.id DESC
+LIMIT $5
+`
+
+type ListBooksByCreatedAtParams struct {
+	AuthorID        uuid.NullUUID
+	CreatedAfter    sql.NullTime
+	CursorID        uuid.NullUUID
+	CursorCreatedAt sql.NullTime
+	Limit           int32
+}
+
+type ListBooksByCreatedAtRow struct {
+	ID        uuid.UUID
+	Title     string
+	CreatedAt time.Time
+}
+
+func (q *Queries) ListBooksByCreatedAt(ctx context.Context, arg ListBooksByCreatedAtParams) ([]ListBooksByCreatedAtRow, error) {
+	rows, err := q.db.QueryContext(ctx, listBooksByCreatedAt,
+		arg.AuthorID,
+		arg.CreatedAfter,
+		arg.CursorID,
+		arg.CursorCreatedAt,
+		arg.Limit,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []ListBooksByCreatedAtRow
+	for rows.Next() {
+		var i ListBooksByCreatedAtRow
+		if err := rows.Scan(&i.ID, &i.Title, &i.CreatedAt); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
@@ -0,0 +1,66 @@
+// Code generated by sqlc. DO NOT EDIT.
+// versions:
+//   sqlc v1.28.0
+// source: list_books_by_title.sql
+
+package database
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+const listBooksByTitle = `-- name: ListBooksByTitle :many
+SELECT b.id, b.title, b.created_at FROM books b
+WHERE ($1::UUID IS NULL OR b.id IN (SELECT ba.book_id FROM book_authors ba WHERE ba.author_id = $1::UUID))
+    AND ($2::TIMESTAMP IS NULL OR b.created_at >= $2::TIMESTAMP)
+    AND ($3::UUID IS NULL OR (b.title, b.id) > ($4::TEXT, $3::UUID))
+ORDER BY b.title, b.id
+LIMIT $5
+`
+
+type ListBooksByTitleParams struct {
+	AuthorID     uuid.NullUUID
+	CreatedAfter sql.NullTime
+	CursorID     uuid.NullUUID
+	CursorTitle  sql.NullString
+	Limit        int32
+}
+
+type ListBooksByTitleRow struct {
+	ID        uuid.UUID
+	Title     string
+	CreatedAt time.Time
+}
+
+func (q *Queries) ListBooksByTitle(ctx context.Context, arg ListBooksByTitleParams) ([]ListBooksByTitleRow, error) {
+	rows, err := q.db.QueryContext(ctx, listBooksByTitle,
+		arg.AuthorID,
+		arg.CreatedAfter,
+		arg.CursorID,
+		arg.CursorTitle,
+		arg.Limit,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []ListBooksByTitleRow
+	for rows.Next() {
+		var i ListBooksByTitleRow
+		if err := rows.Scan(&i.ID, &i.Title, &i.CreatedAt); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
@@ -19,14 +19,20 @@ type Author struct {
 	CreatedAt time.Time
 	UpdatedAt time.Time
 	Tsv       interface{}
+	Language  sql.NullString
 }
 
 type Book struct {
-	ID        uuid.UUID
-	Title     string
-	CreatedAt time.Time
-	UpdatedAt time.Time
-	Tsv       interface{}
+	ID              uuid.UUID
+	Title           string
+	CreatedAt       time.Time
+	UpdatedAt       time.Time
+	Tsv             interface{}
+	Isbn            sql.NullString
+	PublicationYear sql.NullInt32
+	Language        sql.NullString
+	PageCount       sql.NullInt32
+	Description     sql.NullString
 }
 
 type BookAuthor struct {
@@ -35,3 +41,27 @@ type BookAuthor struct {
 	CreatedAt time.Time
 	UpdatedAt time.Time
 }
+
+type BookGenre struct {
+	BookID    uuid.UUID
+	GenreID   uuid.UUID
+	CreatedAt time.Time
+}
+
+type Genre struct {
+	ID        uuid.UUID
+	Name      string
+	CreatedAt time.Time
+	UpdatedAt time.Time
+}
+
+type Outbox struct {
+	ID         int64
+	Topic      string
+	MessageKey string
+	Payload    []byte
+	Attempts   int32
+	LastError  sql.NullString
+	CreatedAt  time.Time
+	SentAt     sql.NullTime
+}
@@ -7,20 +7,22 @@ package database
 
 import (
 	"context"
+	"database/sql"
 
 	"github.com/google/uuid"
 )
 
 const searchAuthors = `-- name: SearchAuthors :many
-SELECT id, full_name, ts_rank(tsv, plainto_tsquery('english', $1)) AS rank
-FROM authors WHERE tsv @@ plainto_tsquery('english', $1)
+SELECT id, full_name, ts_rank(tsv, plainto_tsquery(text_search_config(coalesce($1::TEXT, language)), $2::TEXT)) AS rank
+FROM authors WHERE tsv @@ plainto_tsquery(text_search_config(coalesce($1::TEXT, language)), $2::TEXT)
 ORDER BY rank DESC
-LIMIT $2
+LIMIT $3
 `
 
 type SearchAuthorsParams struct {
-	PlaintoTsquery string
-	Limit          int32
+	Lang  sql.NullString
+	Query string
+	Limit int32
 }
 
 type SearchAuthorsRow struct {
@@ -30,7 +32,7 @@ type SearchAuthorsRow struct {
 }
 
 func (q *Queries) SearchAuthors(ctx context.Context, arg SearchAuthorsParams) ([]SearchAuthorsRow, error) {
-	rows, err := q.db.QueryContext(ctx, searchAuthors, arg.PlaintoTsquery, arg.Limit)
+	rows, err := q.db.QueryContext(ctx, searchAuthors, arg.Lang, arg.Query, arg.Limit)
 	if err != nil {
 		return nil, err
 	}
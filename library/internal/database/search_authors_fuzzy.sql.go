@@ -0,0 +1,55 @@
+// Code generated by sqlc. DO NOT EDIT.
+// versions:
+//   sqlc v1.28.0
+// source: search_authors_fuzzy.sql
+
+package database
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/google/uuid"
+)
+
+const searchAuthorsFuzzy = `-- name: SearchAuthorsFuzzy :many
+SELECT id, full_name, (ts_rank(tsv, plainto_tsquery(text_search_config(coalesce($1::TEXT, language)), $2::TEXT)) + word_similarity($2::TEXT, full_name))::REAL AS rank
+FROM authors WHERE tsv @@ plainto_tsquery(text_search_config(coalesce($1::TEXT, language)), $2::TEXT) OR $2::TEXT <% full_name
+ORDER BY rank DESC
+LIMIT $3
+`
+
+type SearchAuthorsFuzzyParams struct {
+	Lang  sql.NullString
+	Query string
+	Limit int32
+}
+
+type SearchAuthorsFuzzyRow struct {
+	ID       uuid.UUID
+	FullName string
+	Rank     float32
+}
+
+func (q *Queries) SearchAuthorsFuzzy(ctx context.Context, arg SearchAuthorsFuzzyParams) ([]SearchAuthorsFuzzyRow, error) {
+	rows, err := q.db.QueryContext(ctx, searchAuthorsFuzzy, arg.Lang, arg.Query, arg.Limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []SearchAuthorsFuzzyRow
+	for rows.Next() {
+		var i SearchAuthorsFuzzyRow
+		if err := rows.Scan(&i.ID, &i.FullName, &i.Rank); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
@@ -0,0 +1,55 @@
+// Code generated by sqlc. DO NOT EDIT.
+// versions:
+//   sqlc v1.28.0
+// source: search_authors_prefix.sql
+
+package database
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/google/uuid"
+)
+
+const searchAuthorsPrefix = `-- name: SearchAuthorsPrefix :many
+SELECT id, full_name, ts_rank(tsv, to_tsquery(text_search_config(coalesce($1::TEXT, language)), $2::TEXT)) AS rank
+FROM authors WHERE tsv @@ to_tsquery(text_search_config(coalesce($1::TEXT, language)), $2::TEXT)
+ORDER BY rank DESC
+LIMIT $3
+`
+
+type SearchAuthorsPrefixParams struct {
+	Lang  sql.NullString
+	Query string
+	Limit int32
+}
+
+type SearchAuthorsPrefixRow struct {
+	ID       uuid.UUID
+	FullName string
+	Rank     float32
+}
+
+func (q *Queries) SearchAuthorsPrefix(ctx context.Context, arg SearchAuthorsPrefixParams) ([]SearchAuthorsPrefixRow, error) {
+	rows, err := q.db.QueryContext(ctx, searchAuthorsPrefix, arg.Lang, arg.Query, arg.Limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []SearchAuthorsPrefixRow
+	for rows.Next() {
+		var i SearchAuthorsPrefixRow
+		if err := rows.Scan(&i.ID, &i.FullName, &i.Rank); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
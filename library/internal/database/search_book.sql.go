@@ -7,30 +7,34 @@ package database
 
 import (
 	"context"
+	"database/sql"
 
 	"github.com/google/uuid"
 )
 
 const searchBooks = `-- name: SearchBooks :many
-SELECT id, title, ts_rank(tsv, plainto_tsquery('english', $1)) AS rank
-FROM books WHERE tsv @@ plainto_tsquery('english', $1)
+SELECT id, title, ts_rank(tsv, plainto_tsquery(text_search_config(coalesce($1::TEXT, language)), $2::TEXT)) AS rank,
+    ts_headline(text_search_config(coalesce($1::TEXT, language)), concat_ws('. ', title, book_authors_text(id), description), plainto_tsquery(text_search_config(coalesce($1::TEXT, language)), $2::TEXT))::TEXT AS headline
+FROM books WHERE tsv @@ plainto_tsquery(text_search_config(coalesce($1::TEXT, language)), $2::TEXT)
 ORDER BY rank DESC
-LIMIT $2
+LIMIT $3
 `
 
 type SearchBooksParams struct {
-	PlaintoTsquery string
-	Limit          int32
+	Lang  sql.NullString
+	Query string
+	Limit int32
 }
 
 type SearchBooksRow struct {
-	ID    uuid.UUID
-	Title string
-	Rank  float32
+	ID       uuid.UUID
+	Title    string
+	Rank     float32
+	Headline string
 }
 
 func (q *Queries) SearchBooks(ctx context.Context, arg SearchBooksParams) ([]SearchBooksRow, error) {
-	rows, err := q.db.QueryContext(ctx, searchBooks, arg.PlaintoTsquery, arg.Limit)
+	rows, err := q.db.QueryContext(ctx, searchBooks, arg.Lang, arg.Query, arg.Limit)
 	if err != nil {
 		return nil, err
 	}
@@ -38,7 +42,12 @@ func (q *Queries) SearchBooks(ctx context.Context, arg SearchBooksParams) ([]Sea
 	var items []SearchBooksRow
 	for rows.Next() {
 		var i SearchBooksRow
-		if err := rows.Scan(&i.ID, &i.Title, &i.Rank); err != nil {
+		if err := rows.Scan(
+			&i.ID,
+			&i.Title,
+			&i.Rank,
+			&i.Headline,
+		); err != nil {
 			return nil, err
 		}
 		items = append(items, i)
@@ -0,0 +1,62 @@
+// Code generated by sqlc. DO NOT EDIT.
+// versions:
+//   sqlc v1.28.0
+// source: search_books_fuzzy.sql
+
+package database
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/google/uuid"
+)
+
+const searchBooksFuzzy = `-- name: SearchBooksFuzzy :many
+SELECT id, title, (ts_rank(tsv, plainto_tsquery(text_search_config(coalesce($1::TEXT, language)), $2::TEXT)) + word_similarity($2::TEXT, title))::REAL AS rank,
+    ts_headline(text_search_config(coalesce($1::TEXT, language)), concat_ws('. ', title, book_authors_text(id), description), plainto_tsquery(text_search_config(coalesce($1::TEXT, language)), $2::TEXT))::TEXT AS headline
+FROM books WHERE tsv @@ plainto_tsquery(text_search_config(coalesce($1::TEXT, language)), $2::TEXT) OR $2::TEXT <% title
+ORDER BY rank DESC
+LIMIT $3
+`
+
+type SearchBooksFuzzyParams struct {
+	Lang  sql.NullString
+	Query string
+	Limit int32
+}
+
+type SearchBooksFuzzyRow struct {
+	ID       uuid.UUID
+	Title    string
+	Rank     float32
+	Headline string
+}
+
+func (q *Queries) SearchBooksFuzzy(ctx context.Context, arg SearchBooksFuzzyParams) ([]SearchBooksFuzzyRow, error) {
+	rows, err := q.db.QueryContext(ctx, searchBooksFuzzy, arg.Lang, arg.Query, arg.Limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []SearchBooksFuzzyRow
+	for rows.Next() {
+		var i SearchBooksFuzzyRow
+		if err := rows.Scan(
+			&i.ID,
+			&i.Title,
+			&i.Rank,
+			&i.Headline,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
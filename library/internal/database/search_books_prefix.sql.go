@@ -0,0 +1,62 @@
+// Code generated by sqlc. DO NOT EDIT.
+// versions:
+//   sqlc v1.28.0
+// source: search_books_prefix.sql
+
+package database
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/google/uuid"
+)
+
+const searchBooksPrefix = `-- name: SearchBooksPrefix :many
+SELECT id, title, ts_rank(tsv, to_tsquery(text_search_config(coalesce($1::TEXT, language)), $2::TEXT)) AS rank,
+    ts_headline(text_search_config(coalesce($1::TEXT, language)), concat_ws('. ', title, book_authors_text(id), description), to_tsquery(text_search_config(coalesce($1::TEXT, language)), $2::TEXT))::TEXT AS headline
+FROM books WHERE tsv @@ to_tsquery(text_search_config(coalesce($1::TEXT, language)), $2::TEXT)
+ORDER BY rank DESC
+LIMIT $3
+`
+
+type SearchBooksPrefixParams struct {
+	Lang  sql.NullString
+	Query string
+	Limit int32
+}
+
+type SearchBooksPrefixRow struct {
+	ID       uuid.UUID
+	Title    string
+	Rank     float32
+	Headline string
+}
+
+func (q *Queries) SearchBooksPrefix(ctx context.Context, arg SearchBooksPrefixParams) ([]SearchBooksPrefixRow, error) {
+	rows, err := q.db.QueryContext(ctx, searchBooksPrefix, arg.Lang, arg.Query, arg.Limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []SearchBooksPrefixRow
+	for rows.Next() {
+		var i SearchBooksPrefixRow
+		if err := rows.Scan(
+			&i.ID,
+			&i.Title,
+			&i.Rank,
+			&i.Headline,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
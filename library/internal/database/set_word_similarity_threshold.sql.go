@@ -0,0 +1,19 @@
+// Code generated by sqlc. DO NOT EDIT.
+// versions:
+//   sqlc v1.28.0
+// source: set_word_similarity_threshold.sql
+
+package database
+
+import (
+	"context"
+)
+
+const setWordSimilarityThreshold = `-- name: SetWordSimilarityThreshold :exec
+SELECT set_config('pg_trgm.word_similarity_threshold', $1::TEXT, true)
+`
+
+func (q *Queries) SetWordSimilarityThreshold(ctx context.Context, threshold string) error {
+	_, err := q.db.ExecContext(ctx, setWordSimilarityThreshold, threshold)
+	return err
+}
@@ -17,6 +17,7 @@ UPDATE authors SET
     full_name = $2,
     birth_date = $3,
     death_date = $4,
+    language = $5,
     updated_at = NOW()
 WHERE id = $1
 `
@@ -26,6 +27,7 @@ type UpdateAuthorParams struct {
 	FullName  string
 	BirthDate sql.NullTime
 	DeathDate sql.NullTime
+	Language  sql.NullString
 }
 
 func (q *Queries) UpdateAuthor(ctx context.Context, arg UpdateAuthorParams) (int64, error) {
@@ -34,6 +36,7 @@ func (q *Queries) UpdateAuthor(ctx context.Context, arg UpdateAuthorParams) (int
 		arg.FullName,
 		arg.BirthDate,
 		arg.DeathDate,
+		arg.Language,
 	)
 	if err != nil {
 		return 0, err
@@ -7,23 +7,44 @@ package database
 
 import (
 	"context"
+	"database/sql"
 
 	"github.com/google/uuid"
 )
 
 const updateBook = `-- name: UpdateBook :one
-UPDATE books SET title = $2, updated_at = NOW()
+UPDATE books SET
+    title = $2,
+    isbn = $3,
+    publication_year = $4,
+    language = $5,
+    page_count = $6,
+    description = $7,
+    updated_at = NOW()
 WHERE id = $1
 RETURNING 1
 `
 
 type UpdateBookParams struct {
-	ID    uuid.UUID
-	Title string
+	ID              uuid.UUID
+	Title           string
+	Isbn            sql.NullString
+	PublicationYear sql.NullInt32
+	Language        sql.NullString
+	PageCount       sql.NullInt32
+	Description     sql.NullString
 }
 
 func (q *Queries) UpdateBook(ctx context.Context, arg UpdateBookParams) (int32, error) {
-	row := q.db.QueryRowContext(ctx, updateBook, arg.ID, arg.Title)
+	row := q.db.QueryRowContext(ctx, updateBook,
+		arg.ID,
+		arg.Title,
+		arg.Isbn,
+		arg.PublicationYear,
+		arg.Language,
+		arg.PageCount,
+		arg.Description,
+	)
 	var column_1 int32
 	err := row.Scan(&column_1)
 	return column_1, err
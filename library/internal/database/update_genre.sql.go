@@ -0,0 +1,30 @@
+// Code generated by sqlc. DO NOT EDIT.
+// versions:
+//   sqlc v1.28.0
+// source: update_genre.sql
+
+package database
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+)
+
+const updateGenre = `-- name: UpdateGenre :execrows
+UPDATE genres SET name = $2, updated_at = NOW()
+WHERE id = $1
+`
+
+type UpdateGenreParams struct {
+	ID   uuid.UUID
+	Name string
+}
+
+func (q *Queries) UpdateGenre(ctx context.Context, arg UpdateGenreParams) (int64, error) {
+	result, err := q.db.ExecContext(ctx, updateGenre, arg.ID, arg.Name)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
@@ -1,19 +1,31 @@
 package server
 
 import (
+	"context"
 	"database/sql"
 	"encoding/json"
-	"log"
+	"errors"
 	"net/http"
 	"sort"
 
 	"github.com/bakurvik/mylib/library/internal/database"
-	"github.com/segmentio/kafka-go"
 
 	common "github.com/bakurvik/mylib-common"
 	"github.com/google/uuid"
 )
 
+func getAuthorBookIDs(ctx context.Context, queries *database.Queries, authorID uuid.UUID) ([]uuid.UUID, error) {
+	books, err := queries.GetBooksByAuthor(ctx, authorID)
+	if err != nil {
+		return nil, err
+	}
+	bookIDs := make([]uuid.UUID, 0, len(books))
+	for _, book := range books {
+		bookIDs = append(bookIDs, book.ID)
+	}
+	return bookIDs, nil
+}
+
 // @Summary Ping the server
 // @Description  Checks server health. Returns 200 OK if server is up.
 // @Tags Health
@@ -32,8 +44,11 @@ func (cfg *ApiConfig) HandlePing(w http.ResponseWriter, r *http.Request) {
 // @Produce json
 // @Param request body RequestAuthor true "Author's info"
 // @Success 201 {string} string "Created successfully"
-// @Failure 400 {object} ErrorResponse "Invalid request body or empty full_name"
+// @Failure 400 {object} ErrorResponse "Invalid request body, empty full_name or invalid language"
+// @Failure 401 {object} ErrorResponse "Unauthorized"
+// @Failure 403 {object} ErrorResponse "Forbidden"
 // @Failure 500 {object} ErrorResponse
+// @Security BearerAuth
 // @Router /api/authors [post]
 func (cfg *ApiConfig) HandlePostApiAuthors(w http.ResponseWriter, r *http.Request) {
 	decoder := json.NewDecoder(r.Body)
@@ -43,48 +58,76 @@ func (cfg *ApiConfig) HandlePostApiAuthors(w http.ResponseWriter, r *http.Reques
 		common.RespondWithError(w, http.StatusBadRequest, "Invalid request")
 		return
 	}
+	language, err := parseLanguage(request.Language)
+	if err != nil {
+		common.RespondWithError(w, http.StatusBadRequest, err.Error())
+		return
+	}
 
 	if cfg.DB == nil {
 		common.RespondWithError(w, http.StatusInternalServerError, "DB error")
 		return
 	}
 
-	queries := database.New(cfg.DB)
-	authorID, dbErr := queries.CreateAuthor(
+	tx, err := cfg.DB.BeginTx(r.Context(), nil)
+	if err != nil {
+		common.RespondWithError(w, http.StatusInternalServerError, "DB error")
+		return
+	}
+	defer handleTx(tx, &err, w, nil)
+
+	queries := database.New(tx)
+	authorID, err := queries.CreateAuthor(
 		r.Context(),
 		database.CreateAuthorParams{
 			FullName:  request.FullName,
 			BirthDate: common.ToNullTime(request.BirthDate),
-			DeathDate: common.ToNullTime(request.DeathDate)})
-	if dbErr != nil {
-		common.RespondWithError(w, http.StatusInternalServerError, dbErr.Error())
+			DeathDate: common.ToNullTime(request.DeathDate),
+			Language:  language})
+	if err != nil {
 		return
 	}
-	w.WriteHeader(http.StatusCreated)
-
-	authorMessageData, err := json.Marshal(common.AuthorMessage{ID: authorID.String(), FullName: request.FullName, Action: "created"})
+	err = addAuthorMessage(r.Context(), queries, common.AuthorMessage{ID: authorID.String(), FullName: request.FullName, Action: actionCreated})
 	if err != nil {
-		log.Print("Failed to build author message: ", err)
 		return
 	}
-	message := kafka.Message{
-		Key:   []byte(authorID.String()),
-		Value: []byte(authorMessageData),
-	}
+	w.WriteHeader(http.StatusCreated)
+}
 
-	err = cfg.AuthorsKafkaWriter.WriteMessages(r.Context(), message)
-	if err != nil {
-		log.Print("Failed to send author message: ", err)
-		return
+func listAuthors(ctx context.Context, queries *database.Queries, params pageParams) ([]database.ListAuthorsByNameRow, error) {
+	if params.sort == sortCreatedAt {
+		rows, err := queries.ListAuthorsByCreatedAt(ctx, database.ListAuthorsByCreatedAtParams{
+			CreatedAfter:    params.createdAfter,
+			CursorID:        params.cursorID(),
+			CursorCreatedAt: params.cursorTime(),
+			Limit:           params.queryLimit()})
+		if err != nil {
+			return nil, err
+		}
+		authors := make([]database.ListAuthorsByNameRow, 0, len(rows))
+		for _, row := range rows {
+			authors = append(authors, database.ListAuthorsByNameRow(row))
+		}
+		return authors, nil
 	}
+	return queries.ListAuthorsByName(ctx, database.ListAuthorsByNameParams{
+		CreatedAfter:   params.createdAfter,
+		CursorID:       params.cursorID(),
+		CursorFullName: params.cursorName(),
+		Limit:          params.queryLimit()})
 }
 
 // @Summary Get authors
-// @Description Gets all authors from DB
+// @Description Gets a page of authors from DB. Authors are sorted by full name or by creation time (newest first)
 // @Tags Authors
 // @Accept json
 // @Produce json
-// @Success 200 {array} ResponseAuthorShortInfo "Author's short info"
+// @Param limit query int false "Page size"
+// @Param cursor query string false "Cursor returned with the previous page"
+// @Param sort query string false "Sort field: full_name (default) or created_at"
+// @Param created_after query string false "Returns only authors created on or after the date (DD.MM.YYYY)"
+// @Success 200 {object} ResponseAuthorsPage "Page of authors' short info"
+// @Failure 400 {object} ErrorResponse "Invalid query parameters"
 // @Failure 500 {object} ErrorResponse
 // @Router /api/authors [get]
 func (cfg *ApiConfig) HandleGetApiAuthors(w http.ResponseWriter, r *http.Request) {
@@ -93,20 +136,30 @@ func (cfg *ApiConfig) HandleGetApiAuthors(w http.ResponseWriter, r *http.Request
 		return
 	}
 
+	params, err := parsePageParams(r, []string{sortFullName, sortCreatedAt}, cfg.MaxPageLimit)
+	if err != nil {
+		common.RespondWithError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
 	queries := database.New(cfg.DB)
-	authors, dbErr := queries.GetAuthors(r.Context())
+	authors, dbErr := listAuthors(r.Context(), queries, params)
 	if dbErr != nil {
 		common.RespondWithError(w, http.StatusInternalServerError, dbErr.Error())
 		return
 	}
 
-	sort.Slice(authors, func(i, j int) bool { return authors[i].FullName < authors[j].FullName })
-
-	responseAuthors := make([]ResponseAuthorShortInfo, 0, len(authors))
+	response := ResponseAuthorsPage{}
+	if len(authors) > params.limit {
+		last := authors[params.limit-1]
+		response.NextCursor = params.nextCursor(last.ID, last.FullName, last.CreatedAt)
+		authors = authors[:params.limit]
+	}
+	response.Items = make([]ResponseAuthorShortInfo, 0, len(authors))
 	for _, author := range authors {
-		responseAuthors = append(responseAuthors, ResponseAuthorShortInfo{FullName: author.FullName, ID: author.ID.String()})
+		response.Items = append(response.Items, ResponseAuthorShortInfo{FullName: author.FullName, ID: author.ID.String()})
 	}
-	common.RespondWithJSON(w, http.StatusOK, responseAuthors, nil)
+	common.RespondWithJSON(w, http.StatusOK, response, nil)
 }
 
 // @Summary Get author
@@ -142,7 +195,7 @@ func (cfg *ApiConfig) HandleGetApiAuthorsID(w http.ResponseWriter, r *http.Reque
 		return
 	}
 
-	common.RespondWithJSON(w, http.StatusOK, ResponseAuthorFullInfo{FullName: author.FullName, BirthDate: common.NullTimeToString(author.BirthDate), DeathDate: common.NullTimeToString(author.DeathDate)}, nil)
+	common.RespondWithJSON(w, http.StatusOK, ResponseAuthorFullInfo{FullName: author.FullName, BirthDate: common.NullTimeToString(author.BirthDate), DeathDate: common.NullTimeToString(author.DeathDate), Language: author.Language.String}, nil)
 }
 
 // @Summary Delete author
@@ -153,7 +206,10 @@ func (cfg *ApiConfig) HandleGetApiAuthorsID(w http.ResponseWriter, r *http.Reque
 // @Param id path string true "Author ID"
 // @Success 200 {string} string "Deleted successfully"
 // @Failure 400 {object} ErrorResponse "Invalid author ID"
+// @Failure 401 {object} ErrorResponse "Unauthorized"
+// @Failure 403 {object} ErrorResponse "Forbidden"
 // @Failure 500 {object} ErrorResponse
+// @Security BearerAuth
 // @Router /admin/authors/{id} [delete]
 func (cfg *ApiConfig) HandleDeleteAdminAuthors(w http.ResponseWriter, r *http.Request) {
 	uuid, err := uuid.Parse(r.PathValue("id"))
@@ -167,10 +223,28 @@ func (cfg *ApiConfig) HandleDeleteAdminAuthors(w http.ResponseWriter, r *http.Re
 		return
 	}
 
-	queries := database.New(cfg.DB)
-	dbErr := queries.DeleteAuthor(r.Context(), uuid)
-	if dbErr != nil {
-		common.RespondWithError(w, http.StatusInternalServerError, dbErr.Error())
+	tx, err := cfg.DB.BeginTx(r.Context(), nil)
+	if err != nil {
+		common.RespondWithError(w, http.StatusInternalServerError, "DB error")
+		return
+	}
+	defer handleTx(tx, &err, w, nil)
+
+	queries := database.New(tx)
+	bookIDs, err := getAuthorBookIDs(r.Context(), queries, uuid)
+	if err != nil {
+		return
+	}
+	err = queries.DeleteAuthor(r.Context(), uuid)
+	if err != nil {
+		return
+	}
+	err = addAuthorMessage(r.Context(), queries, common.AuthorMessage{ID: uuid.String(), Action: actionDeleted})
+	if err != nil {
+		return
+	}
+	err = addBooksUpdatedMessages(r.Context(), queries, bookIDs)
+	if err != nil {
 		return
 	}
 
@@ -184,9 +258,12 @@ func (cfg *ApiConfig) HandleDeleteAdminAuthors(w http.ResponseWriter, r *http.Re
 // @Produce json
 // @Param request body RequestAuthorWithID true "Author's info"
 // @Success 200 {string} string "Updated successfully"
-// @Failure 400 {object} ErrorResponse "Invalid request body or empty full_name"
+// @Failure 400 {object} ErrorResponse "Invalid request body, empty full_name or invalid language"
 // @Failure 404 {object} ErrorResponse "Author not found"
+// @Failure 401 {object} ErrorResponse "Unauthorized"
+// @Failure 403 {object} ErrorResponse "Forbidden"
 // @Failure 500 {object} ErrorResponse
+// @Security BearerAuth
 // @Router /api/authors [put]
 func (cfg *ApiConfig) HandlePutApiAuthors(w http.ResponseWriter, r *http.Request) {
 	decoder := json.NewDecoder(r.Body)
@@ -202,26 +279,52 @@ func (cfg *ApiConfig) HandlePutApiAuthors(w http.ResponseWriter, r *http.Request
 		common.RespondWithError(w, http.StatusBadRequest, "Invalid id")
 		return
 	}
+	language, err := parseLanguage(request.Language)
+	if err != nil {
+		common.RespondWithError(w, http.StatusBadRequest, err.Error())
+		return
+	}
 
 	if cfg.DB == nil {
 		common.RespondWithError(w, http.StatusInternalServerError, "DB error")
 		return
 	}
 
-	queries := database.New(cfg.DB)
-	rowsCount, dbErr := queries.UpdateAuthor(
+	tx, err := cfg.DB.BeginTx(r.Context(), nil)
+	if err != nil {
+		common.RespondWithError(w, http.StatusInternalServerError, "DB error")
+		return
+	}
+	responseStatus := http.StatusInternalServerError
+	defer handleTx(tx, &err, w, &responseStatus)
+
+	queries := database.New(tx)
+	rowsCount, err := queries.UpdateAuthor(
 		r.Context(),
 		database.UpdateAuthorParams{
 			ID:        uuid,
 			FullName:  request.FullName,
 			BirthDate: common.ToNullTime(request.BirthDate),
-			DeathDate: common.ToNullTime(request.DeathDate)})
-	if dbErr != nil {
-		common.RespondWithError(w, http.StatusInternalServerError, dbErr.Error())
+			DeathDate: common.ToNullTime(request.DeathDate),
+			Language:  language})
+	if err != nil {
 		return
 	}
 	if rowsCount == 0 {
-		common.RespondWithError(w, http.StatusNotFound, "Author not found")
+		responseStatus = http.StatusNotFound
+		err = errors.New("Author not found")
+		return
+	}
+	err = addAuthorMessage(r.Context(), queries, common.AuthorMessage{ID: uuid.String(), FullName: request.FullName, Action: actionUpdated})
+	if err != nil {
+		return
+	}
+	bookIDs, err := getAuthorBookIDs(r.Context(), queries, uuid)
+	if err != nil {
+		return
+	}
+	err = addBooksUpdatedMessages(r.Context(), queries, bookIDs)
+	if err != nil {
 		return
 	}
 	w.WriteHeader(http.StatusOK)
@@ -275,13 +378,15 @@ func (cfg *ApiConfig) HandleGetApiAuthorsBooks(w http.ResponseWriter, r *http.Re
 }
 
 // @Summary Search authors by name
-// @Description Searches authors by name. Uses postgres full text search
+// @Description Searches authors by name. Uses postgres full text search, fuzzy mode also matches names with typos by trigram similarity, prefix mode matches words by their beginnings
 // @Tags Authors
 // @Accept json
 // @Produce json
 // @Param text query string true "Search text"
+// @Param mode query string false "Search mode: exact (default), fuzzy or prefix"
+// @Param lang query string false "Language of search text, two-letter ISO 639-1 code (language of each book or author by default)"
 // @Success 200 {array} ResponseAuthorShortInfo "Authors' info"
-// @Success 400 {object} ErrorResponse "Empty search text"
+// @Success 400 {object} ErrorResponse "Empty search text, invalid mode or lang"
 // @Failure 500 {object} ErrorResponse
 // @Router /api/authors/search [get]
 func (cfg *ApiConfig) HandleGetApiAuthorsSearch(w http.ResponseWriter, r *http.Request) {
@@ -290,16 +395,22 @@ func (cfg *ApiConfig) HandleGetApiAuthorsSearch(w http.ResponseWriter, r *http.R
 		return
 	}
 
-	searchText := r.URL.Query().Get("text")
-	if searchText == "" {
-		common.RespondWithError(w, http.StatusBadRequest, "Empty search text")
+	params, err := parseSearchParams(r)
+	if err != nil {
+		common.RespondWithError(w, http.StatusBadRequest, err.Error())
 		return
 	}
 
-	queries := database.New(cfg.DB)
-	authors, dbErr := queries.SearchAuthors(r.Context(), database.SearchAuthorsParams{PlaintoTsquery: searchText, Limit: int32(cfg.MaxSearchAuthorsLimit)})
-	if dbErr != nil {
-		common.RespondWithError(w, http.StatusInternalServerError, dbErr.Error())
+	tx, err := cfg.DB.BeginTx(r.Context(), nil)
+	if err != nil {
+		common.RespondWithError(w, http.StatusInternalServerError, "DB error")
+		return
+	}
+	defer handleTx(tx, &err, w, nil)
+
+	queries := database.New(tx)
+	authors, err := cfg.searchAuthors(r.Context(), queries, params)
+	if err != nil {
 		return
 	}
 
@@ -10,7 +10,9 @@ import (
 	"net/http"
 	"sort"
 
+	"github.com/bakurvik/mylib/shared/messages"
 	"github.com/google/uuid"
+	"github.com/lib/pq"
 
 	common "github.com/bakurvik/mylib-common"
 	"github.com/bakurvik/mylib/library/internal/database"
@@ -115,8 +117,12 @@ func updateBookAuthors(queries *database.Queries, w http.ResponseWriter, r *http
 // @Produce json
 // @Param request body RequestBook true "Book's info"
 // @Success 201 {string} string "Created successfully"
-// @Failure 400 {object} ErrorResponse "Invalid request body or empty title"
+// @Failure 400 {object} ErrorResponse "Invalid request body, empty title or invalid metadata"
+// @Failure 409 {object} ErrorResponse "Book with the same ISBN already exists"
+// @Failure 401 {object} ErrorResponse "Unauthorized"
+// @Failure 403 {object} ErrorResponse "Forbidden"
 // @Failure 500 {object} ErrorResponse
+// @Security BearerAuth
 // @Router /api/books [post]
 func (cfg *ApiConfig) HandlePostApiBooks(w http.ResponseWriter, r *http.Request) {
 	decoder := json.NewDecoder(r.Body)
@@ -126,6 +132,11 @@ func (cfg *ApiConfig) HandlePostApiBooks(w http.ResponseWriter, r *http.Request)
 		common.RespondWithError(w, http.StatusBadRequest, "Invalid request")
 		return
 	}
+	metadata, err := parseBookMetadata(request.RequestBookMetadata)
+	if err != nil {
+		common.RespondWithError(w, http.StatusBadRequest, err.Error())
+		return
+	}
 
 	if cfg.DB == nil {
 		common.RespondWithError(w, http.StatusInternalServerError, "DB error")
@@ -137,11 +148,23 @@ func (cfg *ApiConfig) HandlePostApiBooks(w http.ResponseWriter, r *http.Request)
 		common.RespondWithError(w, http.StatusInternalServerError, "DB error")
 		return
 	}
-	defer handleTx(tx, &err, w, nil)
+	responseStatus := http.StatusInternalServerError
+	defer handleTx(tx, &err, w, &responseStatus)
 
 	queries := database.New(tx)
 
-	bookID, err := queries.CreateBook(r.Context(), request.Title)
+	bookID, err := queries.CreateBook(r.Context(), database.CreateBookParams{
+		Title:           request.Title,
+		Isbn:            metadata.isbn,
+		PublicationYear: metadata.publicationYear,
+		Language:        metadata.language,
+		PageCount:       metadata.pageCount,
+		Description:     metadata.description})
+	if isUniqueViolation(err) {
+		responseStatus = http.StatusConflict
+		err = errors.New("Book with this isbn already exists")
+		return
+	}
 	if err != nil {
 		return
 	}
@@ -152,6 +175,20 @@ func (cfg *ApiConfig) HandlePostApiBooks(w http.ResponseWriter, r *http.Request)
 			return
 		}
 	}
+	if len(metadata.genres) > 0 {
+		err = setBookGenres(r.Context(), queries, bookID, metadata.genres)
+		if err != nil {
+			return
+		}
+	}
+	authorNames, err := getBookAuthorNames(r.Context(), queries, bookID)
+	if err != nil {
+		return
+	}
+	err = addBookMessage(r.Context(), queries, messages.BookMessage{ID: bookID.String(), Title: request.Title, Authors: authorNames, Action: actionCreated})
+	if err != nil {
+		return
+	}
 	w.WriteHeader(http.StatusCreated)
 }
 
@@ -162,9 +199,13 @@ func (cfg *ApiConfig) HandlePostApiBooks(w http.ResponseWriter, r *http.Request)
 // @Produce json
 // @Param request body RequestBookWithID true "Book's info"
 // @Success 200 {string} string "Updated successfully"
-// @Failure 400 {object} ErrorResponse "Invalid request body or empty title"
+// @Failure 400 {object} ErrorResponse "Invalid request body, empty title or invalid metadata"
 // @Failure 404 {object} ErrorResponse "Book not found"
+// @Failure 409 {object} ErrorResponse "Book with the same ISBN already exists"
+// @Failure 401 {object} ErrorResponse "Unauthorized"
+// @Failure 403 {object} ErrorResponse "Forbidden"
 // @Failure 500 {object} ErrorResponse
+// @Security BearerAuth
 // @Router /api/books [put]
 func (cfg *ApiConfig) HandlePutApiBooks(w http.ResponseWriter, r *http.Request) {
 	decoder := json.NewDecoder(r.Body)
@@ -174,6 +215,11 @@ func (cfg *ApiConfig) HandlePutApiBooks(w http.ResponseWriter, r *http.Request)
 		common.RespondWithError(w, http.StatusBadRequest, "Invalid request")
 		return
 	}
+	metadata, err := parseBookMetadata(request.RequestBookMetadata)
+	if err != nil {
+		common.RespondWithError(w, http.StatusBadRequest, err.Error())
+		return
+	}
 
 	bookUUID, uuidErr := uuid.Parse(request.ID)
 	if uuidErr != nil {
@@ -196,9 +242,22 @@ func (cfg *ApiConfig) HandlePutApiBooks(w http.ResponseWriter, r *http.Request)
 
 	queries := database.New(tx)
 
-	count, err := queries.UpdateBook(r.Context(), database.UpdateBookParams{ID: bookUUID, Title: request.Title})
-	if count == 0 {
+	_, err = queries.UpdateBook(r.Context(), database.UpdateBookParams{
+		ID:              bookUUID,
+		Title:           request.Title,
+		Isbn:            metadata.isbn,
+		PublicationYear: metadata.publicationYear,
+		Language:        metadata.language,
+		PageCount:       metadata.pageCount,
+		Description:     metadata.description})
+	if isUniqueViolation(err) {
+		responseStatus = http.StatusConflict
+		err = errors.New("Book with this isbn already exists")
+		return
+	}
+	if errors.Is(err, sql.ErrNoRows) {
 		responseStatus = http.StatusNotFound
+		err = errors.New("Book not found")
 		return
 	}
 	if err != nil {
@@ -209,6 +268,18 @@ func (cfg *ApiConfig) HandlePutApiBooks(w http.ResponseWriter, r *http.Request)
 	if err != nil {
 		return
 	}
+	err = setBookGenres(r.Context(), queries, bookUUID, metadata.genres)
+	if err != nil {
+		return
+	}
+	authorNames, err := getBookAuthorNames(r.Context(), queries, bookUUID)
+	if err != nil {
+		return
+	}
+	err = addBookMessage(r.Context(), queries, messages.BookMessage{ID: bookUUID.String(), Title: request.Title, Authors: authorNames, Action: actionUpdated})
+	if err != nil {
+		return
+	}
 	w.WriteHeader(http.StatusOK)
 }
 
@@ -220,7 +291,11 @@ func (cfg *ApiConfig) HandlePutApiBooks(w http.ResponseWriter, r *http.Request)
 // @Param id path string true "Book ID"
 // @Success 200 {string} string "Deleted successfully"
 // @Failure 400 {object} ErrorResponse "Invalid book ID"
+// @Failure 401 {object} ErrorResponse "Unauthorized"
+// @Failure 403 {object} ErrorResponse "Forbidden"
+// @Failure 404 {object} ErrorResponse "Book not found"
 // @Failure 500 {object} ErrorResponse
+// @Security BearerAuth
 // @Router /admin/books/{id} [delete]
 func (cfg *ApiConfig) HandleDeleteAdminBooks(w http.ResponseWriter, r *http.Request) {
 	uuid, err := uuid.Parse(r.PathValue("id"))
@@ -234,14 +309,177 @@ func (cfg *ApiConfig) HandleDeleteAdminBooks(w http.ResponseWriter, r *http.Requ
 		return
 	}
 
+	tx, err := cfg.DB.BeginTx(r.Context(), nil)
+	if err != nil {
+		common.RespondWithError(w, http.StatusInternalServerError, "DB error")
+		return
+	}
+	responseStatus := http.StatusInternalServerError
+	defer handleTx(tx, &err, w, &responseStatus)
+
+	queries := database.New(tx)
+	rowsCount, err := queries.DeleteBook(r.Context(), uuid)
+	if err != nil {
+		return
+	}
+	if rowsCount == 0 {
+		responseStatus = http.StatusNotFound
+		err = errors.New("Book not found")
+		return
+	}
+	err = addBookMessage(r.Context(), queries, messages.BookMessage{ID: uuid.String(), Action: actionDeleted})
+	if err != nil {
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func listBooks(ctx context.Context, queries *database.Queries, params pageParams, authorID uuid.NullUUID) ([]database.ListBooksByTitleRow, error) {
+	if params.sort == sortCreatedAt {
+		rows, err := queries.ListBooksByCreatedAt(ctx, database.ListBooksByCreatedAtParams{
+			AuthorID:        authorID,
+			CreatedAfter:    params.createdAfter,
+			CursorID:        params.cursorID(),
+			CursorCreatedAt: params.cursorTime(),
+			Limit:           params.queryLimit()})
+		if err != nil {
+			return nil, err
+		}
+		books := make([]database.ListBooksByTitleRow, 0, len(rows))
+		for _, row := range rows {
+			books = append(books, database.ListBooksByTitleRow(row))
+		}
+		return books, nil
+	}
+	return queries.ListBooksByTitle(ctx, database.ListBooksByTitleParams{
+		AuthorID:     authorID,
+		CreatedAfter: params.createdAfter,
+		CursorID:     params.cursorID(),
+		CursorTitle:  params.cursorName(),
+		Limit:        params.queryLimit()})
+}
+
+// @Summary Get books
+// @Description Gets a page of books from DB. Books are sorted by title or by creation time (newest first)
+// @Tags Books
+// @Accept json
+// @Produce json
+// @Param limit query int false "Page size"
+// @Param cursor query string false "Cursor returned with the previous page"
+// @Param sort query string false "Sort field: title (default) or created_at"
+// @Param author query string false "Returns only books of the author with this ID"
+// @Param created_after query string false "Returns only books created on or after the date (DD.MM.YYYY)"
+// @Success 200 {object} ResponseBooksPage "Page of books' full info"
+// @Failure 400 {object} ErrorResponse "Invalid query parameters"
+// @Failure 500 {object} ErrorResponse
+// @Router /api/books [get]
+func (cfg *ApiConfig) HandleGetApiBooks(w http.ResponseWriter, r *http.Request) {
+	if cfg.DB == nil {
+		common.RespondWithError(w, http.StatusInternalServerError, "DB error")
+		return
+	}
+
+	params, err := parsePageParams(r, []string{sortTitle, sortCreatedAt}, cfg.MaxPageLimit)
+	if err != nil {
+		common.RespondWithError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	authorID := uuid.NullUUID{}
+	if author := r.URL.Query().Get("author"); author != "" {
+		authorUUID, err := uuid.Parse(author)
+		if err != nil {
+			common.RespondWithError(w, http.StatusBadRequest, "Invalid author")
+			return
+		}
+		authorID = uuid.NullUUID{UUID: authorUUID, Valid: true}
+	}
+
+	queries := database.New(cfg.DB)
+	books, dbErr := listBooks(r.Context(), queries, params, authorID)
+	if dbErr != nil {
+		common.RespondWithError(w, http.StatusInternalServerError, dbErr.Error())
+		return
+	}
+
+	response := ResponseBooksPage{}
+	if len(books) > params.limit {
+		last := books[params.limit-1]
+		response.NextCursor = params.nextCursor(last.ID, last.Title, last.CreatedAt)
+		books = books[:params.limit]
+	}
+
+	bookIDs := make([]uuid.UUID, 0, len(books))
+	for _, book := range books {
+		bookIDs = append(bookIDs, book.ID)
+	}
+	bookToInfo, dbErr := getBooksFullInfo(r.Context(), queries, bookIDs)
+	if dbErr != nil {
+		common.RespondWithError(w, http.StatusInternalServerError, dbErr.Error())
+		return
+	}
+
+	response.Items = make([]ResponseBookFullInfo, 0, len(books))
+	for _, book := range books {
+		if info, ok := bookToInfo[book.ID]; ok {
+			response.Items = append(response.Items, info)
+		}
+	}
+	common.RespondWithJSON(w, http.StatusOK, response, nil)
+}
+
+// @Summary Get book
+// @Description Gets a book with requested ID from DB
+// @Tags Books
+// @Accept json
+// @Produce json
+// @Param id path string true "Book ID"
+// @Success 200 {object} ResponseBookFullInfo "Book's full info"
+// @Failure 400 {object} ErrorResponse "Invalid book ID"
+// @Failure 404 {object} ErrorResponse "Book not found"
+// @Failure 500 {object} ErrorResponse
+// @Router /api/books/{id} [get]
+func (cfg *ApiConfig) HandleGetApiBooksID(w http.ResponseWriter, r *http.Request) {
+	if cfg.DB == nil {
+		common.RespondWithError(w, http.StatusInternalServerError, "DB error")
+		return
+	}
+
+	bookID, err := uuid.Parse(r.PathValue("id"))
+	if err != nil {
+		common.RespondWithError(w, http.StatusBadRequest, "Invalid id")
+		return
+	}
+
 	queries := database.New(cfg.DB)
-	dbErr := queries.DeleteBook(r.Context(), uuid)
+	books, dbErr := queries.GetBooks(r.Context(), []uuid.UUID{bookID})
 	if dbErr != nil {
 		common.RespondWithError(w, http.StatusInternalServerError, dbErr.Error())
 		return
 	}
+	if len(books) == 0 {
+		common.RespondWithError(w, http.StatusNotFound, "Book not found")
+		return
+	}
 
-	w.WriteHeader(http.StatusNoContent)
+	authors, dbErr := queries.GetBookAuthorsInfo(r.Context(), bookID)
+	if dbErr != nil {
+		common.RespondWithError(w, http.StatusInternalServerError, dbErr.Error())
+		return
+	}
+	bookToGenres, dbErr := getBooksGenres(r.Context(), queries, []uuid.UUID{bookID})
+	if dbErr != nil {
+		common.RespondWithError(w, http.StatusInternalServerError, dbErr.Error())
+		return
+	}
+
+	response := makeResponseBookFullInfo(books[0], make([]string, 0, len(authors)), bookToGenres[bookID])
+	response.AuthorsInfo = make([]ResponseAuthorShortInfo, 0, len(authors))
+	for _, author := range authors {
+		response.Authors = append(response.Authors, author.FullName)
+		response.AuthorsInfo = append(response.AuthorsInfo, ResponseAuthorShortInfo{ID: author.ID.String(), FullName: author.FullName})
+	}
+	common.RespondWithJSON(w, http.StatusOK, response, nil)
 }
 
 func parseBookIDs(r *http.Request) ([]uuid.UUID, error) {
@@ -266,11 +504,19 @@ func parseBookIDs(r *http.Request) ([]uuid.UUID, error) {
 	return bookUUIDs, nil
 }
 
+func isUniqueViolation(err error) bool {
+	var pqErr *pq.Error
+	return errors.As(err, &pqErr) && pqErr.Code == "23505"
+}
+
 func handleTx(tx *sql.Tx, err *error, w http.ResponseWriter, responseStatus *int) {
 	if p := recover(); p != nil {
 		if rollbackErr := tx.Rollback(); rollbackErr != nil {
 			log.Print("Failed to rollback transaction ", rollbackErr)
 		}
+		if err != nil {
+			*err = fmt.Errorf("panic recovered in handleTx: %v", p)
+		}
 		common.RespondWithError(w, http.StatusInternalServerError, fmt.Sprintf("panic recovered in handleTx: %v", p))
 		return
 	}
@@ -286,6 +532,9 @@ func handleTx(tx *sql.Tx, err *error, w http.ResponseWriter, responseStatus *int
 		return
 	}
 	if commitErr := tx.Commit(); commitErr != nil {
+		if err != nil {
+			*err = commitErr
+		}
 		common.RespondWithError(w, http.StatusInternalServerError, commitErr.Error())
 		return
 	}
@@ -370,12 +619,14 @@ func (cfg *ApiConfig) HandlePostApiBooksSearch(w http.ResponseWriter, r *http.Re
 	if err != nil {
 		return
 	}
+	bookToGenres, err := getBooksGenres(r.Context(), queries, bookUUIDs)
+	if err != nil {
+		return
+	}
 
 	response := make([]ResponseBookFullInfo, 0, len(books))
 	for _, book := range books {
-		responseBook := ResponseBookFullInfo{ID: book.ID.String(), Title: book.Title}
-		responseBook.Authors = bookToAuthors[book.ID]
-		response = append(response, responseBook)
+		response = append(response, makeResponseBookFullInfo(book, bookToAuthors[book.ID], bookToGenres[book.ID]))
 	}
 
 	sort.Slice(response, func(i, j int) bool { return response[i].Title < response[j].Title })
@@ -383,14 +634,16 @@ func (cfg *ApiConfig) HandlePostApiBooksSearch(w http.ResponseWriter, r *http.Re
 	common.RespondWithJSON(w, http.StatusOK, response, nil)
 }
 
-// @Summary Search books by title
-// @Description Searches books by title. Uses postgres full text search
+// @Summary Search books by title, authors and description
+// @Description Searches books by title (highest weight), author names, description and genres. Uses postgres full text search and returns headline with highlighted matches, fuzzy mode also matches titles with typos by trigram similarity, prefix mode matches words by their beginnings
 // @Tags Books
 // @Accept json
 // @Produce json
 // @Param text query string true "Search text"
+// @Param mode query string false "Search mode: exact (default), fuzzy or prefix"
+// @Param lang query string false "Language of search text, two-letter ISO 639-1 code (language of each book or author by default)"
 // @Success 200 {array} ResponseBookFullInfo "Books' full info"
-// @Success 400 {object} ErrorResponse "Empty search text"
+// @Success 400 {object} ErrorResponse "Empty search text, invalid mode or lang"
 // @Failure 500 {object} ErrorResponse
 // @Router /api/books/search [get]
 func (cfg *ApiConfig) HandleGetApiBooksSearch(w http.ResponseWriter, r *http.Request) {
@@ -399,9 +652,9 @@ func (cfg *ApiConfig) HandleGetApiBooksSearch(w http.ResponseWriter, r *http.Req
 		return
 	}
 
-	searchText := r.URL.Query().Get("text")
-	if searchText == "" {
-		common.RespondWithError(w, http.StatusBadRequest, "Empty search text")
+	params, err := parseSearchParams(r)
+	if err != nil {
+		common.RespondWithError(w, http.StatusBadRequest, err.Error())
 		return
 	}
 
@@ -413,9 +666,8 @@ func (cfg *ApiConfig) HandleGetApiBooksSearch(w http.ResponseWriter, r *http.Req
 	defer handleTx(tx, &err, w, nil)
 
 	queries := database.New(tx)
-	books, dbErr := queries.SearchBooks(r.Context(), database.SearchBooksParams{PlaintoTsquery: searchText, Limit: int32(cfg.MaxSearchBooksLimit)})
-	if dbErr != nil {
-		common.RespondWithError(w, http.StatusInternalServerError, dbErr.Error())
+	books, err := cfg.searchBooks(r.Context(), queries, params)
+	if err != nil {
 		return
 	}
 	bookIDs := make([]uuid.UUID, 0, len(books))
@@ -423,26 +675,17 @@ func (cfg *ApiConfig) HandleGetApiBooksSearch(w http.ResponseWriter, r *http.Req
 		bookIDs = append(bookIDs, book.ID)
 	}
 
-	bookAuthors, dbErr := queries.GetAuthorsNamesByBooks(r.Context(), bookIDs)
-	if dbErr != nil {
-		common.RespondWithError(w, http.StatusInternalServerError, dbErr.Error())
+	bookToInfo, err := getBooksFullInfo(r.Context(), queries, bookIDs)
+	if err != nil {
 		return
 	}
-	bookToAuthors := make(map[uuid.UUID][]string)
-	for _, bookAuthor := range bookAuthors {
-		bookToAuthors[bookAuthor.BookID] = append(bookToAuthors[bookAuthor.BookID], bookAuthor.FullName)
-	}
-	for _, authors := range bookToAuthors {
-		sort.Strings(authors)
-	}
 
 	responseBooks := make([]ResponseBookFullInfo, 0, len(books))
 	for _, book := range books {
-		responseBook := ResponseBookFullInfo{ID: book.ID.String(), Title: book.Title}
-		if authors, ok := bookToAuthors[book.ID]; ok {
-			responseBook.Authors = authors
+		if info, ok := bookToInfo[book.ID]; ok {
+			info.Headline = book.Headline
+			responseBooks = append(responseBooks, info)
 		}
-		responseBooks = append(responseBooks, responseBook)
 	}
 	common.RespondWithJSON(w, http.StatusOK, responseBooks, nil)
 }
@@ -0,0 +1,184 @@
+package server
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/bakurvik/mylib/library/internal/database"
+	"github.com/google/uuid"
+)
+
+type bookMetadata struct {
+	isbn            sql.NullString
+	publicationYear sql.NullInt32
+	language        sql.NullString
+	pageCount       sql.NullInt32
+	description     sql.NullString
+	genres          []uuid.UUID
+}
+
+func isDigits(value string) bool {
+	for _, c := range value {
+		if c < '0' || c > '9' {
+			return false
+		}
+	}
+	return true
+}
+
+func isValidISBN10(isbn string) bool {
+	if len(isbn) != 10 || !isDigits(isbn[:9]) {
+		return false
+	}
+	sum := 0
+	for i := 0; i < 9; i++ {
+		sum += int(isbn[i]-'0') * (10 - i)
+	}
+	switch {
+	case isbn[9] == 'X':
+		sum += 10
+	case isbn[9] >= '0' && isbn[9] <= '9':
+		sum += int(isbn[9] - '0')
+	default:
+		return false
+	}
+	return sum%11 == 0
+}
+
+func isbn13CheckDigit(isbn string) byte {
+	sum := 0
+	for i := 0; i < 12; i++ {
+		digit := int(isbn[i] - '0')
+		if i%2 == 1 {
+			digit *= 3
+		}
+		sum += digit
+	}
+	return byte('0' + (10-sum%10)%10)
+}
+
+func isValidISBN13(isbn string) bool {
+	if len(isbn) != 13 || !isDigits(isbn) {
+		return false
+	}
+	return isbn13CheckDigit(isbn) == isbn[12]
+}
+
+// normalizeISBN removes hyphens and spaces, validates the checksum and converts ISBN-10 to ISBN-13,
+// so that the same book can't be stored twice with different ISBN forms.
+func normalizeISBN(isbn string) (string, error) {
+	isbn = strings.ToUpper(strings.NewReplacer("-", "", " ", "").Replace(isbn))
+	if isValidISBN13(isbn) {
+		return isbn, nil
+	}
+	if isValidISBN10(isbn) {
+		isbn13 := "978" + isbn[:9]
+		return isbn13 + string(isbn13CheckDigit(isbn13)), nil
+	}
+	return "", errors.New("Invalid isbn")
+}
+
+func parseBookMetadata(request RequestBookMetadata) (bookMetadata, error) {
+	metadata := bookMetadata{}
+	if request.Isbn != "" {
+		isbn, err := normalizeISBN(request.Isbn)
+		if err != nil {
+			return bookMetadata{}, err
+		}
+		metadata.isbn = sql.NullString{String: isbn, Valid: true}
+	}
+	if request.PublicationYear != 0 {
+		if request.PublicationYear < 0 || request.PublicationYear > time.Now().Year() {
+			return bookMetadata{}, errors.New("Invalid publication_year")
+		}
+		metadata.publicationYear = sql.NullInt32{Int32: int32(request.PublicationYear), Valid: true}
+	}
+	language, err := parseLanguage(request.Language)
+	if err != nil {
+		return bookMetadata{}, err
+	}
+	metadata.language = language
+	if request.PageCount != 0 {
+		if request.PageCount < 0 {
+			return bookMetadata{}, errors.New("Invalid page_count")
+		}
+		metadata.pageCount = sql.NullInt32{Int32: int32(request.PageCount), Valid: true}
+	}
+	if request.Description != "" {
+		metadata.description = sql.NullString{String: request.Description, Valid: true}
+	}
+	metadata.genres = make([]uuid.UUID, 0, len(request.Genres))
+	for _, genreID := range request.Genres {
+		genreUUID, err := uuid.Parse(genreID)
+		if err != nil {
+			log.Print("Invalid genre ", genreID)
+			continue
+		}
+		metadata.genres = append(metadata.genres, genreUUID)
+	}
+	return metadata, nil
+}
+
+// setBookGenres replaces book's genres with existing genres from the list.
+func setBookGenres(ctx context.Context, queries *database.Queries, bookID uuid.UUID, genres []uuid.UUID) error {
+	err := queries.DeleteBookGenres(ctx, bookID)
+	if err != nil {
+		return err
+	}
+	if len(genres) == 0 {
+		return nil
+	}
+	filteredGenres, err := queries.CheckGenres(ctx, genres)
+	if err != nil {
+		return err
+	}
+	return queries.AddBookGenres(ctx, database.AddBookGenresParams{Book: bookID, Genres: filteredGenres})
+}
+
+func getBooksGenres(ctx context.Context, queries *database.Queries, bookIDs []uuid.UUID) (map[uuid.UUID][]ResponseGenre, error) {
+	bookGenres, err := queries.GetGenresByBooks(ctx, bookIDs)
+	if err != nil {
+		return nil, err
+	}
+	bookToGenres := make(map[uuid.UUID][]ResponseGenre)
+	for _, bookGenre := range bookGenres {
+		bookToGenres[bookGenre.BookID] = append(bookToGenres[bookGenre.BookID], ResponseGenre{ID: bookGenre.ID.String(), Name: bookGenre.Name})
+	}
+	return bookToGenres, nil
+}
+
+func makeResponseBookFullInfo(book database.GetBooksRow, authors []string, genres []ResponseGenre) ResponseBookFullInfo {
+	return ResponseBookFullInfo{
+		ID:              book.ID.String(),
+		Title:           book.Title,
+		Authors:         authors,
+		Isbn:            book.Isbn.String,
+		PublicationYear: int(book.PublicationYear.Int32),
+		Language:        book.Language.String,
+		PageCount:       int(book.PageCount.Int32),
+		Description:     book.Description.String,
+		Genres:          genres,
+	}
+}
+
+// getBooksFullInfo returns full info of books by their ids. Books that don't exist are absent from the result,
+// so callers that list books by other queries keep their own order.
+func getBooksFullInfo(ctx context.Context, queries *database.Queries, bookIDs []uuid.UUID) (map[uuid.UUID]ResponseBookFullInfo, error) {
+	books, bookToAuthors, err := getBooksAndAuthors(ctx, queries, bookIDs)
+	if err != nil {
+		return nil, err
+	}
+	bookToGenres, err := getBooksGenres(ctx, queries, bookIDs)
+	if err != nil {
+		return nil, err
+	}
+	bookToInfo := make(map[uuid.UUID]ResponseBookFullInfo, len(books))
+	for _, book := range books {
+		bookToInfo[book.ID] = makeResponseBookFullInfo(book, bookToAuthors[book.ID], bookToGenres[book.ID])
+	}
+	return bookToInfo, nil
+}
@@ -0,0 +1,138 @@
+package server
+
+import (
+	"database/sql"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNormalizeISBN(t *testing.T) {
+	type testCase struct {
+		name         string
+		isbn         string
+		expectedISBN string
+		hasError     bool
+	}
+	testCases := []testCase{
+		{
+			name:         "isbn13",
+			isbn:         "9780306406157",
+			expectedISBN: "9780306406157",
+			hasError:     false,
+		},
+		{
+			name:         "isbn13_with_hyphens",
+			isbn:         "978-0-306-40615-7",
+			expectedISBN: "9780306406157",
+			hasError:     false,
+		},
+		{
+			name:         "isbn10",
+			isbn:         "0-306-40615-2",
+			expectedISBN: "9780306406157",
+			hasError:     false,
+		},
+		{
+			name:         "isbn10_with_x",
+			isbn:         "0 8044 2957 x",
+			expectedISBN: "9780804429573",
+			hasError:     false,
+		},
+		{
+			name:     "invalid_isbn13_checksum",
+			isbn:     "9780306406158",
+			hasError: true,
+		},
+		{
+			name:     "invalid_isbn10_checksum",
+			isbn:     "0306406153",
+			hasError: true,
+		},
+		{
+			name:     "invalid_length",
+			isbn:     "978030640615",
+			hasError: true,
+		},
+		{
+			name:     "letters",
+			isbn:     "97803064061AB",
+			hasError: true,
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			isbn, err := normalizeISBN(tc.isbn)
+			assert.Equal(t, err != nil, tc.hasError)
+			assert.Equal(t, isbn, tc.expectedISBN)
+		})
+	}
+}
+
+func TestParseBookMetadata(t *testing.T) {
+	genreID := uuid.New()
+	type testCase struct {
+		name             string
+		request          RequestBookMetadata
+		expectedMetadata bookMetadata
+		hasError         bool
+	}
+	testCases := []testCase{
+		{
+			name:             "empty",
+			request:          RequestBookMetadata{},
+			expectedMetadata: bookMetadata{genres: []uuid.UUID{}},
+			hasError:         false,
+		},
+		{
+			name: "all_fields",
+			request: RequestBookMetadata{
+				Isbn:            "0-306-40615-2",
+				PublicationYear: 1869,
+				Language:        "EN",
+				PageCount:       1225,
+				Description:     "Novel",
+				Genres:          []string{genreID.String(), "invalid_genre"},
+			},
+			expectedMetadata: bookMetadata{
+				isbn:            sql.NullString{String: "9780306406157", Valid: true},
+				publicationYear: sql.NullInt32{Int32: 1869, Valid: true},
+				language:        sql.NullString{String: "en", Valid: true},
+				pageCount:       sql.NullInt32{Int32: 1225, Valid: true},
+				description:     sql.NullString{String: "Novel", Valid: true},
+				genres:          []uuid.UUID{genreID},
+			},
+			hasError: false,
+		},
+		{
+			name:     "invalid_isbn",
+			request:  RequestBookMetadata{Isbn: "12345"},
+			hasError: true,
+		},
+		{
+			name:     "future_publication_year",
+			request:  RequestBookMetadata{PublicationYear: 3000},
+			hasError: true,
+		},
+		{
+			name:     "invalid_language",
+			request:  RequestBookMetadata{Language: "english"},
+			hasError: true,
+		},
+		{
+			name:     "negative_page_count",
+			request:  RequestBookMetadata{PageCount: -10},
+			hasError: true,
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			metadata, err := parseBookMetadata(tc.request)
+			assert.Equal(t, err != nil, tc.hasError)
+			if !tc.hasError {
+				assert.Equal(t, metadata, tc.expectedMetadata)
+			}
+		})
+	}
+}
@@ -0,0 +1,168 @@
+package server
+
+import (
+	"encoding/json"
+	"net/http"
+
+	common "github.com/bakurvik/mylib-common"
+	"github.com/bakurvik/mylib/library/internal/database"
+	"github.com/google/uuid"
+)
+
+// @Summary Create new genre
+// @Description Creates new genre and stores it in DB
+// @Tags Admin Genres
+// @Accept json
+// @Produce json
+// @Param request body RequestGenre true "Genre's info"
+// @Success 201 {object} ResponseGenre "Created genre"
+// @Failure 400 {object} ErrorResponse "Invalid request body or empty name"
+// @Failure 409 {object} ErrorResponse "Genre already exists"
+// @Failure 401 {object} ErrorResponse "Unauthorized"
+// @Failure 403 {object} ErrorResponse "Forbidden"
+// @Failure 500 {object} ErrorResponse
+// @Security BearerAuth
+// @Router /admin/genres [post]
+func (cfg *ApiConfig) HandlePostAdminGenres(w http.ResponseWriter, r *http.Request) {
+	decoder := json.NewDecoder(r.Body)
+	request := RequestGenre{}
+	err := decoder.Decode(&request)
+	if err != nil || request.Name == "" {
+		common.RespondWithError(w, http.StatusBadRequest, "Invalid request")
+		return
+	}
+
+	if cfg.DB == nil {
+		common.RespondWithError(w, http.StatusInternalServerError, "DB error")
+		return
+	}
+
+	queries := database.New(cfg.DB)
+	genreID, dbErr := queries.CreateGenre(r.Context(), request.Name)
+	if isUniqueViolation(dbErr) {
+		common.RespondWithError(w, http.StatusConflict, "Genre already exists")
+		return
+	}
+	if dbErr != nil {
+		common.RespondWithError(w, http.StatusInternalServerError, dbErr.Error())
+		return
+	}
+	common.RespondWithJSON(w, http.StatusCreated, ResponseGenre{ID: genreID.String(), Name: request.Name}, nil)
+}
+
+// @Summary Get genres
+// @Description Gets all genres from DB sorted by name
+// @Tags Admin Genres
+// @Accept json
+// @Produce json
+// @Success 200 {array} ResponseGenre "Genres"
+// @Failure 401 {object} ErrorResponse "Unauthorized"
+// @Failure 403 {object} ErrorResponse "Forbidden"
+// @Failure 500 {object} ErrorResponse
+// @Security BearerAuth
+// @Router /admin/genres [get]
+func (cfg *ApiConfig) HandleGetAdminGenres(w http.ResponseWriter, r *http.Request) {
+	if cfg.DB == nil {
+		common.RespondWithError(w, http.StatusInternalServerError, "DB error")
+		return
+	}
+
+	queries := database.New(cfg.DB)
+	genres, dbErr := queries.GetGenres(r.Context())
+	if dbErr != nil {
+		common.RespondWithError(w, http.StatusInternalServerError, dbErr.Error())
+		return
+	}
+
+	response := make([]ResponseGenre, 0, len(genres))
+	for _, genre := range genres {
+		response = append(response, ResponseGenre{ID: genre.ID.String(), Name: genre.Name})
+	}
+	common.RespondWithJSON(w, http.StatusOK, response, nil)
+}
+
+// @Summary Update genre
+// @Description Renames existing genre
+// @Tags Admin Genres
+// @Accept json
+// @Produce json
+// @Param request body RequestGenreWithID true "Genre's info"
+// @Success 200 {string} string "Updated successfully"
+// @Failure 400 {object} ErrorResponse "Invalid request body or empty name"
+// @Failure 404 {object} ErrorResponse "Genre not found"
+// @Failure 409 {object} ErrorResponse "Genre already exists"
+// @Failure 401 {object} ErrorResponse "Unauthorized"
+// @Failure 403 {object} ErrorResponse "Forbidden"
+// @Failure 500 {object} ErrorResponse
+// @Security BearerAuth
+// @Router /admin/genres [put]
+func (cfg *ApiConfig) HandlePutAdminGenres(w http.ResponseWriter, r *http.Request) {
+	decoder := json.NewDecoder(r.Body)
+	request := RequestGenreWithID{}
+	err := decoder.Decode(&request)
+	if err != nil || request.Name == "" {
+		common.RespondWithError(w, http.StatusBadRequest, "Invalid request")
+		return
+	}
+
+	genreID, err := uuid.Parse(request.ID)
+	if err != nil {
+		common.RespondWithError(w, http.StatusBadRequest, "Invalid id")
+		return
+	}
+
+	if cfg.DB == nil {
+		common.RespondWithError(w, http.StatusInternalServerError, "DB error")
+		return
+	}
+
+	queries := database.New(cfg.DB)
+	rowsCount, dbErr := queries.UpdateGenre(r.Context(), database.UpdateGenreParams{ID: genreID, Name: request.Name})
+	if isUniqueViolation(dbErr) {
+		common.RespondWithError(w, http.StatusConflict, "Genre already exists")
+		return
+	}
+	if dbErr != nil {
+		common.RespondWithError(w, http.StatusInternalServerError, dbErr.Error())
+		return
+	}
+	if rowsCount == 0 {
+		common.RespondWithError(w, http.StatusNotFound, "Genre not found")
+		return
+	}
+	w.WriteHeader(http.StatusOK)
+}
+
+// @Summary Delete genre
+// @Description Deletes a genre with requested ID from DB. Books lose this genre
+// @Tags Admin Genres
+// @Accept json
+// @Produce json
+// @Param id path string true "Genre ID"
+// @Success 204 {string} string "Deleted successfully"
+// @Failure 400 {object} ErrorResponse "Invalid genre ID"
+// @Failure 401 {object} ErrorResponse "Unauthorized"
+// @Failure 403 {object} ErrorResponse "Forbidden"
+// @Failure 500 {object} ErrorResponse
+// @Security BearerAuth
+// @Router /admin/genres/{id} [delete]
+func (cfg *ApiConfig) HandleDeleteAdminGenres(w http.ResponseWriter, r *http.Request) {
+	genreID, err := uuid.Parse(r.PathValue("id"))
+	if err != nil {
+		common.RespondWithError(w, http.StatusBadRequest, "Invalid id")
+		return
+	}
+
+	if cfg.DB == nil {
+		common.RespondWithError(w, http.StatusInternalServerError, "DB error")
+		return
+	}
+
+	queries := database.New(cfg.DB)
+	dbErr := queries.DeleteGenre(r.Context(), genreID)
+	if dbErr != nil {
+		common.RespondWithError(w, http.StatusInternalServerError, dbErr.Error())
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
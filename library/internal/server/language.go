@@ -0,0 +1,22 @@
+package server
+
+import (
+	"database/sql"
+	"errors"
+	"regexp"
+	"strings"
+)
+
+var languageCodeRegexp = regexp.MustCompile(`^[a-z]{2}$`)
+
+// parseLanguage validates two-letter ISO 639-1 language code. Empty value means that language is not set.
+func parseLanguage(value string) (sql.NullString, error) {
+	if value == "" {
+		return sql.NullString{}, nil
+	}
+	language := strings.ToLower(value)
+	if !languageCodeRegexp.MatchString(language) {
+		return sql.NullString{}, errors.New("Invalid language")
+	}
+	return sql.NullString{String: language, Valid: true}, nil
+}
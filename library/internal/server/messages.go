@@ -0,0 +1,69 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"sort"
+
+	common "github.com/bakurvik/mylib-common"
+	"github.com/bakurvik/mylib/library/internal/database"
+	"github.com/bakurvik/mylib/shared/messages"
+	"github.com/google/uuid"
+)
+
+// Kafka topics of library events.
+const (
+	AuthorsTopic = "authors"
+	BooksTopic   = "books"
+)
+
+const (
+	actionCreated = "created"
+	actionUpdated = "updated"
+	actionDeleted = "deleted"
+)
+
+// addOutboxMessage stores message in outbox table. It must be called within the transaction that changes the data,
+// the message is published to Kafka later by outbox.Relay.
+func addOutboxMessage(ctx context.Context, queries *database.Queries, topic string, key string, data interface{}) error {
+	payload, err := json.Marshal(data)
+	if err != nil {
+		return err
+	}
+	return queries.CreateOutboxMessage(ctx, database.CreateOutboxMessageParams{Topic: topic, MessageKey: key, Payload: payload})
+}
+
+func addAuthorMessage(ctx context.Context, queries *database.Queries, message common.AuthorMessage) error {
+	return addOutboxMessage(ctx, queries, AuthorsTopic, message.ID, message)
+}
+
+func addBookMessage(ctx context.Context, queries *database.Queries, message messages.BookMessage) error {
+	return addOutboxMessage(ctx, queries, BooksTopic, message.ID, message)
+}
+
+// addBooksUpdatedMessages re-reads books with their authors and stores update messages, e.g. after an author was renamed or deleted.
+func addBooksUpdatedMessages(ctx context.Context, queries *database.Queries, bookIDs []uuid.UUID) error {
+	if len(bookIDs) == 0 {
+		return nil
+	}
+	books, bookToAuthors, err := getBooksAndAuthors(ctx, queries, bookIDs)
+	if err != nil {
+		return err
+	}
+	for _, book := range books {
+		err = addBookMessage(ctx, queries, messages.BookMessage{ID: book.ID.String(), Title: book.Title, Authors: bookToAuthors[book.ID], Action: actionUpdated})
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func getBookAuthorNames(ctx context.Context, queries *database.Queries, bookID uuid.UUID) ([]string, error) {
+	authors, err := queries.GetBookAuthors(ctx, bookID)
+	if err != nil {
+		return nil, err
+	}
+	sort.Strings(authors)
+	return authors, nil
+}
@@ -0,0 +1,57 @@
+package server
+
+import (
+	"errors"
+	"log"
+	"net/http"
+
+	common "github.com/bakurvik/mylib-common"
+	"github.com/bakurvik/mylib/library/internal/auth"
+)
+
+// requireRole lets the request through only with a valid access token of a user that has at least the given role.
+// A personal access token is accepted too if it has library:write scope.
+func (cfg *ApiConfig) requireRole(role string, handler http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		token, err := auth.GetBearerToken(r.Header)
+		if err != nil {
+			common.RespondWithError(w, http.StatusUnauthorized, "Unauthorized")
+			return
+		}
+
+		userRole := ""
+		if auth.IsPersonalToken(token) {
+			if cfg.PersonalTokens == nil {
+				common.RespondWithError(w, http.StatusUnauthorized, "Unauthorized")
+				return
+			}
+			info, err := cfg.PersonalTokens.CheckPersonalToken(r.Context(), token)
+			if errors.Is(err, auth.ErrInvalidToken) {
+				common.RespondWithError(w, http.StatusUnauthorized, "Unauthorized")
+				return
+			}
+			if err != nil {
+				log.Print("Failed to check personal access token: ", err)
+				common.RespondWithError(w, http.StatusInternalServerError, "Failed to check authorization")
+				return
+			}
+			if !info.HasScope(auth.ScopeLibraryWrite) {
+				common.RespondWithError(w, http.StatusForbidden, "Forbidden")
+				return
+			}
+			userRole = info.Role
+		} else {
+			_, userRole, err = auth.ValidateJWT(token, cfg.TokenKeys)
+			if err != nil {
+				common.RespondWithError(w, http.StatusUnauthorized, "Unauthorized")
+				return
+			}
+		}
+
+		if !auth.HasRole(userRole, role) {
+			common.RespondWithError(w, http.StatusForbidden, "Forbidden")
+			return
+		}
+		handler(w, r)
+	}
+}
@@ -0,0 +1,132 @@
+package server
+
+import (
+	"context"
+	"crypto/ed25519"
+	"crypto/rand"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/bakurvik/mylib/library/internal/auth"
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+func makeTestToken(t *testing.T, role string, key ed25519.PrivateKey) string {
+	claims := auth.Claims{
+		Role: role,
+		RegisteredClaims: jwt.RegisteredClaims{
+			Issuer:    auth.Issuer,
+			Subject:   uuid.New().String(),
+			ExpiresAt: jwt.NewNumericDate(time.Now().Add(time.Hour)),
+		},
+	}
+	token, err := jwt.NewWithClaims(jwt.SigningMethodEdDSA, claims).SignedString(key)
+	assert.NoError(t, err)
+	return token
+}
+
+func TestRequireRole(t *testing.T) {
+	publicKey, key, _ := ed25519.GenerateKey(rand.Reader)
+	_, anotherKey, _ := ed25519.GenerateKey(rand.Reader)
+	type testCase struct {
+		name               string
+		authHeader         string
+		expectedStatusCode int
+	}
+	testCases := []testCase{
+		{
+			name:               "no_token",
+			authHeader:         "",
+			expectedStatusCode: http.StatusUnauthorized,
+		},
+		{
+			name:               "invalid_token",
+			authHeader:         "Bearer " + makeTestToken(t, auth.RoleAdmin, anotherKey),
+			expectedStatusCode: http.StatusUnauthorized,
+		},
+		{
+			name:               "reader",
+			authHeader:         "Bearer " + makeTestToken(t, auth.RoleReader, key),
+			expectedStatusCode: http.StatusForbidden,
+		},
+		{
+			name:               "editor",
+			authHeader:         "Bearer " + makeTestToken(t, auth.RoleEditor, key),
+			expectedStatusCode: http.StatusOK,
+		},
+		{
+			name:               "admin",
+			authHeader:         "Bearer " + makeTestToken(t, auth.RoleAdmin, key),
+			expectedStatusCode: http.StatusOK,
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			cfg := ApiConfig{TokenKeys: func(token *jwt.Token) (interface{}, error) { return publicKey, nil }}
+			handler := cfg.requireRole(auth.RoleEditor, func(w http.ResponseWriter, r *http.Request) { w.WriteHeader(http.StatusOK) })
+
+			r := httptest.NewRequest(http.MethodPost, ApiBooksPath, nil)
+			if tc.authHeader != "" {
+				r.Header.Set("Authorization", tc.authHeader)
+			}
+			w := httptest.NewRecorder()
+			handler(w, r)
+			assert.Equal(t, w.Code, tc.expectedStatusCode)
+		})
+	}
+}
+
+type staticTokenChecker struct {
+	tokens map[string]auth.TokenInfo
+	err    error
+}
+
+func (c *staticTokenChecker) CheckPersonalToken(ctx context.Context, token string) (auth.TokenInfo, error) {
+	if c.err != nil {
+		return auth.TokenInfo{}, c.err
+	}
+	info, ok := c.tokens[token]
+	if !ok {
+		return auth.TokenInfo{}, auth.ErrInvalidToken
+	}
+	return info, nil
+}
+
+func TestRequireRoleWithPersonalToken(t *testing.T) {
+	checker := &staticTokenChecker{tokens: map[string]auth.TokenInfo{
+		"mylib_pat_editor":      {UserID: uuid.New(), Role: auth.RoleEditor, Scopes: []string{auth.ScopeLibraryWrite}},
+		"mylib_pat_reader":      {UserID: uuid.New(), Role: auth.RoleReader, Scopes: []string{auth.ScopeLibraryWrite}},
+		"mylib_pat_other_scope": {UserID: uuid.New(), Role: auth.RoleAdmin, Scopes: []string{"reading:read"}},
+	}}
+	type testCase struct {
+		name               string
+		token              string
+		checker            auth.PersonalTokenChecker
+		expectedStatusCode int
+	}
+	testCases := []testCase{
+		{name: "editor", token: "mylib_pat_editor", checker: checker, expectedStatusCode: http.StatusOK},
+		{name: "reader", token: "mylib_pat_reader", checker: checker, expectedStatusCode: http.StatusForbidden},
+		{name: "no_scope", token: "mylib_pat_other_scope", checker: checker, expectedStatusCode: http.StatusForbidden},
+		{name: "invalid", token: "mylib_pat_unknown", checker: checker, expectedStatusCode: http.StatusUnauthorized},
+		{name: "no_checker", token: "mylib_pat_editor", checker: nil, expectedStatusCode: http.StatusUnauthorized},
+		{name: "users_service_error", token: "mylib_pat_editor", checker: &staticTokenChecker{err: errors.New("unavailable")}, expectedStatusCode: http.StatusInternalServerError},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			cfg := ApiConfig{PersonalTokens: tc.checker}
+			handler := cfg.requireRole(auth.RoleEditor, func(w http.ResponseWriter, r *http.Request) { w.WriteHeader(http.StatusOK) })
+
+			r := httptest.NewRequest(http.MethodPost, ApiBooksPath, nil)
+			r.Header.Set("Authorization", "Bearer "+tc.token)
+			w := httptest.NewRecorder()
+			handler(w, r)
+			assert.Equal(t, w.Code, tc.expectedStatusCode)
+		})
+	}
+}
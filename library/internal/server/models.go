@@ -4,6 +4,7 @@ type RequestAuthor struct {
 	FullName  string `json:"full_name"`
 	BirthDate string `json:"birth_date,omitempty"`
 	DeathDate string `json:"death_date,omitempty"`
+	Language  string `json:"language,omitempty"`
 }
 
 type RequestAuthorWithID struct {
@@ -11,6 +12,7 @@ type RequestAuthorWithID struct {
 	FullName  string `json:"full_name"`
 	BirthDate string `json:"birth_date,omitempty"`
 	DeathDate string `json:"death_date,omitempty"`
+	Language  string `json:"language,omitempty"`
 }
 
 type ResponseAuthorShortInfo struct {
@@ -22,6 +24,7 @@ type ResponseAuthorFullInfo struct {
 	FullName  string `json:"full_name"`
 	BirthDate string `json:"birth_date,omitempty"`
 	DeathDate string `json:"death_date,omitempty"`
+	Language  string `json:"language,omitempty"`
 }
 
 type ResponseBook struct {
@@ -29,21 +32,64 @@ type ResponseBook struct {
 	Title string `json:"title"`
 }
 
+type RequestBookMetadata struct {
+	Isbn            string   `json:"isbn,omitempty"`
+	PublicationYear int      `json:"publication_year,omitempty"`
+	Language        string   `json:"language,omitempty"`
+	PageCount       int      `json:"page_count,omitempty"`
+	Description     string   `json:"description,omitempty"`
+	Genres          []string `json:"genres,omitempty"`
+}
+
 type RequestBook struct {
 	Title   string   `json:"title"`
 	Authors []string `json:"authors"`
+	RequestBookMetadata
 }
 
 type RequestBookWithID struct {
 	ID      string   `json:"id"`
 	Title   string   `json:"title"`
 	Authors []string `json:"authors"`
+	RequestBookMetadata
 }
 
 type ResponseBookFullInfo struct {
-	ID      string   `json:"id"`
-	Title   string   `json:"title"`
-	Authors []string `json:"authors"`
+	ID              string                    `json:"id"`
+	Title           string                    `json:"title"`
+	Authors         []string                  `json:"authors"`
+	AuthorsInfo     []ResponseAuthorShortInfo `json:"authors_info,omitempty"`
+	Isbn            string                    `json:"isbn,omitempty"`
+	PublicationYear int                       `json:"publication_year,omitempty"`
+	Language        string                    `json:"language,omitempty"`
+	PageCount       int                       `json:"page_count,omitempty"`
+	Description     string                    `json:"description,omitempty"`
+	Genres          []ResponseGenre           `json:"genres,omitempty"`
+	Headline        string                    `json:"headline,omitempty"`
+}
+
+type RequestGenre struct {
+	Name string `json:"name"`
+}
+
+type RequestGenreWithID struct {
+	ID   string `json:"id"`
+	Name string `json:"name"`
+}
+
+type ResponseGenre struct {
+	ID   string `json:"id"`
+	Name string `json:"name"`
+}
+
+type ResponseBooksPage struct {
+	Items      []ResponseBookFullInfo `json:"items"`
+	NextCursor string                 `json:"next_cursor"`
+}
+
+type ResponseAuthorsPage struct {
+	Items      []ResponseAuthorShortInfo `json:"items"`
+	NextCursor string                    `json:"next_cursor"`
 }
 
 type ErrorResponse struct {
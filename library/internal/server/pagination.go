@@ -0,0 +1,138 @@
+package server
+
+import (
+	"database/sql"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"slices"
+	"strconv"
+	"time"
+
+	common "github.com/bakurvik/mylib-common"
+	"github.com/google/uuid"
+)
+
+const (
+	defaultPageLimit = 20
+
+	sortTitle     = "title"
+	sortFullName  = "full_name"
+	sortCreatedAt = "created_at"
+)
+
+// pageCursor points to the last item of the previous page. It is passed to clients as an opaque base64 string.
+type pageCursor struct {
+	Sort string    `json:"sort"`
+	ID   uuid.UUID `json:"id"`
+	Name string    `json:"name,omitempty"`
+	Time time.Time `json:"time"`
+}
+
+type pageParams struct {
+	limit        int
+	sort         string
+	cursor       *pageCursor
+	createdAfter sql.NullTime
+}
+
+func encodeCursor(cursor pageCursor) string {
+	data, _ := json.Marshal(cursor)
+	return base64.RawURLEncoding.EncodeToString(data)
+}
+
+func decodeCursor(value string) (*pageCursor, error) {
+	data, err := base64.RawURLEncoding.DecodeString(value)
+	if err != nil {
+		return nil, err
+	}
+	cursor := pageCursor{}
+	err = json.Unmarshal(data, &cursor)
+	if err != nil {
+		return nil, err
+	}
+	return &cursor, nil
+}
+
+// parsePageParams parses limit, sort, cursor and created_after query parameters.
+// The first of sortFields is used by default.
+func parsePageParams(r *http.Request, sortFields []string, maxLimit int) (pageParams, error) {
+	query := r.URL.Query()
+	params := pageParams{limit: defaultPageLimit, sort: sortFields[0]}
+	if maxLimit > 0 && params.limit > maxLimit {
+		params.limit = maxLimit
+	}
+
+	if limit := query.Get("limit"); limit != "" {
+		value, err := strconv.Atoi(limit)
+		if err != nil || value <= 0 {
+			return pageParams{}, errors.New("Invalid limit")
+		}
+		params.limit = value
+		if maxLimit > 0 && params.limit > maxLimit {
+			params.limit = maxLimit
+		}
+	}
+
+	if sort := query.Get("sort"); sort != "" {
+		if !slices.Contains(sortFields, sort) {
+			return pageParams{}, errors.New("Invalid sort")
+		}
+		params.sort = sort
+	}
+
+	if cursor := query.Get("cursor"); cursor != "" {
+		value, err := decodeCursor(cursor)
+		if err != nil || value.Sort != params.sort {
+			return pageParams{}, errors.New("Invalid cursor")
+		}
+		params.cursor = value
+	}
+
+	if createdAfter := query.Get("created_after"); createdAfter != "" {
+		value, err := time.Parse(common.DateFormat, createdAfter)
+		if err != nil {
+			return pageParams{}, errors.New("Invalid created_after")
+		}
+		params.createdAfter = sql.NullTime{Time: value, Valid: true}
+	}
+	return params, nil
+}
+
+func (p *pageParams) cursorID() uuid.NullUUID {
+	if p.cursor == nil {
+		return uuid.NullUUID{}
+	}
+	return uuid.NullUUID{UUID: p.cursor.ID, Valid: true}
+}
+
+func (p *pageParams) cursorName() sql.NullString {
+	if p.cursor == nil {
+		return sql.NullString{}
+	}
+	return sql.NullString{String: p.cursor.Name, Valid: true}
+}
+
+func (p *pageParams) cursorTime() sql.NullTime {
+	if p.cursor == nil {
+		return sql.NullTime{}
+	}
+	return sql.NullTime{Time: p.cursor.Time, Valid: true}
+}
+
+// queryLimit requests one extra row to find out whether there is a next page.
+func (p *pageParams) queryLimit() int32 {
+	return int32(p.limit + 1)
+}
+
+// nextCursor returns cursor of the page that follows the item with given ID, name and creation time.
+func (p *pageParams) nextCursor(lastID uuid.UUID, lastName string, lastCreatedAt time.Time) string {
+	cursor := pageCursor{Sort: p.sort, ID: lastID}
+	if p.sort == sortCreatedAt {
+		cursor.Time = lastCreatedAt
+	} else {
+		cursor.Name = lastName
+	}
+	return encodeCursor(cursor)
+}
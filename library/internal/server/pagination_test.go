@@ -0,0 +1,108 @@
+package server
+
+import (
+	"database/sql"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParsePageParams(t *testing.T) {
+	bookID := uuid.New()
+	createdAt := time.Date(2025, 5, 1, 10, 30, 0, 123000, time.UTC)
+	titleCursor := encodeCursor(pageCursor{Sort: sortTitle, ID: bookID, Name: "War and Peace"})
+	createdAtCursor := encodeCursor(pageCursor{Sort: sortCreatedAt, ID: bookID, Time: createdAt})
+	type testCase struct {
+		name           string
+		query          string
+		maxLimit       int
+		expectedParams pageParams
+		hasError       bool
+	}
+	testCases := []testCase{
+		{
+			name:           "default",
+			query:          "",
+			maxLimit:       100,
+			expectedParams: pageParams{limit: defaultPageLimit, sort: sortTitle},
+			hasError:       false,
+		},
+		{
+			name:           "default_limit_above_max",
+			query:          "",
+			maxLimit:       5,
+			expectedParams: pageParams{limit: 5, sort: sortTitle},
+			hasError:       false,
+		},
+		{
+			name:           "limit_above_max",
+			query:          "?limit=1000",
+			maxLimit:       100,
+			expectedParams: pageParams{limit: 100, sort: sortTitle},
+			hasError:       false,
+		},
+		{
+			name:     "all_params",
+			query:    "?limit=5&sort=created_at&created_after=01.05.2025&cursor=" + createdAtCursor,
+			maxLimit: 100,
+			expectedParams: pageParams{
+				limit:        5,
+				sort:         sortCreatedAt,
+				cursor:       &pageCursor{Sort: sortCreatedAt, ID: bookID, Time: createdAt},
+				createdAfter: sql.NullTime{Time: time.Date(2025, 5, 1, 0, 0, 0, 0, time.UTC), Valid: true},
+			},
+			hasError: false,
+		},
+		{
+			name:           "title_cursor",
+			query:          "?cursor=" + titleCursor,
+			maxLimit:       100,
+			expectedParams: pageParams{limit: defaultPageLimit, sort: sortTitle, cursor: &pageCursor{Sort: sortTitle, ID: bookID, Name: "War and Peace"}},
+			hasError:       false,
+		},
+		{
+			name:     "invalid_limit",
+			query:    "?limit=-1",
+			maxLimit: 100,
+			hasError: true,
+		},
+		{
+			name:     "invalid_sort",
+			query:    "?sort=rating",
+			maxLimit: 100,
+			hasError: true,
+		},
+		{
+			name:     "invalid_cursor",
+			query:    "?cursor=invalid",
+			maxLimit: 100,
+			hasError: true,
+		},
+		{
+			name:     "cursor_of_other_sort",
+			query:    "?sort=created_at&cursor=" + titleCursor,
+			maxLimit: 100,
+			hasError: true,
+		},
+		{
+			name:     "invalid_created_after",
+			query:    "?created_after=2025-05-01",
+			maxLimit: 100,
+			hasError: true,
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			r := httptest.NewRequest(http.MethodGet, "/test"+tc.query, nil)
+			params, err := parsePageParams(r, []string{sortTitle, sortCreatedAt}, tc.maxLimit)
+			assert.Equal(t, err != nil, tc.hasError)
+			if !tc.hasError {
+				assert.Equal(t, params, tc.expectedParams)
+			}
+		})
+	}
+}
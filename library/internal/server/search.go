@@ -0,0 +1,151 @@
+package server
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"github.com/bakurvik/mylib/library/internal/database"
+)
+
+const (
+	searchModeExact  = "exact"
+	searchModeFuzzy  = "fuzzy"
+	searchModePrefix = "prefix"
+)
+
+type searchParams struct {
+	text string
+	mode string
+	// lang is empty if search text language is not given, then every row is matched in its own language.
+	lang string
+}
+
+// parseSearchParams parses text, mode and lang query parameters.
+// Exact full text search is used by default, unknown languages are searched with postgres simple config.
+func parseSearchParams(r *http.Request) (searchParams, error) {
+	query := r.URL.Query()
+	params := searchParams{text: query.Get("text"), mode: searchModeExact}
+	if params.text == "" {
+		return searchParams{}, errors.New("Empty search text")
+	}
+
+	switch mode := query.Get("mode"); mode {
+	case "":
+	case searchModeExact, searchModeFuzzy, searchModePrefix:
+		params.mode = mode
+	default:
+		return searchParams{}, errors.New("Invalid mode")
+	}
+
+	if lang := query.Get("lang"); lang != "" {
+		language, err := parseLanguage(lang)
+		if err != nil {
+			return searchParams{}, errors.New("Invalid lang")
+		}
+		params.lang = language.String
+	}
+	return params, nil
+}
+
+// makePrefixTsQuery builds to_tsquery input that matches all words of text as prefixes, e.g. "war pea" -> "war:* & pea:*".
+// Every character except letters and digits is treated as a separator, so user input can't break tsquery syntax.
+func makePrefixTsQuery(text string) string {
+	words := strings.FieldsFunc(text, func(r rune) bool { return !unicode.IsLetter(r) && !unicode.IsDigit(r) })
+	for i, word := range words {
+		words[i] = word + ":*"
+	}
+	return strings.Join(words, " & ")
+}
+
+// searchLanguage returns lang argument of search queries. Without language the text is parsed with the text search config
+// of each row, so books and authors are found in their own language, but GIN index on tsv can't be used.
+func searchLanguage(params searchParams) sql.NullString {
+	return sql.NullString{String: params.lang, Valid: params.lang != ""}
+}
+
+// setFuzzyThreshold sets pg_trgm word similarity threshold for the current transaction. Postgres default is used if threshold is not positive.
+func setFuzzyThreshold(ctx context.Context, queries *database.Queries, threshold float64) error {
+	if threshold <= 0 {
+		return nil
+	}
+	return queries.SetWordSimilarityThreshold(ctx, strconv.FormatFloat(threshold, 'f', -1, 64))
+}
+
+// searchBooks finds books by title, author names, description and genres. Fuzzy mode must be called within a transaction so that similarity threshold is applied.
+func (cfg *ApiConfig) searchBooks(ctx context.Context, queries *database.Queries, params searchParams) ([]database.SearchBooksRow, error) {
+	limit := int32(cfg.MaxSearchBooksLimit)
+	switch params.mode {
+	case searchModeFuzzy:
+		err := setFuzzyThreshold(ctx, queries, cfg.FuzzySearchThreshold)
+		if err != nil {
+			return nil, err
+		}
+		books, err := queries.SearchBooksFuzzy(ctx, database.SearchBooksFuzzyParams{Lang: searchLanguage(params), Query: params.text, Limit: limit})
+		if err != nil {
+			return nil, err
+		}
+		result := make([]database.SearchBooksRow, 0, len(books))
+		for _, book := range books {
+			result = append(result, database.SearchBooksRow(book))
+		}
+		return result, nil
+	case searchModePrefix:
+		query := makePrefixTsQuery(params.text)
+		if query == "" {
+			return nil, nil
+		}
+		books, err := queries.SearchBooksPrefix(ctx, database.SearchBooksPrefixParams{Lang: searchLanguage(params), Query: query, Limit: limit})
+		if err != nil {
+			return nil, err
+		}
+		result := make([]database.SearchBooksRow, 0, len(books))
+		for _, book := range books {
+			result = append(result, database.SearchBooksRow(book))
+		}
+		return result, nil
+	default:
+		return queries.SearchBooks(ctx, database.SearchBooksParams{Lang: searchLanguage(params), Query: params.text, Limit: limit})
+	}
+}
+
+// searchAuthors finds authors by name. Fuzzy mode must be called within a transaction so that similarity threshold is applied.
+func (cfg *ApiConfig) searchAuthors(ctx context.Context, queries *database.Queries, params searchParams) ([]database.SearchAuthorsRow, error) {
+	limit := int32(cfg.MaxSearchAuthorsLimit)
+	switch params.mode {
+	case searchModeFuzzy:
+		err := setFuzzyThreshold(ctx, queries, cfg.FuzzySearchThreshold)
+		if err != nil {
+			return nil, err
+		}
+		authors, err := queries.SearchAuthorsFuzzy(ctx, database.SearchAuthorsFuzzyParams{Lang: searchLanguage(params), Query: params.text, Limit: limit})
+		if err != nil {
+			return nil, err
+		}
+		result := make([]database.SearchAuthorsRow, 0, len(authors))
+		for _, author := range authors {
+			result = append(result, database.SearchAuthorsRow(author))
+		}
+		return result, nil
+	case searchModePrefix:
+		query := makePrefixTsQuery(params.text)
+		if query == "" {
+			return nil, nil
+		}
+		authors, err := queries.SearchAuthorsPrefix(ctx, database.SearchAuthorsPrefixParams{Lang: searchLanguage(params), Query: query, Limit: limit})
+		if err != nil {
+			return nil, err
+		}
+		result := make([]database.SearchAuthorsRow, 0, len(authors))
+		for _, author := range authors {
+			result = append(result, database.SearchAuthorsRow(author))
+		}
+		return result, nil
+	default:
+		return queries.SearchAuthors(ctx, database.SearchAuthorsParams{Lang: searchLanguage(params), Query: params.text, Limit: limit})
+	}
+}
@@ -0,0 +1,181 @@
+package server
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseSearchParams(t *testing.T) {
+	type testCase struct {
+		name           string
+		query          string
+		expectedParams searchParams
+		hasError       bool
+	}
+	testCases := []testCase{
+		{
+			name:           "default",
+			query:          "?text=war",
+			expectedParams: searchParams{text: "war", mode: searchModeExact},
+			hasError:       false,
+		},
+		{
+			name:           "exact",
+			query:          "?text=war&mode=exact",
+			expectedParams: searchParams{text: "war", mode: searchModeExact},
+			hasError:       false,
+		},
+		{
+			name:           "fuzzy",
+			query:          "?text=war&mode=fuzzy",
+			expectedParams: searchParams{text: "war", mode: searchModeFuzzy},
+			hasError:       false,
+		},
+		{
+			name:           "prefix_with_lang",
+			query:          "?text=war&mode=prefix&lang=RU",
+			expectedParams: searchParams{text: "war", mode: searchModePrefix, lang: "ru"},
+			hasError:       false,
+		},
+		{
+			name:           "unknown_lang",
+			query:          "?text=war&lang=xx",
+			expectedParams: searchParams{text: "war", mode: searchModeExact, lang: "xx"},
+			hasError:       false,
+		},
+		{
+			name:           "empty_text",
+			query:          "?mode=fuzzy",
+			expectedParams: searchParams{},
+			hasError:       true,
+		},
+		{
+			name:           "invalid_mode",
+			query:          "?text=war&mode=regex",
+			expectedParams: searchParams{},
+			hasError:       true,
+		},
+		{
+			name:           "invalid_lang",
+			query:          "?text=war&lang=english",
+			expectedParams: searchParams{},
+			hasError:       true,
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			r := httptest.NewRequest("GET", "/api/books/search"+tc.query, nil)
+			params, err := parseSearchParams(r)
+			assert.Equal(t, err != nil, tc.hasError)
+			assert.Equal(t, params, tc.expectedParams)
+		})
+	}
+}
+
+func TestMakePrefixTsQuery(t *testing.T) {
+	type testCase struct {
+		name     string
+		text     string
+		expected string
+	}
+	testCases := []testCase{
+		{name: "one_word", text: "tols", expected: "tols:*"},
+		{name: "several_words", text: "war  and pea", expected: "war:* & and:* & pea:*"},
+		{name: "tsquery_syntax", text: "war & !peace:* | (anna)", expected: "war:* & peace:* & anna:*"},
+		{name: "unicode", text: "Войн мир", expected: "Войн:* & мир:*"},
+		{name: "no_words", text: " &|! ", expected: ""},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Equal(t, makePrefixTsQuery(tc.text), tc.expected)
+		})
+	}
+}
+
+// failingConn is a database connection that opens transactions but fails every query.
+type failingConn struct {
+	commits   int
+	rollbacks int
+}
+
+func (c *failingConn) Prepare(query string) (driver.Stmt, error) {
+	return nil, errors.New("query failed")
+}
+func (c *failingConn) Close() error {
+	return nil
+}
+func (c *failingConn) Begin() (driver.Tx, error) {
+	return c, nil
+}
+func (c *failingConn) Commit() error {
+	c.commits++
+	return nil
+}
+func (c *failingConn) Rollback() error {
+	c.rollbacks++
+	return nil
+}
+
+type failingConnector struct {
+	conn *failingConn
+}
+
+func (c *failingConnector) Connect(context.Context) (driver.Conn, error) {
+	return c.conn, nil
+}
+func (c *failingConnector) Driver() driver.Driver {
+	return nil
+}
+
+func TestSearchHandlersQueryError(t *testing.T) {
+	type testCase struct {
+		name    string
+		target  string
+		handler func(cfg *ApiConfig) http.HandlerFunc
+	}
+	testCases := []testCase{
+		{
+			name:    "authors",
+			target:  "/api/authors/search?text=tolstoy",
+			handler: func(cfg *ApiConfig) http.HandlerFunc { return cfg.HandleGetApiAuthorsSearch },
+		},
+		{
+			name:    "authors_fuzzy",
+			target:  "/api/authors/search?text=tolstoy&mode=fuzzy",
+			handler: func(cfg *ApiConfig) http.HandlerFunc { return cfg.HandleGetApiAuthorsSearch },
+		},
+		{
+			name:    "books",
+			target:  "/api/books/search?text=war",
+			handler: func(cfg *ApiConfig) http.HandlerFunc { return cfg.HandleGetApiBooksSearch },
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			conn := &failingConn{}
+			db := sql.OpenDB(&failingConnector{conn: conn})
+			defer db.Close()
+			cfg := &ApiConfig{DB: db, MaxSearchAuthorsLimit: 10, MaxSearchBooksLimit: 10, FuzzySearchThreshold: 0.5}
+
+			w := httptest.NewRecorder()
+			tc.handler(cfg)(w, httptest.NewRequest("GET", tc.target, nil))
+
+			assert.Equal(t, w.Code, http.StatusInternalServerError)
+			decoder := json.NewDecoder(w.Body)
+			response := ErrorResponse{}
+			assert.NoError(t, decoder.Decode(&response))
+			assert.Equal(t, response.Error, "query failed")
+			assert.False(t, decoder.More(), "only one response must be written")
+			assert.Equal(t, conn.commits, 0)
+			assert.Equal(t, conn.rollbacks, 1)
+		})
+	}
+}
@@ -1,12 +1,12 @@
 package server
 
 import (
-	"context"
 	"database/sql"
 	"fmt"
 	"net/http"
 
-	"github.com/segmentio/kafka-go"
+	"github.com/bakurvik/mylib/library/internal/auth"
+	"github.com/golang-jwt/jwt/v5"
 	httpSwagger "github.com/swaggo/http-swagger"
 )
 
@@ -17,40 +17,52 @@ const (
 	ApiBooksPath         = "/api/books"
 	ApiBooksSearchPath   = "/api/books/search"
 	AdminBooksPath       = "/admin/books"
+	AdminGenresPath      = "/admin/genres"
 	PingPath             = "/ping"
 )
 
-type KafkaWriter interface {
-	WriteMessages(ctx context.Context, msgs ...kafka.Message) error
-}
-
 type ApiConfig struct {
 	DB                    *sql.DB
 	MaxSearchBooksLimit   int
 	MaxSearchAuthorsLimit int
-	AuthorsKafkaWriter    KafkaWriter
+	MaxPageLimit          int
+	FuzzySearchThreshold  float64
+	// TokenKeys looks up public keys of access tokens, usually jwks.Cache.Keyfunc
+	TokenKeys jwt.Keyfunc
+	// PersonalTokens checks personal access tokens, they are rejected if it is nil
+	PersonalTokens auth.PersonalTokenChecker
 }
 
+// Handle registers library routes. Reading the catalogue is public, changing it requires editor role
+// and deleting authors or books and managing genres requires admin role.
 func Handle(sm *http.ServeMux, apiCfg *ApiConfig) {
 	// Ping
 	sm.HandleFunc("GET "+PingPath, apiCfg.HandlePing)
 
 	// Authors
-	sm.HandleFunc("POST "+ApiAuthorsPath, apiCfg.HandlePostApiAuthors)
+	sm.HandleFunc("POST "+ApiAuthorsPath, apiCfg.requireRole(auth.RoleEditor, apiCfg.HandlePostApiAuthors))
 	sm.HandleFunc("GET "+ApiAuthorsPath, apiCfg.HandleGetApiAuthors)
 	sm.HandleFunc(fmt.Sprintf("GET %v/{id}", ApiAuthorsPath), apiCfg.HandleGetApiAuthorsID)
-	sm.HandleFunc(fmt.Sprintf("DELETE %v/{id}", AdminAuthorsPath), apiCfg.HandleDeleteAdminAuthors)
-	sm.HandleFunc("PUT "+ApiAuthorsPath, apiCfg.HandlePutApiAuthors)
+	sm.HandleFunc(fmt.Sprintf("DELETE %v/{id}", AdminAuthorsPath), apiCfg.requireRole(auth.RoleAdmin, apiCfg.HandleDeleteAdminAuthors))
+	sm.HandleFunc("PUT "+ApiAuthorsPath, apiCfg.requireRole(auth.RoleEditor, apiCfg.HandlePutApiAuthors))
 	sm.HandleFunc(fmt.Sprintf("GET %v/{id}/books", ApiAuthorsPath), apiCfg.HandleGetApiAuthorsBooks)
 	sm.HandleFunc("GET "+ApiAuthorsSearchPath, apiCfg.HandleGetApiAuthorsSearch)
 
 	// Books
-	sm.HandleFunc("POST "+ApiBooksPath, apiCfg.HandlePostApiBooks)
-	sm.HandleFunc("PUT "+ApiBooksPath, apiCfg.HandlePutApiBooks)
-	sm.HandleFunc(fmt.Sprintf("DELETE %v/{id}", AdminBooksPath), apiCfg.HandleDeleteAdminBooks)
+	sm.HandleFunc("POST "+ApiBooksPath, apiCfg.requireRole(auth.RoleEditor, apiCfg.HandlePostApiBooks))
+	sm.HandleFunc("PUT "+ApiBooksPath, apiCfg.requireRole(auth.RoleEditor, apiCfg.HandlePutApiBooks))
+	sm.HandleFunc("GET "+ApiBooksPath, apiCfg.HandleGetApiBooks)
+	sm.HandleFunc(fmt.Sprintf("GET %v/{id}", ApiBooksPath), apiCfg.HandleGetApiBooksID)
+	sm.HandleFunc(fmt.Sprintf("DELETE %v/{id}", AdminBooksPath), apiCfg.requireRole(auth.RoleAdmin, apiCfg.HandleDeleteAdminBooks))
 	sm.HandleFunc("POST "+ApiBooksSearchPath, apiCfg.HandlePostApiBooksSearch)
 	sm.HandleFunc("GET "+ApiBooksSearchPath, apiCfg.HandleGetApiBooksSearch)
 
+	// Genres
+	sm.HandleFunc("POST "+AdminGenresPath, apiCfg.requireRole(auth.RoleAdmin, apiCfg.HandlePostAdminGenres))
+	sm.HandleFunc("GET "+AdminGenresPath, apiCfg.requireRole(auth.RoleEditor, apiCfg.HandleGetAdminGenres))
+	sm.HandleFunc("PUT "+AdminGenresPath, apiCfg.requireRole(auth.RoleAdmin, apiCfg.HandlePutAdminGenres))
+	sm.HandleFunc(fmt.Sprintf("DELETE %v/{id}", AdminGenresPath), apiCfg.requireRole(auth.RoleAdmin, apiCfg.HandleDeleteAdminGenres))
+
 	// Swagger
 	sm.Handle("/swagger/", httpSwagger.WrapHandler)
 }
@@ -1,13 +1,18 @@
 package main
 
 import (
+	"context"
 	"log"
 	"net/http"
 	"os"
 	"strconv"
+	"time"
 
 	common "github.com/bakurvik/mylib-common"
+	"github.com/bakurvik/mylib/library/internal/auth"
 	"github.com/bakurvik/mylib/library/internal/server"
+	"github.com/bakurvik/mylib/shared/jwks"
+	"github.com/bakurvik/mylib/shared/outbox"
 	"github.com/segmentio/kafka-go"
 
 	_ "github.com/bakurvik/mylib/library/docs"
@@ -25,9 +30,20 @@ import (
 // @host localhost:8080
 // @BasePath /
 
+// @securityDefinitions.apikey BearerAuth
+// @in header
+// @name Authorization
+// @description Access token issued by users service: "Bearer {token}"
+
 const (
 	defaultMaxSearchBooksLimit   = 10
 	defaultMaxSearchAuthorsLimit = 10
+	defaultMaxPageLimit          = 100
+	defaultOutboxBatchSize       = 100
+	defaultOutboxRelayPeriod     = time.Second
+	outboxBatchTimeout           = 10 * time.Millisecond
+	defaultOutboxRetention       = 7 * 24 * time.Hour
+	defaultFuzzySearchThreshold  = 0.5
 )
 
 func getLimit(varName string, defaultValue int) int {
@@ -39,6 +55,42 @@ func getLimit(varName string, defaultValue int) int {
 	return limit
 }
 
+func getOutboxRelayPeriod() time.Duration {
+	period, err := time.ParseDuration(os.Getenv("OUTBOX_RELAY_PERIOD"))
+	if err != nil || period <= 0 {
+		log.Print("Invalid outbox relay period: ", os.Getenv("OUTBOX_RELAY_PERIOD"))
+		return defaultOutboxRelayPeriod
+	}
+	return period
+}
+
+func getOutboxRetention() time.Duration {
+	retention, err := time.ParseDuration(os.Getenv("OUTBOX_RETENTION"))
+	if err != nil || retention < 0 {
+		log.Print("Invalid outbox retention: ", os.Getenv("OUTBOX_RETENTION"))
+		return defaultOutboxRetention
+	}
+	return retention
+}
+
+func getFuzzySearchThreshold() float64 {
+	threshold, err := strconv.ParseFloat(os.Getenv("FUZZY_SEARCH_THRESHOLD"), 64)
+	if err != nil || threshold <= 0 || threshold > 1 {
+		log.Print("Invalid fuzzy search threshold: ", os.Getenv("FUZZY_SEARCH_THRESHOLD"))
+		return defaultFuzzySearchThreshold
+	}
+	return threshold
+}
+
+func getJWKSCacheTTL() time.Duration {
+	ttl, err := time.ParseDuration(os.Getenv("JWKS_CACHE_TTL"))
+	if err != nil || ttl <= 0 {
+		log.Print("Invalid JWKS cache TTL: ", os.Getenv("JWKS_CACHE_TTL"))
+		return jwks.DefaultTTL
+	}
+	return ttl
+}
+
 func main() {
 	db, err := common.SetupDB("./.env")
 	if err != nil {
@@ -46,13 +98,34 @@ func main() {
 	}
 
 	authorsKafkaWriter := kafka.NewWriter(kafka.WriterConfig{
-		Brokers: []string{"localhost:9092"},
-		Topic:   "authors",
+		Brokers:      []string{"localhost:9092"},
+		Topic:        server.AuthorsTopic,
+		BatchTimeout: outboxBatchTimeout,
 	})
 	defer authorsKafkaWriter.Close()
 
+	booksKafkaWriter := kafka.NewWriter(kafka.WriterConfig{
+		Brokers:      []string{"localhost:9092"},
+		Topic:        server.BooksTopic,
+		BatchTimeout: outboxBatchTimeout,
+	})
+	defer booksKafkaWriter.Close()
+
+	relay := outbox.Relay{
+		DB:        db,
+		Writers:   map[string]outbox.KafkaWriter{server.AuthorsTopic: authorsKafkaWriter, server.BooksTopic: booksKafkaWriter},
+		BatchSize: getLimit("OUTBOX_BATCH_SIZE", defaultOutboxBatchSize),
+		Retention: getOutboxRetention(),
+	}
+	go relay.Run(context.Background(), &outbox.TimeTicker{T: time.NewTicker(getOutboxRelayPeriod())})
+
 	sm := http.NewServeMux()
-	apiCfg := server.ApiConfig{DB: db, MaxSearchBooksLimit: getLimit("MAX_SEARCH_BOOKS_LIMIT", defaultMaxSearchBooksLimit), MaxSearchAuthorsLimit: getLimit("MAX_SEARCH_AUTHORS_LIMIT", defaultMaxSearchAuthorsLimit), AuthorsKafkaWriter: authorsKafkaWriter}
+	apiCfg := server.ApiConfig{DB: db, MaxSearchBooksLimit: getLimit("MAX_SEARCH_BOOKS_LIMIT", defaultMaxSearchBooksLimit), MaxSearchAuthorsLimit: getLimit("MAX_SEARCH_AUTHORS_LIMIT", defaultMaxSearchAuthorsLimit), MaxPageLimit: getLimit("MAX_PAGE_LIMIT", defaultMaxPageLimit), FuzzySearchThreshold: getFuzzySearchThreshold(), TokenKeys: jwks.NewCache(os.Getenv("JWKS_URL"), getJWKSCacheTTL()).Keyfunc}
+	if usersServiceHost := os.Getenv("USERS_SERVICE_HOST"); usersServiceHost != "" {
+		apiCfg.PersonalTokens = auth.NewUsersPersonalTokenChecker(usersServiceHost)
+	} else {
+		log.Print("USERS_SERVICE_HOST is not set, personal access tokens are rejected")
+	}
 	server.Handle(sm, &apiCfg)
 
 	s := http.Server{
@@ -0,0 +1,3 @@
+-- name: AddBookGenres :exec
+INSERT INTO book_genres (book_id, genre_id)
+SELECT @book, UNNEST(@genres::UUID[]);
@@ -0,0 +1,3 @@
+-- name: CheckGenres :many
+SELECT id FROM genres
+WHERE id = ANY(@ids::uuid[]);
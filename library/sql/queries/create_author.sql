@@ -1,6 +1,6 @@
 -- name: CreateAuthor :one
-INSERT INTO authors (id, full_name, birth_date, death_date, created_at, updated_at)
+INSERT INTO authors (id, full_name, birth_date, death_date, language, created_at, updated_at)
 VALUES (
-    gen_random_uuid(), $1, $2, $3, NOW(), NOW()
+    gen_random_uuid(), $1, $2, $3, $4, NOW(), NOW()
 )
 RETURNING id;
\ No newline at end of file
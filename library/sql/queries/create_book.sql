@@ -1,6 +1,6 @@
 -- name: CreateBook :one
-INSERT INTO books (id, title, created_at, updated_at)
+INSERT INTO books (id, title, isbn, publication_year, language, page_count, description, created_at, updated_at)
 VALUES (
-    gen_random_uuid(), $1, NOW(), NOW()
+    gen_random_uuid(), $1, $2, $3, $4, $5, $6, NOW(), NOW()
 )
-RETURNING id;
\ No newline at end of file
+RETURNING id;
@@ -0,0 +1,6 @@
+-- name: CreateGenre :one
+INSERT INTO genres (id, name, created_at, updated_at)
+VALUES (
+    gen_random_uuid(), $1, NOW(), NOW()
+)
+RETURNING id;
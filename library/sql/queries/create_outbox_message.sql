@@ -0,0 +1,3 @@
+-- name: CreateOutboxMessage :exec
+INSERT INTO outbox (topic, message_key, payload, created_at)
+VALUES ($1, $2, $3, NOW());
@@ -1,2 +1,2 @@
--- name: DeleteBook :exec
+-- name: DeleteBook :execrows
 DELETE FROM books WHERE id = $1;
\ No newline at end of file
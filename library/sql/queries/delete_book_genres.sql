@@ -0,0 +1,2 @@
+-- name: DeleteBookGenres :exec
+DELETE FROM book_genres WHERE book_id = $1;
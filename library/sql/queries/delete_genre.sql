@@ -0,0 +1,2 @@
+-- name: DeleteGenre :exec
+DELETE FROM genres WHERE id = $1;
@@ -1,3 +1,3 @@
 -- name: GetAuthor :one
-SELECT full_name, birth_date, death_date FROM authors
+SELECT full_name, birth_date, death_date, language FROM authors
 WHERE id = $1;
\ No newline at end of file
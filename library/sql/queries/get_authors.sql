@@ -1,2 +0,0 @@
--- name: GetAuthors :many
-SELECT id, full_name FROM authors;
\ No newline at end of file
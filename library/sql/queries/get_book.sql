@@ -1,3 +0,0 @@
--- name: GetBook :one
-SELECT title FROM books
-WHERE id = $1;
\ No newline at end of file
@@ -0,0 +1,5 @@
+-- name: GetBookAuthorsInfo :many
+SELECT a.id, a.full_name FROM book_authors ba
+JOIN authors a ON ba.author_id = a.id
+WHERE ba.book_id = $1
+ORDER BY a.full_name;
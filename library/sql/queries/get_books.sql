@@ -1,3 +1,3 @@
 -- name: GetBooks :many
-SELECT id, title FROM books
+SELECT id, title, isbn, publication_year, language, page_count, description FROM books
 WHERE id IN (SELECT UNNEST($1::UUID[]));
\ No newline at end of file
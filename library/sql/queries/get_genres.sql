@@ -0,0 +1,3 @@
+-- name: GetGenres :many
+SELECT id, name FROM genres
+ORDER BY name;
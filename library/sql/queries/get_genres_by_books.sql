@@ -0,0 +1,5 @@
+-- name: GetGenresByBooks :many
+SELECT bg.book_id, g.id, g.name FROM book_genres bg
+JOIN genres g ON bg.genre_id = g.id
+WHERE bg.book_id IN (SELECT UNNEST($1::UUID[]))
+ORDER BY g.name;
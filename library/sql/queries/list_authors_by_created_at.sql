@@ -0,0 +1,6 @@
+-- name: ListAuthorsByCreatedAt :many
+SELECT id, full_name, created_at FROM authors
+WHERE (sqlc.narg('created_after')::TIMESTAMP IS NULL OR created_at >= sqlc.narg('created_after')::TIMESTAMP)
+    AND (sqlc.narg('cursor_id')::UUID IS NULL OR (created_at, id) < (sqlc.narg('cursor_created_at')::TIMESTAMP, sqlc.narg('cursor_id')::UUID))
+ORDER BY created_at DESC, id DESC
+LIMIT sqlc.arg('limit');
@@ -0,0 +1,6 @@
+-- name: ListAuthorsByName :many
+SELECT id, full_name, created_at FROM authors
+WHERE (sqlc.narg('created_after')::TIMESTAMP IS NULL OR created_at >= sqlc.narg('created_after')::TIMESTAMP)
+    AND (sqlc.narg('cursor_id')::UUID IS NULL OR (full_name, id) > (sqlc.narg('cursor_full_name')::TEXT, sqlc.narg('cursor_id')::UUID))
+ORDER BY full_name, id
+LIMIT sqlc.arg('limit');
@@ -0,0 +1,7 @@
+-- name: ListBooksByCreatedAt :many
+SELECT b.id, b.title, b.created_at FROM books b
+WHERE (sqlc.narg('author_id')::UUID IS NULL OR b.id IN (SELECT ba.book_id FROM book_authors ba WHERE ba.author_id = sqlc.narg('author_id')::UUID))
+    AND (sqlc.narg('created_after')::TIMESTAMP IS NULL OR b.created_at >= sqlc.narg('created_after')::TIMESTAMP)
+    AND (sqlc.narg('cursor_id')::UUID IS NULL OR (b.created_at, b.id) < (sqlc.narg('cursor_created_at')::TIMESTAMP, sqlc.narg('cursor_id')::UUID))
+ORDER BY b.created_at DESC, b.id DESC
+LIMIT sqlc.arg('limit');
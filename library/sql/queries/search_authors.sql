@@ -1,5 +1,5 @@
 -- name: SearchAuthors :many
-SELECT id, full_name, ts_rank(tsv, plainto_tsquery('english', $1)) AS rank
-FROM authors WHERE tsv @@ plainto_tsquery('english', $1)
+SELECT id, full_name, ts_rank(tsv, plainto_tsquery(text_search_config(coalesce(sqlc.narg('lang')::TEXT, language)), @query::TEXT)) AS rank
+FROM authors WHERE tsv @@ plainto_tsquery(text_search_config(coalesce(sqlc.narg('lang')::TEXT, language)), @query::TEXT)
 ORDER BY rank DESC
-LIMIT $2;
+LIMIT sqlc.arg('limit');
@@ -0,0 +1,5 @@
+-- name: SearchAuthorsFuzzy :many
+SELECT id, full_name, (ts_rank(tsv, plainto_tsquery(text_search_config(coalesce(sqlc.narg('lang')::TEXT, language)), @query::TEXT)) + word_similarity(@query::TEXT, full_name))::REAL AS rank
+FROM authors WHERE tsv @@ plainto_tsquery(text_search_config(coalesce(sqlc.narg('lang')::TEXT, language)), @query::TEXT) OR @query::TEXT <% full_name
+ORDER BY rank DESC
+LIMIT sqlc.arg('limit');
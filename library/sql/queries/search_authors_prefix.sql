@@ -0,0 +1,5 @@
+-- name: SearchAuthorsPrefix :many
+SELECT id, full_name, ts_rank(tsv, to_tsquery(text_search_config(coalesce(sqlc.narg('lang')::TEXT, language)), @query::TEXT)) AS rank
+FROM authors WHERE tsv @@ to_tsquery(text_search_config(coalesce(sqlc.narg('lang')::TEXT, language)), @query::TEXT)
+ORDER BY rank DESC
+LIMIT sqlc.arg('limit');
@@ -1,5 +1,6 @@
 -- name: SearchBooks :many
-SELECT id, title, ts_rank(tsv, plainto_tsquery('english', $1)) AS rank
-FROM books WHERE tsv @@ plainto_tsquery('english', $1)
+SELECT id, title, ts_rank(tsv, plainto_tsquery(text_search_config(coalesce(sqlc.narg('lang')::TEXT, language)), @query::TEXT)) AS rank,
+    ts_headline(text_search_config(coalesce(sqlc.narg('lang')::TEXT, language)), concat_ws('. ', title, book_authors_text(id), description), plainto_tsquery(text_search_config(coalesce(sqlc.narg('lang')::TEXT, language)), @query::TEXT))::TEXT AS headline
+FROM books WHERE tsv @@ plainto_tsquery(text_search_config(coalesce(sqlc.narg('lang')::TEXT, language)), @query::TEXT)
 ORDER BY rank DESC
-LIMIT $2;
+LIMIT sqlc.arg('limit');
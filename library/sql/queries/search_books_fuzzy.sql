@@ -0,0 +1,6 @@
+-- name: SearchBooksFuzzy :many
+SELECT id, title, (ts_rank(tsv, plainto_tsquery(text_search_config(coalesce(sqlc.narg('lang')::TEXT, language)), @query::TEXT)) + word_similarity(@query::TEXT, title))::REAL AS rank,
+    ts_headline(text_search_config(coalesce(sqlc.narg('lang')::TEXT, language)), concat_ws('. ', title, book_authors_text(id), description), plainto_tsquery(text_search_config(coalesce(sqlc.narg('lang')::TEXT, language)), @query::TEXT))::TEXT AS headline
+FROM books WHERE tsv @@ plainto_tsquery(text_search_config(coalesce(sqlc.narg('lang')::TEXT, language)), @query::TEXT) OR @query::TEXT <% title
+ORDER BY rank DESC
+LIMIT sqlc.arg('limit');
@@ -0,0 +1,6 @@
+-- name: SearchBooksPrefix :many
+SELECT id, title, ts_rank(tsv, to_tsquery(text_search_config(coalesce(sqlc.narg('lang')::TEXT, language)), @query::TEXT)) AS rank,
+    ts_headline(text_search_config(coalesce(sqlc.narg('lang')::TEXT, language)), concat_ws('. ', title, book_authors_text(id), description), to_tsquery(text_search_config(coalesce(sqlc.narg('lang')::TEXT, language)), @query::TEXT))::TEXT AS headline
+FROM books WHERE tsv @@ to_tsquery(text_search_config(coalesce(sqlc.narg('lang')::TEXT, language)), @query::TEXT)
+ORDER BY rank DESC
+LIMIT sqlc.arg('limit');
@@ -0,0 +1,2 @@
+-- name: SetWordSimilarityThreshold :exec
+SELECT set_config('pg_trgm.word_similarity_threshold', @threshold::TEXT, true);
@@ -3,5 +3,6 @@ UPDATE authors SET
     full_name = $2,
     birth_date = $3,
     death_date = $4,
+    language = $5,
     updated_at = NOW()
 WHERE id = $1;
\ No newline at end of file
@@ -1,4 +1,11 @@
 -- name: UpdateBook :one
-UPDATE books SET title = $2, updated_at = NOW()
+UPDATE books SET
+    title = $2,
+    isbn = $3,
+    publication_year = $4,
+    language = $5,
+    page_count = $6,
+    description = $7,
+    updated_at = NOW()
 WHERE id = $1
-RETURNING 1;
\ No newline at end of file
+RETURNING 1;
@@ -0,0 +1,3 @@
+-- name: UpdateGenre :execrows
+UPDATE genres SET name = $2, updated_at = NOW()
+WHERE id = $1;
@@ -0,0 +1,13 @@
+-- +goose Up
+CREATE INDEX IF NOT EXISTS idx_books_title_id ON books(title, id);
+CREATE INDEX IF NOT EXISTS idx_books_created_at_id ON books(created_at, id);
+CREATE INDEX IF NOT EXISTS idx_book_authors_author_id ON book_authors(author_id);
+CREATE INDEX IF NOT EXISTS idx_authors_full_name_id ON authors(full_name, id);
+CREATE INDEX IF NOT EXISTS idx_authors_created_at_id ON authors(created_at, id);
+
+-- +goose Down
+DROP INDEX IF EXISTS idx_authors_created_at_id;
+DROP INDEX IF EXISTS idx_authors_full_name_id;
+DROP INDEX IF EXISTS idx_book_authors_author_id;
+DROP INDEX IF EXISTS idx_books_created_at_id;
+DROP INDEX IF EXISTS idx_books_title_id;
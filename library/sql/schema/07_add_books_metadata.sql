@@ -0,0 +1,40 @@
+-- +goose Up
+ALTER TABLE books
+    ADD COLUMN isbn TEXT,
+    ADD COLUMN publication_year INTEGER,
+    ADD COLUMN language TEXT,
+    ADD COLUMN page_count INTEGER CHECK (page_count > 0),
+    ADD COLUMN description TEXT;
+
+CREATE UNIQUE INDEX idx_books_isbn ON books(isbn) WHERE isbn IS NOT NULL;
+
+CREATE TABLE IF NOT EXISTS genres(
+    id UUID PRIMARY KEY,
+    name TEXT NOT NULL UNIQUE,
+    created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMP NOT NULL DEFAULT NOW()
+);
+
+CREATE TABLE IF NOT EXISTS book_genres(
+    book_id UUID NOT NULL REFERENCES books(id) ON DELETE CASCADE,
+    genre_id UUID NOT NULL REFERENCES genres(id) ON DELETE CASCADE,
+    created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    PRIMARY KEY (book_id, genre_id)
+);
+
+CREATE INDEX idx_book_genres_genre_id ON book_genres(genre_id);
+
+-- +goose Down
+DROP INDEX IF EXISTS idx_book_genres_genre_id;
+
+DROP TABLE IF EXISTS book_genres;
+DROP TABLE IF EXISTS genres;
+
+DROP INDEX IF EXISTS idx_books_isbn;
+
+ALTER TABLE books
+    DROP COLUMN IF EXISTS isbn,
+    DROP COLUMN IF EXISTS publication_year,
+    DROP COLUMN IF EXISTS language,
+    DROP COLUMN IF EXISTS page_count,
+    DROP COLUMN IF EXISTS description;
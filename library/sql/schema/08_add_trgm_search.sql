@@ -0,0 +1,9 @@
+-- +goose Up
+CREATE EXTENSION IF NOT EXISTS pg_trgm;
+
+CREATE INDEX IF NOT EXISTS idx_books_title_trgm ON books USING GIN(title gin_trgm_ops);
+CREATE INDEX IF NOT EXISTS idx_authors_full_name_trgm ON authors USING GIN(full_name gin_trgm_ops);
+
+-- +goose Down
+DROP INDEX IF EXISTS idx_authors_full_name_trgm;
+DROP INDEX IF EXISTS idx_books_title_trgm;
@@ -0,0 +1,131 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE FUNCTION book_authors_text(UUID) RETURNS TEXT AS $$
+  SELECT string_agg(a.full_name, ', ' ORDER BY a.full_name) FROM book_authors ba
+  JOIN authors a ON a.id = ba.author_id
+  WHERE ba.book_id = $1;
+$$ LANGUAGE sql STABLE;
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+CREATE FUNCTION book_genres_text(UUID) RETURNS TEXT AS $$
+  SELECT string_agg(g.name, ', ' ORDER BY g.name) FROM book_genres bg
+  JOIN genres g ON g.id = bg.genre_id
+  WHERE bg.book_id = $1;
+$$ LANGUAGE sql STABLE;
+-- +goose StatementEnd
+
+-- Title has weight A, author names B, description and genres C.
+-- +goose StatementBegin
+CREATE FUNCTION books_search_document(UUID, TEXT, TEXT) RETURNS tsvector AS $$
+  SELECT setweight(to_tsvector('english', coalesce($2, '')), 'A') ||
+    setweight(to_tsvector('english', coalesce(book_authors_text($1), '')), 'B') ||
+    setweight(to_tsvector('english', concat_ws(' ', $3, book_genres_text($1))), 'C');
+$$ LANGUAGE sql STABLE;
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+CREATE FUNCTION refresh_books_tsv(UUID[]) RETURNS void AS $$
+  UPDATE books SET tsv = books_search_document(id, title, description) WHERE id = ANY($1);
+$$ LANGUAGE sql;
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+CREATE OR REPLACE FUNCTION books_tsv_trigger() RETURNS trigger AS $$
+BEGIN
+  NEW.tsv := books_search_document(NEW.id, NEW.title, NEW.description);
+  RETURN NEW;
+END;
+$$ LANGUAGE plpgsql;
+-- +goose StatementEnd
+
+DROP TRIGGER IF EXISTS trigger_books_tsv ON books;
+CREATE TRIGGER trigger_books_tsv
+BEFORE INSERT OR UPDATE OF title, description ON books
+FOR EACH ROW EXECUTE FUNCTION books_tsv_trigger();
+
+-- Used for book_authors and book_genres, both reference books by book_id.
+-- +goose StatementBegin
+CREATE FUNCTION book_links_tsv_trigger() RETURNS trigger AS $$
+BEGIN
+  IF TG_OP IN ('UPDATE', 'DELETE') THEN
+    PERFORM refresh_books_tsv(ARRAY[OLD.book_id]);
+  END IF;
+  IF TG_OP IN ('INSERT', 'UPDATE') THEN
+    PERFORM refresh_books_tsv(ARRAY[NEW.book_id]);
+  END IF;
+  RETURN NULL;
+END;
+$$ LANGUAGE plpgsql;
+-- +goose StatementEnd
+
+CREATE TRIGGER trigger_book_authors_tsv
+AFTER INSERT OR UPDATE OR DELETE ON book_authors
+FOR EACH ROW EXECUTE FUNCTION book_links_tsv_trigger();
+
+CREATE TRIGGER trigger_book_genres_tsv
+AFTER INSERT OR UPDATE OR DELETE ON book_genres
+FOR EACH ROW EXECUTE FUNCTION book_links_tsv_trigger();
+
+-- +goose StatementBegin
+CREATE FUNCTION authors_books_tsv_trigger() RETURNS trigger AS $$
+BEGIN
+  PERFORM refresh_books_tsv(ARRAY(SELECT book_id FROM book_authors WHERE author_id = NEW.id));
+  RETURN NULL;
+END;
+$$ LANGUAGE plpgsql;
+-- +goose StatementEnd
+
+CREATE TRIGGER trigger_authors_books_tsv
+AFTER UPDATE OF full_name ON authors
+FOR EACH ROW WHEN (OLD.full_name IS DISTINCT FROM NEW.full_name)
+EXECUTE FUNCTION authors_books_tsv_trigger();
+
+-- +goose StatementBegin
+CREATE FUNCTION genres_books_tsv_trigger() RETURNS trigger AS $$
+BEGIN
+  PERFORM refresh_books_tsv(ARRAY(SELECT book_id FROM book_genres WHERE genre_id = NEW.id));
+  RETURN NULL;
+END;
+$$ LANGUAGE plpgsql;
+-- +goose StatementEnd
+
+CREATE TRIGGER trigger_genres_books_tsv
+AFTER UPDATE OF name ON genres
+FOR EACH ROW WHEN (OLD.name IS DISTINCT FROM NEW.name)
+EXECUTE FUNCTION genres_books_tsv_trigger();
+
+SELECT refresh_books_tsv(ARRAY(SELECT id FROM books));
+
+-- +goose Down
+DROP TRIGGER IF EXISTS trigger_genres_books_tsv ON genres;
+DROP FUNCTION IF EXISTS genres_books_tsv_trigger();
+
+DROP TRIGGER IF EXISTS trigger_authors_books_tsv ON authors;
+DROP FUNCTION IF EXISTS authors_books_tsv_trigger();
+
+DROP TRIGGER IF EXISTS trigger_book_genres_tsv ON book_genres;
+DROP TRIGGER IF EXISTS trigger_book_authors_tsv ON book_authors;
+DROP FUNCTION IF EXISTS book_links_tsv_trigger();
+
+DROP TRIGGER IF EXISTS trigger_books_tsv ON books;
+
+-- +goose StatementBegin
+CREATE OR REPLACE FUNCTION books_tsv_trigger() RETURNS trigger AS $$
+BEGIN
+  NEW.tsv := to_tsvector('english', NEW.title);
+  RETURN NEW;
+END;
+$$ LANGUAGE plpgsql;
+-- +goose StatementEnd
+
+CREATE TRIGGER trigger_books_tsv
+BEFORE INSERT OR UPDATE ON books
+FOR EACH ROW EXECUTE FUNCTION books_tsv_trigger();
+
+UPDATE books SET tsv = to_tsvector('english', title);
+
+DROP FUNCTION IF EXISTS refresh_books_tsv(UUID[]);
+DROP FUNCTION IF EXISTS books_search_document(UUID, TEXT, TEXT);
+DROP FUNCTION IF EXISTS book_genres_text(UUID);
+DROP FUNCTION IF EXISTS book_authors_text(UUID);
@@ -0,0 +1,123 @@
+-- +goose Up
+ALTER TABLE authors ADD COLUMN language TEXT;
+
+-- Maps ISO 639-1 language code to postgres text search config.
+-- Books and authors without language are indexed in english as before, unknown languages fall back to simple config.
+-- +goose StatementBegin
+CREATE FUNCTION text_search_config(TEXT) RETURNS regconfig AS $$
+  SELECT CASE lower($1)
+    WHEN 'ar' THEN 'arabic'
+    WHEN 'da' THEN 'danish'
+    WHEN 'de' THEN 'german'
+    WHEN 'el' THEN 'greek'
+    WHEN 'en' THEN 'english'
+    WHEN 'es' THEN 'spanish'
+    WHEN 'fi' THEN 'finnish'
+    WHEN 'fr' THEN 'french'
+    WHEN 'ga' THEN 'irish'
+    WHEN 'hu' THEN 'hungarian'
+    WHEN 'id' THEN 'indonesian'
+    WHEN 'it' THEN 'italian'
+    WHEN 'lt' THEN 'lithuanian'
+    WHEN 'ne' THEN 'nepali'
+    WHEN 'nl' THEN 'dutch'
+    WHEN 'no' THEN 'norwegian'
+    WHEN 'pt' THEN 'portuguese'
+    WHEN 'ro' THEN 'romanian'
+    WHEN 'ru' THEN 'russian'
+    WHEN 'sv' THEN 'swedish'
+    WHEN 'ta' THEN 'tamil'
+    WHEN 'tr' THEN 'turkish'
+    ELSE CASE WHEN $1 IS NULL THEN 'english' ELSE 'simple' END
+  END::regconfig;
+$$ LANGUAGE sql IMMUTABLE;
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+CREATE OR REPLACE FUNCTION authors_tsv_trigger() RETURNS trigger AS $$
+BEGIN
+  NEW.tsv := to_tsvector(text_search_config(NEW.language), NEW.full_name);
+  RETURN NEW;
+END;
+$$ LANGUAGE plpgsql;
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+CREATE FUNCTION books_search_document(UUID, TEXT, TEXT, TEXT) RETURNS tsvector AS $$
+  SELECT setweight(to_tsvector(text_search_config($4), coalesce($2, '')), 'A') ||
+    setweight(to_tsvector(text_search_config($4), coalesce(book_authors_text($1), '')), 'B') ||
+    setweight(to_tsvector(text_search_config($4), concat_ws(' ', $3, book_genres_text($1))), 'C');
+$$ LANGUAGE sql STABLE;
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+CREATE OR REPLACE FUNCTION refresh_books_tsv(UUID[]) RETURNS void AS $$
+  UPDATE books SET tsv = books_search_document(id, title, description, language) WHERE id = ANY($1);
+$$ LANGUAGE sql;
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+CREATE OR REPLACE FUNCTION books_tsv_trigger() RETURNS trigger AS $$
+BEGIN
+  NEW.tsv := books_search_document(NEW.id, NEW.title, NEW.description, NEW.language);
+  RETURN NEW;
+END;
+$$ LANGUAGE plpgsql;
+-- +goose StatementEnd
+
+DROP TRIGGER IF EXISTS trigger_books_tsv ON books;
+CREATE TRIGGER trigger_books_tsv
+BEFORE INSERT OR UPDATE OF title, description, language ON books
+FOR EACH ROW EXECUTE FUNCTION books_tsv_trigger();
+
+DROP FUNCTION IF EXISTS books_search_document(UUID, TEXT, TEXT);
+
+UPDATE authors SET tsv = to_tsvector(text_search_config(language), full_name);
+SELECT refresh_books_tsv(ARRAY(SELECT id FROM books));
+
+-- +goose Down
+-- +goose StatementBegin
+CREATE FUNCTION books_search_document(UUID, TEXT, TEXT) RETURNS tsvector AS $$
+  SELECT setweight(to_tsvector('english', coalesce($2, '')), 'A') ||
+    setweight(to_tsvector('english', coalesce(book_authors_text($1), '')), 'B') ||
+    setweight(to_tsvector('english', concat_ws(' ', $3, book_genres_text($1))), 'C');
+$$ LANGUAGE sql STABLE;
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+CREATE OR REPLACE FUNCTION refresh_books_tsv(UUID[]) RETURNS void AS $$
+  UPDATE books SET tsv = books_search_document(id, title, description) WHERE id = ANY($1);
+$$ LANGUAGE sql;
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+CREATE OR REPLACE FUNCTION books_tsv_trigger() RETURNS trigger AS $$
+BEGIN
+  NEW.tsv := books_search_document(NEW.id, NEW.title, NEW.description);
+  RETURN NEW;
+END;
+$$ LANGUAGE plpgsql;
+-- +goose StatementEnd
+
+DROP TRIGGER IF EXISTS trigger_books_tsv ON books;
+CREATE TRIGGER trigger_books_tsv
+BEFORE INSERT OR UPDATE OF title, description ON books
+FOR EACH ROW EXECUTE FUNCTION books_tsv_trigger();
+
+DROP FUNCTION IF EXISTS books_search_document(UUID, TEXT, TEXT, TEXT);
+
+-- +goose StatementBegin
+CREATE OR REPLACE FUNCTION authors_tsv_trigger() RETURNS trigger AS $$
+BEGIN
+  NEW.tsv := to_tsvector('english', NEW.full_name);
+  RETURN NEW;
+END;
+$$ LANGUAGE plpgsql;
+-- +goose StatementEnd
+
+UPDATE authors SET tsv = to_tsvector('english', full_name);
+SELECT refresh_books_tsv(ARRAY(SELECT id FROM books));
+
+DROP FUNCTION IF EXISTS text_search_config(TEXT);
+
+ALTER TABLE authors DROP COLUMN IF EXISTS language;
@@ -9,6 +9,7 @@ import (
 	"log"
 	"net/http"
 	"net/http/httptest"
+	"net/url"
 	"os"
 	"sort"
 	"strconv"
@@ -17,6 +18,8 @@ import (
 
 	common "github.com/bakurvik/mylib-common"
 	"github.com/bakurvik/mylib/library/internal/server"
+	"github.com/bakurvik/mylib/shared/messages"
+	"github.com/bakurvik/mylib/shared/outbox"
 	"github.com/google/uuid"
 	"github.com/segmentio/kafka-go"
 
@@ -26,7 +29,7 @@ import (
 
 const (
 	selectAuthors = "SELECT id, full_name, birth_date, death_date, created_at, updated_at FROM authors"
-	insertAuthor  = "INSERT INTO authors(id, full_name, birth_date, death_date, created_at, updated_at) VALUES ($1, $2, $3, $4, $5, $6)"
+	insertAuthor  = "INSERT INTO authors(id, full_name, birth_date, death_date, created_at, updated_at, language) VALUES ($1, $2, $3, $4, $5, $6, $7)"
 )
 
 type author struct {
@@ -36,6 +39,7 @@ type author struct {
 	deathDate sql.NullTime
 	createdAt time.Time
 	updatedAt time.Time
+	language  sql.NullString
 }
 
 type expectedAuthor struct {
@@ -60,16 +64,41 @@ func (w *kafkaMockWriter) WriteMessages(ctx context.Context, msgs ...kafka.Messa
 	return nil
 }
 
-func assertAuthorKafkaMessage(t *testing.T, w server.KafkaWriter, id string, author expectedAuthor) {
-	writer := w.(*kafkaMockWriter)
+func relayOutbox(t *testing.T, db *sql.DB) (*kafkaMockWriter, *kafkaMockWriter) {
+	authorsWriter := &kafkaMockWriter{}
+	booksWriter := &kafkaMockWriter{}
+	relay := outbox.Relay{
+		DB:        db,
+		Writers:   map[string]outbox.KafkaWriter{server.AuthorsTopic: authorsWriter, server.BooksTopic: booksWriter},
+		BatchSize: 100,
+	}
+	_, err := relay.ProcessPending(context.Background())
+	assert.NoError(t, err)
+	return authorsWriter, booksWriter
+}
+
+func assertAuthorKafkaMessage(t *testing.T, writer *kafkaMockWriter, id string, author expectedAuthor, action string) {
 	assert.Equal(t, len(writer.messages), 1)
 	msg := writer.messages[0]
 	assert.Equal(t, msg.key, []byte(id))
-	expectedMSG, err := json.Marshal(common.AuthorMessage{ID: id, FullName: author.fullName, Action: "created"})
+	expectedMSG, err := json.Marshal(common.AuthorMessage{ID: id, FullName: author.fullName, Action: action})
 	assert.NoError(t, err)
 	assert.Equal(t, msg.value, expectedMSG)
 }
 
+func assertBookKafkaMessages(t *testing.T, writer *kafkaMockWriter, expectedMessages []messages.BookMessage) {
+	assert.Equal(t, len(writer.messages), len(expectedMessages))
+	for i, msg := range writer.messages {
+		if i >= len(expectedMessages) {
+			break
+		}
+		assert.Equal(t, msg.key, []byte(expectedMessages[i].ID))
+		expectedMSG, err := json.Marshal(expectedMessages[i])
+		assert.NoError(t, err)
+		assert.Equal(t, msg.value, expectedMSG)
+	}
+}
+
 func assertDateEqual(t *testing.T, timeDate sql.NullTime, expectedDate string) {
 	if expectedDate == "" {
 		assert.False(t, timeDate.Valid)
@@ -94,14 +123,19 @@ func AddAuthorsDB(db *sql.DB, authors []author) {
 	for _, author := range authors {
 		_, err := db.Exec(
 			insertAuthor,
-			author.id, author.fullName, author.birthDate, author.deathDate, author.createdAt, author.updatedAt)
+			author.id, author.fullName, author.birthDate, author.deathDate, author.createdAt, author.updatedAt, author.language)
 		if err != nil {
 			log.Print("Failed to add author to db: ", err)
 		}
 	}
 }
 
-func setupTestServer(db *sql.DB) (*httptest.Server, server.ApiConfig) {
+// setupTestServer starts library server which treats requests without access token as sent by admin.
+func setupTestServer(db *sql.DB) *httptest.Server {
+	return httptest.NewServer(withAdminToken(setupTestHandler(db)))
+}
+
+func setupTestHandler(db *sql.DB) *http.ServeMux {
 	maxSearchBooksLimit, err := strconv.Atoi(os.Getenv("MAX_SEARCH_BOOKS_LIMIT"))
 	if err != nil {
 		log.Print("Invalid MAX_SEARCH_BOOKS_LIMIT value: ", os.Getenv("MAX_SEARCH_BOOKS_LIMIT"))
@@ -111,10 +145,10 @@ func setupTestServer(db *sql.DB) (*httptest.Server, server.ApiConfig) {
 		log.Print("Invalid MAX_SEARCH_AUTHORS_LIMIT value: ", os.Getenv("MAX_SEARCH_AUTHORS_LIMIT"))
 	}
 
-	apiCfg := server.ApiConfig{DB: db, MaxSearchBooksLimit: maxSearchBooksLimit, MaxSearchAuthorsLimit: maxSearcAuthorsLimit, AuthorsKafkaWriter: &kafkaMockWriter{}}
+	apiCfg := server.ApiConfig{DB: db, MaxSearchBooksLimit: maxSearchBooksLimit, MaxSearchAuthorsLimit: maxSearcAuthorsLimit, TokenKeys: testTokenKeys}
 	sm := http.NewServeMux()
 	server.Handle(sm, &apiCfg)
-	return httptest.NewServer(sm), apiCfg
+	return sm
 }
 
 func GetDBAuthors(t *testing.T, db *sql.DB) []author {
@@ -161,12 +195,24 @@ func TestCreateAuthor(t *testing.T) {
 			expectedStatusCode: http.StatusCreated,
 			expectedDBAuthors:  []expectedAuthor{{fullName: "Leo Tolstoy"}},
 		},
+		{
+			name:               "with_language",
+			requestAuthor:      server.RequestAuthor{FullName: "Лев Толстой", Language: "RU"},
+			expectedStatusCode: http.StatusCreated,
+			expectedDBAuthors:  []expectedAuthor{{fullName: "Лев Толстой"}},
+		},
 		{
 			name:               "bad_request",
 			requestAuthor:      server.RequestAuthor{},
 			expectedStatusCode: http.StatusBadRequest,
 			expectedDBAuthors:  nil,
 		},
+		{
+			name:               "invalid_language",
+			requestAuthor:      server.RequestAuthor{FullName: "Leo Tolstoy", Language: "russian"},
+			expectedStatusCode: http.StatusBadRequest,
+			expectedDBAuthors:  nil,
+		},
 	}
 	for _, tc := range testCases {
 		t.Run(tc.name, func(t *testing.T) {
@@ -175,7 +221,7 @@ func TestCreateAuthor(t *testing.T) {
 			defer common.CloseDB(db)
 			cleanupDB(db)
 
-			s, cfg := setupTestServer(db)
+			s := setupTestServer(db)
 			defer s.Close()
 
 			body, _ := json.Marshal(tc.requestAuthor)
@@ -186,8 +232,11 @@ func TestCreateAuthor(t *testing.T) {
 
 			authors := GetDBAuthors(t, db)
 			assertEqual(t, authors, tc.expectedDBAuthors)
+			authorsWriter, _ := relayOutbox(t, db)
 			if tc.expectedDBAuthors != nil {
-				assertAuthorKafkaMessage(t, cfg.AuthorsKafkaWriter, authors[0].id.String(), tc.expectedDBAuthors[0])
+				assertAuthorKafkaMessage(t, authorsWriter, authors[0].id.String(), tc.expectedDBAuthors[0], "created")
+			} else {
+				assert.Equal(t, len(authorsWriter.messages), 0)
 			}
 		})
 	}
@@ -197,12 +246,15 @@ func TestGetAuthors(t *testing.T) {
 	authorID1 := uuid.New()
 	authorID2 := uuid.New()
 	authorID3 := uuid.New()
+	now := time.Now().UTC()
 	type testCase struct {
 		name               string
 		dbAuthors          []author
 		requestAuthor      server.RequestAuthor
+		query              string
 		expectedStatusCode int
 		expectedAuthors    []server.ResponseAuthorShortInfo
+		expectedNextPage   []server.ResponseAuthorShortInfo
 	}
 	testCases := []testCase{
 		{
@@ -227,6 +279,57 @@ func TestGetAuthors(t *testing.T) {
 			expectedStatusCode: http.StatusOK,
 			expectedAuthors:    []server.ResponseAuthorShortInfo{},
 		},
+		{
+			name: "pagination",
+			dbAuthors: []author{
+				{id: authorID1, fullName: "Alexander Pushkin"},
+				{id: authorID2, fullName: "Leo Tolstoy"},
+				{id: authorID3, fullName: "Fyodor Dostoevsky"},
+			},
+			query:              "?limit=2",
+			expectedStatusCode: http.StatusOK,
+			expectedAuthors: []server.ResponseAuthorShortInfo{
+				{FullName: "Alexander Pushkin", ID: authorID1.String()},
+				{FullName: "Fyodor Dostoevsky", ID: authorID3.String()},
+			},
+			expectedNextPage: []server.ResponseAuthorShortInfo{
+				{FullName: "Leo Tolstoy", ID: authorID2.String()},
+			},
+		},
+		{
+			name: "sort_by_created_at",
+			dbAuthors: []author{
+				{id: authorID1, fullName: "Alexander Pushkin", createdAt: now.Add(-time.Hour)},
+				{id: authorID2, fullName: "Leo Tolstoy", createdAt: now},
+				{id: authorID3, fullName: "Fyodor Dostoevsky", createdAt: now.Add(-time.Hour * 48)},
+			},
+			query:              "?sort=created_at&limit=1",
+			expectedStatusCode: http.StatusOK,
+			expectedAuthors: []server.ResponseAuthorShortInfo{
+				{FullName: "Leo Tolstoy", ID: authorID2.String()},
+			},
+			expectedNextPage: []server.ResponseAuthorShortInfo{
+				{FullName: "Alexander Pushkin", ID: authorID1.String()},
+			},
+		},
+		{
+			name: "created_after",
+			dbAuthors: []author{
+				{id: authorID1, fullName: "Alexander Pushkin", createdAt: now},
+				{id: authorID2, fullName: "Leo Tolstoy", createdAt: now.Add(-time.Hour * 48)},
+			},
+			query:              "?created_after=" + now.Format(common.DateFormat),
+			expectedStatusCode: http.StatusOK,
+			expectedAuthors: []server.ResponseAuthorShortInfo{
+				{FullName: "Alexander Pushkin", ID: authorID1.String()},
+			},
+		},
+		{
+			name:               "invalid_sort",
+			dbAuthors:          nil,
+			query:              "?sort=title",
+			expectedStatusCode: http.StatusBadRequest,
+		},
 	}
 	for _, tc := range testCases {
 		t.Run(tc.name, func(t *testing.T) {
@@ -236,19 +339,39 @@ func TestGetAuthors(t *testing.T) {
 			cleanupDB(db)
 			AddAuthorsDB(db, tc.dbAuthors)
 
-			s, _ := setupTestServer(db)
+			s := setupTestServer(db)
 			defer s.Close()
 
-			response, err := http.Get(s.URL + server.ApiAuthorsPath)
+			response, err := http.Get(s.URL + server.ApiAuthorsPath + tc.query)
 			assert.NoError(t, err)
 			defer common.CloseResponseBody(response)
 			assert.Equal(t, tc.expectedStatusCode, response.StatusCode)
+			if tc.expectedStatusCode != http.StatusOK {
+				return
+			}
 
 			decoder := json.NewDecoder(response.Body)
-			responseBody := make([]server.ResponseAuthorShortInfo, 0)
+			responseBody := server.ResponseAuthorsPage{}
 			err = decoder.Decode(&responseBody)
 			assert.NoError(t, err)
-			assert.Equal(t, responseBody, tc.expectedAuthors)
+			assert.Equal(t, responseBody.Items, tc.expectedAuthors)
+			if tc.expectedNextPage == nil {
+				assert.Equal(t, responseBody.NextCursor, "")
+				return
+			}
+
+			separator := "?"
+			if tc.query != "" {
+				separator = "&"
+			}
+			nextResponse, err := http.Get(s.URL + server.ApiAuthorsPath + tc.query + separator + "cursor=" + responseBody.NextCursor)
+			assert.NoError(t, err)
+			defer common.CloseResponseBody(nextResponse)
+			assert.Equal(t, nextResponse.StatusCode, http.StatusOK)
+			nextBody := server.ResponseAuthorsPage{}
+			err = json.NewDecoder(nextResponse.Body).Decode(&nextBody)
+			assert.NoError(t, err)
+			assert.Equal(t, nextBody.Items, tc.expectedNextPage)
 		})
 	}
 }
@@ -304,7 +427,7 @@ func TestGetAuthorsID(t *testing.T) {
 			cleanupDB(db)
 			AddAuthorsDB(db, tc.dbAuthors)
 
-			s, _ := setupTestServer(db)
+			s := setupTestServer(db)
 			defer s.Close()
 
 			response, err := http.Get(fmt.Sprintf("%v%v/{%v}", s.URL, server.ApiAuthorsPath, tc.requestAuthor))
@@ -325,12 +448,16 @@ func TestGetAuthorsID(t *testing.T) {
 
 func TestDeleteAuthor(t *testing.T) {
 	authorID1 := uuid.New()
+	bookID1 := uuid.New()
 	type testCase struct {
-		name               string
-		dbAuthors          []author
-		requestAuthor      string
-		expectedStatusCode int
-		expectedDBAuthors  []expectedAuthor
+		name                      string
+		dbAuthors                 []author
+		dbBooks                   []Book
+		requestAuthor             string
+		expectedStatusCode        int
+		expectedDBAuthors         []expectedAuthor
+		expectedAuthorMessage     bool
+		expectedBookKafkaMessages []messages.BookMessage
 	}
 	testCases := []testCase{
 		{
@@ -338,25 +465,32 @@ func TestDeleteAuthor(t *testing.T) {
 			dbAuthors: []author{
 				{id: authorID1, fullName: "Alexander Pushkin", birthDate: common.ToNullTime("06.06.1799"), deathDate: common.ToNullTime("10.02.1837")},
 			},
-			requestAuthor:      authorID1.String(),
-			expectedStatusCode: http.StatusOK,
-			expectedDBAuthors:  nil,
+			dbBooks:                   []Book{{id: bookID1, title: "Eugene Onegin"}},
+			requestAuthor:             authorID1.String(),
+			expectedStatusCode:        http.StatusOK,
+			expectedDBAuthors:         nil,
+			expectedAuthorMessage:     true,
+			expectedBookKafkaMessages: []messages.BookMessage{{ID: bookID1.String(), Title: "Eugene Onegin", Action: "updated"}},
 		},
 		{
-			name:               "no_author_in_db",
-			dbAuthors:          nil,
-			requestAuthor:      authorID1.String(),
-			expectedStatusCode: http.StatusOK,
-			expectedDBAuthors:  nil,
+			name:                      "no_author_in_db",
+			dbAuthors:                 nil,
+			requestAuthor:             authorID1.String(),
+			expectedStatusCode:        http.StatusOK,
+			expectedDBAuthors:         nil,
+			expectedAuthorMessage:     true,
+			expectedBookKafkaMessages: []messages.BookMessage{},
 		},
 		{
 			name: "invalid_id",
 			dbAuthors: []author{
 				{id: authorID1, fullName: "Alexander Pushkin", birthDate: common.ToNullTime("06.06.1799"), deathDate: common.ToNullTime("10.02.1837")},
 			},
-			requestAuthor:      "invalid_id",
-			expectedStatusCode: http.StatusBadRequest,
-			expectedDBAuthors:  []expectedAuthor{{fullName: "Alexander Pushkin", birthDate: "06.06.1799", deathDate: "10.02.1837"}},
+			requestAuthor:             "invalid_id",
+			expectedStatusCode:        http.StatusBadRequest,
+			expectedDBAuthors:         []expectedAuthor{{fullName: "Alexander Pushkin", birthDate: "06.06.1799", deathDate: "10.02.1837"}},
+			expectedAuthorMessage:     false,
+			expectedBookKafkaMessages: []messages.BookMessage{},
 		},
 	}
 	for _, tc := range testCases {
@@ -366,8 +500,12 @@ func TestDeleteAuthor(t *testing.T) {
 			defer common.CloseDB(db)
 			cleanupDB(db)
 			AddAuthorsDB(db, tc.dbAuthors)
+			AddBooksDB(db, tc.dbBooks)
+			for _, book := range tc.dbBooks {
+				AddBookAuthorsDB(db, book.id.String(), []string{authorID1.String()})
+			}
 
-			s, _ := setupTestServer(db)
+			s := setupTestServer(db)
 			defer s.Close()
 
 			client := &http.Client{}
@@ -381,18 +519,29 @@ func TestDeleteAuthor(t *testing.T) {
 
 			authors := GetDBAuthors(t, db)
 			assertEqual(t, authors, tc.expectedDBAuthors)
+			authorsWriter, booksWriter := relayOutbox(t, db)
+			if tc.expectedAuthorMessage {
+				assertAuthorKafkaMessage(t, authorsWriter, tc.requestAuthor, expectedAuthor{}, "deleted")
+			} else {
+				assert.Equal(t, len(authorsWriter.messages), 0)
+			}
+			assertBookKafkaMessages(t, booksWriter, tc.expectedBookKafkaMessages)
 		})
 	}
 }
 
 func TestUpdateAuthor(t *testing.T) {
 	authorID1 := uuid.New()
+	authorID2 := uuid.New()
+	bookID1 := uuid.New()
 	type testCase struct {
-		name               string
-		requestAuthor      server.RequestAuthorWithID
-		dbAuthors          []author
-		expectedStatusCode int
-		expectedDBAuthors  []expectedAuthor
+		name                      string
+		requestAuthor             server.RequestAuthorWithID
+		dbAuthors                 []author
+		expectedStatusCode        int
+		expectedDBAuthors         []expectedAuthor
+		expectedAuthorMessage     bool
+		expectedBookKafkaMessages []messages.BookMessage
 	}
 	testCases := []testCase{
 		{
@@ -400,9 +549,17 @@ func TestUpdateAuthor(t *testing.T) {
 			requestAuthor: server.RequestAuthorWithID{ID: authorID1.String(), FullName: "Leo Tolstoy", BirthDate: "09.09.1828", DeathDate: "20.11.1910"},
 			dbAuthors: []author{
 				{id: authorID1, fullName: "Alexander Pushkin", birthDate: common.ToNullTime("06.06.1799"), deathDate: common.ToNullTime("10.02.1837")},
+				{id: authorID2, fullName: "Fyodor Dostoevsky"},
 			},
 			expectedStatusCode: http.StatusOK,
-			expectedDBAuthors:  []expectedAuthor{{fullName: "Leo Tolstoy", birthDate: "09.09.1828", deathDate: "20.11.1910"}},
+			expectedDBAuthors: []expectedAuthor{
+				{fullName: "Leo Tolstoy", birthDate: "09.09.1828", deathDate: "20.11.1910"},
+				{fullName: "Fyodor Dostoevsky"},
+			},
+			expectedAuthorMessage: true,
+			expectedBookKafkaMessages: []messages.BookMessage{
+				{ID: bookID1.String(), Title: "Title 1", Authors: []string{"Fyodor Dostoevsky", "Leo Tolstoy"}, Action: "updated"},
+			},
 		},
 		{
 			name:          "not_found",
@@ -410,8 +567,10 @@ func TestUpdateAuthor(t *testing.T) {
 			dbAuthors: []author{
 				{id: authorID1, fullName: "Alexander Pushkin", birthDate: common.ToNullTime("06.06.1799"), deathDate: common.ToNullTime("10.02.1837")},
 			},
-			expectedStatusCode: http.StatusNotFound,
-			expectedDBAuthors:  []expectedAuthor{{fullName: "Alexander Pushkin", birthDate: "06.06.1799", deathDate: "10.02.1837"}},
+			expectedStatusCode:        http.StatusNotFound,
+			expectedDBAuthors:         []expectedAuthor{{fullName: "Alexander Pushkin", birthDate: "06.06.1799", deathDate: "10.02.1837"}},
+			expectedAuthorMessage:     false,
+			expectedBookKafkaMessages: []messages.BookMessage{},
 		},
 		{
 			name:          "invalid_id",
@@ -419,8 +578,10 @@ func TestUpdateAuthor(t *testing.T) {
 			dbAuthors: []author{
 				{id: authorID1, fullName: "Alexander Pushkin", birthDate: common.ToNullTime("06.06.1799"), deathDate: common.ToNullTime("10.02.1837")},
 			},
-			expectedStatusCode: http.StatusBadRequest,
-			expectedDBAuthors:  []expectedAuthor{{fullName: "Alexander Pushkin", birthDate: "06.06.1799", deathDate: "10.02.1837"}},
+			expectedStatusCode:        http.StatusBadRequest,
+			expectedDBAuthors:         []expectedAuthor{{fullName: "Alexander Pushkin", birthDate: "06.06.1799", deathDate: "10.02.1837"}},
+			expectedAuthorMessage:     false,
+			expectedBookKafkaMessages: []messages.BookMessage{},
 		},
 	}
 	for _, tc := range testCases {
@@ -430,8 +591,10 @@ func TestUpdateAuthor(t *testing.T) {
 			defer common.CloseDB(db)
 			cleanupDB(db)
 			AddAuthorsDB(db, tc.dbAuthors)
+			AddBooksDB(db, []Book{{id: bookID1, title: "Title 1"}})
+			AddBookAuthorsDB(db, bookID1.String(), []string{authorID1.String(), authorID2.String()})
 
-			s, _ := setupTestServer(db)
+			s := setupTestServer(db)
 			defer s.Close()
 
 			client := &http.Client{}
@@ -446,6 +609,13 @@ func TestUpdateAuthor(t *testing.T) {
 
 			authors := GetDBAuthors(t, db)
 			assertEqual(t, authors, tc.expectedDBAuthors)
+			authorsWriter, booksWriter := relayOutbox(t, db)
+			if tc.expectedAuthorMessage {
+				assertAuthorKafkaMessage(t, authorsWriter, tc.requestAuthor.ID, expectedAuthor{fullName: tc.requestAuthor.FullName}, "updated")
+			} else {
+				assert.Equal(t, len(authorsWriter.messages), 0)
+			}
+			assertBookKafkaMessages(t, booksWriter, tc.expectedBookKafkaMessages)
 		})
 	}
 }
@@ -490,7 +660,7 @@ func TestGetAuthorBooks(t *testing.T) {
 			AddBookAuthorsDB(db, bookID2.String(), []string{authorID1.String()})
 			AddBookAuthorsDB(db, bookID3.String(), []string{authorID2.String()})
 
-			s, _ := setupTestServer(db)
+			s := setupTestServer(db)
 			defer s.Close()
 
 			response, err := http.Get(fmt.Sprintf("%v%v/{%v}/books", s.URL, server.ApiAuthorsPath, tc.requestAuthor))
@@ -514,7 +684,7 @@ func TestPing_Success(t *testing.T) {
 	assert.NoError(t, err)
 	defer common.CloseDB(db)
 
-	s, _ := setupTestServer(db)
+	s := setupTestServer(db)
 	defer s.Close()
 
 	response, err := http.Get(s.URL + server.PingPath)
@@ -527,9 +697,11 @@ func TestSearchAuthors(t *testing.T) {
 	authorID1 := uuid.New()
 	authorID2 := uuid.New()
 	authorID3 := uuid.New()
+	authorID4 := uuid.New()
 	type testCase struct {
 		name               string
 		requestSearchText  string
+		requestMode        string
 		expectedStatusCode int
 		expectedAuthors    []server.ResponseAuthorShortInfo
 	}
@@ -543,6 +715,57 @@ func TestSearchAuthors(t *testing.T) {
 				{FullName: "Alexander Belyaev", ID: authorID2.String()},
 			},
 		},
+		{
+			name:               "exact mode",
+			requestSearchText:  "Dostoevsky",
+			requestMode:        "exact",
+			expectedStatusCode: http.StatusOK,
+			expectedAuthors:    []server.ResponseAuthorShortInfo{{FullName: "Fyodor Dostoevsky", ID: authorID3.String()}},
+		},
+		{
+			name:               "exact mode with different spelling",
+			requestSearchText:  "Dostoyevsky",
+			expectedStatusCode: http.StatusOK,
+			expectedAuthors:    []server.ResponseAuthorShortInfo{},
+		},
+		{
+			name:               "fuzzy mode with different spelling",
+			requestSearchText:  "Dostoyevsky",
+			requestMode:        "fuzzy",
+			expectedStatusCode: http.StatusOK,
+			expectedAuthors:    []server.ResponseAuthorShortInfo{{FullName: "Fyodor Dostoevsky", ID: authorID3.String()}},
+		},
+		{
+			name:               "fuzzy mode with typo",
+			requestSearchText:  "Tolstoi",
+			requestMode:        "fuzzy",
+			expectedStatusCode: http.StatusOK,
+			expectedAuthors:    []server.ResponseAuthorShortInfo{{FullName: "Leo Tolstoy", ID: authorID4.String()}},
+		},
+		{
+			name:               "prefix mode",
+			requestSearchText:  "Alex",
+			requestMode:        "prefix",
+			expectedStatusCode: http.StatusOK,
+			expectedAuthors: []server.ResponseAuthorShortInfo{
+				{FullName: "Alexander Alexander Pushkin", ID: authorID1.String()},
+				{FullName: "Alexander Belyaev", ID: authorID2.String()},
+			},
+		},
+		{
+			name:               "prefix mode without words",
+			requestSearchText:  "&!",
+			requestMode:        "prefix",
+			expectedStatusCode: http.StatusOK,
+			expectedAuthors:    []server.ResponseAuthorShortInfo{},
+		},
+		{
+			name:               "invalid mode",
+			requestSearchText:  "Alexander",
+			requestMode:        "regex",
+			expectedStatusCode: http.StatusBadRequest,
+			expectedAuthors:    nil,
+		},
 		{
 			name:               "empty search text",
 			requestSearchText:  "",
@@ -560,12 +783,13 @@ func TestSearchAuthors(t *testing.T) {
 				{id: authorID1, fullName: "Alexander Alexander Pushkin"},
 				{id: authorID2, fullName: "Alexander Belyaev"},
 				{id: authorID3, fullName: "Fyodor Dostoevsky"},
+				{id: authorID4, fullName: "Leo Tolstoy"},
 			})
 
-			s, _ := setupTestServer(db)
+			s := setupTestServer(db)
 			defer s.Close()
 
-			response, err := http.Get(s.URL + server.ApiAuthorsSearchPath + "?text=" + tc.requestSearchText)
+			response, err := http.Get(s.URL + server.ApiAuthorsSearchPath + "?text=" + url.QueryEscape(tc.requestSearchText) + "&mode=" + tc.requestMode)
 			assert.NoError(t, err)
 			defer common.CloseResponseBody(response)
 			assert.Equal(t, tc.expectedStatusCode, response.StatusCode)
@@ -580,3 +804,73 @@ func TestSearchAuthors(t *testing.T) {
 		})
 	}
 }
+
+func TestSearchAuthorsLanguage(t *testing.T) {
+	authorID1 := uuid.New()
+	authorID2 := uuid.New()
+	type testCase struct {
+		name               string
+		query              string
+		expectedStatusCode int
+		expectedAuthors    []server.ResponseAuthorShortInfo
+	}
+	testCases := []testCase{
+		{
+			name:               "russian",
+			query:              "?lang=ru&text=" + url.QueryEscape("Толстого"),
+			expectedStatusCode: http.StatusOK,
+			expectedAuthors:    []server.ResponseAuthorShortInfo{{FullName: "Лев Толстой", ID: authorID1.String()}},
+		},
+		{
+			name:               "english",
+			query:              "?lang=en&text=" + url.QueryEscape("Толстого"),
+			expectedStatusCode: http.StatusOK,
+			expectedAuthors:    []server.ResponseAuthorShortInfo{},
+		},
+		{
+			name:               "default_author_language",
+			query:              "?text=" + url.QueryEscape("Толстого"),
+			expectedStatusCode: http.StatusOK,
+			expectedAuthors:    []server.ResponseAuthorShortInfo{{FullName: "Лев Толстой", ID: authorID1.String()}},
+		},
+		{
+			name:               "unknown_language",
+			query:              "?lang=xx&text=" + url.QueryEscape("Kafka"),
+			expectedStatusCode: http.StatusOK,
+			expectedAuthors:    []server.ResponseAuthorShortInfo{{FullName: "Franz Kafka", ID: authorID2.String()}},
+		},
+		{
+			name:               "invalid_language",
+			query:              "?lang=russian&text=" + url.QueryEscape("Толстой"),
+			expectedStatusCode: http.StatusBadRequest,
+			expectedAuthors:    nil,
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			db, err := common.SetupDBByURL("../.env", "TEST_DB_URL")
+			assert.NoError(t, err)
+			defer common.CloseDB(db)
+			cleanupDB(db)
+			AddAuthorsDB(db, []author{
+				{id: authorID1, fullName: "Лев Толстой", language: sql.NullString{String: "ru", Valid: true}},
+				{id: authorID2, fullName: "Franz Kafka", language: sql.NullString{String: "de", Valid: true}},
+			})
+
+			s := setupTestServer(db)
+			defer s.Close()
+
+			response, err := http.Get(s.URL + server.ApiAuthorsSearchPath + tc.query)
+			assert.NoError(t, err)
+			defer common.CloseResponseBody(response)
+			assert.Equal(t, tc.expectedStatusCode, response.StatusCode)
+
+			if tc.expectedAuthors != nil {
+				responseBody := make([]server.ResponseAuthorShortInfo, 0)
+				err = json.NewDecoder(response.Body).Decode(&responseBody)
+				assert.NoError(t, err)
+				assert.Equal(t, responseBody, tc.expectedAuthors)
+			}
+		})
+	}
+}
@@ -7,12 +7,14 @@ import (
 	"fmt"
 	"log"
 	"net/http"
+	"net/http/httptest"
 	"net/url"
 	"testing"
 	"time"
 
 	common "github.com/bakurvik/mylib-common"
 	"github.com/bakurvik/mylib/library/internal/server"
+	"github.com/bakurvik/mylib/shared/messages"
 	"github.com/google/uuid"
 
 	"github.com/lib/pq"
@@ -21,7 +23,8 @@ import (
 
 const (
 	selectBooks       = "SELECT id, title, created_at, updated_at FROM books ORDER BY title"
-	insertBook        = "INSERT INTO books(id, title) VALUES ($1, $2)"
+	insertBook        = "INSERT INTO books(id, title, language) VALUES ($1, $2, $3)"
+	updateBookDesc    = "UPDATE books SET description = $1 WHERE id = $2"
 	selectBookAuthors = "SELECT author_id FROM book_authors ba JOIN authors a ON ba.author_id = a.id WHERE book_id = $1 ORDER BY a.full_name"
 	insertBookAuthors = "INSERT INTO book_authors(book_id, author_id) SELECT $1::uuid, UNNEST($2::text[])::uuid"
 )
@@ -31,13 +34,14 @@ type Book struct {
 	title     string
 	createdAt time.Time
 	updatedAt time.Time
+	language  sql.NullString
 }
 
 func AddBooksDB(db *sql.DB, books []Book) {
 	for _, book := range books {
 		_, err := db.Exec(
 			insertBook,
-			book.id, book.title)
+			book.id, book.title, book.language)
 		if err != nil {
 			log.Print("Failed to add book to db: ", err)
 		}
@@ -52,6 +56,33 @@ func AddBookAuthorsDB(db *sql.DB, bookID string, authors []string) {
 	}
 }
 
+func SetBookDescriptionDB(db *sql.DB, bookID uuid.UUID, description string) {
+	_, err := db.Exec(updateBookDesc, description, bookID)
+	if err != nil {
+		log.Printf("Failed to set book description: %v", err)
+	}
+}
+
+func searchBooks(t *testing.T, s *httptest.Server, text string) []server.ResponseBookFullInfo {
+	response, err := http.Get(s.URL + server.ApiBooksSearchPath + "?text=" + url.QueryEscape(text))
+	assert.NoError(t, err)
+	defer common.CloseResponseBody(response)
+	assert.Equal(t, http.StatusOK, response.StatusCode)
+
+	responseBody := []server.ResponseBookFullInfo{}
+	err = json.NewDecoder(response.Body).Decode(&responseBody)
+	assert.NoError(t, err)
+	return responseBody
+}
+
+func bookIDs(books []server.ResponseBookFullInfo) []string {
+	ids := make([]string, 0, len(books))
+	for _, book := range books {
+		ids = append(ids, book.ID)
+	}
+	return ids
+}
+
 func GetDBBooks(t *testing.T, db *sql.DB) []Book {
 	rows, err := db.Query(selectBooks)
 	if err != nil {
@@ -108,6 +139,7 @@ func TestCreateBook(t *testing.T) {
 		expectedStatusCode      int
 		expectedDBBookTitle     string
 		expectedDBBookAuthorIDs []uuid.UUID
+		expectedBookAuthors     []string
 	}
 	tests := []testCase{
 		{
@@ -117,6 +149,7 @@ func TestCreateBook(t *testing.T) {
 			expectedStatusCode:      http.StatusCreated,
 			expectedDBBookTitle:     "War and Peace",
 			expectedDBBookAuthorIDs: []uuid.UUID{authorID1},
+			expectedBookAuthors:     []string{"Leo Tolstoy"},
 		},
 		{
 			name:                    "several_authors",
@@ -125,6 +158,7 @@ func TestCreateBook(t *testing.T) {
 			expectedStatusCode:      http.StatusCreated,
 			expectedDBBookTitle:     "The Twelve Chairs",
 			expectedDBBookAuthorIDs: []uuid.UUID{authorID1, authorID2},
+			expectedBookAuthors:     []string{"Ilya Ilf", "Yevgeny Petrov"},
 		},
 		{
 			name:                    "no_authors",
@@ -152,7 +186,7 @@ func TestCreateBook(t *testing.T) {
 
 			AddAuthorsDB(db, tc.dbAuthors)
 
-			s, _ := setupTestServer(db)
+			s := setupTestServer(db)
 			defer s.Close()
 
 			body, _ := json.Marshal(tc.requestBook)
@@ -161,12 +195,16 @@ func TestCreateBook(t *testing.T) {
 			defer common.CloseResponseBody(response)
 			assert.Equal(t, tc.expectedStatusCode, response.StatusCode)
 
+			_, booksWriter := relayOutbox(t, db)
 			if tc.expectedDBBookTitle != "" {
 				books := GetDBBooks(t, db)
 				assert.Equal(t, len(books), 1)
 				assert.Equal(t, books[0].title, tc.expectedDBBookTitle)
 				authors := GetDBBookAuthors(t, db, books[0].id)
 				assert.ElementsMatch(t, authors, tc.expectedDBBookAuthorIDs)
+				assertBookKafkaMessages(t, booksWriter, []messages.BookMessage{{ID: books[0].id.String(), Title: tc.expectedDBBookTitle, Authors: tc.expectedBookAuthors, Action: "created"}})
+			} else {
+				assertBookKafkaMessages(t, booksWriter, []messages.BookMessage{})
 			}
 		})
 	}
@@ -186,6 +224,7 @@ func TestUpdateBook(t *testing.T) {
 		expectedStatusCode      int
 		expectedDBBookTitle     string
 		expectedDBBookAuthorIDs []uuid.UUID
+		expectedBookAuthors     []string
 	}
 	tests := []testCase{
 		{
@@ -196,6 +235,7 @@ func TestUpdateBook(t *testing.T) {
 			expectedStatusCode:      http.StatusOK,
 			expectedDBBookTitle:     "The Captain's Daughter",
 			expectedDBBookAuthorIDs: []uuid.UUID{authorID2},
+			expectedBookAuthors:     []string{"Alexander Pushkin"},
 		},
 		{
 			name:                    "merge_authors",
@@ -205,6 +245,7 @@ func TestUpdateBook(t *testing.T) {
 			expectedStatusCode:      http.StatusOK,
 			expectedDBBookTitle:     "The Captain's Daughter",
 			expectedDBBookAuthorIDs: []uuid.UUID{authorID2, authorID3},
+			expectedBookAuthors:     []string{"Alexander Pushkin", "Fyodor Dostoevsky"},
 		},
 		{
 			name:                    "unknown_book",
@@ -235,7 +276,7 @@ func TestUpdateBook(t *testing.T) {
 			AddAuthorsDB(db, tc.dbAuthors)
 			AddBooksDB(db, tc.dbBooks)
 
-			s, _ := setupTestServer(db)
+			s := setupTestServer(db)
 			defer s.Close()
 
 			client := &http.Client{}
@@ -248,12 +289,16 @@ func TestUpdateBook(t *testing.T) {
 			defer common.CloseResponseBody(response)
 			assert.Equal(t, tc.expectedStatusCode, response.StatusCode)
 
+			_, booksWriter := relayOutbox(t, db)
 			if tc.expectedDBBookTitle != "" {
 				books := GetDBBooks(t, db)
 				assert.Equal(t, len(books), 1)
 				assert.Equal(t, books[0].title, tc.expectedDBBookTitle)
 				authors := GetDBBookAuthors(t, db, books[0].id)
 				assert.ElementsMatch(t, authors, tc.expectedDBBookAuthorIDs)
+				assertBookKafkaMessages(t, booksWriter, []messages.BookMessage{{ID: books[0].id.String(), Title: tc.expectedDBBookTitle, Authors: tc.expectedBookAuthors, Action: "updated"}})
+			} else {
+				assertBookKafkaMessages(t, booksWriter, []messages.BookMessage{})
 			}
 		})
 	}
@@ -290,7 +335,7 @@ func TestDeleteBook(t *testing.T) {
 			dbAuthors:          nil,
 			dbBooks:            nil,
 			requestBook:        uuid.NewString(),
-			expectedStatusCode: http.StatusNoContent,
+			expectedStatusCode: http.StatusNotFound,
 		},
 	}
 	for _, tc := range tests {
@@ -303,7 +348,7 @@ func TestDeleteBook(t *testing.T) {
 			AddAuthorsDB(db, tc.dbAuthors)
 			AddBooksDB(db, tc.dbBooks)
 
-			s, _ := setupTestServer(db)
+			s := setupTestServer(db)
 			defer s.Close()
 
 			client := &http.Client{}
@@ -315,9 +360,13 @@ func TestDeleteBook(t *testing.T) {
 			defer common.CloseResponseBody(response)
 			assert.Equal(t, tc.expectedStatusCode, response.StatusCode)
 
+			_, booksWriter := relayOutbox(t, db)
 			if tc.expectedStatusCode == http.StatusNoContent {
 				books := GetDBBooks(t, db)
 				assert.Equal(t, len(books), 0)
+				assertBookKafkaMessages(t, booksWriter, []messages.BookMessage{{ID: tc.requestBook, Action: "deleted"}})
+			} else {
+				assert.Empty(t, booksWriter.messages)
 			}
 		})
 	}
@@ -385,7 +434,7 @@ func TestGetBooks(t *testing.T) {
 			AddBookAuthorsDB(db, book1.String(), []string{author1.String()})
 			AddBookAuthorsDB(db, book2.String(), []string{author1.String(), author2.String()})
 
-			s, _ := setupTestServer(db)
+			s := setupTestServer(db)
 			defer s.Close()
 
 			requestBooks := server.RequestBookIDs{BookIDs: tc.requestedBooks}
@@ -411,6 +460,322 @@ func TestGetBooks(t *testing.T) {
 	}
 }
 
+func TestListBooks(t *testing.T) {
+	book1 := uuid.New()
+	book2 := uuid.New()
+	book3 := uuid.New()
+	author1 := uuid.New()
+	author2 := uuid.New()
+	genreID := uuid.New()
+	book1Info := server.ResponseBookFullInfo{
+		ID:              book1.String(),
+		Title:           "Title 1",
+		Authors:         []string{"Author 1"},
+		PublicationYear: 1869,
+		Language:        "ru",
+		PageCount:       1225,
+		Description:     "Description 1",
+		Genres:          []server.ResponseGenre{{ID: genreID.String(), Name: "Novel"}},
+	}
+
+	type testCase struct {
+		name               string
+		query              string
+		expectedStatusCode int
+		expectedPages      [][]server.ResponseBookFullInfo
+	}
+
+	tests := []testCase{
+		{
+			name:               "single_page",
+			query:              "",
+			expectedStatusCode: http.StatusOK,
+			expectedPages: [][]server.ResponseBookFullInfo{{
+				book1Info,
+				{ID: book2.String(), Title: "Title 2", Authors: []string{"Author 1", "Author 2"}},
+				{ID: book3.String(), Title: "Title 3", Authors: nil},
+			}},
+		},
+		{
+			name:               "several_pages",
+			query:              "?limit=2",
+			expectedStatusCode: http.StatusOK,
+			expectedPages: [][]server.ResponseBookFullInfo{
+				{
+					book1Info,
+					{ID: book2.String(), Title: "Title 2", Authors: []string{"Author 1", "Author 2"}},
+				},
+				{
+					{ID: book3.String(), Title: "Title 3", Authors: nil},
+				},
+			},
+		},
+		{
+			name:               "sort_by_created_at",
+			query:              "?sort=created_at&limit=1",
+			expectedStatusCode: http.StatusOK,
+			expectedPages: [][]server.ResponseBookFullInfo{
+				{{ID: book3.String(), Title: "Title 3", Authors: nil}},
+				{{ID: book2.String(), Title: "Title 2", Authors: []string{"Author 1", "Author 2"}}},
+				{book1Info},
+			},
+		},
+		{
+			name:               "filter_by_author",
+			query:              "?author=" + author2.String(),
+			expectedStatusCode: http.StatusOK,
+			expectedPages: [][]server.ResponseBookFullInfo{{
+				{ID: book2.String(), Title: "Title 2", Authors: []string{"Author 1", "Author 2"}},
+			}},
+		},
+		{
+			name:               "filter_by_created_after",
+			query:              "?created_after=" + time.Now().UTC().Format(common.DateFormat),
+			expectedStatusCode: http.StatusOK,
+			expectedPages: [][]server.ResponseBookFullInfo{{
+				{ID: book2.String(), Title: "Title 2", Authors: []string{"Author 1", "Author 2"}},
+				{ID: book3.String(), Title: "Title 3", Authors: nil},
+			}},
+		},
+		{
+			name:               "invalid_author",
+			query:              "?author=invalid",
+			expectedStatusCode: http.StatusBadRequest,
+		},
+		{
+			name:               "invalid_limit",
+			query:              "?limit=abc",
+			expectedStatusCode: http.StatusBadRequest,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			db, err := common.SetupDBByURL("../.env", "TEST_DB_URL")
+			assert.NoError(t, err)
+			defer common.CloseDB(db)
+			cleanupDB(db)
+			AddBooksDB(db, []Book{{id: book1, title: "Title 1"}, {id: book2, title: "Title 2"}, {id: book3, title: "Title 3"}})
+			_, err = db.Exec("UPDATE books SET created_at = NOW() - INTERVAL '3 days' WHERE id = $1", book1)
+			assert.NoError(t, err)
+			_, err = db.Exec("UPDATE books SET created_at = NOW() + INTERVAL '1 minute' WHERE id = $1", book3)
+			assert.NoError(t, err)
+			AddAuthorsDB(db, []author{{id: author1, fullName: "Author 1"}, {id: author2, fullName: "Author 2"}})
+			AddBookAuthorsDB(db, book1.String(), []string{author1.String()})
+			AddBookAuthorsDB(db, book2.String(), []string{author1.String(), author2.String()})
+			_, err = db.Exec("UPDATE books SET publication_year = 1869, language = 'ru', page_count = 1225, description = 'Description 1' WHERE id = $1", book1)
+			assert.NoError(t, err)
+			AddGenresDB(db, []genre{{id: genreID, name: "Novel"}})
+			AddBookGenresDB(db, book1, []uuid.UUID{genreID})
+
+			s := setupTestServer(db)
+			defer s.Close()
+
+			query := tc.query
+			for i, expectedPage := range tc.expectedPages {
+				response, err := http.Get(s.URL + server.ApiBooksPath + query)
+				assert.NoError(t, err)
+				defer common.CloseResponseBody(response)
+				assert.Equal(t, tc.expectedStatusCode, response.StatusCode)
+
+				responseBody := server.ResponseBooksPage{}
+				err = json.NewDecoder(response.Body).Decode(&responseBody)
+				assert.NoError(t, err)
+				assert.Equal(t, responseBody.Items, expectedPage)
+				if i == len(tc.expectedPages)-1 {
+					assert.Equal(t, responseBody.NextCursor, "")
+					break
+				}
+				separator := "?"
+				if tc.query != "" {
+					separator = "&"
+				}
+				query = tc.query + separator + "cursor=" + responseBody.NextCursor
+			}
+			if len(tc.expectedPages) == 0 {
+				response, err := http.Get(s.URL + server.ApiBooksPath + query)
+				assert.NoError(t, err)
+				defer common.CloseResponseBody(response)
+				assert.Equal(t, tc.expectedStatusCode, response.StatusCode)
+			}
+		})
+	}
+}
+
+func TestBookMetadata(t *testing.T) {
+	genreID1 := uuid.New()
+	genreID2 := uuid.New()
+	otherBookID := uuid.New()
+	type testCase struct {
+		name               string
+		requestBook        server.RequestBook
+		expectedStatusCode int
+		expectedBook       server.ResponseBookFullInfo
+	}
+	testCases := []testCase{
+		{
+			name: "success",
+			requestBook: server.RequestBook{
+				Title: "War and Peace",
+				RequestBookMetadata: server.RequestBookMetadata{
+					Isbn:            "0-306-40615-2",
+					PublicationYear: 1869,
+					Language:        "en",
+					PageCount:       1225,
+					Description:     "Novel about Napoleonic wars",
+					Genres:          []string{genreID2.String(), genreID1.String(), uuid.NewString()},
+				},
+			},
+			expectedStatusCode: http.StatusCreated,
+			expectedBook: server.ResponseBookFullInfo{
+				Title:           "War and Peace",
+				Isbn:            "9780306406157",
+				PublicationYear: 1869,
+				Language:        "en",
+				PageCount:       1225,
+				Description:     "Novel about Napoleonic wars",
+				Genres:          []server.ResponseGenre{{ID: genreID2.String(), Name: "Historical"}, {ID: genreID1.String(), Name: "Novel"}},
+			},
+		},
+		{
+			name: "invalid_isbn",
+			requestBook: server.RequestBook{
+				Title:               "War and Peace",
+				RequestBookMetadata: server.RequestBookMetadata{Isbn: "0-306-40615-3"},
+			},
+			expectedStatusCode: http.StatusBadRequest,
+		},
+		{
+			name: "duplicate_isbn",
+			requestBook: server.RequestBook{
+				Title:               "War and Peace",
+				RequestBookMetadata: server.RequestBookMetadata{Isbn: "9780804429573"},
+			},
+			expectedStatusCode: http.StatusConflict,
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			db, err := common.SetupDBByURL("../.env", "TEST_DB_URL")
+			assert.NoError(t, err)
+			defer common.CloseDB(db)
+			cleanupDB(db)
+			AddGenresDB(db, []genre{{id: genreID1, name: "Novel"}, {id: genreID2, name: "Historical"}})
+			AddBooksDB(db, []Book{{id: otherBookID, title: "Anna Karenina"}})
+			_, err = db.Exec("UPDATE books SET isbn = '9780804429573' WHERE id = $1", otherBookID)
+			assert.NoError(t, err)
+
+			s := setupTestServer(db)
+			defer s.Close()
+
+			body, _ := json.Marshal(tc.requestBook)
+			response, err := http.Post(s.URL+server.ApiBooksPath, "application/json", bytes.NewBuffer(body))
+			assert.NoError(t, err)
+			defer common.CloseResponseBody(response)
+			assert.Equal(t, tc.expectedStatusCode, response.StatusCode)
+
+			books := GetDBBooks(t, db)
+			if tc.expectedStatusCode != http.StatusCreated {
+				assert.Equal(t, len(books), 1)
+				return
+			}
+			assert.Equal(t, len(books), 2)
+			bookID := books[1].id
+			if books[1].id == otherBookID {
+				bookID = books[0].id
+			}
+
+			getResponse, err := http.Get(fmt.Sprintf("%v%v/%v", s.URL, server.ApiBooksPath, bookID))
+			assert.NoError(t, err)
+			defer common.CloseResponseBody(getResponse)
+			assert.Equal(t, getResponse.StatusCode, http.StatusOK)
+			responseBody := server.ResponseBookFullInfo{}
+			err = json.NewDecoder(getResponse.Body).Decode(&responseBody)
+			assert.NoError(t, err)
+			tc.expectedBook.ID = bookID.String()
+			assert.Equal(t, responseBody, tc.expectedBook)
+		})
+	}
+}
+
+func TestGetBookID(t *testing.T) {
+	book1 := uuid.New()
+	book2 := uuid.New()
+	author1 := uuid.New()
+	author2 := uuid.New()
+
+	type testCase struct {
+		name               string
+		requestedBook      string
+		expectedStatusCode int
+		expectedResponse   *server.ResponseBookFullInfo
+	}
+
+	tests := []testCase{
+		{
+			name:               "success",
+			requestedBook:      book1.String(),
+			expectedStatusCode: http.StatusOK,
+			expectedResponse: &server.ResponseBookFullInfo{
+				ID:      book1.String(),
+				Title:   "Title 1",
+				Authors: []string{"Author 1", "Author 2"},
+				AuthorsInfo: []server.ResponseAuthorShortInfo{
+					{ID: author1.String(), FullName: "Author 1"},
+					{ID: author2.String(), FullName: "Author 2"},
+				},
+			},
+		},
+		{
+			name:               "no_authors",
+			requestedBook:      book2.String(),
+			expectedStatusCode: http.StatusOK,
+			expectedResponse:   &server.ResponseBookFullInfo{ID: book2.String(), Title: "Title 2", Authors: []string{}},
+		},
+		{
+			name:               "not_found",
+			requestedBook:      uuid.NewString(),
+			expectedStatusCode: http.StatusNotFound,
+			expectedResponse:   nil,
+		},
+		{
+			name:               "invalid_id",
+			requestedBook:      "invalid_id",
+			expectedStatusCode: http.StatusBadRequest,
+			expectedResponse:   nil,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			db, err := common.SetupDBByURL("../.env", "TEST_DB_URL")
+			assert.NoError(t, err)
+			defer common.CloseDB(db)
+			cleanupDB(db)
+			AddBooksDB(db, []Book{{id: book1, title: "Title 1"}, {id: book2, title: "Title 2"}})
+			AddAuthorsDB(db, []author{{id: author1, fullName: "Author 1"}, {id: author2, fullName: "Author 2"}})
+			AddBookAuthorsDB(db, book1.String(), []string{author2.String(), author1.String()})
+
+			s := setupTestServer(db)
+			defer s.Close()
+
+			response, err := http.Get(fmt.Sprintf("%v%v/%v", s.URL, server.ApiBooksPath, tc.requestedBook))
+			assert.NoError(t, err)
+			defer common.CloseResponseBody(response)
+			assert.Equal(t, tc.expectedStatusCode, response.StatusCode)
+
+			if tc.expectedResponse != nil {
+				decoder := json.NewDecoder(response.Body)
+				responseBody := server.ResponseBookFullInfo{}
+				err = decoder.Decode(&responseBody)
+				assert.NoError(t, err)
+
+				assert.Equal(t, responseBody, *tc.expectedResponse)
+			}
+		})
+	}
+}
+
 func TestSearchBooks(t *testing.T) {
 	book1 := uuid.New()
 	book2 := uuid.New()
@@ -421,6 +786,7 @@ func TestSearchBooks(t *testing.T) {
 	type testCase struct {
 		name               string
 		requestedText      string
+		requestedMode      string
 		expectedStatusCode int
 		expectedResponse   []server.ResponseBookFullInfo
 	}
@@ -432,7 +798,39 @@ func TestSearchBooks(t *testing.T) {
 			expectedStatusCode: http.StatusOK,
 			expectedResponse: []server.ResponseBookFullInfo{
 				{ID: book3.String(), Title: "Great Title, great title", Authors: nil},
-				{ID: book1.String(), Title: "Great Title", Authors: []string{"Author 1"}}},
+				{ID: book1.String(), Title: "Great Title", Authors: []string{"Author 1"}, PublicationYear: 1925, PageCount: 180}},
+		},
+		{
+			name:               "fuzzy_mode_with_typo",
+			requestedText:      "grate title",
+			requestedMode:      "fuzzy",
+			expectedStatusCode: http.StatusOK,
+			expectedResponse: []server.ResponseBookFullInfo{
+				{ID: book3.String(), Title: "Great Title, great title", Authors: nil},
+				{ID: book1.String(), Title: "Great Title", Authors: []string{"Author 1"}, PublicationYear: 1925, PageCount: 180}},
+		},
+		{
+			name:               "exact_mode_with_typo",
+			requestedText:      "grate title",
+			requestedMode:      "exact",
+			expectedStatusCode: http.StatusOK,
+			expectedResponse:   []server.ResponseBookFullInfo{},
+		},
+		{
+			name:               "prefix_mode",
+			requestedText:      "gre tit",
+			requestedMode:      "prefix",
+			expectedStatusCode: http.StatusOK,
+			expectedResponse: []server.ResponseBookFullInfo{
+				{ID: book3.String(), Title: "Great Title, great title", Authors: nil},
+				{ID: book1.String(), Title: "Great Title", Authors: []string{"Author 1"}, PublicationYear: 1925, PageCount: 180}},
+		},
+		{
+			name:               "invalid_mode",
+			requestedText:      "great title",
+			requestedMode:      "unknown",
+			expectedStatusCode: http.StatusBadRequest,
+			expectedResponse:   nil,
 		},
 		{
 			name:               "empty_search_text",
@@ -452,11 +850,13 @@ func TestSearchBooks(t *testing.T) {
 			AddAuthorsDB(db, []author{{id: author1, fullName: "Author 1"}, {id: author2, fullName: "Author 2"}})
 			AddBookAuthorsDB(db, book1.String(), []string{author1.String()})
 			AddBookAuthorsDB(db, book2.String(), []string{author2.String(), author1.String()})
+			_, err = db.Exec("UPDATE books SET publication_year = 1925, page_count = 180 WHERE id = $1", book1)
+			assert.NoError(t, err)
 
-			s, _ := setupTestServer(db)
+			s := setupTestServer(db)
 			defer s.Close()
 
-			response, err := http.Get(s.URL + server.ApiBooksSearchPath + "?text=" + url.QueryEscape(tc.requestedText))
+			response, err := http.Get(s.URL + server.ApiBooksSearchPath + "?text=" + url.QueryEscape(tc.requestedText) + "&mode=" + tc.requestedMode)
 			assert.NoError(t, err)
 			defer common.CloseResponseBody(response)
 			assert.Equal(t, tc.expectedStatusCode, response.StatusCode)
@@ -467,8 +867,207 @@ func TestSearchBooks(t *testing.T) {
 				err = decoder.Decode(&responseBody)
 				assert.NoError(t, err)
 
+				// Headlines are checked in TestSearchBooksDocument
+				for i := range responseBody {
+					responseBody[i].Headline = ""
+				}
 				assert.ElementsMatch(t, responseBody, tc.expectedResponse)
 			}
 		})
 	}
 }
+
+func TestSearchBooksDocument(t *testing.T) {
+	book1 := uuid.New()
+	book2 := uuid.New()
+	book3 := uuid.New()
+	author1 := uuid.New()
+	genre1 := uuid.New()
+
+	type testCase struct {
+		name               string
+		requestedText      string
+		expectedBooks      []string
+		expectedFirstBook  string
+		expectedHighlights []string
+	}
+
+	tests := []testCase{
+		{
+			name:               "title_and_author",
+			requestedText:      "Tolstoy war",
+			expectedBooks:      []string{book1.String()},
+			expectedFirstBook:  book1.String(),
+			expectedHighlights: []string{"<b>War</b>", "<b>Tolstoy</b>"},
+		},
+		{
+			name:               "title_ranked_above_author",
+			requestedText:      "Tolstoy",
+			expectedBooks:      []string{book1.String(), book2.String(), book3.String()},
+			expectedFirstBook:  book3.String(),
+			expectedHighlights: []string{"<b>Tolstoy</b>"},
+		},
+		{
+			name:               "description",
+			requestedText:      "invasion",
+			expectedBooks:      []string{book1.String()},
+			expectedFirstBook:  book1.String(),
+			expectedHighlights: []string{"<b>invasion</b>"},
+		},
+		{
+			name:               "genre",
+			requestedText:      "historical",
+			expectedBooks:      []string{book1.String()},
+			expectedFirstBook:  book1.String(),
+			expectedHighlights: nil,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			db, err := common.SetupDBByURL("../.env", "TEST_DB_URL")
+			assert.NoError(t, err)
+			defer common.CloseDB(db)
+			cleanupDB(db)
+			AddBooksDB(db, []Book{{id: book1, title: "War and Peace"}, {id: book2, title: "Anna Karenina"}, {id: book3, title: "Tolstoy: A Life"}})
+			SetBookDescriptionDB(db, book1, "Novel about the French invasion of Russia")
+			AddAuthorsDB(db, []author{{id: author1, fullName: "Leo Tolstoy"}})
+			AddBookAuthorsDB(db, book1.String(), []string{author1.String()})
+			AddBookAuthorsDB(db, book2.String(), []string{author1.String()})
+			AddGenresDB(db, []genre{{id: genre1, name: "Historical fiction"}})
+			AddBookGenresDB(db, book1, []uuid.UUID{genre1})
+
+			s := setupTestServer(db)
+			defer s.Close()
+
+			books := searchBooks(t, s, tc.requestedText)
+			assert.ElementsMatch(t, bookIDs(books), tc.expectedBooks)
+			if assert.NotEmpty(t, books) {
+				assert.Equal(t, books[0].ID, tc.expectedFirstBook)
+				for _, highlight := range tc.expectedHighlights {
+					assert.Contains(t, books[0].Headline, highlight)
+				}
+			}
+		})
+	}
+}
+
+func TestSearchBooksDocumentUpdates(t *testing.T) {
+	book1 := uuid.New()
+	book2 := uuid.New()
+	author1 := uuid.New()
+	author2 := uuid.New()
+
+	db, err := common.SetupDBByURL("../.env", "TEST_DB_URL")
+	assert.NoError(t, err)
+	defer common.CloseDB(db)
+	cleanupDB(db)
+	AddBooksDB(db, []Book{{id: book1, title: "War and Peace"}, {id: book2, title: "Anna Karenina"}})
+	AddAuthorsDB(db, []author{{id: author1, fullName: "Leo Tolstoy"}, {id: author2, fullName: "Fyodor Dostoevsky"}})
+	AddBookAuthorsDB(db, book1.String(), []string{author1.String()})
+	AddBookAuthorsDB(db, book2.String(), []string{author1.String()})
+
+	s := setupTestServer(db)
+	defer s.Close()
+	client := &http.Client{}
+
+	// Author is renamed
+	body, _ := json.Marshal(server.RequestAuthorWithID{ID: author1.String(), FullName: "Lev Tolstoy"})
+	request, err := http.NewRequest(http.MethodPut, s.URL+server.ApiAuthorsPath, bytes.NewBuffer(body))
+	assert.NoError(t, err)
+	response, err := client.Do(request)
+	assert.NoError(t, err)
+	common.CloseResponseBody(response)
+	assert.Equal(t, http.StatusOK, response.StatusCode)
+	assert.ElementsMatch(t, bookIDs(searchBooks(t, s, "Lev")), []string{book1.String(), book2.String()})
+	assert.Empty(t, searchBooks(t, s, "Leo"))
+
+	// Book authors are changed
+	body, _ = json.Marshal(server.RequestBookWithID{ID: book2.String(), Title: "Anna Karenina", Authors: []string{author2.String()}})
+	request, err = http.NewRequest(http.MethodPut, s.URL+server.ApiBooksPath, bytes.NewBuffer(body))
+	assert.NoError(t, err)
+	response, err = client.Do(request)
+	assert.NoError(t, err)
+	common.CloseResponseBody(response)
+	assert.Equal(t, http.StatusOK, response.StatusCode)
+	assert.ElementsMatch(t, bookIDs(searchBooks(t, s, "Lev")), []string{book1.String()})
+	assert.ElementsMatch(t, bookIDs(searchBooks(t, s, "Dostoevsky")), []string{book2.String()})
+
+	// Author is deleted
+	request, err = http.NewRequest(http.MethodDelete, fmt.Sprintf("%v%v/%v", s.URL, server.AdminAuthorsPath, author2), nil)
+	assert.NoError(t, err)
+	response, err = client.Do(request)
+	assert.NoError(t, err)
+	common.CloseResponseBody(response)
+	assert.Empty(t, searchBooks(t, s, "Dostoevsky"))
+}
+
+func TestSearchBooksLanguage(t *testing.T) {
+	book1 := uuid.New()
+	book2 := uuid.New()
+
+	type testCase struct {
+		name          string
+		query         string
+		expectedBooks []string
+	}
+
+	tests := []testCase{
+		{
+			name:          "russian",
+			query:         "?lang=ru&text=" + url.QueryEscape("войны"),
+			expectedBooks: []string{book1.String()},
+		},
+		{
+			name:          "english",
+			query:         "?lang=en&text=" + url.QueryEscape("войны"),
+			expectedBooks: []string{},
+		},
+		{
+			name:          "default_book_language",
+			query:         "?text=" + url.QueryEscape("войны"),
+			expectedBooks: []string{book1.String()},
+		},
+		{
+			name:          "default_book_language_german",
+			query:         "?text=" + url.QueryEscape("Verwandlungen"),
+			expectedBooks: []string{book2.String()},
+		},
+		{
+			name:          "german",
+			query:         "?lang=de&text=" + url.QueryEscape("Verwandlungen"),
+			expectedBooks: []string{book2.String()},
+		},
+		{
+			name:          "prefix_mode",
+			query:         "?lang=ru&mode=prefix&text=" + url.QueryEscape("Вой"),
+			expectedBooks: []string{book1.String()},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			db, err := common.SetupDBByURL("../.env", "TEST_DB_URL")
+			assert.NoError(t, err)
+			defer common.CloseDB(db)
+			cleanupDB(db)
+			AddBooksDB(db, []Book{
+				{id: book1, title: "Война и мир", language: sql.NullString{String: "ru", Valid: true}},
+				{id: book2, title: "Die Verwandlung", language: sql.NullString{String: "de", Valid: true}},
+			})
+
+			s := setupTestServer(db)
+			defer s.Close()
+
+			response, err := http.Get(s.URL + server.ApiBooksSearchPath + tc.query)
+			assert.NoError(t, err)
+			defer common.CloseResponseBody(response)
+			assert.Equal(t, http.StatusOK, response.StatusCode)
+
+			responseBody := []server.ResponseBookFullInfo{}
+			err = json.NewDecoder(response.Body).Decode(&responseBody)
+			assert.NoError(t, err)
+			assert.ElementsMatch(t, bookIDs(responseBody), tc.expectedBooks)
+		})
+	}
+}
@@ -0,0 +1,247 @@
+package tests
+
+import (
+	"bytes"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"testing"
+
+	common "github.com/bakurvik/mylib-common"
+	"github.com/bakurvik/mylib/library/internal/server"
+	"github.com/google/uuid"
+
+	_ "github.com/lib/pq"
+	"github.com/stretchr/testify/assert"
+)
+
+const (
+	selectGenres    = "SELECT id, name FROM genres ORDER BY name"
+	insertGenre     = "INSERT INTO genres(id, name) VALUES ($1, $2)"
+	insertBookGenre = "INSERT INTO book_genres(book_id, genre_id) VALUES ($1, $2)"
+)
+
+type genre struct {
+	id   uuid.UUID
+	name string
+}
+
+func AddGenresDB(db *sql.DB, genres []genre) {
+	for _, genre := range genres {
+		_, err := db.Exec(insertGenre, genre.id, genre.name)
+		if err != nil {
+			log.Print("Failed to add genre to db: ", err)
+		}
+	}
+}
+
+func AddBookGenresDB(db *sql.DB, bookID uuid.UUID, genres []uuid.UUID) {
+	for _, genreID := range genres {
+		_, err := db.Exec(insertBookGenre, bookID, genreID)
+		if err != nil {
+			log.Print("Failed to add book genre to db: ", err)
+		}
+	}
+}
+
+func GetDBGenres(t *testing.T, db *sql.DB) []genre {
+	rows, err := db.Query(selectGenres)
+	if err != nil {
+		t.Fatalf("Error while selecting genres: %v", err)
+	}
+	defer common.CloseRows(rows)
+	genres := make([]genre, 0)
+
+	for rows.Next() {
+		g := genre{}
+		err := rows.Scan(&g.id, &g.name)
+		if err != nil {
+			log.Fatal("Error scanning row:", err)
+		}
+		genres = append(genres, g)
+	}
+
+	if err := rows.Err(); err != nil {
+		log.Fatal("Error reading rows:", err)
+	}
+	return genres
+}
+
+func genreNames(genres []genre) []string {
+	names := make([]string, 0, len(genres))
+	for _, genre := range genres {
+		names = append(names, genre.name)
+	}
+	return names
+}
+
+func TestCreateGenre(t *testing.T) {
+	type testCase struct {
+		name               string
+		dbGenres           []genre
+		requestGenre       server.RequestGenre
+		expectedStatusCode int
+		expectedDBGenres   []string
+	}
+	testCases := []testCase{
+		{
+			name:               "success",
+			dbGenres:           []genre{},
+			requestGenre:       server.RequestGenre{Name: "Novel"},
+			expectedStatusCode: http.StatusCreated,
+			expectedDBGenres:   []string{"Novel"},
+		},
+		{
+			name:               "already_exists",
+			dbGenres:           []genre{{id: uuid.New(), name: "Novel"}},
+			requestGenre:       server.RequestGenre{Name: "Novel"},
+			expectedStatusCode: http.StatusConflict,
+			expectedDBGenres:   []string{"Novel"},
+		},
+		{
+			name:               "empty_name",
+			dbGenres:           []genre{},
+			requestGenre:       server.RequestGenre{},
+			expectedStatusCode: http.StatusBadRequest,
+			expectedDBGenres:   []string{},
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			db, err := common.SetupDBByURL("../.env", "TEST_DB_URL")
+			assert.NoError(t, err)
+			defer common.CloseDB(db)
+			cleanupDB(db)
+			AddGenresDB(db, tc.dbGenres)
+
+			s := setupTestServer(db)
+			defer s.Close()
+
+			body, _ := json.Marshal(tc.requestGenre)
+			response, err := http.Post(s.URL+server.AdminGenresPath, "application/json", bytes.NewBuffer(body))
+			assert.NoError(t, err)
+			defer common.CloseResponseBody(response)
+			assert.Equal(t, tc.expectedStatusCode, response.StatusCode)
+
+			dbGenres := GetDBGenres(t, db)
+			assert.Equal(t, genreNames(dbGenres), tc.expectedDBGenres)
+			if tc.expectedStatusCode == http.StatusCreated {
+				responseBody := server.ResponseGenre{}
+				err = json.NewDecoder(response.Body).Decode(&responseBody)
+				assert.NoError(t, err)
+				assert.Equal(t, responseBody, server.ResponseGenre{ID: dbGenres[0].id.String(), Name: tc.requestGenre.Name})
+			}
+		})
+	}
+}
+
+func TestGetGenres(t *testing.T) {
+	genreID1 := uuid.New()
+	genreID2 := uuid.New()
+
+	db, err := common.SetupDBByURL("../.env", "TEST_DB_URL")
+	assert.NoError(t, err)
+	defer common.CloseDB(db)
+	cleanupDB(db)
+	AddGenresDB(db, []genre{{id: genreID1, name: "Poetry"}, {id: genreID2, name: "Novel"}})
+
+	s := setupTestServer(db)
+	defer s.Close()
+
+	response, err := http.Get(s.URL + server.AdminGenresPath)
+	assert.NoError(t, err)
+	defer common.CloseResponseBody(response)
+	assert.Equal(t, response.StatusCode, http.StatusOK)
+
+	responseBody := []server.ResponseGenre{}
+	err = json.NewDecoder(response.Body).Decode(&responseBody)
+	assert.NoError(t, err)
+	assert.Equal(t, responseBody, []server.ResponseGenre{{ID: genreID2.String(), Name: "Novel"}, {ID: genreID1.String(), Name: "Poetry"}})
+}
+
+func TestUpdateGenre(t *testing.T) {
+	genreID1 := uuid.New()
+	genreID2 := uuid.New()
+	type testCase struct {
+		name               string
+		requestGenre       server.RequestGenreWithID
+		expectedStatusCode int
+		expectedDBGenres   []string
+	}
+	testCases := []testCase{
+		{
+			name:               "success",
+			requestGenre:       server.RequestGenreWithID{ID: genreID1.String(), Name: "Epic novel"},
+			expectedStatusCode: http.StatusOK,
+			expectedDBGenres:   []string{"Epic novel", "Poetry"},
+		},
+		{
+			name:               "already_exists",
+			requestGenre:       server.RequestGenreWithID{ID: genreID1.String(), Name: "Poetry"},
+			expectedStatusCode: http.StatusConflict,
+			expectedDBGenres:   []string{"Novel", "Poetry"},
+		},
+		{
+			name:               "not_found",
+			requestGenre:       server.RequestGenreWithID{ID: uuid.NewString(), Name: "Drama"},
+			expectedStatusCode: http.StatusNotFound,
+			expectedDBGenres:   []string{"Novel", "Poetry"},
+		},
+		{
+			name:               "invalid_id",
+			requestGenre:       server.RequestGenreWithID{ID: "invalid_id", Name: "Drama"},
+			expectedStatusCode: http.StatusBadRequest,
+			expectedDBGenres:   []string{"Novel", "Poetry"},
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			db, err := common.SetupDBByURL("../.env", "TEST_DB_URL")
+			assert.NoError(t, err)
+			defer common.CloseDB(db)
+			cleanupDB(db)
+			AddGenresDB(db, []genre{{id: genreID1, name: "Novel"}, {id: genreID2, name: "Poetry"}})
+
+			s := setupTestServer(db)
+			defer s.Close()
+
+			body, _ := json.Marshal(tc.requestGenre)
+			request, err := http.NewRequest(http.MethodPut, s.URL+server.AdminGenresPath, bytes.NewBuffer(body))
+			assert.NoError(t, err)
+			response, err := http.DefaultClient.Do(request)
+			assert.NoError(t, err)
+			defer common.CloseResponseBody(response)
+			assert.Equal(t, tc.expectedStatusCode, response.StatusCode)
+
+			assert.Equal(t, genreNames(GetDBGenres(t, db)), tc.expectedDBGenres)
+		})
+	}
+}
+
+func TestDeleteGenre(t *testing.T) {
+	genreID := uuid.New()
+	bookID := uuid.New()
+
+	db, err := common.SetupDBByURL("../.env", "TEST_DB_URL")
+	assert.NoError(t, err)
+	defer common.CloseDB(db)
+	cleanupDB(db)
+	AddGenresDB(db, []genre{{id: genreID, name: "Novel"}})
+	AddBooksDB(db, []Book{{id: bookID, title: "War and Peace"}})
+	AddBookGenresDB(db, bookID, []uuid.UUID{genreID})
+
+	s := setupTestServer(db)
+	defer s.Close()
+
+	request, err := http.NewRequest(http.MethodDelete, fmt.Sprintf("%v%v/%v", s.URL, server.AdminGenresPath, genreID), nil)
+	assert.NoError(t, err)
+	response, err := http.DefaultClient.Do(request)
+	assert.NoError(t, err)
+	defer common.CloseResponseBody(response)
+	assert.Equal(t, response.StatusCode, http.StatusNoContent)
+
+	assert.Equal(t, len(GetDBGenres(t, db)), 0)
+	assert.Equal(t, len(GetDBBooks(t, db)), 1)
+}
@@ -0,0 +1,136 @@
+package tests
+
+import (
+	"bytes"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	common "github.com/bakurvik/mylib-common"
+	"github.com/bakurvik/mylib/library/internal/auth"
+	"github.com/bakurvik/mylib/library/internal/server"
+	"github.com/google/uuid"
+
+	_ "github.com/lib/pq"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRoles(t *testing.T) {
+	authorID := uuid.New()
+	bookID := uuid.New()
+	type testCase struct {
+		name               string
+		method             string
+		path               string
+		body               string
+		role               string
+		expectedStatusCode int
+	}
+	testCases := []testCase{
+		{
+			name:               "public_read",
+			method:             http.MethodGet,
+			path:               server.ApiBooksPath,
+			expectedStatusCode: http.StatusOK,
+		},
+		{
+			name:               "write_without_token",
+			method:             http.MethodPost,
+			path:               server.ApiAuthorsPath,
+			body:               `{"full_name": "Leo Tolstoy"}`,
+			expectedStatusCode: http.StatusUnauthorized,
+		},
+		{
+			name:               "write_by_reader",
+			method:             http.MethodPost,
+			path:               server.ApiAuthorsPath,
+			body:               `{"full_name": "Leo Tolstoy"}`,
+			role:               auth.RoleReader,
+			expectedStatusCode: http.StatusForbidden,
+		},
+		{
+			name:               "write_by_editor",
+			method:             http.MethodPost,
+			path:               server.ApiAuthorsPath,
+			body:               `{"full_name": "Leo Tolstoy"}`,
+			role:               auth.RoleEditor,
+			expectedStatusCode: http.StatusCreated,
+		},
+		{
+			name:               "book_write_by_reader",
+			method:             http.MethodPost,
+			path:               server.ApiBooksPath,
+			body:               fmt.Sprintf(`{"title": "War and Peace", "authors": ["%v"]}`, authorID),
+			role:               auth.RoleReader,
+			expectedStatusCode: http.StatusForbidden,
+		},
+		{
+			name:               "delete_author_by_editor",
+			method:             http.MethodDelete,
+			path:               fmt.Sprintf("%v/%v", server.AdminAuthorsPath, authorID),
+			role:               auth.RoleEditor,
+			expectedStatusCode: http.StatusForbidden,
+		},
+		{
+			name:               "delete_author_by_admin",
+			method:             http.MethodDelete,
+			path:               fmt.Sprintf("%v/%v", server.AdminAuthorsPath, authorID),
+			role:               auth.RoleAdmin,
+			expectedStatusCode: http.StatusOK,
+		},
+		{
+			name:               "delete_book_by_editor",
+			method:             http.MethodDelete,
+			path:               fmt.Sprintf("%v/%v", server.AdminBooksPath, bookID),
+			role:               auth.RoleEditor,
+			expectedStatusCode: http.StatusForbidden,
+		},
+		{
+			name:               "delete_book_by_admin",
+			method:             http.MethodDelete,
+			path:               fmt.Sprintf("%v/%v", server.AdminBooksPath, bookID),
+			role:               auth.RoleAdmin,
+			expectedStatusCode: http.StatusNoContent,
+		},
+		{
+			name:               "get_genres_by_editor",
+			method:             http.MethodGet,
+			path:               server.AdminGenresPath,
+			role:               auth.RoleEditor,
+			expectedStatusCode: http.StatusOK,
+		},
+		{
+			name:               "create_genre_by_editor",
+			method:             http.MethodPost,
+			path:               server.AdminGenresPath,
+			body:               `{"name": "Novel"}`,
+			role:               auth.RoleEditor,
+			expectedStatusCode: http.StatusForbidden,
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			db, err := common.SetupDBByURL("../.env", "TEST_DB_URL")
+			assert.NoError(t, err)
+			defer common.CloseDB(db)
+			cleanupDB(db)
+			AddAuthorsDB(db, []author{{id: authorID, fullName: "Fyodor Dostoevsky"}})
+			AddBooksDB(db, []Book{{id: bookID, title: "Demons"}})
+
+			s := httptest.NewServer(setupTestHandler(db))
+			defer s.Close()
+
+			request, err := http.NewRequest(tc.method, s.URL+tc.path, bytes.NewBufferString(tc.body))
+			assert.NoError(t, err)
+			if tc.role != "" {
+				request.Header.Set("Authorization", "Bearer "+makeAccessToken(tc.role))
+			}
+			client := &http.Client{}
+			response, err := client.Do(request)
+			assert.NoError(t, err)
+			defer common.CloseResponseBody(response)
+			assert.Equal(t, tc.expectedStatusCode, response.StatusCode)
+		})
+	}
+}
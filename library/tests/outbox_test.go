@@ -0,0 +1,309 @@
+package tests
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"log"
+	"testing"
+	"time"
+
+	common "github.com/bakurvik/mylib-common"
+	"github.com/bakurvik/mylib/library/internal/server"
+	"github.com/bakurvik/mylib/shared/outbox"
+	"github.com/segmentio/kafka-go"
+
+	_ "github.com/lib/pq"
+	"github.com/stretchr/testify/assert"
+)
+
+const (
+	insertOutboxMessage = "INSERT INTO outbox(topic, message_key, payload) VALUES ($1, $2, $3)"
+	selectOutbox        = "SELECT message_key, attempts, last_error, sent_at FROM outbox ORDER BY id"
+)
+
+type outboxMessage struct {
+	topic string
+	key   string
+	value string
+}
+
+type dbOutboxMessage struct {
+	key       string
+	attempts  int
+	lastError sql.NullString
+	sentAt    sql.NullTime
+}
+
+type failingKafkaWriter struct {
+	failuresLeft int
+	kafkaMockWriter
+}
+
+func (w *failingKafkaWriter) WriteMessages(ctx context.Context, msgs ...kafka.Message) error {
+	if w.failuresLeft > 0 {
+		w.failuresLeft--
+		return errors.New("kafka is unavailable")
+	}
+	return w.kafkaMockWriter.WriteMessages(ctx, msgs...)
+}
+
+type fakeTicker struct {
+	ch chan time.Time
+}
+
+func (ft *fakeTicker) C() <-chan time.Time {
+	return ft.ch
+}
+func (ft *fakeTicker) Stop() {
+}
+
+func AddOutboxMessagesDB(db *sql.DB, messages []outboxMessage) {
+	for _, message := range messages {
+		_, err := db.Exec(insertOutboxMessage, message.topic, message.key, []byte(message.value))
+		if err != nil {
+			log.Print("Failed to add outbox message to db: ", err)
+		}
+	}
+}
+
+func GetDBOutbox(t *testing.T, db *sql.DB) []dbOutboxMessage {
+	rows, err := db.Query(selectOutbox)
+	if err != nil {
+		t.Fatalf("Error while selecting outbox: %v", err)
+	}
+	defer common.CloseRows(rows)
+	messages := make([]dbOutboxMessage, 0)
+
+	for rows.Next() {
+		message := dbOutboxMessage{}
+		err := rows.Scan(&message.key, &message.attempts, &message.lastError, &message.sentAt)
+		if err != nil {
+			log.Fatal("Error scanning row:", err)
+		}
+		messages = append(messages, message)
+	}
+
+	if err := rows.Err(); err != nil {
+		log.Fatal("Error reading rows:", err)
+	}
+	return messages
+}
+
+func messageKeys(writer *kafkaMockWriter) []string {
+	keys := make([]string, 0, len(writer.messages))
+	for _, msg := range writer.messages {
+		keys = append(keys, string(msg.key))
+	}
+	return keys
+}
+
+func TestOutboxRelayProcessPending(t *testing.T) {
+	type testCase struct {
+		name                string
+		dbMessages          []outboxMessage
+		batchSize           int
+		authorsFailures     int
+		expectedSent        int
+		expectedAuthorsKeys []string
+		expectedBooksKeys   []string
+		expectedSentKeys    []string
+		expectedFailedKeys  []string
+	}
+	testCases := []testCase{
+		{
+			name: "success",
+			dbMessages: []outboxMessage{
+				{topic: server.AuthorsTopic, key: "author1", value: "{}"},
+				{topic: server.BooksTopic, key: "book1", value: "{}"},
+				{topic: server.AuthorsTopic, key: "author2", value: "{}"},
+			},
+			batchSize:           10,
+			expectedSent:        3,
+			expectedAuthorsKeys: []string{"author1", "author2"},
+			expectedBooksKeys:   []string{"book1"},
+			expectedSentKeys:    []string{"author1", "book1", "author2"},
+			expectedFailedKeys:  []string{},
+		},
+		{
+			name: "batch_size",
+			dbMessages: []outboxMessage{
+				{topic: server.AuthorsTopic, key: "author1", value: "{}"},
+				{topic: server.BooksTopic, key: "book1", value: "{}"},
+				{topic: server.AuthorsTopic, key: "author2", value: "{}"},
+			},
+			batchSize:           2,
+			expectedSent:        2,
+			expectedAuthorsKeys: []string{"author1"},
+			expectedBooksKeys:   []string{"book1"},
+			expectedSentKeys:    []string{"author1", "book1"},
+			expectedFailedKeys:  []string{},
+		},
+		{
+			name: "kafka_error_stops_batch",
+			dbMessages: []outboxMessage{
+				{topic: server.BooksTopic, key: "book1", value: "{}"},
+				{topic: server.AuthorsTopic, key: "author1", value: "{}"},
+				{topic: server.BooksTopic, key: "book2", value: "{}"},
+			},
+			batchSize:           10,
+			authorsFailures:     1,
+			expectedSent:        1,
+			expectedAuthorsKeys: []string{},
+			expectedBooksKeys:   []string{"book1"},
+			expectedSentKeys:    []string{"book1"},
+			expectedFailedKeys:  []string{"author1"},
+		},
+		{
+			name: "unknown_topic",
+			dbMessages: []outboxMessage{
+				{topic: "unknown", key: "key1", value: "{}"},
+			},
+			batchSize:           10,
+			expectedSent:        0,
+			expectedAuthorsKeys: []string{},
+			expectedBooksKeys:   []string{},
+			expectedSentKeys:    []string{},
+			expectedFailedKeys:  []string{"key1"},
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			db, err := common.SetupDBByURL("../.env", "TEST_DB_URL")
+			assert.NoError(t, err)
+			defer common.CloseDB(db)
+			cleanupDB(db)
+			AddOutboxMessagesDB(db, tc.dbMessages)
+
+			authorsWriter := &failingKafkaWriter{failuresLeft: tc.authorsFailures}
+			booksWriter := &kafkaMockWriter{}
+			relay := outbox.Relay{
+				DB:        db,
+				Writers:   map[string]outbox.KafkaWriter{server.AuthorsTopic: authorsWriter, server.BooksTopic: booksWriter},
+				BatchSize: tc.batchSize,
+			}
+
+			sent, err := relay.ProcessPending(context.Background())
+			assert.NoError(t, err)
+			assert.Equal(t, sent, tc.expectedSent)
+			assert.Equal(t, messageKeys(&authorsWriter.kafkaMockWriter), tc.expectedAuthorsKeys)
+			assert.Equal(t, messageKeys(booksWriter), tc.expectedBooksKeys)
+
+			sentKeys := make([]string, 0)
+			failedKeys := make([]string, 0)
+			for _, message := range GetDBOutbox(t, db) {
+				if message.sentAt.Valid {
+					sentKeys = append(sentKeys, message.key)
+				}
+				if message.lastError.Valid {
+					assert.Equal(t, message.attempts, 1)
+					failedKeys = append(failedKeys, message.key)
+				}
+			}
+			assert.Equal(t, sentKeys, tc.expectedSentKeys)
+			assert.Equal(t, failedKeys, tc.expectedFailedKeys)
+		})
+	}
+}
+
+func TestOutboxRelayRetry(t *testing.T) {
+	db, err := common.SetupDBByURL("../.env", "TEST_DB_URL")
+	assert.NoError(t, err)
+	defer common.CloseDB(db)
+	cleanupDB(db)
+	AddOutboxMessagesDB(db, []outboxMessage{
+		{topic: server.AuthorsTopic, key: "author1", value: "{}"},
+		{topic: server.AuthorsTopic, key: "author2", value: "{}"},
+	})
+
+	authorsWriter := &failingKafkaWriter{failuresLeft: 2}
+	relay := outbox.Relay{
+		DB:        db,
+		Writers:   map[string]outbox.KafkaWriter{server.AuthorsTopic: authorsWriter},
+		BatchSize: 1,
+	}
+
+	ft := fakeTicker{ch: make(chan time.Time)}
+	done := make(chan struct{})
+	go func() {
+		relay.Run(context.Background(), &ft)
+		close(done)
+	}()
+	for range 3 {
+		ft.ch <- time.Now()
+	}
+	close(ft.ch)
+	<-done
+
+	assert.Equal(t, messageKeys(&authorsWriter.kafkaMockWriter), []string{"author1", "author2"})
+	messages := GetDBOutbox(t, db)
+	assert.Equal(t, len(messages), 2)
+	assert.Equal(t, messages[0].attempts, 3)
+	assert.True(t, messages[0].sentAt.Valid)
+	assert.False(t, messages[0].lastError.Valid)
+	assert.Equal(t, messages[1].attempts, 1)
+	assert.True(t, messages[1].sentAt.Valid)
+}
+
+func TestOutboxRelaySkipsWhileLocked(t *testing.T) {
+	db, err := common.SetupDBByURL("../.env", "TEST_DB_URL")
+	assert.NoError(t, err)
+	defer common.CloseDB(db)
+	cleanupDB(db)
+	AddOutboxMessagesDB(db, []outboxMessage{
+		{topic: server.AuthorsTopic, key: "author1", value: "{}"},
+	})
+
+	conn, err := db.Conn(context.Background())
+	assert.NoError(t, err)
+	defer conn.Close()
+	_, err = conn.ExecContext(context.Background(), "SELECT pg_advisory_lock(hashtext('outbox'))")
+	assert.NoError(t, err)
+
+	authorsWriter := &kafkaMockWriter{}
+	relay := outbox.Relay{
+		DB:        db,
+		Writers:   map[string]outbox.KafkaWriter{server.AuthorsTopic: authorsWriter},
+		BatchSize: 10,
+	}
+	sent, err := relay.ProcessPending(context.Background())
+	assert.NoError(t, err)
+	assert.Equal(t, sent, 0)
+	assert.Equal(t, len(authorsWriter.messages), 0)
+
+	_, err = conn.ExecContext(context.Background(), "SELECT pg_advisory_unlock(hashtext('outbox'))")
+	assert.NoError(t, err)
+	sent, err = relay.ProcessPending(context.Background())
+	assert.NoError(t, err)
+	assert.Equal(t, sent, 1)
+	assert.Equal(t, messageKeys(authorsWriter), []string{"author1"})
+}
+
+func TestOutboxRelayDeleteSent(t *testing.T) {
+	db, err := common.SetupDBByURL("../.env", "TEST_DB_URL")
+	assert.NoError(t, err)
+	defer common.CloseDB(db)
+	cleanupDB(db)
+	AddOutboxMessagesDB(db, []outboxMessage{
+		{topic: server.AuthorsTopic, key: "old_sent", value: "{}"},
+		{topic: server.AuthorsTopic, key: "new_sent", value: "{}"},
+		{topic: server.AuthorsTopic, key: "old_pending", value: "{}"},
+	})
+	_, err = db.Exec("UPDATE outbox SET sent_at = NOW() - INTERVAL '2 hours' WHERE message_key = 'old_sent'")
+	assert.NoError(t, err)
+	_, err = db.Exec("UPDATE outbox SET sent_at = NOW() WHERE message_key = 'new_sent'")
+	assert.NoError(t, err)
+	_, err = db.Exec("UPDATE outbox SET created_at = NOW() - INTERVAL '2 hours' WHERE message_key = 'old_pending'")
+	assert.NoError(t, err)
+
+	relay := outbox.Relay{DB: db, Retention: time.Hour}
+	deleted, err := relay.DeleteSent(context.Background())
+	assert.NoError(t, err)
+	assert.Equal(t, deleted, int64(1))
+
+	keys := make([]string, 0)
+	for _, message := range GetDBOutbox(t, db) {
+		keys = append(keys, message.key)
+	}
+	assert.Equal(t, keys, []string{"new_sent", "old_pending"})
+}
@@ -1,13 +1,23 @@
 package tests
 
 import (
+	"crypto/ed25519"
+	"crypto/rand"
 	"database/sql"
 	"log"
+	"net/http"
+	"time"
+
+	"github.com/bakurvik/mylib/library/internal/auth"
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/google/uuid"
 )
 
 const (
 	deleteAuthors = "DELETE FROM authors"
 	deleteBooks   = "DELETE FROM books"
+	deleteOutbox  = "DELETE FROM outbox"
+	deleteGenres  = "DELETE FROM genres"
 )
 
 func cleanupDB(db *sql.DB) {
@@ -19,4 +29,46 @@ func cleanupDB(db *sql.DB) {
 	if err != nil {
 		log.Print("Failed to cleanup books: ", err)
 	}
+	_, err = db.Query(deleteOutbox)
+	if err != nil {
+		log.Print("Failed to cleanup outbox: ", err)
+	}
+	_, err = db.Query(deleteGenres)
+	if err != nil {
+		log.Print("Failed to cleanup genres: ", err)
+	}
+}
+
+// testPublicKey and testPrivateKey stand for the signing key of users service.
+var testPublicKey, testPrivateKey, _ = ed25519.GenerateKey(rand.Reader)
+
+func testTokenKeys(token *jwt.Token) (interface{}, error) {
+	return testPublicKey, nil
+}
+
+func makeAccessToken(role string) string {
+	claims := auth.Claims{
+		Role: role,
+		RegisteredClaims: jwt.RegisteredClaims{
+			Issuer:    auth.Issuer,
+			Subject:   uuid.New().String(),
+			ExpiresAt: jwt.NewNumericDate(time.Now().Add(time.Hour)),
+		},
+	}
+	token, err := jwt.NewWithClaims(jwt.SigningMethodEdDSA, claims).SignedString(testPrivateKey)
+	if err != nil {
+		log.Print("Failed to make access token: ", err)
+	}
+	return token
+}
+
+// withAdminToken authorizes requests without Authorization header as admin.
+func withAdminToken(handler http.Handler) http.Handler {
+	token := makeAccessToken(auth.RoleAdmin)
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") == "" {
+			r.Header.Set("Authorization", "Bearer "+token)
+		}
+		handler.ServeHTTP(w, r)
+	})
 }
@@ -0,0 +1,160 @@
+// Package jwks verifies access tokens with public keys published by users service at /.well-known/jwks.json.
+// Every service that checks tokens imports it from the shared module.
+package jwks
+
+import (
+	"crypto"
+	"crypto/ed25519"
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"math/big"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+const (
+	DefaultTTL = 10 * time.Minute
+	// defaultMinRefreshInterval limits how often keys are refetched because of unknown kid or failed requests.
+	defaultMinRefreshInterval = 10 * time.Second
+	requestTimeout            = 5 * time.Second
+)
+
+// Algorithms are signing algorithms of keys that Cache can hold. Tokens signed with other algorithms must be rejected.
+var Algorithms = []string{jwt.SigningMethodEdDSA.Alg(), jwt.SigningMethodRS256.Alg()}
+
+type jwk struct {
+	Kty string `json:"kty"`
+	Crv string `json:"crv"`
+	X   string `json:"x"`
+	N   string `json:"n"`
+	E   string `json:"e"`
+	Kid string `json:"kid"`
+	Use string `json:"use"`
+}
+
+type jwkSet struct {
+	Keys []jwk `json:"keys"`
+}
+
+// Cache keeps public keys fetched from JWKS URL. Keys are refetched when they are older than TTL or a token has unknown kid,
+// so rotated keys are picked up without restart. If the keys can't be fetched, the previously fetched ones are used.
+type Cache struct {
+	url                string
+	ttl                time.Duration
+	minRefreshInterval time.Duration
+	client             *http.Client
+
+	mu          sync.Mutex
+	keys        map[string]crypto.PublicKey
+	fetchedAt   time.Time
+	attemptedAt time.Time
+}
+
+func NewCache(url string, ttl time.Duration) *Cache {
+	if ttl <= 0 {
+		ttl = DefaultTTL
+	}
+	return &Cache{
+		url:                url,
+		ttl:                ttl,
+		minRefreshInterval: defaultMinRefreshInterval,
+		client:             &http.Client{Timeout: requestTimeout},
+		keys:               map[string]crypto.PublicKey{},
+	}
+}
+
+// Keyfunc returns public key for token's kid header. It is passed to jwt.Parse.
+func (c *Cache) Keyfunc(token *jwt.Token) (interface{}, error) {
+	kid, _ := token.Header["kid"].(string)
+	if kid == "" {
+		return nil, errors.New("no kid in token header")
+	}
+	return c.Key(kid)
+}
+
+// Key returns public key with given ID, fetching keys if needed.
+func (c *Cache) Key(kid string) (crypto.PublicKey, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	key, ok := c.keys[kid]
+	if ok && time.Since(c.fetchedAt) < c.ttl {
+		return key, nil
+	}
+
+	var fetchErr error
+	if time.Since(c.attemptedAt) >= c.minRefreshInterval {
+		c.attemptedAt = time.Now()
+		keys, err := c.fetch()
+		if err != nil {
+			log.Print("Failed to fetch JWKS: ", err)
+			fetchErr = err
+		} else {
+			c.keys = keys
+			c.fetchedAt = c.attemptedAt
+		}
+	}
+
+	key, ok = c.keys[kid]
+	if !ok {
+		if fetchErr != nil {
+			return nil, fmt.Errorf("unknown key id %v: %w", kid, fetchErr)
+		}
+		return nil, fmt.Errorf("unknown key id %v", kid)
+	}
+	return key, nil
+}
+
+func (c *Cache) fetch() (map[string]crypto.PublicKey, error) {
+	response, err := c.client.Get(c.url)
+	if err != nil {
+		return nil, err
+	}
+	defer response.Body.Close()
+	if response.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("unexpected status %v", response.StatusCode)
+	}
+
+	set := jwkSet{}
+	err = json.NewDecoder(response.Body).Decode(&set)
+	if err != nil {
+		return nil, err
+	}
+	return parseKeys(set.Keys)
+}
+
+// parseKeys converts signing keys of JWKS to public keys by their IDs. Keys of unsupported types are skipped.
+func parseKeys(keys []jwk) (map[string]crypto.PublicKey, error) {
+	result := map[string]crypto.PublicKey{}
+	for _, key := range keys {
+		if key.Kid == "" || (key.Use != "" && key.Use != "sig") {
+			continue
+		}
+		switch {
+		case key.Kty == "OKP" && key.Crv == "Ed25519":
+			x, err := base64.RawURLEncoding.DecodeString(key.X)
+			if err != nil || len(x) != ed25519.PublicKeySize {
+				return nil, fmt.Errorf("invalid Ed25519 key %v", key.Kid)
+			}
+			result[key.Kid] = ed25519.PublicKey(x)
+		case key.Kty == "RSA":
+			n, err := base64.RawURLEncoding.DecodeString(key.N)
+			if err != nil {
+				return nil, fmt.Errorf("invalid RSA key %v", key.Kid)
+			}
+			e, err := base64.RawURLEncoding.DecodeString(key.E)
+			if err != nil || len(e) == 0 || len(e) > 4 {
+				return nil, fmt.Errorf("invalid RSA key %v", key.Kid)
+			}
+			result[key.Kid] = &rsa.PublicKey{N: new(big.Int).SetBytes(n), E: int(new(big.Int).SetBytes(e).Int64())}
+		}
+	}
+	return result, nil
+}
@@ -0,0 +1,171 @@
+package jwks
+
+import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/stretchr/testify/assert"
+)
+
+type jwksServer struct {
+	mu       sync.Mutex
+	keys     []jwk
+	fail     bool
+	requests int
+}
+
+func (s *jwksServer) setKeys(keys []jwk, fail bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.keys = keys
+	s.fail = fail
+}
+
+func (s *jwksServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.requests++
+	if s.fail {
+		w.WriteHeader(http.StatusServiceUnavailable)
+		return
+	}
+	_ = json.NewEncoder(w).Encode(jwkSet{Keys: s.keys})
+}
+
+func makeEd25519Key(t *testing.T, kid string) (jwk, ed25519.PublicKey) {
+	publicKey, _, err := ed25519.GenerateKey(rand.Reader)
+	assert.NoError(t, err)
+	return jwk{Kty: "OKP", Crv: "Ed25519", X: base64.RawURLEncoding.EncodeToString(publicKey), Kid: kid, Use: "sig"}, publicKey
+}
+
+func TestCacheKey(t *testing.T) {
+	oldKey, oldPublicKey := makeEd25519Key(t, "old")
+	newKey, newPublicKey := makeEd25519Key(t, "new")
+	handler := &jwksServer{keys: []jwk{oldKey}}
+	s := httptest.NewServer(handler)
+	defer s.Close()
+
+	cache := NewCache(s.URL, time.Hour)
+	cache.minRefreshInterval = 0
+
+	key, err := cache.Key("old")
+	assert.NoError(t, err)
+	assert.Equal(t, key, oldPublicKey)
+
+	// Cached keys are used without requests.
+	_, err = cache.Key("old")
+	assert.NoError(t, err)
+	assert.Equal(t, handler.requests, 1)
+
+	// Rotated key is fetched when a token with unknown kid comes.
+	handler.setKeys([]jwk{newKey, oldKey}, false)
+	key, err = cache.Key("new")
+	assert.NoError(t, err)
+	assert.Equal(t, key, newPublicKey)
+	assert.Equal(t, handler.requests, 2)
+
+	_, err = cache.Key("unknown")
+	assert.Error(t, err)
+}
+
+func TestCacheKeyStale(t *testing.T) {
+	key, publicKey := makeEd25519Key(t, "kid")
+	handler := &jwksServer{keys: []jwk{key}}
+	s := httptest.NewServer(handler)
+	defer s.Close()
+
+	cache := NewCache(s.URL, time.Hour)
+	cache.minRefreshInterval = 0
+	_, err := cache.Key("kid")
+	assert.NoError(t, err)
+
+	// Expired keys are refetched, but still used while users service is unavailable.
+	cache.ttl = time.Nanosecond
+	handler.setKeys(nil, true)
+	result, err := cache.Key("kid")
+	assert.NoError(t, err)
+	assert.Equal(t, result, publicKey)
+	assert.Equal(t, handler.requests, 2)
+
+	_, err = cache.Key("unknown")
+	assert.Error(t, err)
+}
+
+func TestCacheKeyRefreshInterval(t *testing.T) {
+	handler := &jwksServer{}
+	s := httptest.NewServer(handler)
+	defer s.Close()
+
+	cache := NewCache(s.URL, time.Hour)
+	for range 3 {
+		_, err := cache.Key("unknown")
+		assert.Error(t, err)
+	}
+	assert.Equal(t, handler.requests, 1)
+}
+
+func TestCacheKeyfunc(t *testing.T) {
+	publicKey, privateKey, _ := ed25519.GenerateKey(rand.Reader)
+	handler := &jwksServer{keys: []jwk{{Kty: "OKP", Crv: "Ed25519", X: base64.RawURLEncoding.EncodeToString(publicKey), Kid: "kid"}}}
+	s := httptest.NewServer(handler)
+	defer s.Close()
+	cache := NewCache(s.URL, time.Hour)
+
+	type testCase struct {
+		name     string
+		kid      string
+		hasError bool
+	}
+	testCases := []testCase{
+		{name: "valid", kid: "kid", hasError: false},
+		{name: "unknown_kid", kid: "another", hasError: true},
+		{name: "no_kid", kid: "", hasError: true},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			token := jwt.NewWithClaims(jwt.SigningMethodEdDSA, jwt.RegisteredClaims{Subject: "user"})
+			if tc.kid != "" {
+				token.Header["kid"] = tc.kid
+			}
+			tokenString, err := token.SignedString(privateKey)
+			assert.NoError(t, err)
+
+			_, err = jwt.Parse(tokenString, cache.Keyfunc, jwt.WithValidMethods(Algorithms))
+			assert.Equal(t, err != nil, tc.hasError)
+		})
+	}
+}
+
+func TestParseKeys(t *testing.T) {
+	ed25519Key, ed25519PublicKey := makeEd25519Key(t, "ed25519")
+	rsaPrivateKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	assert.NoError(t, err)
+	rsaKey := jwk{
+		Kty: "RSA",
+		N:   base64.RawURLEncoding.EncodeToString(rsaPrivateKey.N.Bytes()),
+		E:   base64.RawURLEncoding.EncodeToString(big.NewInt(int64(rsaPrivateKey.E)).Bytes()),
+		Kid: "rsa",
+	}
+	encryptionKey := ed25519Key
+	encryptionKey.Kid = "enc"
+	encryptionKey.Use = "enc"
+
+	keys, err := parseKeys([]jwk{ed25519Key, rsaKey, encryptionKey, {Kty: "EC", Crv: "P-256", Kid: "ec"}})
+	assert.NoError(t, err)
+	assert.Equal(t, len(keys), 2)
+	assert.Equal(t, keys["ed25519"], ed25519PublicKey)
+	assert.Equal(t, keys["rsa"], &rsaPrivateKey.PublicKey)
+
+	_, err = parseKeys([]jwk{{Kty: "OKP", Crv: "Ed25519", X: "short", Kid: "invalid"}})
+	assert.Error(t, err)
+}
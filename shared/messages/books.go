@@ -0,0 +1,12 @@
+// Package messages defines Kafka messages that are published by one service and consumed by others,
+// so that producers and consumers share the same payload.
+package messages
+
+// BookMessage is published to books topic by library service when a book or its authors change.
+// Action is created, updated or deleted, deleted messages have only ID set.
+type BookMessage struct {
+	ID      string   `json:"id"`
+	Title   string   `json:"title"`
+	Authors []string `json:"authors"`
+	Action  string   `json:"action"`
+}
@@ -0,0 +1,226 @@
+package outbox
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/lib/pq"
+	"github.com/segmentio/kafka-go"
+)
+
+const (
+	lockRelay          = `SELECT pg_try_advisory_lock(hashtext('outbox'))`
+	unlockRelay        = `SELECT pg_advisory_unlock(hashtext('outbox'))`
+	getPendingMessages = `SELECT id, topic, message_key, payload FROM outbox
+WHERE sent_at IS NULL
+ORDER BY id
+LIMIT $1`
+	markMessagesSent = `UPDATE outbox SET sent_at = NOW(), attempts = attempts + 1, last_error = NULL
+WHERE id = ANY($1)`
+	markMessagesFailed = `UPDATE outbox SET attempts = attempts + 1, last_error = $2
+WHERE id = ANY($1)`
+	deleteSentMessages = `DELETE FROM outbox WHERE sent_at < NOW() - make_interval(secs => $1)`
+)
+
+// cleanupPeriod is how often Run deletes sent messages older than Relay.Retention.
+const cleanupPeriod = time.Minute
+
+type KafkaWriter interface {
+	WriteMessages(ctx context.Context, msgs ...kafka.Message) error
+}
+
+type Ticker interface {
+	Stop()
+	C() <-chan time.Time
+}
+
+type TimeTicker struct {
+	T *time.Ticker
+}
+
+func (tt *TimeTicker) C() <-chan time.Time {
+	return tt.T.C
+}
+func (tt *TimeTicker) Stop() {
+	tt.T.Stop()
+}
+
+// Relay publishes messages stored in outbox table to Kafka. Every service that writes to outbox table runs its own Relay
+// with a writer per topic.
+// Only one relay of a service publishes at a time, it holds a session advisory lock for the whole run
+// and the replicas skip their runs until it is released. Messages are published in id order,
+// a failed message is retried on the next run and blocks the following ones.
+// Ids of concurrent transactions may be committed out of order, so the order of events is kept
+// only for changes of the same row, which are serialized by the row lock.
+// A message may be published twice if marking it sent fails, consumers must be idempotent.
+type Relay struct {
+	DB        *sql.DB
+	Writers   map[string]KafkaWriter
+	BatchSize int
+	// Retention is how long sent messages are kept in outbox table, zero keeps them forever.
+	Retention time.Duration
+}
+
+type message struct {
+	id    int64
+	topic string
+	key   string
+	value []byte
+}
+
+// topicBatch is a sequence of stored messages of the same topic, it is published with a single write.
+type topicBatch struct {
+	topic    string
+	ids      []int64
+	messages []kafka.Message
+}
+
+// splitBatches groups consecutive messages of the same topic so that the stored order is kept across topics.
+func splitBatches(messages []message) []topicBatch {
+	batches := make([]topicBatch, 0)
+	for _, msg := range messages {
+		if len(batches) == 0 || batches[len(batches)-1].topic != msg.topic {
+			batches = append(batches, topicBatch{topic: msg.topic})
+		}
+		last := &batches[len(batches)-1]
+		last.ids = append(last.ids, msg.id)
+		last.messages = append(last.messages, kafka.Message{Key: []byte(msg.key), Value: msg.value})
+	}
+	return batches
+}
+
+func (r *Relay) publish(ctx context.Context, batch topicBatch) error {
+	writer, ok := r.Writers[batch.topic]
+	if !ok {
+		return fmt.Errorf("no writer for topic %v", batch.topic)
+	}
+	return writer.WriteMessages(ctx, batch.messages...)
+}
+
+func getPending(ctx context.Context, conn *sql.Conn, limit int) ([]message, error) {
+	rows, err := conn.QueryContext(ctx, getPendingMessages, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	messages := make([]message, 0)
+	for rows.Next() {
+		msg := message{}
+		if err := rows.Scan(&msg.id, &msg.topic, &msg.key, &msg.value); err != nil {
+			return nil, err
+		}
+		messages = append(messages, msg)
+	}
+	return messages, rows.Err()
+}
+
+// ProcessPending publishes one batch of pending messages and returns the number of sent messages.
+// It returns 0 without publishing anything while another relay holds the lock.
+// No transaction is kept open while messages are written to Kafka.
+func (r *Relay) ProcessPending(ctx context.Context) (int, error) {
+	conn, err := r.DB.Conn(ctx)
+	if err != nil {
+		return 0, err
+	}
+	defer func() {
+		if err := conn.Close(); err != nil {
+			log.Print("Failed to close connection ", err)
+		}
+	}()
+
+	locked := false
+	if err := conn.QueryRowContext(ctx, lockRelay).Scan(&locked); err != nil {
+		return 0, err
+	}
+	if !locked {
+		return 0, nil
+	}
+	defer unlock(conn)
+
+	messages, err := getPending(ctx, conn, r.BatchSize)
+	if err != nil {
+		return 0, err
+	}
+
+	sent := make([]int64, 0, len(messages))
+	for _, batch := range splitBatches(messages) {
+		publishErr := r.publish(ctx, batch)
+		if publishErr != nil {
+			log.Printf("Failed to publish %v outbox messages starting from %v: %v", len(batch.ids), batch.ids[0], publishErr)
+			_, err = conn.ExecContext(ctx, markMessagesFailed, pq.Array(batch.ids), publishErr.Error())
+			if err != nil {
+				return 0, err
+			}
+			break
+		}
+		sent = append(sent, batch.ids...)
+	}
+
+	if len(sent) > 0 {
+		_, err = conn.ExecContext(ctx, markMessagesSent, pq.Array(sent))
+		if err != nil {
+			return 0, err
+		}
+	}
+	return len(sent), nil
+}
+
+// unlock releases the relay lock. The lock belongs to the session, so a connection
+// that failed to release it is discarded instead of going back to the pool.
+func unlock(conn *sql.Conn) {
+	_, err := conn.ExecContext(context.Background(), unlockRelay)
+	if err != nil {
+		log.Print("Failed to release outbox relay lock: ", err)
+		_ = conn.Raw(func(any) error {
+			return driver.ErrBadConn
+		})
+	}
+}
+
+// DeleteSent deletes messages sent more than Retention ago and returns the number of deleted messages.
+func (r *Relay) DeleteSent(ctx context.Context) (int64, error) {
+	if r.Retention <= 0 {
+		return 0, nil
+	}
+	result, err := r.DB.ExecContext(ctx, deleteSentMessages, r.Retention.Seconds())
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+// Run processes pending messages on every tick until ticker is stopped,
+// sent messages are deleted at most once per cleanupPeriod.
+func (r *Relay) Run(ctx context.Context, ticker Ticker) {
+	defer ticker.Stop()
+	var lastCleanup time.Time
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case _, ok := <-ticker.C():
+			if !ok {
+				return
+			}
+			for {
+				sent, err := r.ProcessPending(ctx)
+				if err != nil {
+					log.Print("Failed to process outbox: ", err)
+				}
+				if err != nil || sent < r.BatchSize {
+					break
+				}
+			}
+			if time.Since(lastCleanup) >= cleanupPeriod {
+				lastCleanup = time.Now()
+				if _, err := r.DeleteSent(ctx); err != nil {
+					log.Print("Failed to delete sent outbox messages: ", err)
+				}
+			}
+		}
+	}
+}
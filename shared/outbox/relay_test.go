@@ -0,0 +1,52 @@
+package outbox
+
+import (
+	"testing"
+
+	"github.com/segmentio/kafka-go"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSplitBatches(t *testing.T) {
+	type testCase struct {
+		name     string
+		messages []message
+		expected []topicBatch
+	}
+	testCases := []testCase{
+		{
+			name:     "empty",
+			messages: []message{},
+			expected: []topicBatch{},
+		},
+		{
+			name: "same_topic",
+			messages: []message{
+				{id: 1, topic: "authors", key: "author1", value: []byte("{}")},
+				{id: 2, topic: "authors", key: "author2", value: []byte("{}")},
+			},
+			expected: []topicBatch{
+				{topic: "authors", ids: []int64{1, 2}, messages: []kafka.Message{{Key: []byte("author1"), Value: []byte("{}")}, {Key: []byte("author2"), Value: []byte("{}")}}},
+			},
+		},
+		{
+			name: "keeps_order_across_topics",
+			messages: []message{
+				{id: 1, topic: "authors", key: "author1", value: []byte("{}")},
+				{id: 2, topic: "books", key: "book1", value: []byte("{}")},
+				{id: 3, topic: "books", key: "book2", value: []byte("{}")},
+				{id: 4, topic: "authors", key: "author2", value: []byte("{}")},
+			},
+			expected: []topicBatch{
+				{topic: "authors", ids: []int64{1}, messages: []kafka.Message{{Key: []byte("author1"), Value: []byte("{}")}}},
+				{topic: "books", ids: []int64{2, 3}, messages: []kafka.Message{{Key: []byte("book1"), Value: []byte("{}")}, {Key: []byte("book2"), Value: []byte("{}")}}},
+				{topic: "authors", ids: []int64{4}, messages: []kafka.Message{{Key: []byte("author2"), Value: []byte("{}")}}},
+			},
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Equal(t, splitBatches(tc.messages), tc.expected)
+		})
+	}
+}
@@ -21,7 +21,7 @@ const docTemplate = `{
     "paths": {
         "/api/authors": {
             "get": {
-                "description": "Gets user reading from DB. Uses access token from an HTTP-only cookie",
+                "description": "Gets user reading from DB, optionally only books with the status or on the custom shelf. Uses access token from an HTTP-only cookie",
                 "consumes": [
                     "application/json"
                 ],
@@ -38,6 +38,12 @@ const docTemplate = `{
                         "description": "Reading status",
                         "name": "status",
                         "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Shelf ID",
+                        "name": "shelf",
+                        "in": "query"
                     }
                 ],
                 "responses": {
@@ -50,12 +56,24 @@ const docTemplate = `{
                             }
                         }
                     },
+                    "400": {
+                        "description": "Unknown reading status or invalid shelf id",
+                        "schema": {
+                            "$ref": "#/definitions/server.ErrorResponse"
+                        }
+                    },
                     "401": {
                         "description": "Unauthorized",
                         "schema": {
                             "$ref": "#/definitions/server.ErrorResponse"
                         }
                     },
+                    "404": {
+                        "description": "Unknown shelf",
+                        "schema": {
+                            "$ref": "#/definitions/server.ErrorResponse"
+                        }
+                    },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {
@@ -65,7 +83,7 @@ const docTemplate = `{
                 }
             },
             "post": {
-                "description": "Updates user reading in DB. Uses access token from an HTTP-only cookie",
+                "description": "Updates the current reading cycle of the book in DB. Moving a finished book back to reading or want_to_read starts a new cycle, dates and rating of the finished one are kept. Uses access token from an HTTP-only cookie",
                 "consumes": [
                     "application/json"
                 ],
@@ -117,7 +135,7 @@ const docTemplate = `{
         },
         "/api/authors/{bookID}": {
             "get": {
-                "description": "Gets one user reading full info from DB. Uses access token from an HTTP-only cookie",
+                "description": "Gets one user reading full info from DB with all reading cycles of the book and their reviews. Uses access token from an HTTP-only cookie",
                 "consumes": [
                     "application/json"
                 ],
@@ -207,88 +225,1168 @@ const docTemplate = `{
                 }
             }
         },
-        "/ping": {
+        "/api/books/{bookID}/reviews": {
             "get": {
-                "description": "Checks server health. Returns 200 OK if server is up.",
-                "consumes": [
+                "description": "Gets a page of reviews of the book from users with public profiles, the newest first. Doesn't require authorization",
+                "produces": [
                     "application/json"
                 ],
+                "tags": [
+                    "Reviews"
+                ],
+                "summary": "Get book reviews",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Book ID",
+                        "name": "bookID",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Page size",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Cursor returned with the previous page",
+                        "name": "cursor",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Page of reviews",
+                        "schema": {
+                            "$ref": "#/definitions/server.ResponseBookReviewsPage"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid bookID or query parameters",
+                        "schema": {
+                            "$ref": "#/definitions/server.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/server.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/user-reading/export": {
+            "get": {
+                "description": "Gets all reading data of the user without book info from library: books in the order they were added, their previous reading cycles, progress updates, custom shelves and reviews. Used by account data export of users service",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Health"
+                    "User reading"
                 ],
-                "summary": "Ping the server",
+                "summary": "Export user reading",
                 "responses": {
                     "200": {
-                        "description": "OK",
+                        "description": "All reading data",
                         "schema": {
-                            "type": "string"
+                            "$ref": "#/definitions/server.ResponseUserReadingExport"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/server.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/server.ErrorResponse"
                         }
                     }
                 }
             }
-        }
-    },
-    "definitions": {
-        "server.ErrorResponse": {
-            "type": "object",
-            "properties": {
-                "error": {
-                    "type": "string"
+        },
+        "/api/user-reading/reviews": {
+            "put": {
+                "description": "Creates or edits the review of the current reading cycle of a finished book. Every read of the book has its own review, re-reading the book keeps the review with the finished cycle. Uses access token from an HTTP-only cookie",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Reviews"
+                ],
+                "summary": "Write review",
+                "parameters": [
+                    {
+                        "description": "Book id with review",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/server.RequestReview"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Edited review",
+                        "schema": {
+                            "$ref": "#/definitions/server.ResponseReview"
+                        }
+                    },
+                    "201": {
+                        "description": "Created review",
+                        "schema": {
+                            "$ref": "#/definitions/server.ResponseReview"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid request body or the book is not finished",
+                        "schema": {
+                            "$ref": "#/definitions/server.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/server.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Unknown user book",
+                        "schema": {
+                            "$ref": "#/definitions/server.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/server.ErrorResponse"
+                        }
+                    }
                 }
             }
         },
-        "server.ResponseUserReading": {
-            "type": "object",
-            "properties": {
-                "authors": {
-                    "type": "array",
-                    "items": {
-                        "type": "string"
+        "/api/user-reading/reviews/{bookID}": {
+            "delete": {
+                "description": "Deletes the review of the current reading cycle of the book. Uses access token from an HTTP-only cookie",
+                "tags": [
+                    "Reviews"
+                ],
+                "summary": "Delete review",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Book ID",
+                        "name": "bookID",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "Deleted successfully"
+                    },
+                    "400": {
+                        "description": "Invalid bookID",
+                        "schema": {
+                            "$ref": "#/definitions/server.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/server.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Unknown review",
+                        "schema": {
+                            "$ref": "#/definitions/server.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/server.ErrorResponse"
+                        }
                     }
-                },
-                "id": {
-                    "type": "string"
-                },
-                "rating": {
-                    "type": "integer"
-                },
-                "status": {
-                    "type": "string"
-                },
-                "title": {
-                    "type": "string"
                 }
             }
         },
-        "server.ResponseUserReadingFullInfo": {
-            "type": "object",
-            "properties": {
-                "authors": {
-                    "type": "array",
-                    "items": {
-                        "type": "string"
+        "/api/user-reading/shelves": {
+            "get": {
+                "description": "Gets custom shelves of the user in their order with number of books on each. Uses access token from an HTTP-only cookie",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Shelves"
+                ],
+                "summary": "Get shelves",
+                "responses": {
+                    "200": {
+                        "description": "Shelves",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/server.ResponseShelf"
+                            }
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/server.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/server.ErrorResponse"
+                        }
                     }
-                },
-                "finish_date": {
-                    "type": "string"
-                },
-                "id": {
-                    "type": "string"
-                },
-                "rating": {
-                    "type": "integer"
-                },
-                "start_date": {
-                    "type": "string"
-                },
-                "status": {
-                    "type": "string"
-                },
-                "title": {
-                    "type": "string"
+                }
+            },
+            "post": {
+                "description": "Creates a custom shelf at the end of the user's shelves. Uses access token from an HTTP-only cookie",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Shelves"
+                ],
+                "summary": "Create shelf",
+                "parameters": [
+                    {
+                        "description": "Shelf name",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/server.RequestShelf"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created shelf",
+                        "schema": {
+                            "$ref": "#/definitions/server.ResponseShelf"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid request body",
+                        "schema": {
+                            "$ref": "#/definitions/server.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/server.ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Shelf with this name already exists",
+                        "schema": {
+                            "$ref": "#/definitions/server.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/server.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/user-reading/shelves/order": {
+            "put": {
+                "description": "Sets the order of custom shelves. All shelves of the user must be listed. Uses access token from an HTTP-only cookie",
+                "consumes": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Shelves"
+                ],
+                "summary": "Reorder shelves",
+                "parameters": [
+                    {
+                        "description": "Shelf IDs in the new order",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/server.RequestShelvesOrder"
+                        }
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "Reordered successfully"
+                    },
+                    "400": {
+                        "description": "Invalid request body",
+                        "schema": {
+                            "$ref": "#/definitions/server.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/server.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/server.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/user-reading/shelves/{shelfID}": {
+            "put": {
+                "description": "Renames a custom shelf. Uses access token from an HTTP-only cookie",
+                "consumes": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Shelves"
+                ],
+                "summary": "Rename shelf",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Shelf ID",
+                        "name": "shelfID",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "New shelf name",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/server.RequestShelf"
+                        }
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "Renamed successfully"
+                    },
+                    "400": {
+                        "description": "Invalid request body",
+                        "schema": {
+                            "$ref": "#/definitions/server.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/server.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Unknown shelf",
+                        "schema": {
+                            "$ref": "#/definitions/server.ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Shelf with this name already exists",
+                        "schema": {
+                            "$ref": "#/definitions/server.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/server.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "description": "Deletes a custom shelf. Books on the shelf stay in user reading. Uses access token from an HTTP-only cookie",
+                "tags": [
+                    "Shelves"
+                ],
+                "summary": "Delete shelf",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Shelf ID",
+                        "name": "shelfID",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "Deleted successfully"
+                    },
+                    "400": {
+                        "description": "Invalid shelfID",
+                        "schema": {
+                            "$ref": "#/definitions/server.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/server.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Unknown shelf",
+                        "schema": {
+                            "$ref": "#/definitions/server.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/server.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/user-reading/shelves/{shelfID}/books/{bookID}": {
+            "put": {
+                "description": "Puts a book of user reading on a custom shelf. A book can be on several shelves. Uses access token from an HTTP-only cookie",
+                "tags": [
+                    "Shelves"
+                ],
+                "summary": "Put book on shelf",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Shelf ID",
+                        "name": "shelfID",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Book ID",
+                        "name": "bookID",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "Book is on the shelf"
+                    },
+                    "400": {
+                        "description": "Invalid shelfID or bookID",
+                        "schema": {
+                            "$ref": "#/definitions/server.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/server.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Unknown shelf or user book",
+                        "schema": {
+                            "$ref": "#/definitions/server.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/server.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "description": "Removes a book from a custom shelf, the book stays in user reading. Uses access token from an HTTP-only cookie",
+                "tags": [
+                    "Shelves"
+                ],
+                "summary": "Remove book from shelf",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Shelf ID",
+                        "name": "shelfID",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Book ID",
+                        "name": "bookID",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "Removed successfully"
+                    },
+                    "400": {
+                        "description": "Invalid shelfID or bookID",
+                        "schema": {
+                            "$ref": "#/definitions/server.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/server.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Book is not on the shelf",
+                        "schema": {
+                            "$ref": "#/definitions/server.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/server.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/user-reading/stats": {
+            "get": {
+                "description": "Gets number of books by reading status and number of finished reads, re-reads of the same book are counted separately. Uses access token from an HTTP-only cookie",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "User reading"
+                ],
+                "summary": "Get user reading stats",
+                "responses": {
+                    "200": {
+                        "description": "User reading stats",
+                        "schema": {
+                            "$ref": "#/definitions/server.ResponseUserReadingStats"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/server.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/server.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/user-reading/{bookID}/progress": {
+            "get": {
+                "description": "Gets history of progress updates of the current reading cycle of the book, the oldest first. Updates of previous cycles are kept with them. Uses access token from an HTTP-only cookie",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Reading progress"
+                ],
+                "summary": "Get reading progress",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Book ID",
+                        "name": "bookID",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Progress updates",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/server.ResponseReadingProgress"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid bookID",
+                        "schema": {
+                            "$ref": "#/definitions/server.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/server.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Book is not in user reading",
+                        "schema": {
+                            "$ref": "#/definitions/server.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/server.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "description": "Saves current page or percent of the book with optional note and reading session duration. The first update of a book the user wants to read starts reading it. When percent reaches 100 the response suggests finishing the book, the update with finish flag finishes it with the date of the update. An update of a finished book starts reading it again, the finished reading is kept in reading cycles. Uses access token from an HTTP-only cookie",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Reading progress"
+                ],
+                "summary": "Add reading progress",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Book ID",
+                        "name": "bookID",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Progress update",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/server.RequestReadingProgress"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Saved update with reading status",
+                        "schema": {
+                            "$ref": "#/definitions/server.ResponseReadingProgressUpdate"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid request body or recorded_at before the start or finish date of the book",
+                        "schema": {
+                            "$ref": "#/definitions/server.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/server.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Book is not in user reading",
+                        "schema": {
+                            "$ref": "#/definitions/server.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/server.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/ping": {
+            "get": {
+                "description": "Checks server health. Returns 200 OK if server is up.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Health"
+                ],
+                "summary": "Ping the server",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        }
+    },
+    "definitions": {
+        "server.ErrorResponse": {
+            "type": "object",
+            "properties": {
+                "error": {
+                    "type": "string"
+                }
+            }
+        },
+        "server.ExportReadingCycle": {
+            "type": "object",
+            "properties": {
+                "book_id": {
+                    "type": "string"
+                },
+                "finish_date": {
+                    "type": "string"
+                },
+                "rating": {
+                    "type": "integer"
+                },
+                "start_date": {
+                    "type": "string"
+                }
+            }
+        },
+        "server.ExportReadingProgress": {
+            "type": "object",
+            "properties": {
+                "book_id": {
+                    "type": "string"
+                },
+                "current": {
+                    "description": "Current is set for progress of the current reading cycle, the others are updates of previous cycles.",
+                    "type": "boolean"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "minutes": {
+                    "type": "integer"
+                },
+                "note": {
+                    "type": "string"
+                },
+                "page": {
+                    "type": "integer"
+                },
+                "percent": {
+                    "type": "number"
+                },
+                "recorded_at": {
+                    "type": "string"
+                }
+            }
+        },
+        "server.ExportReview": {
+            "type": "object",
+            "properties": {
+                "body": {
+                    "type": "string"
+                },
+                "book_id": {
+                    "type": "string"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "current": {
+                    "description": "Current is set for the review of the current reading cycle, the others are reviews of previous cycles.",
+                    "type": "boolean"
+                },
+                "edited_at": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "spoiler": {
+                    "type": "boolean"
+                }
+            }
+        },
+        "server.ExportShelf": {
+            "type": "object",
+            "properties": {
+                "book_ids": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "name": {
+                    "type": "string"
+                }
+            }
+        },
+        "server.ExportUserReading": {
+            "type": "object",
+            "properties": {
+                "added_at": {
+                    "type": "string"
+                },
+                "book_id": {
+                    "type": "string"
+                },
+                "finish_date": {
+                    "type": "string"
+                },
+                "rating": {
+                    "type": "integer"
+                },
+                "start_date": {
+                    "type": "string"
+                },
+                "status": {
+                    "type": "string"
+                }
+            }
+        },
+        "server.RequestReadingProgress": {
+            "type": "object",
+            "properties": {
+                "finish": {
+                    "description": "Finish marks the book finished when Percent is 100.",
+                    "type": "boolean"
+                },
+                "minutes": {
+                    "description": "Minutes is the duration of the reading session.",
+                    "type": "integer"
+                },
+                "note": {
+                    "type": "string"
+                },
+                "page": {
+                    "description": "Page or Percent is required, Percent is from 0 to 100.",
+                    "type": "integer"
+                },
+                "percent": {
+                    "type": "number"
+                },
+                "recorded_at": {
+                    "description": "RecordedAt is RFC 3339 time of the update, now by default.",
+                    "type": "string"
+                }
+            }
+        },
+        "server.RequestReview": {
+            "type": "object",
+            "properties": {
+                "body": {
+                    "type": "string"
+                },
+                "book_id": {
+                    "type": "string"
+                },
+                "spoiler": {
+                    "type": "boolean"
+                }
+            }
+        },
+        "server.RequestShelf": {
+            "type": "object",
+            "properties": {
+                "name": {
+                    "type": "string"
+                }
+            }
+        },
+        "server.RequestShelvesOrder": {
+            "type": "object",
+            "properties": {
+                "shelf_ids": {
+                    "description": "ShelfIDs are all shelves of the user in the new order.",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                }
+            }
+        },
+        "server.ResponseBookReview": {
+            "type": "object",
+            "properties": {
+                "body": {
+                    "type": "string"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "display_name": {
+                    "type": "string"
+                },
+                "edited_at": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "rating": {
+                    "type": "integer"
+                },
+                "spoiler": {
+                    "type": "boolean"
+                },
+                "user_id": {
+                    "type": "string"
+                }
+            }
+        },
+        "server.ResponseBookReviewsPage": {
+            "type": "object",
+            "properties": {
+                "items": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/server.ResponseBookReview"
+                    }
+                },
+                "next_cursor": {
+                    "type": "string"
+                }
+            }
+        },
+        "server.ResponseReadingCycle": {
+            "type": "object",
+            "properties": {
+                "current": {
+                    "type": "boolean"
+                },
+                "finish_date": {
+                    "type": "string"
+                },
+                "rating": {
+                    "type": "integer"
+                },
+                "review": {
+                    "description": "Review is the user's review of this read of the book.",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/server.ResponseReview"
+                        }
+                    ]
+                },
+                "start_date": {
+                    "type": "string"
+                }
+            }
+        },
+        "server.ResponseReadingProgress": {
+            "type": "object",
+            "properties": {
+                "id": {
+                    "type": "integer"
+                },
+                "minutes": {
+                    "type": "integer"
+                },
+                "note": {
+                    "type": "string"
+                },
+                "page": {
+                    "type": "integer"
+                },
+                "percent": {
+                    "type": "number"
+                },
+                "recorded_at": {
+                    "type": "string"
+                }
+            }
+        },
+        "server.ResponseReadingProgressUpdate": {
+            "type": "object",
+            "properties": {
+                "progress": {
+                    "$ref": "#/definitions/server.ResponseReadingProgress"
+                },
+                "status": {
+                    "type": "string"
+                },
+                "suggest_finish": {
+                    "type": "boolean"
+                }
+            }
+        },
+        "server.ResponseReview": {
+            "type": "object",
+            "properties": {
+                "body": {
+                    "type": "string"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "edited_at": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "spoiler": {
+                    "type": "boolean"
+                }
+            }
+        },
+        "server.ResponseShelf": {
+            "type": "object",
+            "properties": {
+                "books_count": {
+                    "type": "integer"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "position": {
+                    "type": "integer"
+                }
+            }
+        },
+        "server.ResponseUserReading": {
+            "type": "object",
+            "properties": {
+                "authors": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "id": {
+                    "type": "string"
+                },
+                "rating": {
+                    "type": "integer"
+                },
+                "status": {
+                    "type": "string"
+                },
+                "title": {
+                    "type": "string"
+                }
+            }
+        },
+        "server.ResponseUserReadingExport": {
+            "type": "object",
+            "properties": {
+                "books": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/server.ExportUserReading"
+                    }
+                },
+                "cycles": {
+                    "description": "Cycles are finished reads of the books before their current cycle.",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/server.ExportReadingCycle"
+                    }
+                },
+                "progress": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/server.ExportReadingProgress"
+                    }
+                },
+                "reviews": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/server.ExportReview"
+                    }
+                },
+                "shelves": {
+                    "description": "Shelves are custom shelves of the user in their order.",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/server.ExportShelf"
+                    }
+                }
+            }
+        },
+        "server.ResponseUserReadingFullInfo": {
+            "type": "object",
+            "properties": {
+                "authors": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "cycles": {
+                    "description": "Cycles are all reads of the book, the oldest first and the current one last.",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/server.ResponseReadingCycle"
+                    }
+                },
+                "finish_date": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "rating": {
+                    "type": "integer"
+                },
+                "start_date": {
+                    "type": "string"
+                },
+                "status": {
+                    "type": "string"
+                },
+                "title": {
+                    "type": "string"
+                }
+            }
+        },
+        "server.ResponseUserReadingStats": {
+            "type": "object",
+            "properties": {
+                "finished": {
+                    "type": "integer"
+                },
+                "reading": {
+                    "type": "integer"
+                },
+                "reads": {
+                    "description": "Reads is the number of finished reads including re-reads of the same book.",
+                    "type": "integer"
+                },
+                "reread_books": {
+                    "description": "RereadBooks is the number of books that were finished at least twice.",
+                    "type": "integer"
+                },
+                "want_to_read": {
+                    "type": "integer"
                 }
             }
         },
@@ -302,6 +1400,7 @@ const docTemplate = `{
                     "type": "string"
                 },
                 "rating": {
+                    "description": "Rating of a finished book is from 1 to 5, 0 means the book is not rated.",
                     "type": "integer"
                 },
                 "start_date": {
@@ -0,0 +1,123 @@
+package auth
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"slices"
+	"strings"
+
+	"github.com/bakurvik/mylib/shared/jwks"
+	"github.com/bakurvik/mylib/user-reading/internal/clients"
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/google/uuid"
+)
+
+// Issuer is iss claim of access tokens issued by users service.
+const Issuer = "mylib.users"
+
+// PersonalTokenPrefix starts personal access tokens issued by users service. Unlike access tokens they are opaque,
+// so they are checked by users service.
+const PersonalTokenPrefix = "mylib_pat_"
+
+// Scopes of personal access tokens for user reading. Access tokens have all of them.
+const (
+	ScopeReadingRead  = "reading:read"
+	ScopeReadingWrite = "reading:write"
+)
+
+var (
+	// ErrUnauthorized means that request has no valid access token.
+	ErrUnauthorized = errors.New("Unauthorized")
+	// ErrForbidden means that request has a valid personal access token without the required scope.
+	ErrForbidden = errors.New("Forbidden")
+)
+
+// Authenticator finds out which user sent the request and checks that the token has the scope.
+// It returns ErrUnauthorized if the user is unknown and ErrForbidden if the scope is missing.
+type Authenticator interface {
+	Authenticate(r *http.Request, scope string) (uuid.UUID, error)
+}
+
+func IsPersonalToken(token string) bool {
+	return strings.HasPrefix(token, PersonalTokenPrefix)
+}
+
+// Claims are JWT claims of access tokens issued by users service.
+type Claims struct {
+	Role string `json:"role"`
+	jwt.RegisteredClaims
+}
+
+// ValidateJWT checks access token with the public key returned by keyfunc and returns user ID from it.
+func ValidateJWT(tokenString string, keyfunc jwt.Keyfunc) (uuid.UUID, error) {
+	claims := Claims{}
+	_, err := jwt.ParseWithClaims(tokenString, &claims, keyfunc, jwt.WithValidMethods(jwks.Algorithms), jwt.WithIssuer(Issuer))
+	if err != nil {
+		return uuid.Nil, err
+	}
+	return uuid.Parse(claims.Subject)
+}
+
+func GetBearerToken(headers http.Header) (string, error) {
+	authHeader := headers.Get("Authorization")
+	if authHeader == "" {
+		return "", errors.New("no authorization header")
+	}
+	token, found := strings.CutPrefix(authHeader, "Bearer ")
+	if !found {
+		return "", errors.New("no token in header")
+	}
+	return token, nil
+}
+
+// LocalAuthenticator verifies access tokens without calling users service. Tokens that can't be verified because
+// their key is unavailable, e.g. JWKS can't be fetched, are passed to Fallback if it is set.
+// Personal access tokens are passed to PersonalTokens, they are rejected if it is not set.
+type LocalAuthenticator struct {
+	Keys           jwt.Keyfunc
+	Fallback       Authenticator
+	PersonalTokens Authenticator
+}
+
+func (a *LocalAuthenticator) Authenticate(r *http.Request, scope string) (uuid.UUID, error) {
+	token, err := GetBearerToken(r.Header)
+	if err != nil {
+		return uuid.Nil, ErrUnauthorized
+	}
+	if IsPersonalToken(token) {
+		if a.PersonalTokens == nil {
+			return uuid.Nil, ErrUnauthorized
+		}
+		return a.PersonalTokens.Authenticate(r, scope)
+	}
+	userID, err := ValidateJWT(token, a.Keys)
+	if errors.Is(err, jwt.ErrTokenUnverifiable) && a.Fallback != nil {
+		return a.Fallback.Authenticate(r, scope)
+	}
+	if err != nil {
+		return uuid.Nil, ErrUnauthorized
+	}
+	return userID, nil
+}
+
+// WhoamiAuthenticator asks users service who the user is on every request. It checks both access tokens
+// and personal access tokens.
+type WhoamiAuthenticator struct {
+	UsersServiceHost string
+}
+
+func (a *WhoamiAuthenticator) Authenticate(r *http.Request, scope string) (uuid.UUID, error) {
+	userID, scopes, statusCode, err := clients.GetUser(r.Header, a.UsersServiceHost)
+	if statusCode == http.StatusUnauthorized {
+		return uuid.Nil, ErrUnauthorized
+	}
+	if err != nil {
+		return uuid.Nil, fmt.Errorf("failed to get user: %w", err)
+	}
+	token, _ := GetBearerToken(r.Header)
+	if IsPersonalToken(token) && !slices.Contains(scopes, scope) {
+		return uuid.Nil, ErrForbidden
+	}
+	return userID, nil
+}
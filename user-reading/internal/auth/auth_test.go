@@ -0,0 +1,184 @@
+package auth
+
+import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	common "github.com/bakurvik/mylib-common"
+	"github.com/bakurvik/mylib/user-reading/internal/clients"
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+type staticAuthenticator struct {
+	userID uuid.UUID
+	err    error
+	calls  int
+}
+
+func (a *staticAuthenticator) Authenticate(r *http.Request, scope string) (uuid.UUID, error) {
+	a.calls++
+	return a.userID, a.err
+}
+
+func makeToken(t *testing.T, key ed25519.PrivateKey, kid string, userID uuid.UUID, expiresIn time.Duration) string {
+	claims := Claims{
+		RegisteredClaims: jwt.RegisteredClaims{
+			Issuer:    Issuer,
+			Subject:   userID.String(),
+			ExpiresAt: jwt.NewNumericDate(time.Now().Add(expiresIn)),
+		},
+	}
+	token := jwt.NewWithClaims(jwt.SigningMethodEdDSA, claims)
+	token.Header["kid"] = kid
+	tokenString, err := token.SignedString(key)
+	assert.NoError(t, err)
+	return tokenString
+}
+
+func TestLocalAuthenticator(t *testing.T) {
+	userID := uuid.New()
+	fallbackUserID := uuid.New()
+	personalTokenUserID := uuid.New()
+	publicKey, privateKey, _ := ed25519.GenerateKey(rand.Reader)
+	_, anotherPrivateKey, _ := ed25519.GenerateKey(rand.Reader)
+	keys := func(token *jwt.Token) (interface{}, error) {
+		if token.Header["kid"] != "kid" {
+			return nil, errors.New("unknown key id")
+		}
+		return publicKey, nil
+	}
+
+	type testCase struct {
+		name               string
+		authHeader         string
+		withFallback       bool
+		withPersonalTokens bool
+		expectedUserID     uuid.UUID
+		expectedErr        error
+		fallbackCalled     bool
+	}
+	testCases := []testCase{
+		{
+			name:           "valid",
+			authHeader:     "Bearer " + makeToken(t, privateKey, "kid", userID, time.Hour),
+			withFallback:   true,
+			expectedUserID: userID,
+		},
+		{
+			name:         "no_token",
+			authHeader:   "",
+			withFallback: true,
+			expectedErr:  ErrUnauthorized,
+		},
+		{
+			name:         "expired",
+			authHeader:   "Bearer " + makeToken(t, privateKey, "kid", userID, -time.Hour),
+			withFallback: true,
+			expectedErr:  ErrUnauthorized,
+		},
+		{
+			name:         "invalid_signature",
+			authHeader:   "Bearer " + makeToken(t, anotherPrivateKey, "kid", userID, time.Hour),
+			withFallback: true,
+			expectedErr:  ErrUnauthorized,
+		},
+		{
+			name:         "unknown_key_without_fallback",
+			authHeader:   "Bearer " + makeToken(t, anotherPrivateKey, "another", userID, time.Hour),
+			withFallback: false,
+			expectedErr:  ErrUnauthorized,
+		},
+		{
+			name:           "unknown_key_with_fallback",
+			authHeader:     "Bearer " + makeToken(t, anotherPrivateKey, "another", userID, time.Hour),
+			withFallback:   true,
+			expectedUserID: fallbackUserID,
+			fallbackCalled: true,
+		},
+		{
+			name:               "personal_token",
+			authHeader:         "Bearer mylib_pat_token",
+			withFallback:       true,
+			withPersonalTokens: true,
+			expectedUserID:     personalTokenUserID,
+		},
+		{
+			name:         "personal_token_not_accepted",
+			authHeader:   "Bearer mylib_pat_token",
+			withFallback: true,
+			expectedErr:  ErrUnauthorized,
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			fallback := &staticAuthenticator{userID: fallbackUserID}
+			authenticator := LocalAuthenticator{Keys: keys}
+			if tc.withFallback {
+				authenticator.Fallback = fallback
+			}
+			if tc.withPersonalTokens {
+				authenticator.PersonalTokens = &staticAuthenticator{userID: personalTokenUserID}
+			}
+
+			r := httptest.NewRequest(http.MethodGet, "/", nil)
+			if tc.authHeader != "" {
+				r.Header.Set("Authorization", tc.authHeader)
+			}
+			userID, err := authenticator.Authenticate(r, ScopeReadingRead)
+			assert.Equal(t, err, tc.expectedErr)
+			assert.Equal(t, userID, tc.expectedUserID)
+			assert.Equal(t, fallback.calls > 0, tc.fallbackCalled)
+		})
+	}
+}
+
+func TestWhoamiAuthenticator(t *testing.T) {
+	userID := uuid.New()
+	type testCase struct {
+		name           string
+		token          string
+		statusCode     int
+		scopes         []string
+		expectedUserID uuid.UUID
+		expectedErr    error
+		hasError       bool
+	}
+	testCases := []testCase{
+		{name: "success", token: "token", statusCode: http.StatusOK, expectedUserID: userID},
+		{name: "unauthorized", token: "token", statusCode: http.StatusUnauthorized, expectedErr: ErrUnauthorized, hasError: true},
+		{name: "users_service_error", token: "token", statusCode: http.StatusInternalServerError, hasError: true},
+		{name: "personal_token", token: "mylib_pat_token", statusCode: http.StatusOK, scopes: []string{ScopeReadingRead}, expectedUserID: userID},
+		{name: "personal_token_without_scope", token: "mylib_pat_token", statusCode: http.StatusOK, scopes: []string{ScopeReadingWrite}, expectedErr: ErrForbidden, hasError: true},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			usersServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				assert.Equal(t, r.URL.Path, clients.UsersAuthWhoamiPath)
+				assert.Equal(t, r.Header.Get("Authorization"), "Bearer "+tc.token)
+				if tc.statusCode != http.StatusOK {
+					common.RespondWithError(w, tc.statusCode, "error")
+					return
+				}
+				common.RespondWithJSON(w, tc.statusCode, clients.ResponseUserID{ID: userID.String(), Scopes: tc.scopes}, nil)
+			}))
+			defer usersServer.Close()
+
+			r := httptest.NewRequest(http.MethodGet, "/", nil)
+			r.Header.Set("Authorization", "Bearer "+tc.token)
+			authenticator := WhoamiAuthenticator{UsersServiceHost: usersServer.URL}
+			result, err := authenticator.Authenticate(r, ScopeReadingRead)
+			assert.Equal(t, err != nil, tc.hasError)
+			if tc.expectedErr != nil {
+				assert.ErrorIs(t, err, tc.expectedErr)
+			}
+			assert.Equal(t, result, tc.expectedUserID)
+		})
+	}
+}
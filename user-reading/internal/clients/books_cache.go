@@ -10,6 +10,17 @@ type cacheBookInfo struct {
 	info      ResponseBookFullInfo
 	ready     chan struct{}
 	updatedAt time.Time
+	// stale is set when the book changed while the lookup was in flight, its result is dropped from cache then.
+	stale bool
+}
+
+func (cb *cacheBookInfo) inFlight() bool {
+	select {
+	case <-cb.ready:
+		return false
+	default:
+		return true
+	}
 }
 
 type booksCache struct {
@@ -29,7 +40,7 @@ func (bc *booksCache) closeChannels(bookIDs []string) {
 			default:
 				close(cacheBook.ready)
 			}
-			if cacheBook.info.ID == "" {
+			if cacheBook.info.ID == "" || cacheBook.stale {
 				delete(bc.IDToInfo, bookID)
 			}
 		}
@@ -37,7 +48,8 @@ func (bc *booksCache) closeChannels(bookIDs []string) {
 	}
 }
 
-func (bc *booksCache) update(bookInfo ResponseBookFullInfo) {
+// store saves book info returned by library for the lookup in flight.
+func (bc *booksCache) store(bookInfo ResponseBookFullInfo) {
 	bc.mu.Lock()
 	if _, ok := bc.IDToInfo[bookInfo.ID]; ok {
 		bc.IDToInfo[bookInfo.ID].info = bookInfo
@@ -46,6 +58,20 @@ func (bc *booksCache) update(bookInfo ResponseBookFullInfo) {
 	bc.mu.Unlock()
 }
 
+// update applies book update event. The lookup in flight may return data older than the event, so it is marked stale instead.
+func (bc *booksCache) update(bookInfo ResponseBookFullInfo) {
+	bc.mu.Lock()
+	if cacheBook, ok := bc.IDToInfo[bookInfo.ID]; ok {
+		if cacheBook.inFlight() {
+			cacheBook.stale = true
+		} else {
+			cacheBook.info = bookInfo
+			cacheBook.updatedAt = time.Now()
+		}
+	}
+	bc.mu.Unlock()
+}
+
 func (bc *booksCache) prepareLookup(bookID string, requestBookIDs *[]string, updateInfoWG *sync.WaitGroup, booksInfoMU *sync.Mutex, booksInfo map[string]ResponseBookFullInfo) {
 	bc.mu.Lock()
 	info, ok := bc.IDToInfo[bookID]
@@ -105,3 +131,16 @@ func CleanupBooksCache(oldDataThreshold time.Duration, ticker Ticker) {
 		log.Printf("Deleted %v elements from books cache", len(keysToDelete))
 	}
 }
+
+// delete applies book delete event. The entry of the lookup in flight is marked stale and deleted when the lookup closes its channel.
+func (bc *booksCache) delete(bookID string) {
+	bc.mu.Lock()
+	if cacheBook, ok := bc.IDToInfo[bookID]; ok {
+		if cacheBook.inFlight() {
+			cacheBook.stale = true
+		} else {
+			delete(bc.IDToInfo, bookID)
+		}
+	}
+	bc.mu.Unlock()
+}
@@ -1,6 +1,7 @@
 package clients
 
 import (
+	"sync"
 	"testing"
 	"time"
 
@@ -87,3 +88,43 @@ func TestCleanupBooksCache(t *testing.T) {
 		})
 	}
 }
+
+func TestBooksCacheChangedDuringLookup(t *testing.T) {
+	bookID := uuid.NewString()
+	type testCase struct {
+		name   string
+		change func()
+	}
+	testCases := []testCase{
+		{
+			name:   "deleted",
+			change: func() { bc.delete(bookID) },
+		},
+		{
+			name:   "updated",
+			change: func() { bc.update(ResponseBookFullInfo{ID: bookID, Title: "Anna Karenina"}) },
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			cleanupAndFillCache(nil)
+			requestBookIDs := make([]string, 0)
+			booksInfo := make(map[string]ResponseBookFullInfo)
+			wg := sync.WaitGroup{}
+			bc.prepareLookup(bookID, &requestBookIDs, &wg, &sync.Mutex{}, booksInfo)
+			assert.Equal(t, requestBookIDs, []string{bookID})
+
+			tc.change()
+			bc.store(ResponseBookFullInfo{ID: bookID, Title: "War and Peace"})
+			bc.closeChannels(requestBookIDs)
+			wg.Wait()
+
+			assert.Equal(t, booksInfo, map[string]ResponseBookFullInfo{bookID: {ID: bookID, Title: "War and Peace"}})
+			bc.mu.Lock()
+			_, ok := bc.IDToInfo[bookID]
+			bc.mu.Unlock()
+			assert.False(t, ok)
+		})
+	}
+}
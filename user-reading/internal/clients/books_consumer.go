@@ -0,0 +1,46 @@
+package clients
+
+import (
+	"context"
+	"encoding/json"
+	"log"
+
+	"github.com/bakurvik/mylib/shared/messages"
+	"github.com/segmentio/kafka-go"
+)
+
+const (
+	bookActionCreated = "created"
+	bookActionUpdated = "updated"
+	bookActionDeleted = "deleted"
+)
+
+type KafkaReader interface {
+	FetchMessage(ctx context.Context) (kafka.Message, error)
+	CommitMessages(ctx context.Context, msgs ...kafka.Message) error
+}
+
+func handleBookMessage(msg kafka.Message) {
+	bookMessage := messages.BookMessage{}
+	err := json.Unmarshal(msg.Value, &bookMessage)
+	if err != nil {
+		log.Print("Failed to parse book message: ", err)
+		return
+	}
+	switch bookMessage.Action {
+	case bookActionCreated, bookActionUpdated:
+		bc.update(ResponseBookFullInfo{ID: bookMessage.ID, Title: bookMessage.Title, Authors: bookMessage.Authors})
+	case bookActionDeleted:
+		bc.delete(bookMessage.ID)
+	default:
+		log.Print("Unknown book action: ", bookMessage.Action)
+	}
+}
+
+// ConsumeBooksMessages keeps books cache in sync with library changes until the reader is closed or ctx is cancelled.
+func ConsumeBooksMessages(ctx context.Context, reader KafkaReader) {
+	consume(ctx, reader, "book", func(msg kafka.Message) error {
+		handleBookMessage(msg)
+		return nil
+	})
+}
@@ -0,0 +1,127 @@
+package clients
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"testing"
+
+	"github.com/bakurvik/mylib/shared/messages"
+	"github.com/google/uuid"
+	"github.com/segmentio/kafka-go"
+	"github.com/stretchr/testify/assert"
+)
+
+type fakeKafkaReader struct {
+	messages    []kafka.Message
+	committed   []kafka.Message
+	fetchErrors int
+}
+
+func (r *fakeKafkaReader) FetchMessage(ctx context.Context) (kafka.Message, error) {
+	if r.fetchErrors > 0 {
+		r.fetchErrors--
+		return kafka.Message{}, errors.New("broker is unavailable")
+	}
+	if len(r.messages) == 0 {
+		return kafka.Message{}, io.EOF
+	}
+	msg := r.messages[0]
+	r.messages = r.messages[1:]
+	return msg, nil
+}
+
+func (r *fakeKafkaReader) CommitMessages(ctx context.Context, msgs ...kafka.Message) error {
+	r.committed = append(r.committed, msgs...)
+	return nil
+}
+
+func makeBookMessage(t *testing.T, message messages.BookMessage) kafka.Message {
+	value, err := json.Marshal(message)
+	assert.NoError(t, err)
+	return kafka.Message{Key: []byte(message.ID), Value: value}
+}
+
+func closedChannel() chan struct{} {
+	ch := make(chan struct{})
+	close(ch)
+	return ch
+}
+
+func TestConsumeBooksMessages(t *testing.T) {
+	bookID := uuid.NewString()
+	type testCase struct {
+		name          string
+		cacheBooks    []cacheBookInfo
+		messages      []messages.BookMessage
+		expectedCache map[string]ResponseBookFullInfo
+	}
+	testCases := []testCase{
+		{
+			name:       "updated",
+			cacheBooks: []cacheBookInfo{{info: ResponseBookFullInfo{ID: bookID, Title: "War and Peace"}, ready: closedChannel()}},
+			messages:   []messages.BookMessage{{ID: bookID, Title: "Anna Karenina", Authors: []string{"Leo Tolstoy"}, Action: "updated"}},
+			expectedCache: map[string]ResponseBookFullInfo{
+				bookID: {ID: bookID, Title: "Anna Karenina", Authors: []string{"Leo Tolstoy"}},
+			},
+		},
+		{
+			name:          "not_cached",
+			cacheBooks:    []cacheBookInfo{},
+			messages:      []messages.BookMessage{{ID: bookID, Title: "War and Peace", Action: "created"}},
+			expectedCache: map[string]ResponseBookFullInfo{},
+		},
+		{
+			name:          "deleted",
+			cacheBooks:    []cacheBookInfo{{info: ResponseBookFullInfo{ID: bookID, Title: "War and Peace"}, ready: closedChannel()}},
+			messages:      []messages.BookMessage{{ID: bookID, Action: "deleted"}},
+			expectedCache: map[string]ResponseBookFullInfo{},
+		},
+		{
+			name:       "deleted_while_lookup_in_progress",
+			cacheBooks: []cacheBookInfo{{info: ResponseBookFullInfo{ID: bookID}, ready: make(chan struct{})}},
+			messages:   []messages.BookMessage{{ID: bookID, Action: "deleted"}},
+			expectedCache: map[string]ResponseBookFullInfo{
+				bookID: {ID: bookID},
+			},
+		},
+		{
+			name:       "updated_while_lookup_in_progress",
+			cacheBooks: []cacheBookInfo{{info: ResponseBookFullInfo{ID: bookID}, ready: make(chan struct{})}},
+			messages:   []messages.BookMessage{{ID: bookID, Title: "Anna Karenina", Action: "updated"}},
+			expectedCache: map[string]ResponseBookFullInfo{
+				bookID: {ID: bookID},
+			},
+		},
+		{
+			name:       "unknown_action",
+			cacheBooks: []cacheBookInfo{{info: ResponseBookFullInfo{ID: bookID, Title: "War and Peace"}, ready: closedChannel()}},
+			messages:   []messages.BookMessage{{ID: bookID, Action: "unknown"}},
+			expectedCache: map[string]ResponseBookFullInfo{
+				bookID: {ID: bookID, Title: "War and Peace"},
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			cleanupAndFillCache(tc.cacheBooks)
+			reader := fakeKafkaReader{}
+			for _, message := range tc.messages {
+				reader.messages = append(reader.messages, makeBookMessage(t, message))
+			}
+
+			ConsumeBooksMessages(context.Background(), &reader)
+
+			assert.Equal(t, len(reader.committed), len(tc.messages))
+			cache := make(map[string]ResponseBookFullInfo)
+			bc.mu.Lock()
+			for id, book := range bc.IDToInfo {
+				cache[id] = book.info
+			}
+			bc.mu.Unlock()
+			assert.Equal(t, cache, tc.expectedCache)
+		})
+	}
+}
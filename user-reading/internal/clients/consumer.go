@@ -0,0 +1,51 @@
+package clients
+
+import (
+	"context"
+	"errors"
+	"io"
+	"log"
+	"time"
+
+	"github.com/segmentio/kafka-go"
+)
+
+// fetchRetryDelay is the first pause after a failed fetch, it doubles with every failure in a row up to maxFetchRetryDelay.
+var (
+	fetchRetryDelay    = time.Second
+	maxFetchRetryDelay = 30 * time.Second
+)
+
+// consume passes messages of the topic to handle and commits them until the reader is closed or ctx is cancelled.
+// Failed fetches are retried with backoff, so a broker outage pauses consuming instead of stopping it.
+// An error of handle stops consuming without committing the message.
+func consume(ctx context.Context, reader KafkaReader, topic string, handle func(msg kafka.Message) error) {
+	delay := fetchRetryDelay
+	for {
+		msg, err := reader.FetchMessage(ctx)
+		if errors.Is(err, io.EOF) || errors.Is(err, context.Canceled) || ctx.Err() != nil {
+			return
+		}
+		if err != nil {
+			log.Printf("Failed to fetch %v message, retrying in %v: %v", topic, delay, err)
+			select {
+			case <-ctx.Done():
+				return
+			case <-time.After(delay):
+			}
+			delay = min(delay*2, maxFetchRetryDelay)
+			continue
+		}
+		delay = fetchRetryDelay
+
+		err = handle(msg)
+		if err != nil {
+			return
+		}
+
+		err = reader.CommitMessages(ctx, msg)
+		if err != nil {
+			log.Printf("Failed to commit %v message: %v", topic, err)
+		}
+	}
+}
@@ -0,0 +1,35 @@
+package clients
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/segmentio/kafka-go"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestConsumeFetchErrors(t *testing.T) {
+	fetchRetryDelay = time.Millisecond
+	reader := fakeKafkaReader{messages: []kafka.Message{{Key: []byte("1")}, {Key: []byte("2")}}, fetchErrors: 3}
+	handled := []string{}
+
+	consume(context.Background(), &reader, "test", func(msg kafka.Message) error {
+		handled = append(handled, string(msg.Key))
+		return nil
+	})
+
+	assert.Equal(t, handled, []string{"1", "2"})
+	assert.Equal(t, len(reader.committed), 2)
+}
+
+func TestConsumeCancelledWhileRetrying(t *testing.T) {
+	fetchRetryDelay = time.Millisecond
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	reader := fakeKafkaReader{fetchErrors: 1 << 30}
+
+	consume(ctx, &reader, "test", func(msg kafka.Message) error { return nil })
+
+	assert.Empty(t, reader.committed)
+}
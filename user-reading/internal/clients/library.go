@@ -49,7 +49,7 @@ func GetBooksInfoWithCache(bookIDs []string, host string) (int, map[string]Respo
 		return 0, nil, err
 	}
 	for _, bookInfo := range responseData {
-		bc.update(bookInfo)
+		bc.store(bookInfo)
 	}
 	bc.closeChannels(request.BookIDs)
 
@@ -7,7 +7,8 @@ const (
 )
 
 type ResponseUserID struct {
-	ID string `json:"user_id"`
+	ID     string   `json:"user_id"`
+	Scopes []string `json:"scopes,omitempty"`
 }
 
 type ResponseBookFullInfo struct {
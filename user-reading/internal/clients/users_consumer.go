@@ -0,0 +1,93 @@
+package clients
+
+import (
+	"context"
+	"encoding/json"
+	"log"
+	"time"
+
+	"github.com/bakurvik/mylib/user-reading/internal/database"
+	"github.com/google/uuid"
+	"github.com/segmentio/kafka-go"
+)
+
+const (
+	userActionDeleted = "deleted"
+	userActionUpdated = "updated"
+)
+
+// userMessageRetryDelay is the pause before handling a user message again after a DB failure.
+var userMessageRetryDelay = 5 * time.Second
+
+type UserMessage struct {
+	ID                string `json:"id"`
+	Action            string `json:"action"`
+	DisplayName       string `json:"display_name,omitempty"`
+	ReadingListPublic bool   `json:"reading_list_public,omitempty"`
+}
+
+// UsersStore keeps data of users service in DB, database.Queries implements it.
+type UsersStore interface {
+	DeleteAllUserReading(ctx context.Context, userID uuid.UUID) (int64, error)
+	UpsertUserProfile(ctx context.Context, arg database.UpsertUserProfileParams) error
+}
+
+// retryUserMessage calls handle until it succeeds, because the message is committed afterwards
+// and the change would be lost otherwise.
+func retryUserMessage(ctx context.Context, userID uuid.UUID, handle func() error) error {
+	for {
+		err := handle()
+		if err == nil {
+			return nil
+		}
+		log.Printf("Failed to handle message of user %v: %v", userID, err)
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(userMessageRetryDelay):
+		}
+	}
+}
+
+// handleUserMessage deletes reading data of a deleted user and saves display name and visibility
+// of an updated profile, reviews of users with public profiles are shown to everyone.
+func handleUserMessage(ctx context.Context, msg kafka.Message, store UsersStore) error {
+	userMessage := UserMessage{}
+	err := json.Unmarshal(msg.Value, &userMessage)
+	if err != nil {
+		log.Print("Failed to parse user message: ", err)
+		return nil
+	}
+	if userMessage.Action != userActionDeleted && userMessage.Action != userActionUpdated {
+		return nil
+	}
+	userID, err := uuid.Parse(userMessage.ID)
+	if err != nil {
+		log.Print("Invalid user id in user message: ", userMessage.ID)
+		return nil
+	}
+
+	if userMessage.Action == userActionUpdated {
+		return retryUserMessage(ctx, userID, func() error {
+			return store.UpsertUserProfile(ctx, database.UpsertUserProfileParams{
+				UserID:      userID,
+				DisplayName: userMessage.DisplayName,
+				Public:      userMessage.ReadingListPublic,
+			})
+		})
+	}
+	return retryUserMessage(ctx, userID, func() error {
+		count, err := store.DeleteAllUserReading(ctx, userID)
+		if err == nil {
+			log.Printf("Deleted %d reading records of deleted user %v", count, userID)
+		}
+		return err
+	})
+}
+
+// ConsumeUsersMessages handles events of users service until the reader is closed or ctx is cancelled.
+func ConsumeUsersMessages(ctx context.Context, reader KafkaReader, store UsersStore) {
+	consume(ctx, reader, "user", func(msg kafka.Message) error {
+		return handleUserMessage(ctx, msg, store)
+	})
+}
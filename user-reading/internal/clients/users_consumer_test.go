@@ -0,0 +1,126 @@
+package clients
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/bakurvik/mylib/user-reading/internal/database"
+	"github.com/google/uuid"
+	"github.com/segmentio/kafka-go"
+	"github.com/stretchr/testify/assert"
+)
+
+type fakeUsersStore struct {
+	failuresLeft int
+	deleted      []uuid.UUID
+	profiles     []database.UpsertUserProfileParams
+}
+
+func (s *fakeUsersStore) DeleteAllUserReading(ctx context.Context, userID uuid.UUID) (int64, error) {
+	if s.failuresLeft > 0 {
+		s.failuresLeft--
+		return 0, errors.New("db is unavailable")
+	}
+	s.deleted = append(s.deleted, userID)
+	return 1, nil
+}
+
+func (s *fakeUsersStore) UpsertUserProfile(ctx context.Context, arg database.UpsertUserProfileParams) error {
+	if s.failuresLeft > 0 {
+		s.failuresLeft--
+		return errors.New("db is unavailable")
+	}
+	s.profiles = append(s.profiles, arg)
+	return nil
+}
+
+func makeUserMessage(t *testing.T, message UserMessage) kafka.Message {
+	value, err := json.Marshal(message)
+	assert.NoError(t, err)
+	return kafka.Message{Key: []byte(message.ID), Value: value}
+}
+
+func TestConsumeUsersMessages(t *testing.T) {
+	userMessageRetryDelay = time.Millisecond
+	fetchRetryDelay = time.Millisecond
+	userID := uuid.New()
+	type testCase struct {
+		name             string
+		messages         []kafka.Message
+		fetchErrors      int
+		failures         int
+		expectedDeleted  []uuid.UUID
+		expectedProfiles []database.UpsertUserProfileParams
+	}
+	testCases := []testCase{
+		{
+			name:            "deleted",
+			messages:        []kafka.Message{makeUserMessage(t, UserMessage{ID: userID.String(), Action: "deleted"})},
+			expectedDeleted: []uuid.UUID{userID},
+		},
+		{
+			name:            "deleted_after_failures",
+			messages:        []kafka.Message{makeUserMessage(t, UserMessage{ID: userID.String(), Action: "deleted"})},
+			failures:        2,
+			expectedDeleted: []uuid.UUID{userID},
+		},
+		{
+			name: "updated_after_failure",
+			messages: []kafka.Message{
+				makeUserMessage(t, UserMessage{ID: userID.String(), Action: "updated", DisplayName: "Name", ReadingListPublic: true}),
+				makeUserMessage(t, UserMessage{ID: userID.String(), Action: "updated"}),
+			},
+			failures: 1,
+			expectedProfiles: []database.UpsertUserProfileParams{
+				{UserID: userID, DisplayName: "Name", Public: true},
+				{UserID: userID},
+			},
+		},
+		{
+			name:            "deleted_after_fetch_errors",
+			messages:        []kafka.Message{makeUserMessage(t, UserMessage{ID: userID.String(), Action: "deleted"})},
+			fetchErrors:     2,
+			expectedDeleted: []uuid.UUID{userID},
+		},
+		{
+			name:     "unknown_action",
+			messages: []kafka.Message{makeUserMessage(t, UserMessage{ID: userID.String(), Action: "created"})},
+		},
+		{
+			name:     "invalid_user_id",
+			messages: []kafka.Message{makeUserMessage(t, UserMessage{ID: "invalid", Action: "deleted"})},
+		},
+		{
+			name:     "invalid_message",
+			messages: []kafka.Message{{Value: []byte("{")}},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			reader := fakeKafkaReader{messages: tc.messages, fetchErrors: tc.fetchErrors}
+			store := fakeUsersStore{failuresLeft: tc.failures}
+
+			ConsumeUsersMessages(context.Background(), &reader, &store)
+
+			assert.Equal(t, len(reader.committed), len(tc.messages))
+			assert.Equal(t, store.deleted, tc.expectedDeleted)
+			assert.Equal(t, store.profiles, tc.expectedProfiles)
+		})
+	}
+}
+
+func TestConsumeUsersMessagesCancelled(t *testing.T) {
+	userMessageRetryDelay = time.Millisecond
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	reader := fakeKafkaReader{messages: []kafka.Message{makeUserMessage(t, UserMessage{ID: uuid.NewString(), Action: "deleted"})}}
+	store := fakeUsersStore{failuresLeft: 1 << 30}
+
+	ConsumeUsersMessages(ctx, &reader, &store)
+
+	assert.Empty(t, reader.committed)
+}
@@ -0,0 +1,29 @@
+// Code generated by sqlc. DO NOT EDIT.
+// versions:
+//   sqlc v1.28.0
+// source: add_shelf_book.sql
+
+package database
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+)
+
+const addShelfBook = `-- name: AddShelfBook :exec
+INSERT INTO shelf_books (shelf_id, user_id, book_id)
+VALUES ($1, $2, $3)
+ON CONFLICT (shelf_id, book_id) DO NOTHING
+`
+
+type AddShelfBookParams struct {
+	ShelfID uuid.UUID
+	UserID  uuid.UUID
+	BookID  uuid.UUID
+}
+
+func (q *Queries) AddShelfBook(ctx context.Context, arg AddShelfBookParams) error {
+	_, err := q.db.ExecContext(ctx, addShelfBook, arg.ShelfID, arg.UserID, arg.BookID)
+	return err
+}
@@ -0,0 +1,45 @@
+// Code generated by sqlc. DO NOT EDIT.
+// versions:
+//   sqlc v1.28.0
+// source: archive_reading_cycle.sql
+
+package database
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+)
+
+const archiveReadingCycle = `-- name: ArchiveReadingCycle :execrows
+WITH archived AS (
+    INSERT INTO reading_cycles (user_id, book_id, rating, start_date, finish_date)
+    SELECT user_reading.user_id, user_reading.book_id, user_reading.rating, user_reading.start_date, user_reading.finish_date
+    FROM user_reading
+    WHERE user_reading.user_id = $1 AND user_reading.book_id = $2 AND user_reading.status = 'finished'
+    RETURNING reading_cycles.id, reading_cycles.user_id, reading_cycles.book_id
+),
+archived_progress AS (
+    UPDATE reading_progress SET cycle_id = archived.id
+    FROM archived
+    WHERE reading_progress.user_id = archived.user_id AND reading_progress.book_id = archived.book_id AND reading_progress.cycle_id IS NULL
+)
+UPDATE reviews SET cycle_id = archived.id
+FROM archived
+WHERE reviews.user_id = archived.user_id AND reviews.book_id = archived.book_id AND reviews.cycle_id IS NULL
+`
+
+type ArchiveReadingCycleParams struct {
+	UserID uuid.UUID
+	BookID uuid.UUID
+}
+
+// Progress updates of the finished cycle are kept with it.
+// The review of the finished cycle is kept with it.
+func (q *Queries) ArchiveReadingCycle(ctx context.Context, arg ArchiveReadingCycleParams) (int64, error) {
+	result, err := q.db.ExecContext(ctx, archiveReadingCycle, arg.UserID, arg.BookID)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
@@ -0,0 +1,47 @@
+// Code generated by sqlc. DO NOT EDIT.
+// versions:
+//   sqlc v1.28.0
+// source: create_reading_progress.sql
+
+package database
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+const createReadingProgress = `-- name: CreateReadingProgress :one
+INSERT INTO reading_progress (user_id, book_id, page, percent, minutes, note, recorded_at)
+VALUES (
+    $1, $2, $3, $4, $5, $6, $7
+)
+RETURNING id
+`
+
+type CreateReadingProgressParams struct {
+	UserID     uuid.UUID
+	BookID     uuid.UUID
+	Page       sql.NullInt32
+	Percent    sql.NullFloat64
+	Minutes    int32
+	Note       string
+	RecordedAt time.Time
+}
+
+func (q *Queries) CreateReadingProgress(ctx context.Context, arg CreateReadingProgressParams) (int64, error) {
+	row := q.db.QueryRowContext(ctx, createReadingProgress,
+		arg.UserID,
+		arg.BookID,
+		arg.Page,
+		arg.Percent,
+		arg.Minutes,
+		arg.Note,
+		arg.RecordedAt,
+	)
+	var id int64
+	err := row.Scan(&id)
+	return id, err
+}
@@ -0,0 +1,36 @@
+// Code generated by sqlc. DO NOT EDIT.
+// versions:
+//   sqlc v1.28.0
+// source: create_shelf.sql
+
+package database
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+)
+
+const createShelf = `-- name: CreateShelf :one
+INSERT INTO shelves (user_id, name, position)
+SELECT $1, $2, COALESCE(MAX(shelves.position) + 1, 0) FROM shelves
+WHERE shelves.user_id = $1
+RETURNING id, position
+`
+
+type CreateShelfParams struct {
+	UserID uuid.UUID
+	Name   string
+}
+
+type CreateShelfRow struct {
+	ID       uuid.UUID
+	Position int32
+}
+
+func (q *Queries) CreateShelf(ctx context.Context, arg CreateShelfParams) (CreateShelfRow, error) {
+	row := q.db.QueryRowContext(ctx, createShelf, arg.UserID, arg.Name)
+	var i CreateShelfRow
+	err := row.Scan(&i.ID, &i.Position)
+	return i, err
+}
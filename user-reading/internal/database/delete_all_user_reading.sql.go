@@ -0,0 +1,32 @@
+// Code generated by sqlc. DO NOT EDIT.
+// versions:
+//   sqlc v1.28.0
+// source: delete_all_user_reading.sql
+
+package database
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+)
+
+const deleteAllUserReading = `-- name: DeleteAllUserReading :execrows
+WITH deleted_shelves AS (
+    DELETE FROM shelves
+    WHERE shelves.user_id = $1
+), deleted_profile AS (
+    DELETE FROM user_profiles
+    WHERE user_profiles.user_id = $1
+)
+DELETE FROM user_reading
+WHERE user_reading.user_id = $1
+`
+
+func (q *Queries) DeleteAllUserReading(ctx context.Context, userID uuid.UUID) (int64, error) {
+	result, err := q.db.ExecContext(ctx, deleteAllUserReading, userID)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
@@ -0,0 +1,30 @@
+// Code generated by sqlc. DO NOT EDIT.
+// versions:
+//   sqlc v1.28.0
+// source: delete_review.sql
+
+package database
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+)
+
+const deleteReview = `-- name: DeleteReview :execrows
+DELETE FROM reviews
+WHERE user_id = $1 AND book_id = $2 AND cycle_id IS NULL
+`
+
+type DeleteReviewParams struct {
+	UserID uuid.UUID
+	BookID uuid.UUID
+}
+
+func (q *Queries) DeleteReview(ctx context.Context, arg DeleteReviewParams) (int64, error) {
+	result, err := q.db.ExecContext(ctx, deleteReview, arg.UserID, arg.BookID)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
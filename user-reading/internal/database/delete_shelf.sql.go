@@ -0,0 +1,30 @@
+// Code generated by sqlc. DO NOT EDIT.
+// versions:
+//   sqlc v1.28.0
+// source: delete_shelf.sql
+
+package database
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+)
+
+const deleteShelf = `-- name: DeleteShelf :execrows
+DELETE FROM shelves
+WHERE id = $1 AND user_id = $2
+`
+
+type DeleteShelfParams struct {
+	ID     uuid.UUID
+	UserID uuid.UUID
+}
+
+func (q *Queries) DeleteShelf(ctx context.Context, arg DeleteShelfParams) (int64, error) {
+	result, err := q.db.ExecContext(ctx, deleteShelf, arg.ID, arg.UserID)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
@@ -0,0 +1,31 @@
+// Code generated by sqlc. DO NOT EDIT.
+// versions:
+//   sqlc v1.28.0
+// source: delete_shelf_book.sql
+
+package database
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+)
+
+const deleteShelfBook = `-- name: DeleteShelfBook :execrows
+DELETE FROM shelf_books
+WHERE shelf_id = $1 AND user_id = $2 AND book_id = $3
+`
+
+type DeleteShelfBookParams struct {
+	ShelfID uuid.UUID
+	UserID  uuid.UUID
+	BookID  uuid.UUID
+}
+
+func (q *Queries) DeleteShelfBook(ctx context.Context, arg DeleteShelfBookParams) (int64, error) {
+	result, err := q.db.ExecContext(ctx, deleteShelfBook, arg.ShelfID, arg.UserID, arg.BookID)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
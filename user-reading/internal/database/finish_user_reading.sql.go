@@ -0,0 +1,32 @@
+// Code generated by sqlc. DO NOT EDIT.
+// versions:
+//   sqlc v1.28.0
+// source: finish_user_reading.sql
+
+package database
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/google/uuid"
+)
+
+const finishUserReading = `-- name: FinishUserReading :execrows
+UPDATE user_reading SET status = 'finished', start_date = COALESCE(start_date, $1), finish_date = $1
+WHERE user_id = $2 AND book_id = $3 AND (start_date IS NULL OR start_date <= $1)
+`
+
+type FinishUserReadingParams struct {
+	FinishDate sql.NullTime
+	UserID     uuid.UUID
+	BookID     uuid.UUID
+}
+
+func (q *Queries) FinishUserReading(ctx context.Context, arg FinishUserReadingParams) (int64, error) {
+	result, err := q.db.ExecContext(ctx, finishUserReading, arg.FinishDate, arg.UserID, arg.BookID)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
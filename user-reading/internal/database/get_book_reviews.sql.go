@@ -0,0 +1,82 @@
+// Code generated by sqlc. DO NOT EDIT.
+// versions:
+//   sqlc v1.28.0
+// source: get_book_reviews.sql
+
+package database
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+const getBookReviews = `-- name: GetBookReviews :many
+SELECT reviews.id, reviews.user_id, user_profiles.display_name, COALESCE(reading_cycles.rating, user_reading.rating)::INTEGER AS rating,
+    reviews.body, reviews.spoiler, reviews.created_at, reviews.edited_at
+FROM reviews
+JOIN user_profiles ON user_profiles.user_id = reviews.user_id
+JOIN user_reading ON user_reading.user_id = reviews.user_id AND user_reading.book_id = reviews.book_id
+LEFT JOIN reading_cycles ON reading_cycles.id = reviews.cycle_id
+WHERE reviews.book_id = $1 AND user_profiles.public
+    AND ($2::BIGINT IS NULL OR (reviews.created_at, reviews.id) < ($3::TIMESTAMP, $2::BIGINT))
+ORDER BY reviews.created_at DESC, reviews.id DESC
+LIMIT $4
+`
+
+type GetBookReviewsParams struct {
+	BookID          uuid.UUID
+	CursorID        sql.NullInt64
+	CursorCreatedAt sql.NullTime
+	Limit           int32
+}
+
+type GetBookReviewsRow struct {
+	ID          int64
+	UserID      uuid.UUID
+	DisplayName string
+	Rating      int32
+	Body        string
+	Spoiler     bool
+	CreatedAt   time.Time
+	EditedAt    sql.NullTime
+}
+
+func (q *Queries) GetBookReviews(ctx context.Context, arg GetBookReviewsParams) ([]GetBookReviewsRow, error) {
+	rows, err := q.db.QueryContext(ctx, getBookReviews,
+		arg.BookID,
+		arg.CursorID,
+		arg.CursorCreatedAt,
+		arg.Limit,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []GetBookReviewsRow
+	for rows.Next() {
+		var i GetBookReviewsRow
+		if err := rows.Scan(
+			&i.ID,
+			&i.UserID,
+			&i.DisplayName,
+			&i.Rating,
+			&i.Body,
+			&i.Spoiler,
+			&i.CreatedAt,
+			&i.EditedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
@@ -0,0 +1,59 @@
+// Code generated by sqlc. DO NOT EDIT.
+// versions:
+//   sqlc v1.28.0
+// source: get_reading_cycles.sql
+
+package database
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/google/uuid"
+)
+
+const getReadingCycles = `-- name: GetReadingCycles :many
+SELECT id, rating, start_date, finish_date FROM reading_cycles
+WHERE user_id = $1 AND book_id = $2
+ORDER BY id
+`
+
+type GetReadingCyclesParams struct {
+	UserID uuid.UUID
+	BookID uuid.UUID
+}
+
+type GetReadingCyclesRow struct {
+	ID         int64
+	Rating     int32
+	StartDate  sql.NullTime
+	FinishDate sql.NullTime
+}
+
+func (q *Queries) GetReadingCycles(ctx context.Context, arg GetReadingCyclesParams) ([]GetReadingCyclesRow, error) {
+	rows, err := q.db.QueryContext(ctx, getReadingCycles, arg.UserID, arg.BookID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []GetReadingCyclesRow
+	for rows.Next() {
+		var i GetReadingCyclesRow
+		if err := rows.Scan(
+			&i.ID,
+			&i.Rating,
+			&i.StartDate,
+			&i.FinishDate,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
@@ -0,0 +1,64 @@
+// Code generated by sqlc. DO NOT EDIT.
+// versions:
+//   sqlc v1.28.0
+// source: get_reading_progress.sql
+
+package database
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+const getReadingProgress = `-- name: GetReadingProgress :many
+SELECT id, page, percent, minutes, note, recorded_at FROM reading_progress
+WHERE user_id = $1 AND book_id = $2 AND cycle_id IS NULL
+ORDER BY recorded_at, id
+`
+
+type GetReadingProgressParams struct {
+	UserID uuid.UUID
+	BookID uuid.UUID
+}
+
+type GetReadingProgressRow struct {
+	ID         int64
+	Page       sql.NullInt32
+	Percent    sql.NullFloat64
+	Minutes    int32
+	Note       string
+	RecordedAt time.Time
+}
+
+func (q *Queries) GetReadingProgress(ctx context.Context, arg GetReadingProgressParams) ([]GetReadingProgressRow, error) {
+	rows, err := q.db.QueryContext(ctx, getReadingProgress, arg.UserID, arg.BookID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []GetReadingProgressRow
+	for rows.Next() {
+		var i GetReadingProgressRow
+		if err := rows.Scan(
+			&i.ID,
+			&i.Page,
+			&i.Percent,
+			&i.Minutes,
+			&i.Note,
+			&i.RecordedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
@@ -0,0 +1,63 @@
+// Code generated by sqlc. DO NOT EDIT.
+// versions:
+//   sqlc v1.28.0
+// source: get_reviews.sql
+
+package database
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+const getReviews = `-- name: GetReviews :many
+SELECT id, cycle_id, body, spoiler, created_at, edited_at FROM reviews
+WHERE user_id = $1 AND book_id = $2
+`
+
+type GetReviewsParams struct {
+	UserID uuid.UUID
+	BookID uuid.UUID
+}
+
+type GetReviewsRow struct {
+	ID        int64
+	CycleID   sql.NullInt64
+	Body      string
+	Spoiler   bool
+	CreatedAt time.Time
+	EditedAt  sql.NullTime
+}
+
+func (q *Queries) GetReviews(ctx context.Context, arg GetReviewsParams) ([]GetReviewsRow, error) {
+	rows, err := q.db.QueryContext(ctx, getReviews, arg.UserID, arg.BookID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []GetReviewsRow
+	for rows.Next() {
+		var i GetReviewsRow
+		if err := rows.Scan(
+			&i.ID,
+			&i.CycleID,
+			&i.Body,
+			&i.Spoiler,
+			&i.CreatedAt,
+			&i.EditedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
@@ -0,0 +1,65 @@
+// Code generated by sqlc. DO NOT EDIT.
+// versions:
+//   sqlc v1.28.0
+// source: get_user_reading_by_shelf.sql
+
+package database
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+const getUserReadingByShelf = `-- name: GetUserReadingByShelf :many
+SELECT user_reading.book_id, user_reading.status, user_reading.rating, user_reading.start_date, user_reading.finish_date, user_reading.created_at
+FROM user_reading
+JOIN shelf_books ON shelf_books.user_id = user_reading.user_id AND shelf_books.book_id = user_reading.book_id
+WHERE user_reading.user_id = $1 AND shelf_books.shelf_id = $2
+`
+
+type GetUserReadingByShelfParams struct {
+	UserID  uuid.UUID
+	ShelfID uuid.UUID
+}
+
+type GetUserReadingByShelfRow struct {
+	BookID     uuid.UUID
+	Status     ReadingStatus
+	Rating     int32
+	StartDate  sql.NullTime
+	FinishDate sql.NullTime
+	CreatedAt  time.Time
+}
+
+func (q *Queries) GetUserReadingByShelf(ctx context.Context, arg GetUserReadingByShelfParams) ([]GetUserReadingByShelfRow, error) {
+	rows, err := q.db.QueryContext(ctx, getUserReadingByShelf, arg.UserID, arg.ShelfID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []GetUserReadingByShelfRow
+	for rows.Next() {
+		var i GetUserReadingByShelfRow
+		if err := rows.Scan(
+			&i.BookID,
+			&i.Status,
+			&i.Rating,
+			&i.StartDate,
+			&i.FinishDate,
+			&i.CreatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
@@ -0,0 +1,54 @@
+// Code generated by sqlc. DO NOT EDIT.
+// versions:
+//   sqlc v1.28.0
+// source: get_user_reading_cycles.sql
+
+package database
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/google/uuid"
+)
+
+const getUserReadingCycles = `-- name: GetUserReadingCycles :many
+SELECT book_id, rating, start_date, finish_date FROM reading_cycles
+WHERE user_id = $1
+ORDER BY id
+`
+
+type GetUserReadingCyclesRow struct {
+	BookID     uuid.UUID
+	Rating     int32
+	StartDate  sql.NullTime
+	FinishDate sql.NullTime
+}
+
+func (q *Queries) GetUserReadingCycles(ctx context.Context, userID uuid.UUID) ([]GetUserReadingCyclesRow, error) {
+	rows, err := q.db.QueryContext(ctx, getUserReadingCycles, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []GetUserReadingCyclesRow
+	for rows.Next() {
+		var i GetUserReadingCyclesRow
+		if err := rows.Scan(
+			&i.BookID,
+			&i.Rating,
+			&i.StartDate,
+			&i.FinishDate,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
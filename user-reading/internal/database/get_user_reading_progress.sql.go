@@ -0,0 +1,63 @@
+// Code generated by sqlc. DO NOT EDIT.
+// versions:
+//   sqlc v1.28.0
+// source: get_user_reading_progress.sql
+
+package database
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+const getUserReadingProgress = `-- name: GetUserReadingProgress :many
+SELECT id, book_id, cycle_id, page, percent, minutes, note, recorded_at FROM reading_progress
+WHERE user_id = $1
+ORDER BY recorded_at, id
+`
+
+type GetUserReadingProgressRow struct {
+	ID         int64
+	BookID     uuid.UUID
+	CycleID    sql.NullInt64
+	Page       sql.NullInt32
+	Percent    sql.NullFloat64
+	Minutes    int32
+	Note       string
+	RecordedAt time.Time
+}
+
+func (q *Queries) GetUserReadingProgress(ctx context.Context, userID uuid.UUID) ([]GetUserReadingProgressRow, error) {
+	rows, err := q.db.QueryContext(ctx, getUserReadingProgress, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []GetUserReadingProgressRow
+	for rows.Next() {
+		var i GetUserReadingProgressRow
+		if err := rows.Scan(
+			&i.ID,
+			&i.BookID,
+			&i.CycleID,
+			&i.Page,
+			&i.Percent,
+			&i.Minutes,
+			&i.Note,
+			&i.RecordedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
@@ -0,0 +1,61 @@
+// Code generated by sqlc. DO NOT EDIT.
+// versions:
+//   sqlc v1.28.0
+// source: get_user_reviews.sql
+
+package database
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+const getUserReviews = `-- name: GetUserReviews :many
+SELECT id, book_id, cycle_id, body, spoiler, created_at, edited_at FROM reviews
+WHERE user_id = $1
+ORDER BY created_at, id
+`
+
+type GetUserReviewsRow struct {
+	ID        int64
+	BookID    uuid.UUID
+	CycleID   sql.NullInt64
+	Body      string
+	Spoiler   bool
+	CreatedAt time.Time
+	EditedAt  sql.NullTime
+}
+
+func (q *Queries) GetUserReviews(ctx context.Context, userID uuid.UUID) ([]GetUserReviewsRow, error) {
+	rows, err := q.db.QueryContext(ctx, getUserReviews, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []GetUserReviewsRow
+	for rows.Next() {
+		var i GetUserReviewsRow
+		if err := rows.Scan(
+			&i.ID,
+			&i.BookID,
+			&i.CycleID,
+			&i.Body,
+			&i.Spoiler,
+			&i.CreatedAt,
+			&i.EditedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
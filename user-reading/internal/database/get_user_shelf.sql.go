@@ -0,0 +1,34 @@
+// Code generated by sqlc. DO NOT EDIT.
+// versions:
+//   sqlc v1.28.0
+// source: get_user_shelf.sql
+
+package database
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+)
+
+const getUserShelf = `-- name: GetUserShelf :one
+SELECT name, position FROM shelves
+WHERE id = $1 AND user_id = $2
+`
+
+type GetUserShelfParams struct {
+	ID     uuid.UUID
+	UserID uuid.UUID
+}
+
+type GetUserShelfRow struct {
+	Name     string
+	Position int32
+}
+
+func (q *Queries) GetUserShelf(ctx context.Context, arg GetUserShelfParams) (GetUserShelfRow, error) {
+	row := q.db.QueryRowContext(ctx, getUserShelf, arg.ID, arg.UserID)
+	var i GetUserShelfRow
+	err := row.Scan(&i.Name, &i.Position)
+	return i, err
+}
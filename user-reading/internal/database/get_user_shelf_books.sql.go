@@ -0,0 +1,46 @@
+// Code generated by sqlc. DO NOT EDIT.
+// versions:
+//   sqlc v1.28.0
+// source: get_user_shelf_books.sql
+
+package database
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+)
+
+const getUserShelfBooks = `-- name: GetUserShelfBooks :many
+SELECT shelf_id, book_id FROM shelf_books
+WHERE user_id = $1
+ORDER BY shelf_id, added_at
+`
+
+type GetUserShelfBooksRow struct {
+	ShelfID uuid.UUID
+	BookID  uuid.UUID
+}
+
+func (q *Queries) GetUserShelfBooks(ctx context.Context, userID uuid.UUID) ([]GetUserShelfBooksRow, error) {
+	rows, err := q.db.QueryContext(ctx, getUserShelfBooks, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []GetUserShelfBooksRow
+	for rows.Next() {
+		var i GetUserShelfBooksRow
+		if err := rows.Scan(&i.ShelfID, &i.BookID); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
@@ -0,0 +1,55 @@
+// Code generated by sqlc. DO NOT EDIT.
+// versions:
+//   sqlc v1.28.0
+// source: get_user_shelves.sql
+
+package database
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+)
+
+const getUserShelves = `-- name: GetUserShelves :many
+SELECT shelves.id, shelves.name, shelves.position, COUNT(shelf_books.book_id) AS books_count FROM shelves
+LEFT JOIN shelf_books ON shelf_books.shelf_id = shelves.id
+WHERE shelves.user_id = $1
+GROUP BY shelves.id
+ORDER BY shelves.position, shelves.created_at
+`
+
+type GetUserShelvesRow struct {
+	ID         uuid.UUID
+	Name       string
+	Position   int32
+	BooksCount int64
+}
+
+func (q *Queries) GetUserShelves(ctx context.Context, userID uuid.UUID) ([]GetUserShelvesRow, error) {
+	rows, err := q.db.QueryContext(ctx, getUserShelves, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []GetUserShelvesRow
+	for rows.Next() {
+		var i GetUserShelvesRow
+		if err := rows.Scan(
+			&i.ID,
+			&i.Name,
+			&i.Position,
+			&i.BooksCount,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
@@ -56,6 +56,62 @@ func (ns NullReadingStatus) Value() (driver.Value, error) {
 	return string(ns.ReadingStatus), nil
 }
 
+type ReadingCycle struct {
+	ID         int64
+	UserID     uuid.UUID
+	BookID     uuid.UUID
+	Rating     int32
+	StartDate  sql.NullTime
+	FinishDate sql.NullTime
+	CreatedAt  time.Time
+}
+
+type ReadingProgress struct {
+	ID         int64
+	UserID     uuid.UUID
+	BookID     uuid.UUID
+	Page       sql.NullInt32
+	Percent    sql.NullFloat64
+	Minutes    int32
+	Note       string
+	RecordedAt time.Time
+	CreatedAt  time.Time
+	CycleID    sql.NullInt64
+}
+
+type Review struct {
+	ID        int64
+	UserID    uuid.UUID
+	BookID    uuid.UUID
+	CycleID   sql.NullInt64
+	Body      string
+	Spoiler   bool
+	CreatedAt time.Time
+	EditedAt  sql.NullTime
+}
+
+type Shelf struct {
+	ID        uuid.UUID
+	UserID    uuid.UUID
+	Name      string
+	Position  int32
+	CreatedAt time.Time
+}
+
+type ShelfBook struct {
+	ShelfID uuid.UUID
+	UserID  uuid.UUID
+	BookID  uuid.UUID
+	AddedAt time.Time
+}
+
+type UserProfile struct {
+	UserID      uuid.UUID
+	DisplayName string
+	Public      bool
+	UpdatedAt   time.Time
+}
+
 type UserReading struct {
 	UserID     uuid.UUID
 	BookID     uuid.UUID
@@ -0,0 +1,31 @@
+// Code generated by sqlc. DO NOT EDIT.
+// versions:
+//   sqlc v1.28.0
+// source: rename_shelf.sql
+
+package database
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+)
+
+const renameShelf = `-- name: RenameShelf :execrows
+UPDATE shelves SET name = $3
+WHERE id = $1 AND user_id = $2
+`
+
+type RenameShelfParams struct {
+	ID     uuid.UUID
+	UserID uuid.UUID
+	Name   string
+}
+
+func (q *Queries) RenameShelf(ctx context.Context, arg RenameShelfParams) (int64, error) {
+	result, err := q.db.ExecContext(ctx, renameShelf, arg.ID, arg.UserID, arg.Name)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
@@ -0,0 +1,29 @@
+// Code generated by sqlc. DO NOT EDIT.
+// versions:
+//   sqlc v1.28.0
+// source: restart_user_reading.sql
+
+package database
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/google/uuid"
+)
+
+const restartUserReading = `-- name: RestartUserReading :exec
+UPDATE user_reading SET status = 'reading', rating = 0, start_date = $1, finish_date = NULL
+WHERE user_id = $2 AND book_id = $3
+`
+
+type RestartUserReadingParams struct {
+	StartDate sql.NullTime
+	UserID    uuid.UUID
+	BookID    uuid.UUID
+}
+
+func (q *Queries) RestartUserReading(ctx context.Context, arg RestartUserReadingParams) error {
+	_, err := q.db.ExecContext(ctx, restartUserReading, arg.StartDate, arg.UserID, arg.BookID)
+	return err
+}
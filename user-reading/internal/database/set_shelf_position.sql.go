@@ -0,0 +1,31 @@
+// Code generated by sqlc. DO NOT EDIT.
+// versions:
+//   sqlc v1.28.0
+// source: set_shelf_position.sql
+
+package database
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+)
+
+const setShelfPosition = `-- name: SetShelfPosition :execrows
+UPDATE shelves SET position = $3
+WHERE id = $1 AND user_id = $2
+`
+
+type SetShelfPositionParams struct {
+	ID       uuid.UUID
+	UserID   uuid.UUID
+	Position int32
+}
+
+func (q *Queries) SetShelfPosition(ctx context.Context, arg SetShelfPositionParams) (int64, error) {
+	result, err := q.db.ExecContext(ctx, setShelfPosition, arg.ID, arg.UserID, arg.Position)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
@@ -0,0 +1,29 @@
+// Code generated by sqlc. DO NOT EDIT.
+// versions:
+//   sqlc v1.28.0
+// source: start_user_reading.sql
+
+package database
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/google/uuid"
+)
+
+const startUserReading = `-- name: StartUserReading :exec
+UPDATE user_reading SET status = 'reading', start_date = COALESCE(start_date, $1)
+WHERE user_id = $2 AND book_id = $3
+`
+
+type StartUserReadingParams struct {
+	StartDate sql.NullTime
+	UserID    uuid.UUID
+	BookID    uuid.UUID
+}
+
+func (q *Queries) StartUserReading(ctx context.Context, arg StartUserReadingParams) error {
+	_, err := q.db.ExecContext(ctx, startUserReading, arg.StartDate, arg.UserID, arg.BookID)
+	return err
+}
@@ -0,0 +1,47 @@
+// Code generated by sqlc. DO NOT EDIT.
+// versions:
+//   sqlc v1.28.0
+// source: upsert_review.sql
+
+package database
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+const upsertReview = `-- name: UpsertReview :one
+INSERT INTO reviews (user_id, book_id, body, spoiler)
+VALUES ($1, $2, $3, $4)
+ON CONFLICT (user_id, book_id) WHERE cycle_id IS NULL
+DO UPDATE SET body = EXCLUDED.body, spoiler = EXCLUDED.spoiler, edited_at = NOW()
+RETURNING id, created_at, edited_at
+`
+
+type UpsertReviewParams struct {
+	UserID  uuid.UUID
+	BookID  uuid.UUID
+	Body    string
+	Spoiler bool
+}
+
+type UpsertReviewRow struct {
+	ID        int64
+	CreatedAt time.Time
+	EditedAt  sql.NullTime
+}
+
+func (q *Queries) UpsertReview(ctx context.Context, arg UpsertReviewParams) (UpsertReviewRow, error) {
+	row := q.db.QueryRowContext(ctx, upsertReview,
+		arg.UserID,
+		arg.BookID,
+		arg.Body,
+		arg.Spoiler,
+	)
+	var i UpsertReviewRow
+	err := row.Scan(&i.ID, &i.CreatedAt, &i.EditedAt)
+	return i, err
+}
@@ -0,0 +1,29 @@
+// Code generated by sqlc. DO NOT EDIT.
+// versions:
+//   sqlc v1.28.0
+// source: upsert_user_profile.sql
+
+package database
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+)
+
+const upsertUserProfile = `-- name: UpsertUserProfile :exec
+INSERT INTO user_profiles (user_id, display_name, public)
+VALUES ($1, $2, $3)
+ON CONFLICT (user_id) DO UPDATE SET display_name = EXCLUDED.display_name, public = EXCLUDED.public, updated_at = NOW()
+`
+
+type UpsertUserProfileParams struct {
+	UserID      uuid.UUID
+	DisplayName string
+	Public      bool
+}
+
+func (q *Queries) UpsertUserProfile(ctx context.Context, arg UpsertUserProfileParams) error {
+	_, err := q.db.ExecContext(ctx, upsertUserProfile, arg.UserID, arg.DisplayName, arg.Public)
+	return err
+}
@@ -3,12 +3,14 @@ package server
 import (
 	"database/sql"
 
+	"github.com/bakurvik/mylib/user-reading/internal/auth"
 	"github.com/bakurvik/mylib/user-reading/internal/config"
 )
 
 type ApiConfig struct {
 	DB                   *sql.DB
 	UsersServiceHost     string
+	Auth                 auth.Authenticator
 	LibraryServiceHost   string
 	UseLibraryBooksCache bool
 	BooksCacheCfg        config.BooksCacheConfig
@@ -0,0 +1,117 @@
+package server
+
+import (
+	"net/http"
+	"sort"
+
+	"github.com/bakurvik/mylib/user-reading/internal/database"
+	"github.com/google/uuid"
+
+	common "github.com/bakurvik/mylib-common"
+)
+
+// @Summary Export user reading
+// @Description Gets all reading data of the user without book info from library: books in the order they were added, their previous reading cycles, progress updates, custom shelves and reviews. Used by account data export of users service
+// @Tags User reading
+// @Produce json
+// @Success 200 {object} ResponseUserReadingExport "All reading data"
+// @Failure 401 {object} ErrorResponse "Unauthorized"
+// @Failure 500 {object} ErrorResponse
+// @Router /api/user-reading/export [get]
+func (cfg *ApiConfig) HandleGetApiUserReadingExportPath(w http.ResponseWriter, r *http.Request) {
+	if cfg.DB == nil {
+		common.RespondWithError(w, http.StatusInternalServerError, "DB error")
+		return
+	}
+
+	userID := userIDFromContext(r.Context())
+
+	queries := database.New(cfg.DB)
+	userReading, err := queries.GetUserReading(r.Context(), userID)
+	if err != nil {
+		common.RespondWithError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	sort.SliceStable(userReading, func(i, j int) bool {
+		return userReading[i].CreatedAt.Before(userReading[j].CreatedAt)
+	})
+
+	response := ResponseUserReadingExport{Books: make([]ExportUserReading, 0, len(userReading))}
+	for _, book := range userReading {
+		response.Books = append(response.Books, ExportUserReading{
+			BookID:     book.BookID.String(),
+			Status:     string(book.Status),
+			Rating:     int(book.Rating),
+			StartDate:  common.NullTimeToString(book.StartDate),
+			FinishDate: common.NullTimeToString(book.FinishDate),
+			AddedAt:    book.CreatedAt,
+		})
+	}
+
+	cycles, err := queries.GetUserReadingCycles(r.Context(), userID)
+	if err != nil {
+		common.RespondWithError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	response.Cycles = make([]ExportReadingCycle, 0, len(cycles))
+	for _, cycle := range cycles {
+		response.Cycles = append(response.Cycles, ExportReadingCycle{
+			BookID:     cycle.BookID.String(),
+			Rating:     int(cycle.Rating),
+			StartDate:  common.NullTimeToString(cycle.StartDate),
+			FinishDate: common.NullTimeToString(cycle.FinishDate),
+		})
+	}
+
+	progress, err := queries.GetUserReadingProgress(r.Context(), userID)
+	if err != nil {
+		common.RespondWithError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	response.Progress = make([]ExportReadingProgress, 0, len(progress))
+	for _, p := range progress {
+		response.Progress = append(response.Progress, ExportReadingProgress{
+			BookID:                  p.BookID.String(),
+			Current:                 !p.CycleID.Valid,
+			ResponseReadingProgress: toResponseReadingProgress(p.ID, p.Page, p.Percent, p.Minutes, p.Note, p.RecordedAt),
+		})
+	}
+
+	shelves, err := queries.GetUserShelves(r.Context(), userID)
+	if err != nil {
+		common.RespondWithError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	shelfBooks, err := queries.GetUserShelfBooks(r.Context(), userID)
+	if err != nil {
+		common.RespondWithError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	shelfToBookIDs := map[uuid.UUID][]string{}
+	for _, book := range shelfBooks {
+		shelfToBookIDs[book.ShelfID] = append(shelfToBookIDs[book.ShelfID], book.BookID.String())
+	}
+	response.Shelves = make([]ExportShelf, 0, len(shelves))
+	for _, shelf := range shelves {
+		bookIDs := shelfToBookIDs[shelf.ID]
+		if bookIDs == nil {
+			bookIDs = []string{}
+		}
+		response.Shelves = append(response.Shelves, ExportShelf{Name: shelf.Name, BookIDs: bookIDs})
+	}
+
+	reviews, err := queries.GetUserReviews(r.Context(), userID)
+	if err != nil {
+		common.RespondWithError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	response.Reviews = make([]ExportReview, 0, len(reviews))
+	for _, review := range reviews {
+		response.Reviews = append(response.Reviews, ExportReview{
+			BookID:         review.BookID.String(),
+			Current:        !review.CycleID.Valid,
+			ResponseReview: toResponseReview(review.ID, review.Body, review.Spoiler, review.CreatedAt, review.EditedAt),
+		})
+	}
+	common.RespondWithJSON(w, http.StatusOK, response, nil)
+}
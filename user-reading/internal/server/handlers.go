@@ -5,6 +5,8 @@ import (
 	"database/sql"
 	"encoding/json"
 	"errors"
+	"fmt"
+	"log"
 	"net/http"
 	"sort"
 	"time"
@@ -20,6 +22,9 @@ const (
 	readingStatus    = database.ReadingStatusReading
 	wantToReadStatus = database.ReadingStatusWantToRead
 	finishedStatus   = database.ReadingStatusFinished
+
+	minRating = 1
+	maxRating = 5
 )
 
 type dbUserReading struct {
@@ -59,6 +64,10 @@ func parseUserReading(r *http.Request) (dbUserReading, error) {
 	if err != nil {
 		return dbUserReading{}, err
 	}
+	// Rating 0 means the book is not rated.
+	if request.Rating != 0 && (request.Rating < minRating || request.Rating > maxRating) {
+		return dbUserReading{}, fmt.Errorf("rating must be from %d to %d", minRating, maxRating)
+	}
 	startDate := common.ToNullTime(request.StartDate)
 	finishDate := common.ToNullTime(request.FinishDate)
 	if startDate.Valid && finishDate.Valid && startDate.Time.After(finishDate.Time) {
@@ -71,49 +80,21 @@ func parseUserReading(r *http.Request) (dbUserReading, error) {
 	return res, nil
 }
 
-func checkUserAndBook(r *http.Request, cfg *ApiConfig, bookUUID uuid.UUID) (uuid.UUID, int, error) {
-	type userResponse struct {
-		userID     uuid.UUID
-		statusCode int
-		err        error
-	}
-	userChan := make(chan userResponse)
-	go func() {
-		userID, statusCode, err := clients.GetUser(r.Header, cfg.UsersServiceHost)
-		userChan <- userResponse{userID: userID, statusCode: statusCode, err: err}
-	}()
-
-	type bookResponse struct {
-		statusCode int
-		err        error
-	}
-	bookChan := make(chan bookResponse)
-	go func() {
-		statusCode, err := clients.CheckBook(bookUUID, cfg.LibraryServiceHost)
-		bookChan <- bookResponse{statusCode: statusCode, err: err}
-	}()
-
-	userResp := <-userChan
-	bookResp := <-bookChan
-	if userResp.statusCode == http.StatusUnauthorized {
-		return uuid.Nil, http.StatusUnauthorized, errors.New("Unauthorized")
-	}
-	if userResp.err != nil {
-		return uuid.Nil, http.StatusInternalServerError, errors.New("failed to check authorization")
-	}
-
-	switch bookResp.statusCode {
+// checkBook checks that the book exists in library service.
+func checkBook(cfg *ApiConfig, bookUUID uuid.UUID) (int, error) {
+	statusCode, err := clients.CheckBook(bookUUID, cfg.LibraryServiceHost)
+	switch statusCode {
 	case http.StatusNotFound:
-		return uuid.Nil, http.StatusBadRequest, errors.New("book not found")
+		return http.StatusBadRequest, errors.New("book not found")
 	case http.StatusBadRequest:
-		return uuid.Nil, http.StatusBadRequest, errors.New("invalid book id")
+		return http.StatusBadRequest, errors.New("invalid book id")
 	case http.StatusInternalServerError:
-		return uuid.Nil, http.StatusInternalServerError, errors.New("failed to check book")
+		return http.StatusInternalServerError, errors.New("failed to check book")
 	}
-	if bookResp.err != nil {
-		return uuid.Nil, http.StatusInternalServerError, errors.New("failed to check book")
+	if err != nil {
+		return http.StatusInternalServerError, errors.New("failed to check book")
 	}
-	return userResp.userID, http.StatusOK, nil
+	return http.StatusOK, nil
 }
 
 // @Summary Ping the server
@@ -150,11 +131,12 @@ func (cfg *ApiConfig) HandlePostApiUserReadingPath(w http.ResponseWriter, r *htt
 		return
 	}
 
-	userUUID, statusCode, err := checkUserAndBook(r, cfg, userReading.bookID)
+	statusCode, err := checkBook(cfg, userReading.bookID)
 	if err != nil {
 		common.RespondWithError(w, statusCode, err.Error())
 		return
 	}
+	userUUID := userIDFromContext(r.Context())
 
 	queries := database.New(cfg.DB)
 	dbErr := queries.CreateUserReading(
@@ -174,7 +156,7 @@ func (cfg *ApiConfig) HandlePostApiUserReadingPath(w http.ResponseWriter, r *htt
 }
 
 // @Summary Update user reading
-// @Description Updates user reading in DB. Uses access token from an HTTP-only cookie
+// @Description Updates the current reading cycle of the book in DB. Moving a finished book back to reading or want_to_read starts a new cycle, dates and rating of the finished one are kept. Uses access token from an HTTP-only cookie
 // @Tags User reading
 // @Accept json
 // @Produce json
@@ -196,29 +178,62 @@ func (cfg *ApiConfig) HandlePutApiUserReadingPath(w http.ResponseWriter, r *http
 		return
 	}
 
-	userUUID, statusCode, err := checkUserAndBook(r, cfg, userReading.bookID)
+	statusCode, err := checkBook(cfg, userReading.bookID)
 	if err != nil {
 		common.RespondWithError(w, statusCode, err.Error())
 		return
 	}
+	userUUID := userIDFromContext(r.Context())
 
-	queries := database.New(cfg.DB)
-	count, dbErr := queries.UpdateUserReading(
-		r.Context(),
+	statusCode, dbErr := updateUserReading(r.Context(), cfg.DB, userUUID, userReading)
+	if dbErr != nil {
+		common.RespondWithError(w, statusCode, dbErr.Error())
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// updateUserReading updates the current reading cycle of the book. When a finished book is read again,
+// the finished cycle is kept in reading_cycles with its review and the update starts a new one.
+func updateUserReading(ctx context.Context, db *sql.DB, userID uuid.UUID, userReading dbUserReading) (int, error) {
+	tx, err := db.BeginTx(ctx, nil)
+	if err != nil {
+		return http.StatusInternalServerError, err
+	}
+	defer func() {
+		if err != nil {
+			if rollbackErr := tx.Rollback(); rollbackErr != nil {
+				log.Print("Failed to rollback transaction ", rollbackErr)
+			}
+		}
+	}()
+
+	queries := database.New(tx)
+	if userReading.status != finishedStatus {
+		_, err = queries.ArchiveReadingCycle(ctx, database.ArchiveReadingCycleParams{UserID: userID, BookID: userReading.bookID})
+		if err != nil {
+			return http.StatusInternalServerError, err
+		}
+	}
+	_, err = queries.UpdateUserReading(
+		ctx,
 		database.UpdateUserReadingParams{
-			UserID: userUUID,
+			UserID: userID,
 			BookID: userReading.bookID,
 			Status: userReading.status,
 			Rating: userReading.rating, StartDate: userReading.startDate, FinishDate: userReading.finishDate})
-	if count == 0 {
-		common.RespondWithError(w, http.StatusBadRequest, "Unknown user reading")
-		return
+	if err == sql.ErrNoRows {
+		return http.StatusBadRequest, errors.New("Unknown user reading")
 	}
-	if dbErr != nil {
-		common.RespondWithError(w, http.StatusInternalServerError, dbErr.Error())
-		return
+	if err != nil {
+		return http.StatusInternalServerError, err
 	}
-	w.WriteHeader(http.StatusNoContent)
+
+	err = tx.Commit()
+	if err != nil {
+		return http.StatusInternalServerError, err
+	}
+	return http.StatusNoContent, nil
 }
 
 // @Summary Delete user reading
@@ -244,15 +259,7 @@ func (cfg *ApiConfig) HandleDeleteApiUserReadingPath(w http.ResponseWriter, r *h
 		return
 	}
 
-	userID, usersStatusCode, err := clients.GetUser(r.Header, cfg.UsersServiceHost)
-	if usersStatusCode == http.StatusUnauthorized {
-		common.RespondWithError(w, http.StatusUnauthorized, "Unauthorized")
-		return
-	}
-	if err != nil {
-		common.RespondWithError(w, http.StatusInternalServerError, "Failed to check authorization")
-		return
-	}
+	userID := userIDFromContext(r.Context())
 
 	queries := database.New(cfg.DB)
 	dbErr := queries.DeleteUserReading(r.Context(), database.DeleteUserReadingParams{UserID: userID, BookID: bookID})
@@ -310,6 +317,16 @@ func getUserReadingByStatus(db *sql.DB, userID uuid.UUID, status database.Readin
 	return res, nil
 }
 
+func filterUserReadingByStatus(userReading []dbUserReading, status database.ReadingStatus) []dbUserReading {
+	res := make([]dbUserReading, 0, len(userReading))
+	for _, book := range userReading {
+		if book.status == status {
+			res = append(res, book)
+		}
+	}
+	return res
+}
+
 func compareDates(left dbUserReading, right dbUserReading, getDateField func(dbUserReading) sql.NullTime) bool {
 	leftDate := getDateField(left)
 	rightDate := getDateField(right)
@@ -347,13 +364,16 @@ func sortUserReading(books []dbUserReading) {
 }
 
 // @Summary Get user reading
-// @Description Gets user reading from DB. Uses access token from an HTTP-only cookie
+// @Description Gets user reading from DB, optionally only books with the status or on the custom shelf. Uses access token from an HTTP-only cookie
 // @Tags User reading
 // @Accept json
 // @Produce json
 // @Param status query string false "Reading status"
+// @Param shelf query string false "Shelf ID"
 // @Success 200 {array} ResponseUserReading "User reading"
+// @Failure 400 {object} ErrorResponse "Unknown reading status or invalid shelf id"
 // @Failure 401 {object} ErrorResponse "Unauthorized"
+// @Failure 404 {object} ErrorResponse "Unknown shelf"
 // @Failure 500 {object} ErrorResponse
 // @Router /api/authors [get]
 func (cfg *ApiConfig) HandleGetApiUserReadingPath(w http.ResponseWriter, r *http.Request) {
@@ -362,26 +382,40 @@ func (cfg *ApiConfig) HandleGetApiUserReadingPath(w http.ResponseWriter, r *http
 		return
 	}
 
-	userID, usersStatusCode, err := clients.GetUser(r.Header, cfg.UsersServiceHost)
-	if usersStatusCode == http.StatusUnauthorized {
-		common.RespondWithError(w, http.StatusUnauthorized, "Unauthorized")
-		return
-	}
-	if err != nil {
-		common.RespondWithError(w, http.StatusInternalServerError, "Failed to check authorization")
-		return
-	}
+	userID := userIDFromContext(r.Context())
 
 	requestStatus := r.URL.Query().Get("status")
-	userReading := []dbUserReading{}
-	if requestStatus == "" {
-		userReading, err = getUserReading(cfg.DB, userID, r.Context())
-	} else {
-		dbStatus, err := mapUserReadingStatus(requestStatus)
-		if err != nil {
+	var dbStatus database.ReadingStatus
+	if requestStatus != "" {
+		var statusErr error
+		dbStatus, statusErr = mapUserReadingStatus(requestStatus)
+		if statusErr != nil {
 			common.RespondWithError(w, http.StatusBadRequest, "Unknown reading status")
 			return
 		}
+	}
+	requestShelf := r.URL.Query().Get("shelf")
+	userReading := []dbUserReading{}
+	var err error
+	switch {
+	case requestShelf != "":
+		shelfID, shelfErr := uuid.Parse(requestShelf)
+		if shelfErr != nil {
+			common.RespondWithError(w, http.StatusBadRequest, "Invalid shelf id")
+			return
+		}
+		statusCode, shelfErr := checkShelf(r.Context(), database.New(cfg.DB), userID, shelfID)
+		if shelfErr != nil {
+			common.RespondWithError(w, statusCode, shelfErr.Error())
+			return
+		}
+		userReading, err = getUserReadingByShelf(cfg.DB, userID, shelfID, r.Context())
+		if requestStatus != "" {
+			userReading = filterUserReadingByStatus(userReading, dbStatus)
+		}
+	case requestStatus == "":
+		userReading, err = getUserReading(cfg.DB, userID, r.Context())
+	default:
 		userReading, err = getUserReadingByStatus(cfg.DB, userID, dbStatus, r.Context())
 	}
 	if err != nil {
@@ -424,7 +458,7 @@ func (cfg *ApiConfig) HandleGetApiUserReadingPath(w http.ResponseWriter, r *http
 }
 
 // @Summary Get one user reading full info
-// @Description Gets one user reading full info from DB. Uses access token from an HTTP-only cookie
+// @Description Gets one user reading full info from DB with all reading cycles of the book and their reviews. Uses access token from an HTTP-only cookie
 // @Tags User reading
 // @Accept json
 // @Produce json
@@ -439,15 +473,7 @@ func (cfg *ApiConfig) HandleGetApiUserReadingByBookPath(w http.ResponseWriter, r
 		return
 	}
 
-	userID, usersStatusCode, err := clients.GetUser(r.Header, cfg.UsersServiceHost)
-	if usersStatusCode == http.StatusUnauthorized {
-		common.RespondWithError(w, http.StatusUnauthorized, "Unauthorized")
-		return
-	}
-	if err != nil {
-		common.RespondWithError(w, http.StatusInternalServerError, "Failed to check authorization")
-		return
-	}
+	userID := userIDFromContext(r.Context())
 
 	bookID, err := uuid.Parse(r.PathValue("bookID"))
 	if err != nil {
@@ -480,6 +506,22 @@ func (cfg *ApiConfig) HandleGetApiUserReadingByBookPath(w http.ResponseWriter, r
 		common.RespondWithError(w, http.StatusNotFound, "Unknown book")
 		return
 	}
+	cycles, dbErr := queries.GetReadingCycles(r.Context(), database.GetReadingCyclesParams{UserID: userID, BookID: bookID})
+	if dbErr != nil {
+		common.RespondWithError(w, http.StatusInternalServerError, "Failed to get reading cycles")
+		return
+	}
+	reviews, dbErr := queries.GetReviews(r.Context(), database.GetReviewsParams{UserID: userID, BookID: bookID})
+	if dbErr != nil {
+		common.RespondWithError(w, http.StatusInternalServerError, "Failed to get reviews")
+		return
+	}
+	// Review of the current cycle has no cycle ID, it is stored with key 0.
+	cycleToReview := make(map[int64]*ResponseReview, len(reviews))
+	for _, review := range reviews {
+		responseReview := toResponseReview(review.ID, review.Body, review.Spoiler, review.CreatedAt, review.EditedAt)
+		cycleToReview[review.CycleID.Int64] = &responseReview
+	}
 	response := ResponseUserReadingFullInfo{
 		ResponseUserReading: ResponseUserReading{
 			ID:      bookID.String(),
@@ -490,6 +532,53 @@ func (cfg *ApiConfig) HandleGetApiUserReadingByBookPath(w http.ResponseWriter, r
 		},
 		StartDate:  common.NullTimeToString(userReading.StartDate),
 		FinishDate: common.NullTimeToString(userReading.FinishDate),
-	}
+		Cycles:     make([]ResponseReadingCycle, 0, len(cycles)+1),
+	}
+	for _, cycle := range cycles {
+		response.Cycles = append(response.Cycles, ResponseReadingCycle{
+			Rating:     int(cycle.Rating),
+			StartDate:  common.NullTimeToString(cycle.StartDate),
+			FinishDate: common.NullTimeToString(cycle.FinishDate),
+			Review:     cycleToReview[cycle.ID],
+		})
+	}
+	response.Cycles = append(response.Cycles, ResponseReadingCycle{
+		Rating:     response.Rating,
+		StartDate:  response.StartDate,
+		FinishDate: response.FinishDate,
+		Current:    true,
+		Review:     cycleToReview[0],
+	})
 	common.RespondWithJSON(w, http.StatusOK, response, nil)
 }
+
+// @Summary Get user reading stats
+// @Description Gets number of books by reading status and number of finished reads, re-reads of the same book are counted separately. Uses access token from an HTTP-only cookie
+// @Tags User reading
+// @Produce json
+// @Success 200 {object} ResponseUserReadingStats "User reading stats"
+// @Failure 401 {object} ErrorResponse "Unauthorized"
+// @Failure 500 {object} ErrorResponse
+// @Router /api/user-reading/stats [get]
+func (cfg *ApiConfig) HandleGetApiUserReadingStatsPath(w http.ResponseWriter, r *http.Request) {
+	if cfg.DB == nil {
+		common.RespondWithError(w, http.StatusInternalServerError, "DB error")
+		return
+	}
+
+	userID := userIDFromContext(r.Context())
+
+	queries := database.New(cfg.DB)
+	stats, err := queries.GetUserReadingStats(r.Context(), userID)
+	if err != nil {
+		common.RespondWithError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	common.RespondWithJSON(w, http.StatusOK, ResponseUserReadingStats{
+		WantToRead:  stats.WantToRead,
+		Reading:     stats.Reading,
+		Finished:    stats.Finished,
+		Reads:       stats.Finished + stats.PreviousReads,
+		RereadBooks: stats.RereadBooks,
+	}, nil)
+}
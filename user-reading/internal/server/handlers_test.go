@@ -2,6 +2,8 @@ package server
 
 import (
 	"database/sql"
+	"net/http"
+	"strings"
 	"testing"
 	"time"
 
@@ -168,3 +170,30 @@ func TestSortUserReading(t *testing.T) {
 		})
 	}
 }
+
+func TestParseUserReadingRating(t *testing.T) {
+	bookID := uuid.New()
+	type testCase struct {
+		name           string
+		body           string
+		expectedError  bool
+		expectedRating int32
+	}
+	testCases := []testCase{
+		{name: "min_rating", body: `{"book_id": "` + bookID.String() + `", "status": "finished", "rating": 1}`, expectedRating: 1},
+		{name: "max_rating", body: `{"book_id": "` + bookID.String() + `", "status": "finished", "rating": 5}`, expectedRating: 5},
+		{name: "not_rated", body: `{"book_id": "` + bookID.String() + `", "status": "finished"}`},
+		{name: "not_finished", body: `{"book_id": "` + bookID.String() + `", "status": "reading", "rating": 3}`},
+		{name: "over_max", body: `{"book_id": "` + bookID.String() + `", "status": "finished", "rating": 6}`, expectedError: true},
+		{name: "negative", body: `{"book_id": "` + bookID.String() + `", "status": "finished", "rating": -1}`, expectedError: true},
+		{name: "half_star", body: `{"book_id": "` + bookID.String() + `", "status": "finished", "rating": 3.5}`, expectedError: true},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			request, _ := http.NewRequest(http.MethodPost, "/", strings.NewReader(tc.body))
+			userReading, err := parseUserReading(request)
+			assert.Equal(t, err != nil, tc.expectedError)
+			assert.Equal(t, userReading.rating, tc.expectedRating)
+		})
+	}
+}
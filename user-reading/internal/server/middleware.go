@@ -0,0 +1,48 @@
+package server
+
+import (
+	"context"
+	"errors"
+	"log"
+	"net/http"
+
+	common "github.com/bakurvik/mylib-common"
+	"github.com/bakurvik/mylib/user-reading/internal/auth"
+	"github.com/google/uuid"
+)
+
+type contextKey int
+
+const userIDKey contextKey = iota
+
+// requireUser lets the request through only if the user is authenticated and puts user ID into request context.
+// A personal access token must have the scope.
+func (cfg *ApiConfig) requireUser(scope string, handler http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if cfg.Auth == nil {
+			common.RespondWithError(w, http.StatusInternalServerError, "Failed to check authorization")
+			return
+		}
+		userID, err := cfg.Auth.Authenticate(r, scope)
+		if errors.Is(err, auth.ErrUnauthorized) {
+			common.RespondWithError(w, http.StatusUnauthorized, "Unauthorized")
+			return
+		}
+		if errors.Is(err, auth.ErrForbidden) {
+			common.RespondWithError(w, http.StatusForbidden, "Forbidden")
+			return
+		}
+		if err != nil {
+			log.Print("Failed to check authorization: ", err)
+			common.RespondWithError(w, http.StatusInternalServerError, "Failed to check authorization")
+			return
+		}
+		handler(w, r.WithContext(context.WithValue(r.Context(), userIDKey, userID)))
+	}
+}
+
+// userIDFromContext returns ID of the user authenticated by requireUser.
+func userIDFromContext(ctx context.Context) uuid.UUID {
+	userID, _ := ctx.Value(userIDKey).(uuid.UUID)
+	return userID
+}
@@ -0,0 +1,70 @@
+package server
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/bakurvik/mylib/user-reading/internal/auth"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+type testAuthenticator struct {
+	userID uuid.UUID
+	err    error
+}
+
+func (a *testAuthenticator) Authenticate(r *http.Request, scope string) (uuid.UUID, error) {
+	return a.userID, a.err
+}
+
+func TestRequireUser(t *testing.T) {
+	userID := uuid.New()
+	type testCase struct {
+		name               string
+		authenticator      auth.Authenticator
+		expectedStatusCode int
+	}
+	testCases := []testCase{
+		{
+			name:               "success",
+			authenticator:      &testAuthenticator{userID: userID},
+			expectedStatusCode: http.StatusOK,
+		},
+		{
+			name:               "unauthorized",
+			authenticator:      &testAuthenticator{err: auth.ErrUnauthorized},
+			expectedStatusCode: http.StatusUnauthorized,
+		},
+		{
+			name:               "forbidden",
+			authenticator:      &testAuthenticator{err: auth.ErrForbidden},
+			expectedStatusCode: http.StatusForbidden,
+		},
+		{
+			name:               "authenticator_error",
+			authenticator:      &testAuthenticator{err: errors.New("users service is down")},
+			expectedStatusCode: http.StatusInternalServerError,
+		},
+		{
+			name:               "no_authenticator",
+			authenticator:      nil,
+			expectedStatusCode: http.StatusInternalServerError,
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			cfg := ApiConfig{Auth: tc.authenticator}
+			handler := cfg.requireUser(auth.ScopeReadingRead, func(w http.ResponseWriter, r *http.Request) {
+				assert.Equal(t, userIDFromContext(r.Context()), userID)
+				w.WriteHeader(http.StatusOK)
+			})
+
+			w := httptest.NewRecorder()
+			handler(w, httptest.NewRequest(http.MethodGet, ApiUserReadingPath, nil))
+			assert.Equal(t, w.Code, tc.expectedStatusCode)
+		})
+	}
+}
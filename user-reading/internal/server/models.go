@@ -1,8 +1,11 @@
 package server
 
+import "time"
+
 type UserReading struct {
-	BookID     string `json:"book_id"`
-	Status     string `json:"status"`
+	BookID string `json:"book_id"`
+	Status string `json:"status"`
+	// Rating of a finished book is from 1 to 5, 0 means the book is not rated.
 	Rating     int    `json:"rating"`
 	StartDate  string `json:"start_date,omitempty"`
 	FinishDate string `json:"finish_date,omitempty"`
@@ -20,8 +23,149 @@ type ResponseUserReading struct {
 	Rating  int      `json:"rating"`
 }
 
+// ResponseReadingCycle is one read of the book. Current is the cycle UserReading describes, the others are finished reads before it.
+type ResponseReadingCycle struct {
+	Rating     int    `json:"rating"`
+	StartDate  string `json:"start_date,omitempty"`
+	FinishDate string `json:"finish_date,omitempty"`
+	Current    bool   `json:"current"`
+	// Review is the user's review of this read of the book.
+	Review *ResponseReview `json:"review,omitempty"`
+}
+
 type ResponseUserReadingFullInfo struct {
 	ResponseUserReading
 	StartDate  string `json:"start_date,omitempty"`
 	FinishDate string `json:"finish_date,omitempty"`
+	// Cycles are all reads of the book, the oldest first and the current one last.
+	Cycles []ResponseReadingCycle `json:"cycles"`
+}
+
+type ResponseUserReadingStats struct {
+	WantToRead int64 `json:"want_to_read"`
+	Reading    int64 `json:"reading"`
+	Finished   int64 `json:"finished"`
+	// Reads is the number of finished reads including re-reads of the same book.
+	Reads int64 `json:"reads"`
+	// RereadBooks is the number of books that were finished at least twice.
+	RereadBooks int64 `json:"reread_books"`
+}
+
+type ExportUserReading struct {
+	BookID     string    `json:"book_id"`
+	Status     string    `json:"status"`
+	Rating     int       `json:"rating"`
+	StartDate  string    `json:"start_date,omitempty"`
+	FinishDate string    `json:"finish_date,omitempty"`
+	AddedAt    time.Time `json:"added_at"`
+}
+
+type ExportReadingCycle struct {
+	BookID     string `json:"book_id"`
+	Rating     int    `json:"rating"`
+	StartDate  string `json:"start_date,omitempty"`
+	FinishDate string `json:"finish_date,omitempty"`
+}
+
+type ExportReadingProgress struct {
+	BookID string `json:"book_id"`
+	// Current is set for progress of the current reading cycle, the others are updates of previous cycles.
+	Current bool `json:"current"`
+	ResponseReadingProgress
+}
+
+// ResponseUserReadingExport is all reading data of the user, users service puts it into account data export.
+type ResponseUserReadingExport struct {
+	Books []ExportUserReading `json:"books"`
+	// Cycles are finished reads of the books before their current cycle.
+	Cycles   []ExportReadingCycle    `json:"cycles"`
+	Progress []ExportReadingProgress `json:"progress"`
+	// Shelves are custom shelves of the user in their order.
+	Shelves []ExportShelf  `json:"shelves"`
+	Reviews []ExportReview `json:"reviews"`
+}
+
+type RequestReadingProgress struct {
+	// Page or Percent is required, Percent is from 0 to 100.
+	Page    *int     `json:"page,omitempty"`
+	Percent *float64 `json:"percent,omitempty"`
+	// Minutes is the duration of the reading session.
+	Minutes int    `json:"minutes,omitempty"`
+	Note    string `json:"note,omitempty"`
+	// RecordedAt is RFC 3339 time of the update, now by default.
+	RecordedAt string `json:"recorded_at,omitempty"`
+	// Finish marks the book finished when Percent is 100.
+	Finish bool `json:"finish,omitempty"`
+}
+
+type ResponseReadingProgress struct {
+	ID         int64     `json:"id"`
+	Page       *int      `json:"page,omitempty"`
+	Percent    *float64  `json:"percent,omitempty"`
+	Minutes    int       `json:"minutes,omitempty"`
+	Note       string    `json:"note,omitempty"`
+	RecordedAt time.Time `json:"recorded_at"`
+}
+
+// ResponseReadingProgressUpdate is the saved update with reading status after it. SuggestFinish is set when
+// the update reached 100% of a book that is not finished yet, the book can be finished by sending the update with Finish.
+type ResponseReadingProgressUpdate struct {
+	Progress      ResponseReadingProgress `json:"progress"`
+	Status        string                  `json:"status"`
+	SuggestFinish bool                    `json:"suggest_finish"`
+}
+
+type RequestShelf struct {
+	Name string `json:"name"`
+}
+
+type RequestShelvesOrder struct {
+	// ShelfIDs are all shelves of the user in the new order.
+	ShelfIDs []string `json:"shelf_ids"`
+}
+
+type ResponseShelf struct {
+	ID         string `json:"id"`
+	Name       string `json:"name"`
+	Position   int    `json:"position"`
+	BooksCount int64  `json:"books_count"`
+}
+
+type ExportShelf struct {
+	Name    string   `json:"name"`
+	BookIDs []string `json:"book_ids"`
+}
+
+type RequestReview struct {
+	BookID  string `json:"book_id"`
+	Body    string `json:"body"`
+	Spoiler bool   `json:"spoiler"`
+}
+
+type ResponseReview struct {
+	ID        int64      `json:"id"`
+	Body      string     `json:"body"`
+	Spoiler   bool       `json:"spoiler"`
+	CreatedAt time.Time  `json:"created_at"`
+	EditedAt  *time.Time `json:"edited_at,omitempty"`
+}
+
+// ResponseBookReview is a review shown to everyone. Rating is the rating of the reviewed read of the book.
+type ResponseBookReview struct {
+	ResponseReview
+	UserID      string `json:"user_id"`
+	DisplayName string `json:"display_name"`
+	Rating      int    `json:"rating"`
+}
+
+type ResponseBookReviewsPage struct {
+	Items      []ResponseBookReview `json:"items"`
+	NextCursor string               `json:"next_cursor"`
+}
+
+type ExportReview struct {
+	BookID string `json:"book_id"`
+	// Current is set for the review of the current reading cycle, the others are reviews of previous cycles.
+	Current bool `json:"current"`
+	ResponseReview
 }
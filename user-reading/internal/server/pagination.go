@@ -0,0 +1,87 @@
+package server
+
+import (
+	"database/sql"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+const (
+	defaultPageLimit = 20
+	maxPageLimit     = 100
+)
+
+// pageCursor points to the last review of the previous page. It is passed to clients as an opaque base64 string.
+type pageCursor struct {
+	ID   int64     `json:"id"`
+	Time time.Time `json:"time"`
+}
+
+type pageParams struct {
+	limit  int
+	cursor *pageCursor
+}
+
+func encodeCursor(cursor pageCursor) string {
+	data, _ := json.Marshal(cursor)
+	return base64.RawURLEncoding.EncodeToString(data)
+}
+
+func decodeCursor(value string) (*pageCursor, error) {
+	data, err := base64.RawURLEncoding.DecodeString(value)
+	if err != nil {
+		return nil, err
+	}
+	cursor := pageCursor{}
+	err = json.Unmarshal(data, &cursor)
+	if err != nil {
+		return nil, err
+	}
+	return &cursor, nil
+}
+
+// parsePageParams parses limit and cursor query parameters.
+func parsePageParams(r *http.Request) (pageParams, error) {
+	query := r.URL.Query()
+	params := pageParams{limit: defaultPageLimit}
+
+	if limit := query.Get("limit"); limit != "" {
+		value, err := strconv.Atoi(limit)
+		if err != nil || value <= 0 {
+			return pageParams{}, errors.New("Invalid limit")
+		}
+		params.limit = min(value, maxPageLimit)
+	}
+
+	if cursor := query.Get("cursor"); cursor != "" {
+		value, err := decodeCursor(cursor)
+		if err != nil {
+			return pageParams{}, errors.New("Invalid cursor")
+		}
+		params.cursor = value
+	}
+	return params, nil
+}
+
+func (p *pageParams) cursorID() sql.NullInt64 {
+	if p.cursor == nil {
+		return sql.NullInt64{}
+	}
+	return sql.NullInt64{Int64: p.cursor.ID, Valid: true}
+}
+
+func (p *pageParams) cursorTime() sql.NullTime {
+	if p.cursor == nil {
+		return sql.NullTime{}
+	}
+	return sql.NullTime{Time: p.cursor.Time, Valid: true}
+}
+
+// queryLimit requests one extra row to find out whether there is a next page.
+func (p *pageParams) queryLimit() int32 {
+	return int32(p.limit + 1)
+}
@@ -0,0 +1,38 @@
+package server
+
+import (
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParsePageParams(t *testing.T) {
+	createdAt := time.Date(2026, 10, 1, 10, 30, 0, 123000, time.UTC)
+	cursor := encodeCursor(pageCursor{ID: 42, Time: createdAt})
+	type testCase struct {
+		name           string
+		query          string
+		expectedParams pageParams
+		hasError       bool
+	}
+	testCases := []testCase{
+		{name: "default", query: "", expectedParams: pageParams{limit: defaultPageLimit}},
+		{name: "limit_above_max", query: "?limit=1000", expectedParams: pageParams{limit: maxPageLimit}},
+		{name: "all_params", query: "?limit=5&cursor=" + cursor, expectedParams: pageParams{limit: 5, cursor: &pageCursor{ID: 42, Time: createdAt}}},
+		{name: "zero_limit", query: "?limit=0", hasError: true},
+		{name: "invalid_limit", query: "?limit=ten", hasError: true},
+		{name: "invalid_cursor", query: "?cursor=invalid", hasError: true},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			request := httptest.NewRequest("GET", "/api/books/id/reviews"+tc.query, nil)
+			params, err := parsePageParams(request)
+			assert.Equal(t, err != nil, tc.hasError)
+			if !tc.hasError {
+				assert.Equal(t, params, tc.expectedParams)
+			}
+		})
+	}
+}
@@ -0,0 +1,273 @@
+package server
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"log"
+	"net/http"
+	"time"
+	"unicode/utf8"
+
+	"github.com/bakurvik/mylib/user-reading/internal/database"
+	"github.com/google/uuid"
+
+	common "github.com/bakurvik/mylib-common"
+)
+
+const (
+	maxProgressNoteLen   = 1000
+	maxProgressMinutes   = 24 * 60
+	maxProgressClockSkew = time.Minute
+	finishedPercent      = 100
+)
+
+type dbReadingProgress struct {
+	page       sql.NullInt32
+	percent    sql.NullFloat64
+	minutes    int32
+	note       string
+	recordedAt time.Time
+	finish     bool
+}
+
+func (p dbReadingProgress) isComplete() bool {
+	return p.percent.Valid && p.percent.Float64 >= finishedPercent
+}
+
+func parseReadingProgress(r *http.Request, now time.Time) (dbReadingProgress, error) {
+	decoder := json.NewDecoder(r.Body)
+	request := RequestReadingProgress{}
+	err := decoder.Decode(&request)
+	if err != nil {
+		return dbReadingProgress{}, err
+	}
+
+	res := dbReadingProgress{minutes: int32(request.Minutes), note: request.Note, recordedAt: now, finish: request.Finish}
+	if request.Page == nil && request.Percent == nil {
+		return dbReadingProgress{}, errors.New("page or percent is required")
+	}
+	if request.Page != nil {
+		if *request.Page < 0 {
+			return dbReadingProgress{}, errors.New("invalid page")
+		}
+		res.page = sql.NullInt32{Int32: int32(*request.Page), Valid: true}
+	}
+	if request.Percent != nil {
+		if *request.Percent < 0 || *request.Percent > finishedPercent {
+			return dbReadingProgress{}, errors.New("percent must be from 0 to 100")
+		}
+		res.percent = sql.NullFloat64{Float64: *request.Percent, Valid: true}
+	}
+	if request.Minutes < 0 || request.Minutes > maxProgressMinutes {
+		return dbReadingProgress{}, errors.New("invalid minutes")
+	}
+	if utf8.RuneCountInString(request.Note) > maxProgressNoteLen {
+		return dbReadingProgress{}, errors.New("note is too long")
+	}
+	if request.RecordedAt != "" {
+		recordedAt, err := time.Parse(time.RFC3339, request.RecordedAt)
+		if err != nil {
+			return dbReadingProgress{}, errors.New("invalid recorded_at, RFC 3339 time is expected")
+		}
+		if recordedAt.After(now.Add(maxProgressClockSkew)) {
+			return dbReadingProgress{}, errors.New("recorded_at is in the future")
+		}
+		res.recordedAt = recordedAt.UTC()
+	}
+	if res.finish && !res.isComplete() {
+		return dbReadingProgress{}, errors.New("book can be finished only with 100 percent")
+	}
+	return res, nil
+}
+
+func toResponseReadingProgress(id int64, page sql.NullInt32, percent sql.NullFloat64, minutes int32, note string, recordedAt time.Time) ResponseReadingProgress {
+	res := ResponseReadingProgress{ID: id, Minutes: int(minutes), Note: note, RecordedAt: recordedAt}
+	if page.Valid {
+		p := int(page.Int32)
+		res.Page = &p
+	}
+	if percent.Valid {
+		res.Percent = &percent.Float64
+	}
+	return res
+}
+
+// truncateToDate returns the date of t, start and finish dates of user reading are stored without time.
+func truncateToDate(t time.Time) sql.NullTime {
+	return sql.NullTime{Time: t.UTC().Truncate(24 * time.Hour), Valid: true}
+}
+
+// addReadingProgress saves the update and moves the book forward: the first update of a book the user wants to read
+// starts reading it, an update of a finished book archives the finished reading and starts a new one,
+// an update with 100 percent and finish flag finishes it.
+func addReadingProgress(ctx context.Context, db *sql.DB, userID uuid.UUID, bookID uuid.UUID, progress dbReadingProgress) (ResponseReadingProgressUpdate, int, error) {
+	tx, err := db.BeginTx(ctx, nil)
+	if err != nil {
+		return ResponseReadingProgressUpdate{}, http.StatusInternalServerError, err
+	}
+	defer func() {
+		if err != nil {
+			if rollbackErr := tx.Rollback(); rollbackErr != nil {
+				log.Print("Failed to rollback transaction ", rollbackErr)
+			}
+		}
+	}()
+
+	queries := database.New(tx)
+	userReading, err := queries.GetUserReadingByBook(ctx, database.GetUserReadingByBookParams{UserID: userID, BookID: bookID})
+	if err == sql.ErrNoRows {
+		return ResponseReadingProgressUpdate{}, http.StatusNotFound, errors.New("Unknown user book")
+	}
+	if err != nil {
+		return ResponseReadingProgressUpdate{}, http.StatusInternalServerError, err
+	}
+
+	recordedDate := truncateToDate(progress.recordedAt)
+	status := userReading.Status
+	switch status {
+	case wantToReadStatus:
+		err = queries.StartUserReading(ctx, database.StartUserReadingParams{UserID: userID, BookID: bookID, StartDate: recordedDate})
+		if err != nil {
+			return ResponseReadingProgressUpdate{}, http.StatusInternalServerError, err
+		}
+		status = readingStatus
+	case finishedStatus:
+		if userReading.FinishDate.Valid && recordedDate.Time.Before(userReading.FinishDate.Time) {
+			err = errors.New("recorded_at is before the finish date of the book")
+			return ResponseReadingProgressUpdate{}, http.StatusBadRequest, err
+		}
+		_, err = queries.ArchiveReadingCycle(ctx, database.ArchiveReadingCycleParams{UserID: userID, BookID: bookID})
+		if err != nil {
+			return ResponseReadingProgressUpdate{}, http.StatusInternalServerError, err
+		}
+		err = queries.RestartUserReading(ctx, database.RestartUserReadingParams{UserID: userID, BookID: bookID, StartDate: recordedDate})
+		if err != nil {
+			return ResponseReadingProgressUpdate{}, http.StatusInternalServerError, err
+		}
+		status = readingStatus
+	}
+
+	progressID, err := queries.CreateReadingProgress(ctx, database.CreateReadingProgressParams{
+		UserID:     userID,
+		BookID:     bookID,
+		Page:       progress.page,
+		Percent:    progress.percent,
+		Minutes:    progress.minutes,
+		Note:       progress.note,
+		RecordedAt: progress.recordedAt,
+	})
+	if err != nil {
+		return ResponseReadingProgressUpdate{}, http.StatusInternalServerError, err
+	}
+
+	if progress.finish {
+		var rowsCount int64
+		rowsCount, err = queries.FinishUserReading(ctx, database.FinishUserReadingParams{UserID: userID, BookID: bookID, FinishDate: recordedDate})
+		if err != nil {
+			return ResponseReadingProgressUpdate{}, http.StatusInternalServerError, err
+		}
+		if rowsCount == 0 {
+			err = errors.New("recorded_at is before the start date of the book")
+			return ResponseReadingProgressUpdate{}, http.StatusBadRequest, err
+		}
+		status = finishedStatus
+	}
+
+	err = tx.Commit()
+	if err != nil {
+		return ResponseReadingProgressUpdate{}, http.StatusInternalServerError, err
+	}
+	return ResponseReadingProgressUpdate{
+		Progress:      toResponseReadingProgress(progressID, progress.page, progress.percent, progress.minutes, progress.note, progress.recordedAt),
+		Status:        string(status),
+		SuggestFinish: progress.isComplete() && status != finishedStatus,
+	}, http.StatusCreated, nil
+}
+
+// @Summary Add reading progress
+// @Description Saves current page or percent of the book with optional note and reading session duration. The first update of a book the user wants to read starts reading it. When percent reaches 100 the response suggests finishing the book, the update with finish flag finishes it with the date of the update. An update of a finished book starts reading it again, the finished reading is kept in reading cycles. Uses access token from an HTTP-only cookie
+// @Tags Reading progress
+// @Accept json
+// @Produce json
+// @Param bookID path string true "Book ID"
+// @Param request body RequestReadingProgress true "Progress update"
+// @Success 201 {object} ResponseReadingProgressUpdate "Saved update with reading status"
+// @Failure 400 {object} ErrorResponse "Invalid request body or recorded_at before the start or finish date of the book"
+// @Failure 401 {object} ErrorResponse "Unauthorized"
+// @Failure 404 {object} ErrorResponse "Book is not in user reading"
+// @Failure 500 {object} ErrorResponse
+// @Router /api/user-reading/{bookID}/progress [post]
+func (cfg *ApiConfig) HandlePostApiUserReadingProgressPath(w http.ResponseWriter, r *http.Request) {
+	bookID, err := uuid.Parse(r.PathValue("bookID"))
+	if err != nil {
+		common.RespondWithError(w, http.StatusBadRequest, "Invalid bookID")
+		return
+	}
+	progress, err := parseReadingProgress(r, time.Now().UTC())
+	if err != nil {
+		common.RespondWithError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	if cfg.DB == nil {
+		common.RespondWithError(w, http.StatusInternalServerError, "DB error")
+		return
+	}
+
+	userID := userIDFromContext(r.Context())
+	response, statusCode, err := addReadingProgress(r.Context(), cfg.DB, userID, bookID, progress)
+	if err != nil {
+		common.RespondWithError(w, statusCode, err.Error())
+		return
+	}
+	common.RespondWithJSON(w, statusCode, response, nil)
+}
+
+// @Summary Get reading progress
+// @Description Gets history of progress updates of the current reading cycle of the book, the oldest first. Updates of previous cycles are kept with them. Uses access token from an HTTP-only cookie
+// @Tags Reading progress
+// @Produce json
+// @Param bookID path string true "Book ID"
+// @Success 200 {array} ResponseReadingProgress "Progress updates"
+// @Failure 400 {object} ErrorResponse "Invalid bookID"
+// @Failure 401 {object} ErrorResponse "Unauthorized"
+// @Failure 404 {object} ErrorResponse "Book is not in user reading"
+// @Failure 500 {object} ErrorResponse
+// @Router /api/user-reading/{bookID}/progress [get]
+func (cfg *ApiConfig) HandleGetApiUserReadingProgressPath(w http.ResponseWriter, r *http.Request) {
+	bookID, err := uuid.Parse(r.PathValue("bookID"))
+	if err != nil {
+		common.RespondWithError(w, http.StatusBadRequest, "Invalid bookID")
+		return
+	}
+
+	if cfg.DB == nil {
+		common.RespondWithError(w, http.StatusInternalServerError, "DB error")
+		return
+	}
+
+	userID := userIDFromContext(r.Context())
+	queries := database.New(cfg.DB)
+	_, dbErr := queries.GetUserReadingByBook(r.Context(), database.GetUserReadingByBookParams{UserID: userID, BookID: bookID})
+	if dbErr == sql.ErrNoRows {
+		common.RespondWithError(w, http.StatusNotFound, "Unknown user book")
+		return
+	}
+	if dbErr != nil {
+		common.RespondWithError(w, http.StatusInternalServerError, dbErr.Error())
+		return
+	}
+
+	progress, dbErr := queries.GetReadingProgress(r.Context(), database.GetReadingProgressParams{UserID: userID, BookID: bookID})
+	if dbErr != nil {
+		common.RespondWithError(w, http.StatusInternalServerError, dbErr.Error())
+		return
+	}
+	response := make([]ResponseReadingProgress, 0, len(progress))
+	for _, p := range progress {
+		response = append(response, toResponseReadingProgress(p.ID, p.Page, p.Percent, p.Minutes, p.Note, p.RecordedAt))
+	}
+	common.RespondWithJSON(w, http.StatusOK, response, nil)
+}
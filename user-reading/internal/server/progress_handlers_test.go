@@ -0,0 +1,62 @@
+package server
+
+import (
+	"database/sql"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseReadingProgress(t *testing.T) {
+	now := time.Date(2026, 10, 17, 12, 0, 0, 0, time.UTC)
+	type testCase struct {
+		name             string
+		body             string
+		expectedError    bool
+		expectedProgress dbReadingProgress
+	}
+	testCases := []testCase{
+		{
+			name:             "page",
+			body:             `{"page": 42, "minutes": 30, "note": "Chapter 3"}`,
+			expectedProgress: dbReadingProgress{page: sql.NullInt32{Int32: 42, Valid: true}, minutes: 30, note: "Chapter 3", recordedAt: now},
+		},
+		{
+			name:             "percent_with_time",
+			body:             `{"percent": 12.5, "recorded_at": "2026-10-16T21:30:00+03:00"}`,
+			expectedProgress: dbReadingProgress{percent: sql.NullFloat64{Float64: 12.5, Valid: true}, recordedAt: time.Date(2026, 10, 16, 18, 30, 0, 0, time.UTC)},
+		},
+		{
+			name:             "finish",
+			body:             `{"percent": 100, "finish": true}`,
+			expectedProgress: dbReadingProgress{percent: sql.NullFloat64{Float64: 100, Valid: true}, recordedAt: now, finish: true},
+		},
+		{name: "no_page_or_percent", body: `{"note": "note"}`, expectedError: true},
+		{name: "negative_page", body: `{"page": -1}`, expectedError: true},
+		{name: "percent_over_100", body: `{"percent": 100.5}`, expectedError: true},
+		{name: "negative_minutes", body: `{"page": 1, "minutes": -5}`, expectedError: true},
+		{name: "invalid_time", body: `{"page": 1, "recorded_at": "16.10.2026"}`, expectedError: true},
+		{name: "future_time", body: `{"page": 1, "recorded_at": "2026-10-18T12:00:00Z"}`, expectedError: true},
+		{name: "finish_not_complete", body: `{"percent": 90, "finish": true}`, expectedError: true},
+		{name: "finish_by_page", body: `{"page": 300, "finish": true}`, expectedError: true},
+		{name: "long_note", body: `{"page": 1, "note": "` + strings.Repeat("a", maxProgressNoteLen+1) + `"}`, expectedError: true},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			request, _ := http.NewRequest(http.MethodPost, "/", strings.NewReader(tc.body))
+			progress, err := parseReadingProgress(request, now)
+			assert.Equal(t, err != nil, tc.expectedError)
+			if !tc.expectedError {
+				assert.Equal(t, progress, tc.expectedProgress)
+			}
+		})
+	}
+}
+
+func TestTruncateToDate(t *testing.T) {
+	date := truncateToDate(time.Date(2026, 10, 16, 23, 30, 0, 0, time.FixedZone("UTC-3", -3*60*60)))
+	assert.Equal(t, date, sql.NullTime{Time: time.Date(2026, 10, 17, 0, 0, 0, 0, time.UTC), Valid: true})
+}
@@ -0,0 +1,202 @@
+package server
+
+import (
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/bakurvik/mylib/user-reading/internal/database"
+	"github.com/google/uuid"
+
+	common "github.com/bakurvik/mylib-common"
+)
+
+const maxReviewBodyLen = 10000
+
+type dbReview struct {
+	bookID  uuid.UUID
+	body    string
+	spoiler bool
+}
+
+func parseReview(r *http.Request) (dbReview, error) {
+	decoder := json.NewDecoder(r.Body)
+	request := RequestReview{}
+	err := decoder.Decode(&request)
+	if err != nil {
+		return dbReview{}, err
+	}
+	bookID, err := uuid.Parse(request.BookID)
+	if err != nil {
+		return dbReview{}, errors.New("invalid book id")
+	}
+	body := strings.TrimSpace(request.Body)
+	if body == "" {
+		return dbReview{}, errors.New("review body is required")
+	}
+	if utf8.RuneCountInString(body) > maxReviewBodyLen {
+		return dbReview{}, errors.New("review body is too long")
+	}
+	return dbReview{bookID: bookID, body: body, spoiler: request.Spoiler}, nil
+}
+
+func toResponseReview(id int64, body string, spoiler bool, createdAt time.Time, editedAt sql.NullTime) ResponseReview {
+	res := ResponseReview{ID: id, Body: body, Spoiler: spoiler, CreatedAt: createdAt}
+	if editedAt.Valid {
+		res.EditedAt = &editedAt.Time
+	}
+	return res
+}
+
+// @Summary Write review
+// @Description Creates or edits the review of the current reading cycle of a finished book. Every read of the book has its own review, re-reading the book keeps the review with the finished cycle. Uses access token from an HTTP-only cookie
+// @Tags Reviews
+// @Accept json
+// @Produce json
+// @Param request body RequestReview true "Book id with review"
+// @Success 200 {object} ResponseReview "Edited review"
+// @Success 201 {object} ResponseReview "Created review"
+// @Failure 400 {object} ErrorResponse "Invalid request body or the book is not finished"
+// @Failure 401 {object} ErrorResponse "Unauthorized"
+// @Failure 404 {object} ErrorResponse "Unknown user book"
+// @Failure 500 {object} ErrorResponse
+// @Router /api/user-reading/reviews [put]
+func (cfg *ApiConfig) HandlePutApiUserReadingReviewsPath(w http.ResponseWriter, r *http.Request) {
+	review, err := parseReview(r)
+	if err != nil {
+		common.RespondWithError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	if cfg.DB == nil {
+		common.RespondWithError(w, http.StatusInternalServerError, "DB error")
+		return
+	}
+
+	userID := userIDFromContext(r.Context())
+
+	queries := database.New(cfg.DB)
+	userReading, dbErr := queries.GetUserReadingByBook(r.Context(), database.GetUserReadingByBookParams{UserID: userID, BookID: review.bookID})
+	if dbErr == sql.ErrNoRows {
+		common.RespondWithError(w, http.StatusNotFound, "Unknown user book")
+		return
+	}
+	if dbErr != nil {
+		common.RespondWithError(w, http.StatusInternalServerError, dbErr.Error())
+		return
+	}
+	if userReading.Status != finishedStatus {
+		common.RespondWithError(w, http.StatusBadRequest, "Only finished books can be reviewed")
+		return
+	}
+
+	saved, dbErr := queries.UpsertReview(r.Context(), database.UpsertReviewParams{UserID: userID, BookID: review.bookID, Body: review.body, Spoiler: review.spoiler})
+	if dbErr != nil {
+		common.RespondWithError(w, http.StatusInternalServerError, dbErr.Error())
+		return
+	}
+	statusCode := http.StatusOK
+	if !saved.EditedAt.Valid {
+		statusCode = http.StatusCreated
+	}
+	common.RespondWithJSON(w, statusCode, toResponseReview(saved.ID, review.body, review.spoiler, saved.CreatedAt, saved.EditedAt), nil)
+}
+
+// @Summary Delete review
+// @Description Deletes the review of the current reading cycle of the book. Uses access token from an HTTP-only cookie
+// @Tags Reviews
+// @Param bookID path string true "Book ID"
+// @Success 204 "Deleted successfully"
+// @Failure 400 {object} ErrorResponse "Invalid bookID"
+// @Failure 401 {object} ErrorResponse "Unauthorized"
+// @Failure 404 {object} ErrorResponse "Unknown review"
+// @Failure 500 {object} ErrorResponse
+// @Router /api/user-reading/reviews/{bookID} [delete]
+func (cfg *ApiConfig) HandleDeleteApiUserReadingReviewPath(w http.ResponseWriter, r *http.Request) {
+	bookID, err := uuid.Parse(r.PathValue("bookID"))
+	if err != nil {
+		common.RespondWithError(w, http.StatusBadRequest, "Invalid bookID")
+		return
+	}
+
+	if cfg.DB == nil {
+		common.RespondWithError(w, http.StatusInternalServerError, "DB error")
+		return
+	}
+
+	userID := userIDFromContext(r.Context())
+
+	queries := database.New(cfg.DB)
+	deleted, dbErr := queries.DeleteReview(r.Context(), database.DeleteReviewParams{UserID: userID, BookID: bookID})
+	if dbErr != nil {
+		common.RespondWithError(w, http.StatusInternalServerError, dbErr.Error())
+		return
+	}
+	if deleted == 0 {
+		common.RespondWithError(w, http.StatusNotFound, "Unknown review")
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// @Summary Get book reviews
+// @Description Gets a page of reviews of the book from users with public profiles, the newest first. Doesn't require authorization
+// @Tags Reviews
+// @Produce json
+// @Param bookID path string true "Book ID"
+// @Param limit query int false "Page size"
+// @Param cursor query string false "Cursor returned with the previous page"
+// @Success 200 {object} ResponseBookReviewsPage "Page of reviews"
+// @Failure 400 {object} ErrorResponse "Invalid bookID or query parameters"
+// @Failure 500 {object} ErrorResponse
+// @Router /api/books/{bookID}/reviews [get]
+func (cfg *ApiConfig) HandleGetApiBookReviewsPath(w http.ResponseWriter, r *http.Request) {
+	bookID, err := uuid.Parse(r.PathValue("bookID"))
+	if err != nil {
+		common.RespondWithError(w, http.StatusBadRequest, "Invalid bookID")
+		return
+	}
+	params, err := parsePageParams(r)
+	if err != nil {
+		common.RespondWithError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	if cfg.DB == nil {
+		common.RespondWithError(w, http.StatusInternalServerError, "DB error")
+		return
+	}
+
+	queries := database.New(cfg.DB)
+	reviews, dbErr := queries.GetBookReviews(r.Context(), database.GetBookReviewsParams{
+		BookID:          bookID,
+		CursorID:        params.cursorID(),
+		CursorCreatedAt: params.cursorTime(),
+		Limit:           params.queryLimit(),
+	})
+	if dbErr != nil {
+		common.RespondWithError(w, http.StatusInternalServerError, dbErr.Error())
+		return
+	}
+
+	response := ResponseBookReviewsPage{}
+	if len(reviews) > params.limit {
+		last := reviews[params.limit-1]
+		response.NextCursor = encodeCursor(pageCursor{ID: last.ID, Time: last.CreatedAt})
+		reviews = reviews[:params.limit]
+	}
+	response.Items = make([]ResponseBookReview, 0, len(reviews))
+	for _, review := range reviews {
+		response.Items = append(response.Items, ResponseBookReview{
+			ResponseReview: toResponseReview(review.ID, review.Body, review.Spoiler, review.CreatedAt, review.EditedAt),
+			UserID:         review.UserID.String(),
+			DisplayName:    review.DisplayName,
+			Rating:         int(review.Rating),
+		})
+	}
+	common.RespondWithJSON(w, http.StatusOK, response, nil)
+}
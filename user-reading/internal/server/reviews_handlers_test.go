@@ -0,0 +1,44 @@
+package server
+
+import (
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseReview(t *testing.T) {
+	bookID := uuid.New()
+	type testCase struct {
+		name           string
+		body           string
+		expectedError  bool
+		expectedReview dbReview
+	}
+	testCases := []testCase{
+		{
+			name:           "review",
+			body:           `{"book_id": "` + bookID.String() + `", "body": " Great ending ", "spoiler": true}`,
+			expectedReview: dbReview{bookID: bookID, body: "Great ending", spoiler: true},
+		},
+		{
+			name:           "longest_body",
+			body:           `{"book_id": "` + bookID.String() + `", "body": "` + strings.Repeat("я", maxReviewBodyLen) + `"}`,
+			expectedReview: dbReview{bookID: bookID, body: strings.Repeat("я", maxReviewBodyLen)},
+		},
+		{name: "invalid_book_id", body: `{"book_id": "book", "body": "Review"}`, expectedError: true},
+		{name: "empty_body", body: `{"book_id": "` + bookID.String() + `", "body": " "}`, expectedError: true},
+		{name: "long_body", body: `{"book_id": "` + bookID.String() + `", "body": "` + strings.Repeat("a", maxReviewBodyLen+1) + `"}`, expectedError: true},
+		{name: "invalid_body", body: `{"book_id": "` + bookID.String() + `", "spoiler": "yes"}`, expectedError: true},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			request, _ := http.NewRequest(http.MethodPut, "/", strings.NewReader(tc.body))
+			review, err := parseReview(request)
+			assert.Equal(t, err != nil, tc.expectedError)
+			assert.Equal(t, review, tc.expectedReview)
+		})
+	}
+}
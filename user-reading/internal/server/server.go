@@ -4,12 +4,18 @@ import (
 	"fmt"
 	"net/http"
 
+	"github.com/bakurvik/mylib/user-reading/internal/auth"
 	httpSwagger "github.com/swaggo/http-swagger"
 )
 
 const (
-	ApiUserReadingPath = "/api/user-reading"
-	PingPath           = "/ping"
+	ApiUserReadingPath        = "/api/user-reading"
+	ApiUserReadingExportPath  = "/api/user-reading/export"
+	ApiUserReadingStatsPath   = "/api/user-reading/stats"
+	ApiUserReadingShelvesPath = "/api/user-reading/shelves"
+	ApiUserReadingReviewsPath = "/api/user-reading/reviews"
+	ApiBooksPath              = "/api/books"
+	PingPath                  = "/ping"
 )
 
 func Handle(sm *http.ServeMux, apiCfg *ApiConfig) {
@@ -17,11 +23,29 @@ func Handle(sm *http.ServeMux, apiCfg *ApiConfig) {
 	sm.HandleFunc("GET "+PingPath, apiCfg.HandlePing)
 
 	// User reading
-	sm.HandleFunc("POST "+ApiUserReadingPath, apiCfg.HandlePostApiUserReadingPath)
-	sm.HandleFunc("PUT "+ApiUserReadingPath, apiCfg.HandlePutApiUserReadingPath)
-	sm.HandleFunc(fmt.Sprintf("DELETE %v/{bookID}", ApiUserReadingPath), apiCfg.HandleDeleteApiUserReadingPath)
-	sm.HandleFunc("GET "+ApiUserReadingPath, apiCfg.HandleGetApiUserReadingPath)
-	sm.HandleFunc(fmt.Sprintf("GET %v/{bookID}", ApiUserReadingPath), apiCfg.HandleGetApiUserReadingByBookPath)
+	sm.HandleFunc("POST "+ApiUserReadingPath, apiCfg.requireUser(auth.ScopeReadingWrite, apiCfg.HandlePostApiUserReadingPath))
+	sm.HandleFunc("PUT "+ApiUserReadingPath, apiCfg.requireUser(auth.ScopeReadingWrite, apiCfg.HandlePutApiUserReadingPath))
+	sm.HandleFunc(fmt.Sprintf("DELETE %v/{bookID}", ApiUserReadingPath), apiCfg.requireUser(auth.ScopeReadingWrite, apiCfg.HandleDeleteApiUserReadingPath))
+	sm.HandleFunc("GET "+ApiUserReadingPath, apiCfg.requireUser(auth.ScopeReadingRead, apiCfg.HandleGetApiUserReadingPath))
+	sm.HandleFunc(fmt.Sprintf("GET %v/{bookID}", ApiUserReadingPath), apiCfg.requireUser(auth.ScopeReadingRead, apiCfg.HandleGetApiUserReadingByBookPath))
+	sm.HandleFunc(fmt.Sprintf("POST %v/{bookID}/progress", ApiUserReadingPath), apiCfg.requireUser(auth.ScopeReadingWrite, apiCfg.HandlePostApiUserReadingProgressPath))
+	sm.HandleFunc(fmt.Sprintf("GET %v/{bookID}/progress", ApiUserReadingPath), apiCfg.requireUser(auth.ScopeReadingRead, apiCfg.HandleGetApiUserReadingProgressPath))
+	sm.HandleFunc("GET "+ApiUserReadingStatsPath, apiCfg.requireUser(auth.ScopeReadingRead, apiCfg.HandleGetApiUserReadingStatsPath))
+	sm.HandleFunc("GET "+ApiUserReadingExportPath, apiCfg.requireUser(auth.ScopeReadingRead, apiCfg.HandleGetApiUserReadingExportPath))
+
+	// Shelves
+	sm.HandleFunc("POST "+ApiUserReadingShelvesPath, apiCfg.requireUser(auth.ScopeReadingWrite, apiCfg.HandlePostApiUserReadingShelvesPath))
+	sm.HandleFunc("GET "+ApiUserReadingShelvesPath, apiCfg.requireUser(auth.ScopeReadingRead, apiCfg.HandleGetApiUserReadingShelvesPath))
+	sm.HandleFunc(fmt.Sprintf("PUT %v/order", ApiUserReadingShelvesPath), apiCfg.requireUser(auth.ScopeReadingWrite, apiCfg.HandlePutApiUserReadingShelvesOrderPath))
+	sm.HandleFunc(fmt.Sprintf("PUT %v/{shelfID}", ApiUserReadingShelvesPath), apiCfg.requireUser(auth.ScopeReadingWrite, apiCfg.HandlePutApiUserReadingShelfPath))
+	sm.HandleFunc(fmt.Sprintf("DELETE %v/{shelfID}", ApiUserReadingShelvesPath), apiCfg.requireUser(auth.ScopeReadingWrite, apiCfg.HandleDeleteApiUserReadingShelfPath))
+	sm.HandleFunc(fmt.Sprintf("PUT %v/{shelfID}/books/{bookID}", ApiUserReadingShelvesPath), apiCfg.requireUser(auth.ScopeReadingWrite, apiCfg.HandlePutApiUserReadingShelfBookPath))
+	sm.HandleFunc(fmt.Sprintf("DELETE %v/{shelfID}/books/{bookID}", ApiUserReadingShelvesPath), apiCfg.requireUser(auth.ScopeReadingWrite, apiCfg.HandleDeleteApiUserReadingShelfBookPath))
+
+	// Reviews
+	sm.HandleFunc("PUT "+ApiUserReadingReviewsPath, apiCfg.requireUser(auth.ScopeReadingWrite, apiCfg.HandlePutApiUserReadingReviewsPath))
+	sm.HandleFunc(fmt.Sprintf("DELETE %v/{bookID}", ApiUserReadingReviewsPath), apiCfg.requireUser(auth.ScopeReadingWrite, apiCfg.HandleDeleteApiUserReadingReviewPath))
+	sm.HandleFunc(fmt.Sprintf("GET %v/{bookID}/reviews", ApiBooksPath), apiCfg.HandleGetApiBookReviewsPath)
 
 	// Swagger
 	sm.Handle("/swagger/", httpSwagger.WrapHandler)
@@ -0,0 +1,437 @@
+package server
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"log"
+	"net/http"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/bakurvik/mylib/user-reading/internal/database"
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+
+	common "github.com/bakurvik/mylib-common"
+)
+
+const maxShelfNameLen = 100
+
+var errShelfExists = errors.New("shelf with this name already exists")
+
+func isUniqueViolation(err error) bool {
+	var pqErr *pq.Error
+	return errors.As(err, &pqErr) && pqErr.Code == "23505"
+}
+
+func parseShelf(r *http.Request) (string, error) {
+	decoder := json.NewDecoder(r.Body)
+	request := RequestShelf{}
+	err := decoder.Decode(&request)
+	if err != nil {
+		return "", err
+	}
+	name := strings.TrimSpace(request.Name)
+	if name == "" {
+		return "", errors.New("shelf name is required")
+	}
+	if utf8.RuneCountInString(name) > maxShelfNameLen {
+		return "", errors.New("shelf name is too long")
+	}
+	return name, nil
+}
+
+// parseShelvesOrder returns shelf IDs of the request, every shelf must be listed once.
+func parseShelvesOrder(r *http.Request) ([]uuid.UUID, error) {
+	decoder := json.NewDecoder(r.Body)
+	request := RequestShelvesOrder{}
+	err := decoder.Decode(&request)
+	if err != nil {
+		return nil, err
+	}
+	res := make([]uuid.UUID, 0, len(request.ShelfIDs))
+	seen := map[uuid.UUID]bool{}
+	for _, id := range request.ShelfIDs {
+		shelfID, err := uuid.Parse(id)
+		if err != nil {
+			return nil, errors.New("invalid shelf id")
+		}
+		if seen[shelfID] {
+			return nil, errors.New("duplicate shelf id")
+		}
+		seen[shelfID] = true
+		res = append(res, shelfID)
+	}
+	return res, nil
+}
+
+func parseShelfID(r *http.Request) (uuid.UUID, error) {
+	shelfID, err := uuid.Parse(r.PathValue("shelfID"))
+	if err != nil {
+		return uuid.Nil, errors.New("Invalid shelfID")
+	}
+	return shelfID, nil
+}
+
+// checkShelf checks that the shelf belongs to the user.
+func checkShelf(ctx context.Context, queries *database.Queries, userID uuid.UUID, shelfID uuid.UUID) (int, error) {
+	_, err := queries.GetUserShelf(ctx, database.GetUserShelfParams{ID: shelfID, UserID: userID})
+	if err == sql.ErrNoRows {
+		return http.StatusNotFound, errors.New("Unknown shelf")
+	}
+	if err != nil {
+		return http.StatusInternalServerError, err
+	}
+	return http.StatusOK, nil
+}
+
+// setShelvesOrder sets positions of the shelves to their indexes. The request must list all shelves of the user,
+// so positions stay unique.
+func setShelvesOrder(ctx context.Context, db *sql.DB, userID uuid.UUID, shelfIDs []uuid.UUID) (int, error) {
+	tx, err := db.BeginTx(ctx, nil)
+	if err != nil {
+		return http.StatusInternalServerError, err
+	}
+	defer func() {
+		if err != nil {
+			if rollbackErr := tx.Rollback(); rollbackErr != nil {
+				log.Print("Failed to rollback transaction ", rollbackErr)
+			}
+		}
+	}()
+
+	queries := database.New(tx)
+	shelves, err := queries.GetUserShelves(ctx, userID)
+	if err != nil {
+		return http.StatusInternalServerError, err
+	}
+	if len(shelves) != len(shelfIDs) {
+		err = errors.New("all shelves must be listed")
+		return http.StatusBadRequest, err
+	}
+	for i, shelfID := range shelfIDs {
+		var updated int64
+		updated, err = queries.SetShelfPosition(ctx, database.SetShelfPositionParams{ID: shelfID, UserID: userID, Position: int32(i)})
+		if err != nil {
+			return http.StatusInternalServerError, err
+		}
+		if updated == 0 {
+			err = errors.New("Unknown shelf")
+			return http.StatusBadRequest, err
+		}
+	}
+
+	err = tx.Commit()
+	if err != nil {
+		return http.StatusInternalServerError, err
+	}
+	return http.StatusNoContent, nil
+}
+
+func getUserReadingByShelf(db *sql.DB, userID uuid.UUID, shelfID uuid.UUID, ctx context.Context) ([]dbUserReading, error) {
+	queries := database.New(db)
+	userReading, dbErr := queries.GetUserReadingByShelf(ctx, database.GetUserReadingByShelfParams{UserID: userID, ShelfID: shelfID})
+	if dbErr != nil {
+		return nil, dbErr
+	}
+	res := make([]dbUserReading, 0, len(userReading))
+	for _, book := range userReading {
+		res = append(res, dbUserReading{
+			bookID:     book.BookID,
+			status:     book.Status,
+			rating:     book.Rating,
+			startDate:  book.StartDate,
+			finishDate: book.FinishDate,
+			createdAt:  book.CreatedAt})
+	}
+	return res, nil
+}
+
+// @Summary Create shelf
+// @Description Creates a custom shelf at the end of the user's shelves. Uses access token from an HTTP-only cookie
+// @Tags Shelves
+// @Accept json
+// @Produce json
+// @Param request body RequestShelf true "Shelf name"
+// @Success 201 {object} ResponseShelf "Created shelf"
+// @Failure 400 {object} ErrorResponse "Invalid request body"
+// @Failure 401 {object} ErrorResponse "Unauthorized"
+// @Failure 409 {object} ErrorResponse "Shelf with this name already exists"
+// @Failure 500 {object} ErrorResponse
+// @Router /api/user-reading/shelves [post]
+func (cfg *ApiConfig) HandlePostApiUserReadingShelvesPath(w http.ResponseWriter, r *http.Request) {
+	name, err := parseShelf(r)
+	if err != nil {
+		common.RespondWithError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	if cfg.DB == nil {
+		common.RespondWithError(w, http.StatusInternalServerError, "DB error")
+		return
+	}
+
+	userID := userIDFromContext(r.Context())
+
+	queries := database.New(cfg.DB)
+	shelf, dbErr := queries.CreateShelf(r.Context(), database.CreateShelfParams{UserID: userID, Name: name})
+	if isUniqueViolation(dbErr) {
+		common.RespondWithError(w, http.StatusConflict, errShelfExists.Error())
+		return
+	}
+	if dbErr != nil {
+		common.RespondWithError(w, http.StatusInternalServerError, dbErr.Error())
+		return
+	}
+	common.RespondWithJSON(w, http.StatusCreated, ResponseShelf{ID: shelf.ID.String(), Name: name, Position: int(shelf.Position)}, nil)
+}
+
+// @Summary Get shelves
+// @Description Gets custom shelves of the user in their order with number of books on each. Uses access token from an HTTP-only cookie
+// @Tags Shelves
+// @Produce json
+// @Success 200 {array} ResponseShelf "Shelves"
+// @Failure 401 {object} ErrorResponse "Unauthorized"
+// @Failure 500 {object} ErrorResponse
+// @Router /api/user-reading/shelves [get]
+func (cfg *ApiConfig) HandleGetApiUserReadingShelvesPath(w http.ResponseWriter, r *http.Request) {
+	if cfg.DB == nil {
+		common.RespondWithError(w, http.StatusInternalServerError, "DB error")
+		return
+	}
+
+	userID := userIDFromContext(r.Context())
+
+	queries := database.New(cfg.DB)
+	shelves, err := queries.GetUserShelves(r.Context(), userID)
+	if err != nil {
+		common.RespondWithError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	response := make([]ResponseShelf, 0, len(shelves))
+	for _, shelf := range shelves {
+		response = append(response, ResponseShelf{
+			ID:         shelf.ID.String(),
+			Name:       shelf.Name,
+			Position:   int(shelf.Position),
+			BooksCount: shelf.BooksCount,
+		})
+	}
+	common.RespondWithJSON(w, http.StatusOK, response, nil)
+}
+
+// @Summary Rename shelf
+// @Description Renames a custom shelf. Uses access token from an HTTP-only cookie
+// @Tags Shelves
+// @Accept json
+// @Param shelfID path string true "Shelf ID"
+// @Param request body RequestShelf true "New shelf name"
+// @Success 204 "Renamed successfully"
+// @Failure 400 {object} ErrorResponse "Invalid request body"
+// @Failure 401 {object} ErrorResponse "Unauthorized"
+// @Failure 404 {object} ErrorResponse "Unknown shelf"
+// @Failure 409 {object} ErrorResponse "Shelf with this name already exists"
+// @Failure 500 {object} ErrorResponse
+// @Router /api/user-reading/shelves/{shelfID} [put]
+func (cfg *ApiConfig) HandlePutApiUserReadingShelfPath(w http.ResponseWriter, r *http.Request) {
+	shelfID, err := parseShelfID(r)
+	if err != nil {
+		common.RespondWithError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	name, err := parseShelf(r)
+	if err != nil {
+		common.RespondWithError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	if cfg.DB == nil {
+		common.RespondWithError(w, http.StatusInternalServerError, "DB error")
+		return
+	}
+
+	userID := userIDFromContext(r.Context())
+
+	queries := database.New(cfg.DB)
+	updated, dbErr := queries.RenameShelf(r.Context(), database.RenameShelfParams{ID: shelfID, UserID: userID, Name: name})
+	if isUniqueViolation(dbErr) {
+		common.RespondWithError(w, http.StatusConflict, errShelfExists.Error())
+		return
+	}
+	if dbErr != nil {
+		common.RespondWithError(w, http.StatusInternalServerError, dbErr.Error())
+		return
+	}
+	if updated == 0 {
+		common.RespondWithError(w, http.StatusNotFound, "Unknown shelf")
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// @Summary Reorder shelves
+// @Description Sets the order of custom shelves. All shelves of the user must be listed. Uses access token from an HTTP-only cookie
+// @Tags Shelves
+// @Accept json
+// @Param request body RequestShelvesOrder true "Shelf IDs in the new order"
+// @Success 204 "Reordered successfully"
+// @Failure 400 {object} ErrorResponse "Invalid request body"
+// @Failure 401 {object} ErrorResponse "Unauthorized"
+// @Failure 500 {object} ErrorResponse
+// @Router /api/user-reading/shelves/order [put]
+func (cfg *ApiConfig) HandlePutApiUserReadingShelvesOrderPath(w http.ResponseWriter, r *http.Request) {
+	shelfIDs, err := parseShelvesOrder(r)
+	if err != nil {
+		common.RespondWithError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	if cfg.DB == nil {
+		common.RespondWithError(w, http.StatusInternalServerError, "DB error")
+		return
+	}
+
+	userID := userIDFromContext(r.Context())
+
+	statusCode, dbErr := setShelvesOrder(r.Context(), cfg.DB, userID, shelfIDs)
+	if dbErr != nil {
+		common.RespondWithError(w, statusCode, dbErr.Error())
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// @Summary Delete shelf
+// @Description Deletes a custom shelf. Books on the shelf stay in user reading. Uses access token from an HTTP-only cookie
+// @Tags Shelves
+// @Param shelfID path string true "Shelf ID"
+// @Success 204 "Deleted successfully"
+// @Failure 400 {object} ErrorResponse "Invalid shelfID"
+// @Failure 401 {object} ErrorResponse "Unauthorized"
+// @Failure 404 {object} ErrorResponse "Unknown shelf"
+// @Failure 500 {object} ErrorResponse
+// @Router /api/user-reading/shelves/{shelfID} [delete]
+func (cfg *ApiConfig) HandleDeleteApiUserReadingShelfPath(w http.ResponseWriter, r *http.Request) {
+	shelfID, err := parseShelfID(r)
+	if err != nil {
+		common.RespondWithError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	if cfg.DB == nil {
+		common.RespondWithError(w, http.StatusInternalServerError, "DB error")
+		return
+	}
+
+	userID := userIDFromContext(r.Context())
+
+	queries := database.New(cfg.DB)
+	deleted, dbErr := queries.DeleteShelf(r.Context(), database.DeleteShelfParams{ID: shelfID, UserID: userID})
+	if dbErr != nil {
+		common.RespondWithError(w, http.StatusInternalServerError, dbErr.Error())
+		return
+	}
+	if deleted == 0 {
+		common.RespondWithError(w, http.StatusNotFound, "Unknown shelf")
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// @Summary Put book on shelf
+// @Description Puts a book of user reading on a custom shelf. A book can be on several shelves. Uses access token from an HTTP-only cookie
+// @Tags Shelves
+// @Param shelfID path string true "Shelf ID"
+// @Param bookID path string true "Book ID"
+// @Success 204 "Book is on the shelf"
+// @Failure 400 {object} ErrorResponse "Invalid shelfID or bookID"
+// @Failure 401 {object} ErrorResponse "Unauthorized"
+// @Failure 404 {object} ErrorResponse "Unknown shelf or user book"
+// @Failure 500 {object} ErrorResponse
+// @Router /api/user-reading/shelves/{shelfID}/books/{bookID} [put]
+func (cfg *ApiConfig) HandlePutApiUserReadingShelfBookPath(w http.ResponseWriter, r *http.Request) {
+	shelfID, err := parseShelfID(r)
+	if err != nil {
+		common.RespondWithError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	bookID, err := uuid.Parse(r.PathValue("bookID"))
+	if err != nil {
+		common.RespondWithError(w, http.StatusBadRequest, "Invalid bookID")
+		return
+	}
+
+	if cfg.DB == nil {
+		common.RespondWithError(w, http.StatusInternalServerError, "DB error")
+		return
+	}
+
+	userID := userIDFromContext(r.Context())
+
+	queries := database.New(cfg.DB)
+	statusCode, shelfErr := checkShelf(r.Context(), queries, userID, shelfID)
+	if shelfErr != nil {
+		common.RespondWithError(w, statusCode, shelfErr.Error())
+		return
+	}
+	_, dbErr := queries.GetUserReadingByBook(r.Context(), database.GetUserReadingByBookParams{UserID: userID, BookID: bookID})
+	if dbErr == sql.ErrNoRows {
+		common.RespondWithError(w, http.StatusNotFound, "Unknown user book")
+		return
+	}
+	if dbErr != nil {
+		common.RespondWithError(w, http.StatusInternalServerError, dbErr.Error())
+		return
+	}
+	dbErr = queries.AddShelfBook(r.Context(), database.AddShelfBookParams{ShelfID: shelfID, UserID: userID, BookID: bookID})
+	if dbErr != nil {
+		common.RespondWithError(w, http.StatusInternalServerError, dbErr.Error())
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// @Summary Remove book from shelf
+// @Description Removes a book from a custom shelf, the book stays in user reading. Uses access token from an HTTP-only cookie
+// @Tags Shelves
+// @Param shelfID path string true "Shelf ID"
+// @Param bookID path string true "Book ID"
+// @Success 204 "Removed successfully"
+// @Failure 400 {object} ErrorResponse "Invalid shelfID or bookID"
+// @Failure 401 {object} ErrorResponse "Unauthorized"
+// @Failure 404 {object} ErrorResponse "Book is not on the shelf"
+// @Failure 500 {object} ErrorResponse
+// @Router /api/user-reading/shelves/{shelfID}/books/{bookID} [delete]
+func (cfg *ApiConfig) HandleDeleteApiUserReadingShelfBookPath(w http.ResponseWriter, r *http.Request) {
+	shelfID, err := parseShelfID(r)
+	if err != nil {
+		common.RespondWithError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	bookID, err := uuid.Parse(r.PathValue("bookID"))
+	if err != nil {
+		common.RespondWithError(w, http.StatusBadRequest, "Invalid bookID")
+		return
+	}
+
+	if cfg.DB == nil {
+		common.RespondWithError(w, http.StatusInternalServerError, "DB error")
+		return
+	}
+
+	userID := userIDFromContext(r.Context())
+
+	queries := database.New(cfg.DB)
+	deleted, dbErr := queries.DeleteShelfBook(r.Context(), database.DeleteShelfBookParams{ShelfID: shelfID, UserID: userID, BookID: bookID})
+	if dbErr != nil {
+		common.RespondWithError(w, http.StatusInternalServerError, dbErr.Error())
+		return
+	}
+	if deleted == 0 {
+		common.RespondWithError(w, http.StatusNotFound, "Book is not on the shelf")
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
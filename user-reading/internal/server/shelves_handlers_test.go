@@ -0,0 +1,62 @@
+package server
+
+import (
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseShelf(t *testing.T) {
+	type testCase struct {
+		name          string
+		body          string
+		expectedError bool
+		expectedName  string
+	}
+	testCases := []testCase{
+		{name: "name", body: `{"name": "Book club 2026"}`, expectedName: "Book club 2026"},
+		{name: "trimmed_name", body: `{"name": "  Favourites "}`, expectedName: "Favourites"},
+		{name: "longest_name", body: `{"name": "` + strings.Repeat("я", maxShelfNameLen) + `"}`, expectedName: strings.Repeat("я", maxShelfNameLen)},
+		{name: "empty_name", body: `{"name": "  "}`, expectedError: true},
+		{name: "long_name", body: `{"name": "` + strings.Repeat("a", maxShelfNameLen+1) + `"}`, expectedError: true},
+		{name: "invalid_body", body: `{"name": 1}`, expectedError: true},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			request, _ := http.NewRequest(http.MethodPost, "/", strings.NewReader(tc.body))
+			name, err := parseShelf(request)
+			assert.Equal(t, err != nil, tc.expectedError)
+			assert.Equal(t, name, tc.expectedName)
+		})
+	}
+}
+
+func TestParseShelvesOrder(t *testing.T) {
+	shelf1 := uuid.New()
+	shelf2 := uuid.New()
+	type testCase struct {
+		name          string
+		body          string
+		expectedError bool
+		expectedIDs   []uuid.UUID
+	}
+	testCases := []testCase{
+		{name: "order", body: `{"shelf_ids": ["` + shelf2.String() + `", "` + shelf1.String() + `"]}`, expectedIDs: []uuid.UUID{shelf2, shelf1}},
+		{name: "empty", body: `{"shelf_ids": []}`, expectedIDs: []uuid.UUID{}},
+		{name: "invalid_id", body: `{"shelf_ids": ["shelf"]}`, expectedError: true},
+		{name: "duplicate_id", body: `{"shelf_ids": ["` + shelf1.String() + `", "` + shelf1.String() + `"]}`, expectedError: true},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			request, _ := http.NewRequest(http.MethodPut, "/", strings.NewReader(tc.body))
+			shelfIDs, err := parseShelvesOrder(request)
+			assert.Equal(t, err != nil, tc.expectedError)
+			if !tc.expectedError {
+				assert.Equal(t, shelfIDs, tc.expectedIDs)
+			}
+		})
+	}
+}
@@ -1,20 +1,26 @@
 package main
 
 import (
+	"context"
 	"log"
 	"net/http"
 	"os"
 	"time"
 
+	"github.com/bakurvik/mylib/shared/jwks"
+	"github.com/bakurvik/mylib/user-reading/internal/auth"
 	"github.com/bakurvik/mylib/user-reading/internal/clients"
 	"github.com/bakurvik/mylib/user-reading/internal/config"
+	"github.com/bakurvik/mylib/user-reading/internal/database"
 	"github.com/bakurvik/mylib/user-reading/internal/server"
+	"github.com/google/uuid"
 
 	common "github.com/bakurvik/mylib-common"
 
 	_ "github.com/bakurvik/mylib/user-reading/docs"
 
 	_ "github.com/lib/pq"
+	"github.com/segmentio/kafka-go"
 )
 
 // @title User reading Service API
@@ -54,6 +60,35 @@ func getBooksCacheConfig() config.BooksCacheConfig {
 	return cfg
 }
 
+// getAuthenticator verifies access tokens locally with public keys from JWKS_URL. With AUTH_WHOAMI_FALLBACK=true
+// tokens that can't be verified locally are checked by users service, without JWKS_URL all of them are.
+// Personal access tokens are always checked by users service.
+func getAuthenticator(usersServiceHost string) auth.Authenticator {
+	var whoami auth.Authenticator
+	if os.Getenv("AUTH_WHOAMI_FALLBACK") == "true" {
+		whoami = &auth.WhoamiAuthenticator{UsersServiceHost: usersServiceHost}
+	}
+
+	jwksURL := os.Getenv("JWKS_URL")
+	if jwksURL == "" {
+		if whoami == nil {
+			log.Fatal("Either JWKS_URL or AUTH_WHOAMI_FALLBACK must be set")
+		}
+		return whoami
+	}
+
+	ttl, err := time.ParseDuration(os.Getenv("JWKS_CACHE_TTL"))
+	if err != nil {
+		log.Print("Invalid JWKS cache TTL: ", os.Getenv("JWKS_CACHE_TTL"))
+		ttl = jwks.DefaultTTL
+	}
+	return &auth.LocalAuthenticator{
+		Keys:           jwks.NewCache(jwksURL, ttl).Keyfunc,
+		Fallback:       whoami,
+		PersonalTokens: &auth.WhoamiAuthenticator{UsersServiceHost: usersServiceHost},
+	}
+}
+
 func main() {
 	db, err := common.SetupDB("./.env")
 	if err != nil {
@@ -61,12 +96,35 @@ func main() {
 	}
 
 	sm := http.NewServeMux()
-	apiCfg := server.ApiConfig{DB: db, UsersServiceHost: os.Getenv("USERS_SERVICE_HOST"), LibraryServiceHost: os.Getenv("LIBRARY_SERVICE_HOST"), BooksCacheCfg: getBooksCacheConfig()}
+	usersServiceHost := os.Getenv("USERS_SERVICE_HOST")
+	apiCfg := server.ApiConfig{DB: db, UsersServiceHost: usersServiceHost, Auth: getAuthenticator(usersServiceHost), LibraryServiceHost: os.Getenv("LIBRARY_SERVICE_HOST"), BooksCacheCfg: getBooksCacheConfig()}
 	server.Handle(sm, &apiCfg)
 
 	ticker := time.NewTicker(apiCfg.BooksCacheCfg.CleanupPeriod)
 	go clients.CleanupBooksCache(apiCfg.BooksCacheCfg.CleanupOldDataThreshold, &clients.TimeTicker{T: ticker})
 
+	if apiCfg.BooksCacheCfg.Enable {
+		// The cache is per process, so every instance reads all partitions of books in its own group
+		// and starts from new events, older ones are of no use to an empty cache.
+		booksKafkaReader := kafka.NewReader(kafka.ReaderConfig{
+			Brokers:     []string{"localhost:9092"},
+			GroupID:     "user-reading-books-cache-" + uuid.NewString(),
+			Topic:       "books",
+			StartOffset: kafka.LastOffset,
+		})
+		defer booksKafkaReader.Close()
+		go clients.ConsumeBooksMessages(context.Background(), booksKafkaReader)
+	}
+
+	// Reading data has no foreign key to users, it is deleted when users service reports a deleted user.
+	usersKafkaReader := kafka.NewReader(kafka.ReaderConfig{
+		Brokers: []string{"localhost:9092"},
+		GroupID: "user-reading-users",
+		Topic:   "users",
+	})
+	defer usersKafkaReader.Close()
+	go clients.ConsumeUsersMessages(context.Background(), usersKafkaReader, database.New(db))
+
 	s := http.Server{
 		Addr:    ":8080",
 		Handler: common.CORSMiddleware(common.LoggingMiddleware(sm)),
@@ -0,0 +1,4 @@
+-- name: AddShelfBook :exec
+INSERT INTO shelf_books (shelf_id, user_id, book_id)
+VALUES ($1, $2, $3)
+ON CONFLICT (shelf_id, book_id) DO NOTHING;
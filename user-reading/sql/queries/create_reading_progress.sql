@@ -0,0 +1,6 @@
+-- name: CreateReadingProgress :one
+INSERT INTO reading_progress (user_id, book_id, page, percent, minutes, note, recorded_at)
+VALUES (
+    $1, $2, $3, $4, $5, $6, $7
+)
+RETURNING id;
@@ -0,0 +1,5 @@
+-- name: CreateShelf :one
+INSERT INTO shelves (user_id, name, position)
+SELECT @user_id, @name, COALESCE(MAX(shelves.position) + 1, 0) FROM shelves
+WHERE shelves.user_id = @user_id
+RETURNING id, position;
@@ -0,0 +1,10 @@
+-- name: DeleteAllUserReading :execrows
+WITH deleted_shelves AS (
+    DELETE FROM shelves
+    WHERE shelves.user_id = @user_id
+), deleted_profile AS (
+    DELETE FROM user_profiles
+    WHERE user_profiles.user_id = @user_id
+)
+DELETE FROM user_reading
+WHERE user_reading.user_id = @user_id;
@@ -0,0 +1,3 @@
+-- name: DeleteReview :execrows
+DELETE FROM reviews
+WHERE user_id = $1 AND book_id = $2 AND cycle_id IS NULL;
@@ -0,0 +1,3 @@
+-- name: DeleteShelf :execrows
+DELETE FROM shelves
+WHERE id = $1 AND user_id = $2;
@@ -0,0 +1,3 @@
+-- name: DeleteShelfBook :execrows
+DELETE FROM shelf_books
+WHERE shelf_id = $1 AND user_id = $2 AND book_id = $3;
@@ -0,0 +1,3 @@
+-- name: FinishUserReading :execrows
+UPDATE user_reading SET status = 'finished', start_date = COALESCE(start_date, @finish_date), finish_date = @finish_date
+WHERE user_id = @user_id AND book_id = @book_id AND (start_date IS NULL OR start_date <= @finish_date);
@@ -0,0 +1,11 @@
+-- name: GetBookReviews :many
+SELECT reviews.id, reviews.user_id, user_profiles.display_name, COALESCE(reading_cycles.rating, user_reading.rating)::INTEGER AS rating,
+    reviews.body, reviews.spoiler, reviews.created_at, reviews.edited_at
+FROM reviews
+JOIN user_profiles ON user_profiles.user_id = reviews.user_id
+JOIN user_reading ON user_reading.user_id = reviews.user_id AND user_reading.book_id = reviews.book_id
+LEFT JOIN reading_cycles ON reading_cycles.id = reviews.cycle_id
+WHERE reviews.book_id = sqlc.arg('book_id') AND user_profiles.public
+    AND (sqlc.narg('cursor_id')::BIGINT IS NULL OR (reviews.created_at, reviews.id) < (sqlc.narg('cursor_created_at')::TIMESTAMP, sqlc.narg('cursor_id')::BIGINT))
+ORDER BY reviews.created_at DESC, reviews.id DESC
+LIMIT sqlc.arg('limit');
@@ -0,0 +1,4 @@
+-- name: GetReadingCycles :many
+SELECT id, rating, start_date, finish_date FROM reading_cycles
+WHERE user_id = $1 AND book_id = $2
+ORDER BY id;
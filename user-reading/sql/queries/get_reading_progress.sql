@@ -0,0 +1,4 @@
+-- name: GetReadingProgress :many
+SELECT id, page, percent, minutes, note, recorded_at FROM reading_progress
+WHERE user_id = $1 AND book_id = $2 AND cycle_id IS NULL
+ORDER BY recorded_at, id;
@@ -0,0 +1,3 @@
+-- name: GetReviews :many
+SELECT id, cycle_id, body, spoiler, created_at, edited_at FROM reviews
+WHERE user_id = $1 AND book_id = $2;
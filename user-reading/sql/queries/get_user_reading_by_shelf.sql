@@ -0,0 +1,5 @@
+-- name: GetUserReadingByShelf :many
+SELECT user_reading.book_id, user_reading.status, user_reading.rating, user_reading.start_date, user_reading.finish_date, user_reading.created_at
+FROM user_reading
+JOIN shelf_books ON shelf_books.user_id = user_reading.user_id AND shelf_books.book_id = user_reading.book_id
+WHERE user_reading.user_id = $1 AND shelf_books.shelf_id = $2;
@@ -0,0 +1,4 @@
+-- name: GetUserReadingCycles :many
+SELECT book_id, rating, start_date, finish_date FROM reading_cycles
+WHERE user_id = $1
+ORDER BY id;
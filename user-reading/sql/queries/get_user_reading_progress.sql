@@ -0,0 +1,4 @@
+-- name: GetUserReadingProgress :many
+SELECT id, book_id, cycle_id, page, percent, minutes, note, recorded_at FROM reading_progress
+WHERE user_id = $1
+ORDER BY recorded_at, id;
@@ -0,0 +1,13 @@
+-- name: GetUserReadingStats :one
+SELECT
+    COUNT(*) FILTER (WHERE status = 'want_to_read') AS want_to_read,
+    COUNT(*) FILTER (WHERE status = 'reading') AS reading,
+    COUNT(*) FILTER (WHERE status = 'finished') AS finished,
+    (SELECT COUNT(*) FROM reading_cycles WHERE reading_cycles.user_id = @user_id) AS previous_reads,
+    -- Archived cycles are finished, so a book is re-read when it has two of them or its current cycle is finished too.
+    COUNT(*) FILTER (WHERE (
+        SELECT COUNT(*) FROM reading_cycles
+        WHERE reading_cycles.user_id = user_reading.user_id AND reading_cycles.book_id = user_reading.book_id
+    ) + (status = 'finished')::INT >= 2) AS reread_books
+FROM user_reading
+WHERE user_reading.user_id = @user_id;
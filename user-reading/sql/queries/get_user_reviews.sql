@@ -0,0 +1,4 @@
+-- name: GetUserReviews :many
+SELECT id, book_id, cycle_id, body, spoiler, created_at, edited_at FROM reviews
+WHERE user_id = $1
+ORDER BY created_at, id;
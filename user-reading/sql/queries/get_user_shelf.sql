@@ -0,0 +1,3 @@
+-- name: GetUserShelf :one
+SELECT name, position FROM shelves
+WHERE id = $1 AND user_id = $2;
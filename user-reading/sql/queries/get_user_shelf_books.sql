@@ -0,0 +1,4 @@
+-- name: GetUserShelfBooks :many
+SELECT shelf_id, book_id FROM shelf_books
+WHERE user_id = $1
+ORDER BY shelf_id, added_at;
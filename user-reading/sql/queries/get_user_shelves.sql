@@ -0,0 +1,6 @@
+-- name: GetUserShelves :many
+SELECT shelves.id, shelves.name, shelves.position, COUNT(shelf_books.book_id) AS books_count FROM shelves
+LEFT JOIN shelf_books ON shelf_books.shelf_id = shelves.id
+WHERE shelves.user_id = $1
+GROUP BY shelves.id
+ORDER BY shelves.position, shelves.created_at;
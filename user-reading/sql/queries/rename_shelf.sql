@@ -0,0 +1,3 @@
+-- name: RenameShelf :execrows
+UPDATE shelves SET name = $3
+WHERE id = $1 AND user_id = $2;
@@ -0,0 +1,3 @@
+-- name: RestartUserReading :exec
+UPDATE user_reading SET status = 'reading', rating = 0, start_date = @start_date, finish_date = NULL
+WHERE user_id = @user_id AND book_id = @book_id;
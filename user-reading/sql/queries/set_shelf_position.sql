@@ -0,0 +1,3 @@
+-- name: SetShelfPosition :execrows
+UPDATE shelves SET position = $3
+WHERE id = $1 AND user_id = $2;
@@ -0,0 +1,3 @@
+-- name: StartUserReading :exec
+UPDATE user_reading SET status = 'reading', start_date = COALESCE(start_date, @start_date)
+WHERE user_id = @user_id AND book_id = @book_id;
@@ -0,0 +1,6 @@
+-- name: UpsertReview :one
+INSERT INTO reviews (user_id, book_id, body, spoiler)
+VALUES ($1, $2, $3, $4)
+ON CONFLICT (user_id, book_id) WHERE cycle_id IS NULL
+DO UPDATE SET body = EXCLUDED.body, spoiler = EXCLUDED.spoiler, edited_at = NOW()
+RETURNING id, created_at, edited_at;
@@ -0,0 +1,4 @@
+-- name: UpsertUserProfile :exec
+INSERT INTO user_profiles (user_id, display_name, public)
+VALUES ($1, $2, $3)
+ON CONFLICT (user_id) DO UPDATE SET display_name = EXCLUDED.display_name, public = EXCLUDED.public, updated_at = NOW();
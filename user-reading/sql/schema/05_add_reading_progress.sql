@@ -0,0 +1,21 @@
+-- +goose Up
+CREATE TABLE IF NOT EXISTS reading_progress(
+    id BIGSERIAL PRIMARY KEY,
+    user_id UUID NOT NULL,
+    book_id UUID NOT NULL,
+    page INTEGER CHECK (page >= 0),
+    percent DOUBLE PRECISION CHECK (percent >= 0 AND percent <= 100),
+    minutes INTEGER NOT NULL DEFAULT 0 CHECK (minutes >= 0),
+    note TEXT NOT NULL DEFAULT '',
+    recorded_at TIMESTAMP NOT NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    CHECK (page IS NOT NULL OR percent IS NOT NULL),
+    FOREIGN KEY (user_id, book_id) REFERENCES user_reading(user_id, book_id) ON DELETE CASCADE
+);
+
+CREATE INDEX idx_reading_progress_user_book ON reading_progress(user_id, book_id, recorded_at);
+
+-- +goose Down
+DROP INDEX IF EXISTS idx_reading_progress_user_book;
+
+DROP TABLE IF EXISTS reading_progress;
@@ -0,0 +1,18 @@
+-- +goose Up
+CREATE TABLE IF NOT EXISTS reading_cycles(
+    id BIGSERIAL PRIMARY KEY,
+    user_id UUID NOT NULL,
+    book_id UUID NOT NULL,
+    rating INTEGER NOT NULL DEFAULT 0,
+    start_date TIMESTAMP,
+    finish_date TIMESTAMP,
+    created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    FOREIGN KEY (user_id, book_id) REFERENCES user_reading(user_id, book_id) ON DELETE CASCADE
+);
+
+CREATE INDEX idx_reading_cycles_user_book ON reading_cycles(user_id, book_id);
+
+-- +goose Down
+DROP INDEX IF EXISTS idx_reading_cycles_user_book;
+
+DROP TABLE IF EXISTS reading_cycles;
@@ -0,0 +1,27 @@
+-- +goose Up
+CREATE TABLE IF NOT EXISTS shelves(
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    user_id UUID NOT NULL,
+    name TEXT NOT NULL,
+    position INTEGER NOT NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    UNIQUE (user_id, name)
+);
+
+CREATE TABLE IF NOT EXISTS shelf_books(
+    shelf_id UUID NOT NULL REFERENCES shelves(id) ON DELETE CASCADE,
+    user_id UUID NOT NULL,
+    book_id UUID NOT NULL,
+    added_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    PRIMARY KEY (shelf_id, book_id),
+    FOREIGN KEY (user_id, book_id) REFERENCES user_reading(user_id, book_id) ON DELETE CASCADE
+);
+
+CREATE INDEX idx_shelf_books_user_book ON shelf_books(user_id, book_id);
+
+-- +goose Down
+DROP INDEX IF EXISTS idx_shelf_books_user_book;
+
+DROP TABLE IF EXISTS shelf_books;
+
+DROP TABLE IF EXISTS shelves;
@@ -0,0 +1,33 @@
+-- +goose Up
+CREATE TABLE IF NOT EXISTS reviews(
+    id BIGSERIAL PRIMARY KEY,
+    user_id UUID NOT NULL,
+    book_id UUID NOT NULL,
+    cycle_id BIGINT UNIQUE REFERENCES reading_cycles(id) ON DELETE CASCADE,
+    body TEXT NOT NULL,
+    spoiler BOOLEAN NOT NULL DEFAULT FALSE,
+    created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    edited_at TIMESTAMP,
+    FOREIGN KEY (user_id, book_id) REFERENCES user_reading(user_id, book_id) ON DELETE CASCADE
+);
+
+-- cycle_id is NULL for the review of the current cycle in user_reading.
+CREATE UNIQUE INDEX idx_reviews_current_cycle ON reviews(user_id, book_id) WHERE cycle_id IS NULL;
+
+CREATE INDEX idx_reviews_book ON reviews(book_id, created_at);
+
+CREATE TABLE IF NOT EXISTS user_profiles(
+    user_id UUID PRIMARY KEY,
+    display_name TEXT NOT NULL,
+    public BOOLEAN NOT NULL,
+    updated_at TIMESTAMP NOT NULL DEFAULT NOW()
+);
+
+-- +goose Down
+DROP TABLE IF EXISTS user_profiles;
+
+DROP INDEX IF EXISTS idx_reviews_book;
+
+DROP INDEX IF EXISTS idx_reviews_current_cycle;
+
+DROP TABLE IF EXISTS reviews;
@@ -0,0 +1,22 @@
+-- +goose Up
+ALTER TABLE reading_progress ADD COLUMN cycle_id BIGINT REFERENCES reading_cycles(id) ON DELETE CASCADE;
+
+-- cycle_id is NULL for progress of the current cycle in user_reading, as for reviews.
+-- Updates recorded before the current cycle started belong to the first archived cycle that finished after them.
+UPDATE reading_progress SET cycle_id = (
+    SELECT reading_cycles.id FROM reading_cycles
+    WHERE reading_cycles.user_id = reading_progress.user_id AND reading_cycles.book_id = reading_progress.book_id
+        AND reading_cycles.finish_date >= date_trunc('day', reading_progress.recorded_at)
+    ORDER BY reading_cycles.finish_date, reading_cycles.id
+    LIMIT 1
+)
+FROM user_reading
+WHERE user_reading.user_id = reading_progress.user_id AND user_reading.book_id = reading_progress.book_id
+    AND (user_reading.start_date IS NULL OR date_trunc('day', reading_progress.recorded_at) < user_reading.start_date);
+
+CREATE INDEX idx_reading_progress_cycle ON reading_progress(cycle_id);
+
+-- +goose Down
+DROP INDEX IF EXISTS idx_reading_progress_cycle;
+
+ALTER TABLE reading_progress DROP COLUMN IF EXISTS cycle_id;
@@ -0,0 +1,96 @@
+package test
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"testing"
+
+	common "github.com/bakurvik/mylib-common"
+	"github.com/bakurvik/mylib/user-reading/internal/clients"
+	"github.com/bakurvik/mylib/user-reading/internal/server"
+	"github.com/google/uuid"
+	_ "github.com/lib/pq"
+	"github.com/stretchr/testify/assert"
+)
+
+func putUserReading(t *testing.T, url string, usersData usersServiceData, userReading server.UserReading) {
+	body, _ := json.Marshal(userReading)
+	request, err := http.NewRequest(http.MethodPut, url, bytes.NewBuffer(body))
+	assert.NoError(t, err)
+	request.Header.Add(usersData.authHeader, usersData.authToken)
+	response, err := http.DefaultClient.Do(request)
+	assert.NoError(t, err)
+	defer common.CloseResponseBody(response)
+	assert.Equal(t, response.StatusCode, http.StatusNoContent)
+}
+
+func getJSON(t *testing.T, url string, usersData usersServiceData, data any) {
+	request, err := http.NewRequest(http.MethodGet, url, nil)
+	assert.NoError(t, err)
+	request.Header.Add(usersData.authHeader, usersData.authToken)
+	response, err := http.DefaultClient.Do(request)
+	assert.NoError(t, err)
+	defer common.CloseResponseBody(response)
+	assert.Equal(t, response.StatusCode, http.StatusOK)
+	err = json.NewDecoder(response.Body).Decode(data)
+	assert.NoError(t, err)
+}
+
+func TestRereadUserReading(t *testing.T) {
+	userID := uuid.New()
+	bookID := uuid.New()
+	anotherBookID := uuid.New()
+	usersData := usersServiceData{userID: userID, authHeader: "Authorization", authToken: "Bearer access_token", statusCode: http.StatusOK}
+	libraryData := libraryServiceData{bookID: bookID.String(), statusCode: http.StatusOK, booksInfo: []clients.ResponseBookFullInfo{{ID: bookID.String(), Title: "Title 1", Authors: []string{"Author 1"}}}}
+
+	db, err := common.SetupDBByURL("../.env", "TEST_DB_URL")
+	assert.NoError(t, err)
+	defer common.CloseDB(db)
+	cleanupDB(db)
+	addDBUserReading(db, userID.String(), []server.UserReading{
+		{BookID: bookID.String(), Status: "finished", Rating: 4, StartDate: "04.02.2003", FinishDate: "19.05.2003"},
+		{BookID: anotherBookID.String(), Status: "want_to_read"},
+	})
+
+	s, usersServer, libraryServer := setupTestServers(t, db, usersData, libraryData)
+	defer s.Close()
+	defer usersServer.Close()
+	defer libraryServer.Close()
+
+	_, err = db.Exec("INSERT INTO reading_progress(user_id, book_id, page, recorded_at) VALUES ($1, $2, 100, '2003-05-01')", userID, bookID)
+	assert.NoError(t, err)
+
+	// Reading the finished book again starts a new cycle, updates of the current cycle don't.
+	putUserReading(t, s.URL+server.ApiUserReadingPath, usersData, server.UserReading{BookID: bookID.String(), Status: "reading", StartDate: "01.09.2026"})
+	stats := server.ResponseUserReadingStats{}
+	getJSON(t, s.URL+server.ApiUserReadingStatsPath, usersData, &stats)
+	assert.Equal(t, stats, server.ResponseUserReadingStats{WantToRead: 1, Reading: 1, Reads: 1, RereadBooks: 0})
+
+	// Progress of the finished cycle is archived with it.
+	response := postReadingProgress(t, fmt.Sprintf("%s%s/%s/progress", s.URL, server.ApiUserReadingPath, bookID), usersData, `{"page": 10}`)
+	common.CloseResponseBody(response)
+	assert.Equal(t, response.StatusCode, http.StatusCreated)
+	progress := []server.ResponseReadingProgress{}
+	getJSON(t, fmt.Sprintf("%s%s/%s/progress", s.URL, server.ApiUserReadingPath, bookID), usersData, &progress)
+	assert.Equal(t, len(progress), 1)
+	assert.Equal(t, *progress[0].Page, 10)
+
+	putUserReading(t, s.URL+server.ApiUserReadingPath, usersData, server.UserReading{BookID: bookID.String(), Status: "finished", Rating: 5, StartDate: "01.09.2026", FinishDate: "01.10.2026"})
+	assert.Equal(t, getDBUserReading(t, db, userID), []server.UserReading{
+		{BookID: bookID.String(), Status: "finished", Rating: 5, StartDate: "01.09.2026", FinishDate: "01.10.2026"},
+		{BookID: anotherBookID.String(), Status: "want_to_read"},
+	})
+
+	userReading := server.ResponseUserReadingFullInfo{}
+	getJSON(t, fmt.Sprintf("%s%s/%s", s.URL, server.ApiUserReadingPath, bookID), usersData, &userReading)
+	assert.Equal(t, userReading.Cycles, []server.ResponseReadingCycle{
+		{Rating: 4, StartDate: "04.02.2003", FinishDate: "19.05.2003"},
+		{Rating: 5, StartDate: "01.09.2026", FinishDate: "01.10.2026", Current: true},
+	})
+
+	stats = server.ResponseUserReadingStats{}
+	getJSON(t, s.URL+server.ApiUserReadingStatsPath, usersData, &stats)
+	assert.Equal(t, stats, server.ResponseUserReadingStats{WantToRead: 1, Finished: 1, Reads: 2, RereadBooks: 1})
+}
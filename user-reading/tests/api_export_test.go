@@ -0,0 +1,124 @@
+package test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	common "github.com/bakurvik/mylib-common"
+	"github.com/bakurvik/mylib/user-reading/internal/database"
+	"github.com/bakurvik/mylib/user-reading/internal/server"
+	"github.com/google/uuid"
+	_ "github.com/lib/pq"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestExportUserReading(t *testing.T) {
+	userID := uuid.New()
+	bookID := uuid.New()
+	anotherBookID := uuid.New()
+
+	type testCase struct {
+		name               string
+		usersData          usersServiceData
+		dbUserReadings     []server.UserReading
+		expectedStatusCode int
+		expectedBooks      []server.ExportUserReading
+	}
+	tests := []testCase{
+		{
+			name:      "success",
+			usersData: usersServiceData{userID: userID, authHeader: "Authorization", authToken: "Bearer access_token", statusCode: http.StatusOK},
+			dbUserReadings: []server.UserReading{
+				{BookID: bookID.String(), Status: "finished", Rating: 5, StartDate: "04.02.2003", FinishDate: "19.05.2003"},
+				{BookID: anotherBookID.String(), Status: "want_to_read"},
+			},
+			expectedStatusCode: http.StatusOK,
+			expectedBooks: []server.ExportUserReading{
+				{BookID: bookID.String(), Status: "finished", Rating: 5, StartDate: "04.02.2003", FinishDate: "19.05.2003"},
+				{BookID: anotherBookID.String(), Status: "want_to_read"},
+			},
+		},
+		{
+			name:               "empty",
+			usersData:          usersServiceData{userID: userID, authHeader: "Authorization", authToken: "Bearer access_token", statusCode: http.StatusOK},
+			dbUserReadings:     []server.UserReading{},
+			expectedStatusCode: http.StatusOK,
+			expectedBooks:      []server.ExportUserReading{},
+		},
+		{
+			name:               "unauthorized",
+			usersData:          usersServiceData{userID: userID, authHeader: "Authorization", authToken: "Bearer access_token", statusCode: http.StatusUnauthorized},
+			dbUserReadings:     []server.UserReading{},
+			expectedStatusCode: http.StatusUnauthorized,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			db, err := common.SetupDBByURL("../.env", "TEST_DB_URL")
+			assert.NoError(t, err)
+			defer common.CloseDB(db)
+			cleanupDB(db)
+
+			addDBUserReading(db, userID.String(), tc.dbUserReadings)
+
+			s, usersServer, libraryServer := setupTestServers(t, db, tc.usersData, libraryServiceData{})
+			defer s.Close()
+			defer usersServer.Close()
+			defer libraryServer.Close()
+
+			client := &http.Client{}
+			request, err := http.NewRequest(http.MethodGet, s.URL+server.ApiUserReadingExportPath, nil)
+			assert.NoError(t, err)
+			request.Header.Add(tc.usersData.authHeader, tc.usersData.authToken)
+
+			response, err := client.Do(request)
+			assert.NoError(t, err)
+			defer common.CloseResponseBody(response)
+			assert.Equal(t, tc.expectedStatusCode, response.StatusCode)
+
+			if tc.expectedStatusCode == http.StatusOK {
+				responseBody := server.ResponseUserReadingExport{}
+				err = json.NewDecoder(response.Body).Decode(&responseBody)
+				assert.NoError(t, err)
+				for i := range responseBody.Books {
+					assert.False(t, responseBody.Books[i].AddedAt.IsZero())
+					responseBody.Books[i].AddedAt = tc.expectedBooks[i].AddedAt
+				}
+				assert.Equal(t, responseBody.Books, tc.expectedBooks)
+			}
+		})
+	}
+}
+
+func TestDeleteAllUserReading(t *testing.T) {
+	db, err := common.SetupDBByURL("../.env", "TEST_DB_URL")
+	assert.NoError(t, err)
+	defer common.CloseDB(db)
+	cleanupDB(db)
+
+	userID := uuid.New()
+	anotherUserID := uuid.New()
+	userReadings := []server.UserReading{{BookID: uuid.NewString(), Status: "reading"}, {BookID: uuid.NewString(), Status: "want_to_read"}}
+	addDBUserReading(db, userID.String(), userReadings)
+	addDBUserReading(db, anotherUserID.String(), userReadings)
+	queries := database.New(db)
+	for _, id := range []uuid.UUID{userID, anotherUserID} {
+		_, err = queries.CreateShelf(context.Background(), database.CreateShelfParams{UserID: id, Name: "Favourites"})
+		assert.NoError(t, err)
+	}
+
+	count, err := queries.DeleteAllUserReading(context.Background(), userID)
+	assert.NoError(t, err)
+	assert.Equal(t, count, int64(2))
+	assert.Empty(t, getDBUserReading(t, db, userID))
+	assert.Equal(t, len(getDBUserReading(t, db, anotherUserID)), 2)
+	shelves, err := queries.GetUserShelves(context.Background(), userID)
+	assert.NoError(t, err)
+	assert.Empty(t, shelves)
+	shelves, err = queries.GetUserShelves(context.Background(), anotherUserID)
+	assert.NoError(t, err)
+	assert.Equal(t, len(shelves), 1)
+}
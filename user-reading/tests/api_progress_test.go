@@ -0,0 +1,219 @@
+package test
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"testing"
+	"time"
+
+	common "github.com/bakurvik/mylib-common"
+	"github.com/bakurvik/mylib/user-reading/internal/server"
+	"github.com/google/uuid"
+	_ "github.com/lib/pq"
+	"github.com/stretchr/testify/assert"
+)
+
+func postReadingProgress(t *testing.T, url string, usersData usersServiceData, body string) *http.Response {
+	request, err := http.NewRequest(http.MethodPost, url, bytes.NewBufferString(body))
+	assert.NoError(t, err)
+	request.Header.Add(usersData.authHeader, usersData.authToken)
+	response, err := http.DefaultClient.Do(request)
+	assert.NoError(t, err)
+	return response
+}
+
+func TestAddReadingProgress(t *testing.T) {
+	userID := uuid.New()
+	bookID := uuid.New()
+	today := time.Now().UTC().Format("02.01.2006")
+	usersData := usersServiceData{userID: userID, authHeader: "Authorization", authToken: "Bearer access_token", statusCode: http.StatusOK}
+
+	type testCase struct {
+		name                string
+		usersData           usersServiceData
+		dbUserReadings      []server.UserReading
+		body                string
+		expectedStatusCode  int
+		expectedStatus      string
+		expectedSuggest     bool
+		expectedUserReading []server.UserReading
+		expectedCycles      int
+	}
+	tests := []testCase{
+		{
+			name:                "want_to_read_starts_reading",
+			usersData:           usersData,
+			dbUserReadings:      []server.UserReading{{BookID: bookID.String(), Status: "want_to_read"}},
+			body:                `{"page": 10, "minutes": 20}`,
+			expectedStatusCode:  http.StatusCreated,
+			expectedStatus:      "reading",
+			expectedUserReading: []server.UserReading{{BookID: bookID.String(), Status: "reading", StartDate: today}},
+		},
+		{
+			name:                "reading_keeps_start_date",
+			usersData:           usersData,
+			dbUserReadings:      []server.UserReading{{BookID: bookID.String(), Status: "reading", StartDate: "04.02.2003"}},
+			body:                `{"percent": 50}`,
+			expectedStatusCode:  http.StatusCreated,
+			expectedStatus:      "reading",
+			expectedUserReading: []server.UserReading{{BookID: bookID.String(), Status: "reading", StartDate: "04.02.2003"}},
+		},
+		{
+			name:                "complete_suggests_finish",
+			usersData:           usersData,
+			dbUserReadings:      []server.UserReading{{BookID: bookID.String(), Status: "reading", StartDate: "04.02.2003"}},
+			body:                `{"percent": 100}`,
+			expectedStatusCode:  http.StatusCreated,
+			expectedStatus:      "reading",
+			expectedSuggest:     true,
+			expectedUserReading: []server.UserReading{{BookID: bookID.String(), Status: "reading", StartDate: "04.02.2003"}},
+		},
+		{
+			name:                "complete_with_finish",
+			usersData:           usersData,
+			dbUserReadings:      []server.UserReading{{BookID: bookID.String(), Status: "reading", StartDate: "04.02.2003"}},
+			body:                `{"percent": 100, "finish": true, "recorded_at": "2003-05-19T20:00:00Z"}`,
+			expectedStatusCode:  http.StatusCreated,
+			expectedStatus:      "finished",
+			expectedUserReading: []server.UserReading{{BookID: bookID.String(), Status: "finished", StartDate: "04.02.2003", FinishDate: "19.05.2003"}},
+		},
+		{
+			name:                "finish_before_start_date",
+			usersData:           usersData,
+			dbUserReadings:      []server.UserReading{{BookID: bookID.String(), Status: "reading", StartDate: "04.02.2003"}},
+			body:                `{"percent": 100, "finish": true, "recorded_at": "2003-01-19T20:00:00Z"}`,
+			expectedStatusCode:  http.StatusBadRequest,
+			expectedUserReading: []server.UserReading{{BookID: bookID.String(), Status: "reading", StartDate: "04.02.2003"}},
+		},
+		{
+			name:                "finished_starts_rereading",
+			usersData:           usersData,
+			dbUserReadings:      []server.UserReading{{BookID: bookID.String(), Status: "finished", Rating: 5, StartDate: "04.02.2003", FinishDate: "19.05.2003"}},
+			body:                `{"page": 10}`,
+			expectedStatusCode:  http.StatusCreated,
+			expectedStatus:      "reading",
+			expectedUserReading: []server.UserReading{{BookID: bookID.String(), Status: "reading", StartDate: today}},
+			expectedCycles:      1,
+		},
+		{
+			name:                "finished_before_finish_date",
+			usersData:           usersData,
+			dbUserReadings:      []server.UserReading{{BookID: bookID.String(), Status: "finished", StartDate: "04.02.2003", FinishDate: "19.05.2003"}},
+			body:                `{"page": 10, "recorded_at": "2003-05-01T20:00:00Z"}`,
+			expectedStatusCode:  http.StatusBadRequest,
+			expectedUserReading: []server.UserReading{{BookID: bookID.String(), Status: "finished", StartDate: "04.02.2003", FinishDate: "19.05.2003"}},
+		},
+		{
+			name:               "unknown_user_book",
+			usersData:          usersData,
+			dbUserReadings:     []server.UserReading{},
+			body:               `{"page": 10}`,
+			expectedStatusCode: http.StatusNotFound,
+		},
+		{
+			name:                "invalid_progress",
+			usersData:           usersData,
+			dbUserReadings:      []server.UserReading{{BookID: bookID.String(), Status: "want_to_read"}},
+			body:                `{"percent": 120}`,
+			expectedStatusCode:  http.StatusBadRequest,
+			expectedUserReading: []server.UserReading{{BookID: bookID.String(), Status: "want_to_read"}},
+		},
+		{
+			name:                "unauthorized",
+			usersData:           usersServiceData{userID: userID, authHeader: "Authorization", authToken: "Bearer access_token", statusCode: http.StatusUnauthorized},
+			dbUserReadings:      []server.UserReading{{BookID: bookID.String(), Status: "want_to_read"}},
+			body:                `{"page": 10}`,
+			expectedStatusCode:  http.StatusUnauthorized,
+			expectedUserReading: []server.UserReading{{BookID: bookID.String(), Status: "want_to_read"}},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			db, err := common.SetupDBByURL("../.env", "TEST_DB_URL")
+			assert.NoError(t, err)
+			defer common.CloseDB(db)
+			cleanupDB(db)
+
+			addDBUserReading(db, userID.String(), tc.dbUserReadings)
+
+			s, usersServer, libraryServer := setupTestServers(t, db, tc.usersData, libraryServiceData{})
+			defer s.Close()
+			defer usersServer.Close()
+			defer libraryServer.Close()
+
+			response := postReadingProgress(t, fmt.Sprintf("%s%s/%s/progress", s.URL, server.ApiUserReadingPath, bookID), tc.usersData, tc.body)
+			defer common.CloseResponseBody(response)
+			assert.Equal(t, tc.expectedStatusCode, response.StatusCode)
+
+			if tc.expectedStatusCode == http.StatusCreated {
+				responseBody := server.ResponseReadingProgressUpdate{}
+				err = json.NewDecoder(response.Body).Decode(&responseBody)
+				assert.NoError(t, err)
+				assert.Equal(t, responseBody.Status, tc.expectedStatus)
+				assert.Equal(t, responseBody.SuggestFinish, tc.expectedSuggest)
+			}
+			if tc.expectedUserReading != nil {
+				assert.Equal(t, getDBUserReading(t, db, userID), tc.expectedUserReading)
+			}
+			cycles := 0
+			err = db.QueryRow("SELECT COUNT(*) FROM reading_cycles").Scan(&cycles)
+			assert.NoError(t, err)
+			assert.Equal(t, cycles, tc.expectedCycles)
+		})
+	}
+}
+
+func TestGetReadingProgress(t *testing.T) {
+	userID := uuid.New()
+	bookID := uuid.New()
+	usersData := usersServiceData{userID: userID, authHeader: "Authorization", authToken: "Bearer access_token", statusCode: http.StatusOK}
+
+	db, err := common.SetupDBByURL("../.env", "TEST_DB_URL")
+	assert.NoError(t, err)
+	defer common.CloseDB(db)
+	cleanupDB(db)
+	addDBUserReading(db, userID.String(), []server.UserReading{{BookID: bookID.String(), Status: "reading"}})
+
+	s, usersServer, libraryServer := setupTestServers(t, db, usersData, libraryServiceData{})
+	defer s.Close()
+	defer usersServer.Close()
+	defer libraryServer.Close()
+
+	url := fmt.Sprintf("%s%s/%s/progress", s.URL, server.ApiUserReadingPath, bookID)
+	for _, body := range []string{
+		`{"percent": 40, "recorded_at": "2026-10-02T10:00:00Z"}`,
+		`{"page": 10, "note": "Prologue", "recorded_at": "2026-10-01T10:00:00Z"}`,
+	} {
+		response := postReadingProgress(t, url, usersData, body)
+		assert.Equal(t, response.StatusCode, http.StatusCreated)
+		common.CloseResponseBody(response)
+	}
+
+	request, err := http.NewRequest(http.MethodGet, url, nil)
+	assert.NoError(t, err)
+	request.Header.Add(usersData.authHeader, usersData.authToken)
+	response, err := http.DefaultClient.Do(request)
+	assert.NoError(t, err)
+	defer common.CloseResponseBody(response)
+	assert.Equal(t, response.StatusCode, http.StatusOK)
+
+	progress := []server.ResponseReadingProgress{}
+	err = json.NewDecoder(response.Body).Decode(&progress)
+	assert.NoError(t, err)
+	assert.Equal(t, len(progress), 2)
+	if len(progress) == 2 {
+		assert.Equal(t, *progress[0].Page, 10)
+		assert.Equal(t, progress[0].Note, "Prologue")
+		assert.Nil(t, progress[0].Percent)
+		assert.Equal(t, *progress[1].Percent, 40.0)
+		assert.True(t, progress[0].RecordedAt.Before(progress[1].RecordedAt))
+	}
+
+	response, err = http.Get(fmt.Sprintf("%s%s/%s/progress", s.URL, server.ApiUserReadingPath, uuid.New()))
+	assert.NoError(t, err)
+	defer common.CloseResponseBody(response)
+	assert.Equal(t, response.StatusCode, http.StatusUnauthorized)
+}
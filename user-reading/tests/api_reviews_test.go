@@ -0,0 +1,136 @@
+package test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"testing"
+
+	common "github.com/bakurvik/mylib-common"
+	"github.com/bakurvik/mylib/user-reading/internal/clients"
+	"github.com/bakurvik/mylib/user-reading/internal/database"
+	"github.com/bakurvik/mylib/user-reading/internal/server"
+	"github.com/google/uuid"
+	_ "github.com/lib/pq"
+	"github.com/stretchr/testify/assert"
+)
+
+func putReview(t *testing.T, url string, usersData usersServiceData, review server.RequestReview, expectedStatusCode int) server.ResponseReview {
+	body, _ := json.Marshal(review)
+	request, err := http.NewRequest(http.MethodPut, url, bytes.NewBuffer(body))
+	assert.NoError(t, err)
+	request.Header.Add(usersData.authHeader, usersData.authToken)
+	response, err := http.DefaultClient.Do(request)
+	assert.NoError(t, err)
+	defer common.CloseResponseBody(response)
+	assert.Equal(t, response.StatusCode, expectedStatusCode)
+	responseReview := server.ResponseReview{}
+	if response.StatusCode == http.StatusOK || response.StatusCode == http.StatusCreated {
+		err = json.NewDecoder(response.Body).Decode(&responseReview)
+		assert.NoError(t, err)
+	}
+	return responseReview
+}
+
+func getReviewBodies(page server.ResponseBookReviewsPage) []string {
+	res := make([]string, 0, len(page.Items))
+	for _, review := range page.Items {
+		res = append(res, review.Body)
+	}
+	return res
+}
+
+func TestReviews(t *testing.T) {
+	userID := uuid.New()
+	anotherUserID := uuid.New()
+	privateUserID := uuid.New()
+	bookID := uuid.New()
+	usersData := usersServiceData{userID: userID, authHeader: "Authorization", authToken: "Bearer access_token", statusCode: http.StatusOK}
+	libraryData := libraryServiceData{bookID: bookID.String(), statusCode: http.StatusOK, booksInfo: []clients.ResponseBookFullInfo{{ID: bookID.String(), Title: "Title 1", Authors: []string{"Author 1"}}}}
+
+	db, err := common.SetupDBByURL("../.env", "TEST_DB_URL")
+	assert.NoError(t, err)
+	defer common.CloseDB(db)
+	cleanupDB(db)
+	addDBUserReading(db, userID.String(), []server.UserReading{{BookID: bookID.String(), Status: "finished", Rating: 4, StartDate: "04.02.2003", FinishDate: "19.05.2003"}})
+	addDBUserReading(db, anotherUserID.String(), []server.UserReading{{BookID: bookID.String(), Status: "finished", Rating: 2}})
+	addDBUserReading(db, privateUserID.String(), []server.UserReading{{BookID: bookID.String(), Status: "finished", Rating: 5}})
+	queries := database.New(db)
+	for _, profile := range []database.UpsertUserProfileParams{
+		{UserID: userID, DisplayName: "Reader", Public: true},
+		{UserID: anotherUserID, DisplayName: "Another reader", Public: true},
+		{UserID: privateUserID, DisplayName: "Private reader", Public: false},
+	} {
+		assert.NoError(t, queries.UpsertUserProfile(context.Background(), profile))
+	}
+	_, err = queries.UpsertReview(context.Background(), database.UpsertReviewParams{UserID: privateUserID, BookID: bookID, Body: "Private review"})
+	assert.NoError(t, err)
+	_, err = queries.UpsertReview(context.Background(), database.UpsertReviewParams{UserID: anotherUserID, BookID: bookID, Body: "Another review", Spoiler: true})
+	assert.NoError(t, err)
+
+	s, usersServer, libraryServer := setupTestServers(t, db, usersData, libraryData)
+	defer s.Close()
+	defer usersServer.Close()
+	defer libraryServer.Close()
+	reviewsURL := s.URL + server.ApiUserReadingReviewsPath
+	bookReviewsURL := fmt.Sprintf("%s%s/%s/reviews", s.URL, server.ApiBooksPath, bookID)
+
+	// One review per reading cycle: the second request edits it.
+	putReview(t, reviewsURL, usersData, server.RequestReview{BookID: uuid.NewString(), Body: "Review"}, http.StatusNotFound)
+	putReview(t, reviewsURL, usersData, server.RequestReview{BookID: bookID.String(), Body: ""}, http.StatusBadRequest)
+	created := putReview(t, reviewsURL, usersData, server.RequestReview{BookID: bookID.String(), Body: "First read"}, http.StatusCreated)
+	assert.Nil(t, created.EditedAt)
+	edited := putReview(t, reviewsURL, usersData, server.RequestReview{BookID: bookID.String(), Body: "First read, edited"}, http.StatusOK)
+	assert.Equal(t, edited.ID, created.ID)
+	assert.NotNil(t, edited.EditedAt)
+
+	// Reading the book again keeps the review with the finished cycle, the new cycle can be reviewed when finished.
+	putUserReading(t, s.URL+server.ApiUserReadingPath, usersData, server.UserReading{BookID: bookID.String(), Status: "reading", StartDate: "01.09.2026"})
+	putReview(t, reviewsURL, usersData, server.RequestReview{BookID: bookID.String(), Body: "Second read"}, http.StatusBadRequest)
+	putUserReading(t, s.URL+server.ApiUserReadingPath, usersData, server.UserReading{BookID: bookID.String(), Status: "finished", Rating: 5, StartDate: "01.09.2026", FinishDate: "01.10.2026"})
+	putReview(t, reviewsURL, usersData, server.RequestReview{BookID: bookID.String(), Body: "Second read"}, http.StatusCreated)
+
+	userReading := server.ResponseUserReadingFullInfo{}
+	getJSON(t, fmt.Sprintf("%s%s/%s", s.URL, server.ApiUserReadingPath, bookID), usersData, &userReading)
+	assert.Equal(t, len(userReading.Cycles), 2)
+	assert.Equal(t, userReading.Cycles[0].Review.Body, "First read, edited")
+	assert.Equal(t, userReading.Cycles[1].Review.Body, "Second read")
+
+	// Reviews of private profiles are not shown, the rating is the rating of the reviewed cycle.
+	page := server.ResponseBookReviewsPage{}
+	getJSON(t, bookReviewsURL, usersData, &page)
+	assert.Equal(t, getReviewBodies(page), []string{"Second read", "First read, edited", "Another review"})
+	assert.Equal(t, page.NextCursor, "")
+	assert.Equal(t, page.Items[0].DisplayName, "Reader")
+	assert.Equal(t, page.Items[0].Rating, 5)
+	assert.Equal(t, page.Items[1].Rating, 4)
+	assert.Equal(t, page.Items[2].UserID, anotherUserID.String())
+	assert.True(t, page.Items[2].Spoiler)
+
+	// Pagination, the endpoint doesn't require authorization.
+	response, err := http.Get(bookReviewsURL + "?limit=2")
+	assert.NoError(t, err)
+	defer common.CloseResponseBody(response)
+	assert.Equal(t, response.StatusCode, http.StatusOK)
+	err = json.NewDecoder(response.Body).Decode(&page)
+	assert.NoError(t, err)
+	assert.Equal(t, getReviewBodies(page), []string{"Second read", "First read, edited"})
+	assert.NotEqual(t, page.NextCursor, "")
+	getJSON(t, bookReviewsURL+"?limit=2&cursor="+page.NextCursor, usersData, &page)
+	assert.Equal(t, getReviewBodies(page), []string{"Another review"})
+	assert.Equal(t, page.NextCursor, "")
+	assert.Equal(t, sendRequest(t, http.MethodGet, bookReviewsURL+"?cursor=invalid", usersData, ""), http.StatusBadRequest)
+
+	// Making the profile private hides the reviews.
+	assert.NoError(t, queries.UpsertUserProfile(context.Background(), database.UpsertUserProfileParams{UserID: anotherUserID, DisplayName: "Another reader"}))
+	getJSON(t, bookReviewsURL, usersData, &page)
+	assert.Equal(t, getReviewBodies(page), []string{"Second read", "First read, edited"})
+
+	// Only the review of the current cycle can be deleted.
+	assert.Equal(t, sendRequest(t, http.MethodDelete, fmt.Sprintf("%s/%s", reviewsURL, bookID), usersData, ""), http.StatusNoContent)
+	assert.Equal(t, sendRequest(t, http.MethodDelete, fmt.Sprintf("%s/%s", reviewsURL, bookID), usersData, ""), http.StatusNotFound)
+	getJSON(t, bookReviewsURL, usersData, &page)
+	assert.Equal(t, getReviewBodies(page), []string{"First read, edited"})
+}
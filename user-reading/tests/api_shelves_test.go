@@ -0,0 +1,141 @@
+package test
+
+import (
+	"bytes"
+	"fmt"
+	"net/http"
+	"testing"
+
+	common "github.com/bakurvik/mylib-common"
+	"github.com/bakurvik/mylib/user-reading/internal/clients"
+	"github.com/bakurvik/mylib/user-reading/internal/server"
+	"github.com/google/uuid"
+	_ "github.com/lib/pq"
+	"github.com/stretchr/testify/assert"
+)
+
+func sendRequest(t *testing.T, method string, url string, usersData usersServiceData, body string) int {
+	request, err := http.NewRequest(method, url, bytes.NewBufferString(body))
+	assert.NoError(t, err)
+	request.Header.Add(usersData.authHeader, usersData.authToken)
+	response, err := http.DefaultClient.Do(request)
+	assert.NoError(t, err)
+	defer common.CloseResponseBody(response)
+	return response.StatusCode
+}
+
+func getShelfIDs(shelves []server.ResponseShelf) []string {
+	res := make([]string, 0, len(shelves))
+	for _, shelf := range shelves {
+		res = append(res, shelf.ID)
+	}
+	return res
+}
+
+func getUserReadingIDs(userReading []server.ResponseUserReading) []string {
+	res := make([]string, 0, len(userReading))
+	for _, book := range userReading {
+		res = append(res, book.ID)
+	}
+	return res
+}
+
+func TestShelves(t *testing.T) {
+	userID := uuid.New()
+	book1 := uuid.New()
+	book2 := uuid.New()
+	book3 := uuid.New()
+	usersData := usersServiceData{userID: userID, authHeader: "Authorization", authToken: "Bearer access_token", statusCode: http.StatusOK}
+	libraryData := libraryServiceData{statusCode: http.StatusOK, booksInfo: []clients.ResponseBookFullInfo{
+		{ID: book1.String(), Title: "Title 1", Authors: []string{"Author 1"}},
+		{ID: book2.String(), Title: "Title 2", Authors: []string{"Author 2"}},
+		{ID: book3.String(), Title: "Title 3", Authors: []string{"Author 3"}},
+	}}
+
+	db, err := common.SetupDBByURL("../.env", "TEST_DB_URL")
+	assert.NoError(t, err)
+	defer common.CloseDB(db)
+	cleanupDB(db)
+	addDBUserReading(db, userID.String(), []server.UserReading{
+		{BookID: book1.String(), Status: "finished", Rating: 4, StartDate: "04.02.2003", FinishDate: "19.05.2003"},
+		{BookID: book2.String(), Status: "reading", StartDate: "01.09.2026"},
+		{BookID: book3.String(), Status: "finished", Rating: 5, StartDate: "01.01.2024", FinishDate: "01.02.2024"},
+	})
+
+	s, usersServer, libraryServer := setupTestServers(t, db, usersData, libraryData)
+	defer s.Close()
+	defer usersServer.Close()
+	defer libraryServer.Close()
+	shelvesURL := s.URL + server.ApiUserReadingShelvesPath
+
+	assert.Equal(t, sendRequest(t, http.MethodPost, shelvesURL, usersData, `{"name": "Favourites"}`), http.StatusCreated)
+	assert.Equal(t, sendRequest(t, http.MethodPost, shelvesURL, usersData, `{"name": "Book club 2026"}`), http.StatusCreated)
+	assert.Equal(t, sendRequest(t, http.MethodPost, shelvesURL, usersData, `{"name": "Favourites"}`), http.StatusConflict)
+	assert.Equal(t, sendRequest(t, http.MethodPost, shelvesURL, usersData, `{"name": ""}`), http.StatusBadRequest)
+
+	shelves := []server.ResponseShelf{}
+	getJSON(t, shelvesURL, usersData, &shelves)
+	assert.Equal(t, len(shelves), 2)
+	favourites, club := shelves[0], shelves[1]
+	assert.Equal(t, favourites.Name, "Favourites")
+	assert.Equal(t, club.Name, "Book club 2026")
+
+	// A book can be on several shelves, putting it on the same shelf again changes nothing.
+	for _, url := range []string{
+		fmt.Sprintf("%s/%s/books/%s", shelvesURL, favourites.ID, book1),
+		fmt.Sprintf("%s/%s/books/%s", shelvesURL, favourites.ID, book2),
+		fmt.Sprintf("%s/%s/books/%s", shelvesURL, favourites.ID, book3),
+		fmt.Sprintf("%s/%s/books/%s", shelvesURL, favourites.ID, book3),
+		fmt.Sprintf("%s/%s/books/%s", shelvesURL, club.ID, book1),
+	} {
+		assert.Equal(t, sendRequest(t, http.MethodPut, url, usersData, ""), http.StatusNoContent)
+	}
+	assert.Equal(t, sendRequest(t, http.MethodPut, fmt.Sprintf("%s/%s/books/%s", shelvesURL, club.ID, uuid.New()), usersData, ""), http.StatusNotFound)
+	assert.Equal(t, sendRequest(t, http.MethodPut, fmt.Sprintf("%s/%s/books/%s", shelvesURL, uuid.New(), book1), usersData, ""), http.StatusNotFound)
+
+	// Books of a shelf are sorted the same way as all user reading.
+	userReading := []server.ResponseUserReading{}
+	getJSON(t, fmt.Sprintf("%s%s?shelf=%s", s.URL, server.ApiUserReadingPath, favourites.ID), usersData, &userReading)
+	assert.Equal(t, getUserReadingIDs(userReading), []string{book2.String(), book3.String(), book1.String()})
+	getJSON(t, fmt.Sprintf("%s%s?shelf=%s&status=finished", s.URL, server.ApiUserReadingPath, favourites.ID), usersData, &userReading)
+	assert.Equal(t, getUserReadingIDs(userReading), []string{book3.String(), book1.String()})
+	assert.Equal(t, sendRequest(t, http.MethodGet, fmt.Sprintf("%s%s?shelf=%s", s.URL, server.ApiUserReadingPath, uuid.New()), usersData, ""), http.StatusNotFound)
+	assert.Equal(t, sendRequest(t, http.MethodGet, s.URL+server.ApiUserReadingPath+"?shelf=shelf", usersData, ""), http.StatusBadRequest)
+
+	// Reorder and rename.
+	assert.Equal(t, sendRequest(t, http.MethodPut, shelvesURL+"/order", usersData, fmt.Sprintf(`{"shelf_ids": ["%s"]}`, club.ID)), http.StatusBadRequest)
+	assert.Equal(t, sendRequest(t, http.MethodPut, shelvesURL+"/order", usersData, fmt.Sprintf(`{"shelf_ids": ["%s", "%s"]}`, club.ID, favourites.ID)), http.StatusNoContent)
+	assert.Equal(t, sendRequest(t, http.MethodPut, shelvesURL+"/"+club.ID, usersData, `{"name": "Favourites"}`), http.StatusConflict)
+	assert.Equal(t, sendRequest(t, http.MethodPut, shelvesURL+"/"+club.ID, usersData, `{"name": "Book club"}`), http.StatusNoContent)
+	getJSON(t, shelvesURL, usersData, &shelves)
+	assert.Equal(t, shelves, []server.ResponseShelf{
+		{ID: club.ID, Name: "Book club", Position: 0, BooksCount: 1},
+		{ID: favourites.ID, Name: "Favourites", Position: 1, BooksCount: 3},
+	})
+
+	// Removing a book from a shelf or from user reading takes it off the shelf.
+	assert.Equal(t, sendRequest(t, http.MethodDelete, fmt.Sprintf("%s/%s/books/%s", shelvesURL, favourites.ID, book3), usersData, ""), http.StatusNoContent)
+	assert.Equal(t, sendRequest(t, http.MethodDelete, fmt.Sprintf("%s/%s/books/%s", shelvesURL, favourites.ID, book3), usersData, ""), http.StatusNotFound)
+	assert.Equal(t, sendRequest(t, http.MethodDelete, fmt.Sprintf("%s%s/%s", s.URL, server.ApiUserReadingPath, book2), usersData, ""), http.StatusNoContent)
+	getJSON(t, fmt.Sprintf("%s%s?shelf=%s", s.URL, server.ApiUserReadingPath, favourites.ID), usersData, &userReading)
+	assert.Equal(t, getUserReadingIDs(userReading), []string{book1.String()})
+
+	// Deleting a shelf keeps its books in user reading.
+	assert.Equal(t, sendRequest(t, http.MethodDelete, shelvesURL+"/"+favourites.ID, usersData, ""), http.StatusNoContent)
+	assert.Equal(t, sendRequest(t, http.MethodDelete, shelvesURL+"/"+favourites.ID, usersData, ""), http.StatusNotFound)
+	getJSON(t, shelvesURL, usersData, &shelves)
+	assert.Equal(t, getShelfIDs(shelves), []string{club.ID})
+	getJSON(t, s.URL+server.ApiUserReadingPath, usersData, &userReading)
+	assert.Equal(t, getUserReadingIDs(userReading), []string{book3.String(), book1.String()})
+
+	// Shelves of another user are not visible.
+	anotherUsersData := usersData
+	anotherUsersData.userID = uuid.New()
+	anotherServer, anotherUsersServer, anotherLibraryServer := setupTestServers(t, db, anotherUsersData, libraryData)
+	defer anotherServer.Close()
+	defer anotherUsersServer.Close()
+	defer anotherLibraryServer.Close()
+	getJSON(t, anotherServer.URL+server.ApiUserReadingShelvesPath, anotherUsersData, &shelves)
+	assert.Equal(t, shelves, []server.ResponseShelf{})
+	assert.Equal(t, sendRequest(t, http.MethodDelete, anotherServer.URL+server.ApiUserReadingShelvesPath+"/"+club.ID, anotherUsersData, ""), http.StatusNotFound)
+}
@@ -2,6 +2,8 @@ package test
 
 import (
 	"bytes"
+	"crypto/ed25519"
+	"crypto/rand"
 	"database/sql"
 	"encoding/json"
 	"fmt"
@@ -10,10 +12,13 @@ import (
 	"net/http/httptest"
 	"net/url"
 	"testing"
+	"time"
 
 	common "github.com/bakurvik/mylib-common"
+	"github.com/bakurvik/mylib/user-reading/internal/auth"
 	"github.com/bakurvik/mylib/user-reading/internal/clients"
 	"github.com/bakurvik/mylib/user-reading/internal/server"
+	"github.com/golang-jwt/jwt/v5"
 	"github.com/google/uuid"
 	_ "github.com/lib/pq"
 	"github.com/stretchr/testify/assert"
@@ -21,6 +26,8 @@ import (
 
 const (
 	deleteUserReading = "DELETE FROM user_reading"
+	deleteShelves     = "DELETE FROM shelves"
+	deleteProfiles    = "DELETE FROM user_profiles"
 	selectUserReading = "SELECT book_id, status, rating, start_date, finish_date FROM user_reading WHERE user_id = $1"
 	insertUserReading = "INSERT INTO user_reading(user_id, book_id, status, rating, start_date, finish_date) VALUES($1, $2, $3, $4, $5, $6)"
 )
@@ -73,16 +80,18 @@ func setupTestServers(t *testing.T, db *sql.DB, usersData usersServiceData, libr
 	libraryServer := mockLibraryServer(t, libraryData)
 	libraryURL, _ := url.Parse(libraryServer.URL)
 
-	apiCfg := server.ApiConfig{DB: db, UsersServiceHost: usersURL.String(), LibraryServiceHost: libraryURL.String()}
+	apiCfg := server.ApiConfig{DB: db, UsersServiceHost: usersURL.String(), LibraryServiceHost: libraryURL.String(), Auth: &auth.WhoamiAuthenticator{UsersServiceHost: usersURL.String()}}
 	sm := http.NewServeMux()
 	server.Handle(sm, &apiCfg)
 	return httptest.NewServer(sm), usersServer, libraryServer
 }
 
 func cleanupDB(db *sql.DB) {
-	_, err := db.Query(deleteUserReading)
-	if err != nil {
-		log.Print("Failed to cleanup db: ", err)
+	for _, query := range []string{deleteShelves, deleteProfiles, deleteUserReading} {
+		_, err := db.Exec(query)
+		if err != nil {
+			log.Print("Failed to cleanup db: ", err)
+		}
 	}
 }
 
@@ -159,16 +168,16 @@ func TestCreateUserReading(t *testing.T) {
 		{
 			name:                 "success",
 			status:               "finished",
-			rating:               7,
+			rating:               5,
 			usersData:            usersServiceData{userID: userID, authHeader: "Authorization", authToken: "Bearer access_token", statusCode: http.StatusOK},
 			libraryData:          libraryServiceData{bookID: bookID.String(), statusCode: http.StatusOK},
 			expectedStatusCode:   http.StatusCreated,
-			expectedUserReadings: []server.UserReading{{BookID: bookID.String(), Status: "finished", Rating: 7}},
+			expectedUserReadings: []server.UserReading{{BookID: bookID.String(), Status: "finished", Rating: 5}},
 		},
 		{
 			name:                 "invalid_book_id",
 			status:               "finished",
-			rating:               7,
+			rating:               5,
 			usersData:            usersServiceData{userID: userID, authHeader: "Authorization", authToken: "Bearer access_token", statusCode: http.StatusOK},
 			libraryData:          libraryServiceData{bookID: "invalid_book_id", statusCode: http.StatusBadRequest},
 			expectedStatusCode:   http.StatusBadRequest,
@@ -177,7 +186,7 @@ func TestCreateUserReading(t *testing.T) {
 		{
 			name:                 "invalid_status",
 			status:               "invalid_status",
-			rating:               7,
+			rating:               5,
 			usersData:            usersServiceData{userID: userID, authHeader: "Authorization", authToken: "Bearer access_token", statusCode: http.StatusOK},
 			libraryData:          libraryServiceData{bookID: bookID.String(), statusCode: http.StatusOK},
 			expectedStatusCode:   http.StatusBadRequest,
@@ -186,7 +195,7 @@ func TestCreateUserReading(t *testing.T) {
 		{
 			name:                 "unauthorized",
 			status:               "finished",
-			rating:               7,
+			rating:               5,
 			usersData:            usersServiceData{userID: userID, authHeader: "Authorization", authToken: "Bearer access_token", statusCode: http.StatusUnauthorized},
 			libraryData:          libraryServiceData{bookID: bookID.String(), statusCode: http.StatusOK},
 			expectedStatusCode:   http.StatusUnauthorized,
@@ -195,7 +204,7 @@ func TestCreateUserReading(t *testing.T) {
 		{
 			name:                 "book_not_found",
 			status:               "finished",
-			rating:               7,
+			rating:               5,
 			usersData:            usersServiceData{userID: userID, authHeader: "Authorization", authToken: "Bearer access_token", statusCode: http.StatusOK},
 			libraryData:          libraryServiceData{bookID: bookID.String(), statusCode: http.StatusNotFound},
 			expectedStatusCode:   http.StatusBadRequest,
@@ -204,18 +213,27 @@ func TestCreateUserReading(t *testing.T) {
 		{
 			name:                 "with_start_and_finish_dates",
 			status:               "finished",
-			rating:               7,
+			rating:               5,
 			startDate:            "04.09.2016",
 			finishDate:           "12.10.2016",
 			usersData:            usersServiceData{userID: userID, authHeader: "Authorization", authToken: "Bearer access_token", statusCode: http.StatusOK},
 			libraryData:          libraryServiceData{bookID: bookID.String(), statusCode: http.StatusOK},
 			expectedStatusCode:   http.StatusCreated,
-			expectedUserReadings: []server.UserReading{{BookID: bookID.String(), Status: "finished", Rating: 7, StartDate: "04.09.2016", FinishDate: "12.10.2016"}},
+			expectedUserReadings: []server.UserReading{{BookID: bookID.String(), Status: "finished", Rating: 5, StartDate: "04.09.2016", FinishDate: "12.10.2016"}},
+		},
+		{
+			name:                 "rating_out_of_bounds",
+			status:               "finished",
+			rating:               6,
+			usersData:            usersServiceData{userID: userID, authHeader: "Authorization", authToken: "Bearer access_token", statusCode: http.StatusOK},
+			libraryData:          libraryServiceData{bookID: bookID.String(), statusCode: http.StatusOK},
+			expectedStatusCode:   http.StatusBadRequest,
+			expectedUserReadings: []server.UserReading{},
 		},
 		{
 			name:                 "invalid_start_and_finish_dates",
 			status:               "finished",
-			rating:               7,
+			rating:               5,
 			startDate:            "04.09.2017",
 			finishDate:           "12.10.2016",
 			usersData:            usersServiceData{userID: userID, authHeader: "Authorization", authToken: "Bearer access_token", statusCode: http.StatusOK},
@@ -489,19 +507,19 @@ func TestGetUserReading(t *testing.T) {
 			name:               "success_one_book",
 			usersData:          usersServiceData{userID: userID, authHeader: "Authorization", authToken: "Bearer access_token", statusCode: http.StatusOK},
 			libraryData:        libraryServiceData{statusCode: http.StatusOK, booksInfo: []clients.ResponseBookFullInfo{{ID: book1.String(), Title: "Title 1", Authors: []string{"Author 1"}}}},
-			dbUserReadings:     []server.UserReading{{BookID: book1.String(), Status: "finished", Rating: 6}},
+			dbUserReadings:     []server.UserReading{{BookID: book1.String(), Status: "finished", Rating: 4}},
 			expectedStatusCode: http.StatusOK,
 			expectedResponse: []server.ResponseUserReading{
-				{ID: book1.String(), Title: "Title 1", Authors: []string{"Author 1"}, Status: "finished", Rating: 6}},
+				{ID: book1.String(), Title: "Title 1", Authors: []string{"Author 1"}, Status: "finished", Rating: 4}},
 		},
 		{
 			name:               "success_several_books_and_authors",
 			usersData:          usersServiceData{userID: userID, authHeader: "Authorization", authToken: "Bearer access_token", statusCode: http.StatusOK},
 			libraryData:        libraryServiceData{statusCode: http.StatusOK, booksInfo: []clients.ResponseBookFullInfo{{ID: book1.String(), Title: "Title 1", Authors: []string{"Author 1"}}, {ID: book2.String(), Title: "Title 2", Authors: []string{"Author 1", "Author 2"}}}},
-			dbUserReadings:     []server.UserReading{{BookID: book1.String(), Status: "finished", Rating: 6}, {BookID: book2.String(), Status: "reading", Rating: 3}},
+			dbUserReadings:     []server.UserReading{{BookID: book1.String(), Status: "finished", Rating: 4}, {BookID: book2.String(), Status: "reading", Rating: 3}},
 			expectedStatusCode: http.StatusOK,
 			expectedResponse: []server.ResponseUserReading{
-				{ID: book1.String(), Title: "Title 1", Authors: []string{"Author 1"}, Status: "finished", Rating: 6},
+				{ID: book1.String(), Title: "Title 1", Authors: []string{"Author 1"}, Status: "finished", Rating: 4},
 				{ID: book2.String(), Title: "Title 2", Authors: []string{"Author 1", "Author 2"}, Status: "reading", Rating: 3}},
 		},
 		{
@@ -524,17 +542,17 @@ func TestGetUserReading(t *testing.T) {
 			name:               "filter_out_unknown_book",
 			usersData:          usersServiceData{userID: userID, authHeader: "Authorization", authToken: "Bearer access_token", statusCode: http.StatusOK},
 			libraryData:        libraryServiceData{statusCode: http.StatusOK, booksInfo: []clients.ResponseBookFullInfo{{ID: book1.String(), Title: "Title 1", Authors: []string{"Author 1"}}}},
-			dbUserReadings:     []server.UserReading{{BookID: book1.String(), Status: "finished", Rating: 6}, {BookID: book2.String(), Status: "reading", Rating: 3}},
+			dbUserReadings:     []server.UserReading{{BookID: book1.String(), Status: "finished", Rating: 4}, {BookID: book2.String(), Status: "reading", Rating: 3}},
 			expectedStatusCode: http.StatusOK,
 			expectedResponse: []server.ResponseUserReading{
-				{ID: book1.String(), Title: "Title 1", Authors: []string{"Author 1"}, Status: "finished", Rating: 6}},
+				{ID: book1.String(), Title: "Title 1", Authors: []string{"Author 1"}, Status: "finished", Rating: 4}},
 		},
 		{
 			name:               "filter_by_query_status",
 			queryStatus:        "reading",
 			usersData:          usersServiceData{userID: userID, authHeader: "Authorization", authToken: "Bearer access_token", statusCode: http.StatusOK},
 			libraryData:        libraryServiceData{statusCode: http.StatusOK, booksInfo: []clients.ResponseBookFullInfo{{ID: book1.String(), Title: "Title 1", Authors: []string{"Author 1"}}, {ID: book2.String(), Title: "Title 2", Authors: []string{"Author 1", "Author 2"}}}},
-			dbUserReadings:     []server.UserReading{{BookID: book1.String(), Status: "finished", Rating: 6}, {BookID: book2.String(), Status: "reading", Rating: 3}},
+			dbUserReadings:     []server.UserReading{{BookID: book1.String(), Status: "finished", Rating: 4}, {BookID: book2.String(), Status: "reading", Rating: 3}},
 			expectedStatusCode: http.StatusOK,
 			expectedResponse: []server.ResponseUserReading{
 				{ID: book2.String(), Title: "Title 2", Authors: []string{"Author 1", "Author 2"}, Status: "reading", Rating: 3}},
@@ -544,7 +562,7 @@ func TestGetUserReading(t *testing.T) {
 			queryStatus:        "invalid_status",
 			usersData:          usersServiceData{userID: userID, authHeader: "Authorization", authToken: "Bearer access_token", statusCode: http.StatusOK},
 			libraryData:        libraryServiceData{statusCode: http.StatusOK, booksInfo: []clients.ResponseBookFullInfo{{ID: book1.String(), Title: "Title 1", Authors: []string{"Author 1"}}, {ID: book2.String(), Title: "Title 2", Authors: []string{"Author 1", "Author 2"}}}},
-			dbUserReadings:     []server.UserReading{{BookID: book1.String(), Status: "finished", Rating: 6}, {BookID: book2.String(), Status: "reading", Rating: 3}},
+			dbUserReadings:     []server.UserReading{{BookID: book1.String(), Status: "finished", Rating: 4}, {BookID: book2.String(), Status: "reading", Rating: 3}},
 			expectedStatusCode: http.StatusBadRequest,
 			expectedResponse:   nil,
 		},
@@ -610,12 +628,13 @@ func TestGetUserReadingByBook(t *testing.T) {
 			requestBookID:      bookID.String(),
 			usersData:          usersServiceData{userID: userID, authHeader: "Authorization", authToken: "Bearer access_token", statusCode: http.StatusOK},
 			libraryData:        libraryServiceData{statusCode: http.StatusOK, booksInfo: []clients.ResponseBookFullInfo{{ID: bookID.String(), Title: "Title 1", Authors: []string{"Author 1"}}}},
-			dbUserReadings:     []server.UserReading{{BookID: bookID.String(), Status: "finished", Rating: 6}},
+			dbUserReadings:     []server.UserReading{{BookID: bookID.String(), Status: "finished", Rating: 4}},
 			expectedStatusCode: http.StatusOK,
 			expectedResponse: server.ResponseUserReadingFullInfo{
 				ResponseUserReading: server.ResponseUserReading{
-					ID: bookID.String(), Title: "Title 1", Authors: []string{"Author 1"}, Status: "finished", Rating: 6,
+					ID: bookID.String(), Title: "Title 1", Authors: []string{"Author 1"}, Status: "finished", Rating: 4,
 				},
+				Cycles: []server.ResponseReadingCycle{{Rating: 4, Current: true}},
 			},
 		},
 		{
@@ -623,13 +642,14 @@ func TestGetUserReadingByBook(t *testing.T) {
 			requestBookID:      bookID.String(),
 			usersData:          usersServiceData{userID: userID, authHeader: "Authorization", authToken: "Bearer access_token", statusCode: http.StatusOK},
 			libraryData:        libraryServiceData{statusCode: http.StatusOK, booksInfo: []clients.ResponseBookFullInfo{{ID: bookID.String(), Title: "Title 1", Authors: []string{"Author 1"}}}},
-			dbUserReadings:     []server.UserReading{{BookID: bookID.String(), Status: "finished", Rating: 6, StartDate: "04.02.2003", FinishDate: "19.05.2003"}},
+			dbUserReadings:     []server.UserReading{{BookID: bookID.String(), Status: "finished", Rating: 4, StartDate: "04.02.2003", FinishDate: "19.05.2003"}},
 			expectedStatusCode: http.StatusOK,
 			expectedResponse: server.ResponseUserReadingFullInfo{
 				ResponseUserReading: server.ResponseUserReading{
-					ID: bookID.String(), Title: "Title 1", Authors: []string{"Author 1"}, Status: "finished", Rating: 6,
+					ID: bookID.String(), Title: "Title 1", Authors: []string{"Author 1"}, Status: "finished", Rating: 4,
 				},
 				StartDate: "04.02.2003", FinishDate: "19.05.2003",
+				Cycles: []server.ResponseReadingCycle{{Rating: 4, StartDate: "04.02.2003", FinishDate: "19.05.2003", Current: true}},
 			},
 		},
 		{
@@ -637,7 +657,7 @@ func TestGetUserReadingByBook(t *testing.T) {
 			requestBookID:      bookID.String(),
 			usersData:          usersServiceData{userID: userID, authHeader: "Authorization", authToken: "Bearer access_token", statusCode: http.StatusUnauthorized},
 			libraryData:        libraryServiceData{statusCode: http.StatusOK, booksInfo: []clients.ResponseBookFullInfo{{ID: bookID.String(), Title: "Title 1", Authors: []string{"Author 1"}}}},
-			dbUserReadings:     []server.UserReading{{BookID: bookID.String(), Status: "finished", Rating: 6}},
+			dbUserReadings:     []server.UserReading{{BookID: bookID.String(), Status: "finished", Rating: 4}},
 			expectedStatusCode: http.StatusUnauthorized,
 		},
 		{
@@ -645,7 +665,7 @@ func TestGetUserReadingByBook(t *testing.T) {
 			requestBookID:      "invalid_book_id",
 			usersData:          usersServiceData{userID: userID, authHeader: "Authorization", authToken: "Bearer access_token", statusCode: http.StatusOK},
 			libraryData:        libraryServiceData{statusCode: http.StatusOK, booksInfo: []clients.ResponseBookFullInfo{{ID: bookID.String(), Title: "Title 1", Authors: []string{"Author 1"}}}},
-			dbUserReadings:     []server.UserReading{{BookID: bookID.String(), Status: "finished", Rating: 6}},
+			dbUserReadings:     []server.UserReading{{BookID: bookID.String(), Status: "finished", Rating: 4}},
 			expectedStatusCode: http.StatusBadRequest,
 		},
 		{
@@ -661,7 +681,7 @@ func TestGetUserReadingByBook(t *testing.T) {
 			requestBookID:      bookID.String(),
 			usersData:          usersServiceData{userID: userID, authHeader: "Authorization", authToken: "Bearer access_token", statusCode: http.StatusOK},
 			libraryData:        libraryServiceData{statusCode: http.StatusOK, booksInfo: []clients.ResponseBookFullInfo{}},
-			dbUserReadings:     []server.UserReading{{BookID: bookID.String(), Status: "finished", Rating: 6}},
+			dbUserReadings:     []server.UserReading{{BookID: bookID.String(), Status: "finished", Rating: 4}},
 			expectedStatusCode: http.StatusNotFound,
 		},
 	}
@@ -701,3 +721,77 @@ func TestGetUserReadingByBook(t *testing.T) {
 		})
 	}
 }
+
+func TestGetUserReadingLocalAuth(t *testing.T) {
+	userID := uuid.New()
+	bookID := uuid.New()
+	publicKey, privateKey, _ := ed25519.GenerateKey(rand.Reader)
+	makeToken := func(issuer string, expiresIn time.Duration) string {
+		claims := auth.Claims{RegisteredClaims: jwt.RegisteredClaims{Issuer: issuer, Subject: userID.String(), ExpiresAt: jwt.NewNumericDate(time.Now().Add(expiresIn))}}
+		token, err := jwt.NewWithClaims(jwt.SigningMethodEdDSA, claims).SignedString(privateKey)
+		assert.NoError(t, err)
+		return token
+	}
+
+	type testCase struct {
+		name               string
+		token              string
+		expectedStatusCode int
+	}
+	tests := []testCase{
+		{
+			name:               "success",
+			token:              makeToken(auth.Issuer, time.Hour),
+			expectedStatusCode: http.StatusOK,
+		},
+		{
+			name:               "expired_token",
+			token:              makeToken(auth.Issuer, -time.Hour),
+			expectedStatusCode: http.StatusUnauthorized,
+		},
+		{
+			name:               "invalid_issuer",
+			token:              makeToken("another", time.Hour),
+			expectedStatusCode: http.StatusUnauthorized,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			db, err := common.SetupDBByURL("../.env", "TEST_DB_URL")
+			assert.NoError(t, err)
+			defer common.CloseDB(db)
+			cleanupDB(db)
+			addDBUserReading(db, userID.String(), []server.UserReading{{BookID: bookID.String(), Status: "finished", Rating: 4}})
+
+			// Users service is down, tokens must be verified without it.
+			usersServer := mockUsersServer(t, usersServiceData{statusCode: http.StatusInternalServerError})
+			defer usersServer.Close()
+			libraryServer := mockLibraryServer(t, libraryServiceData{statusCode: http.StatusOK, booksInfo: []clients.ResponseBookFullInfo{{ID: bookID.String(), Title: "Title", Authors: []string{"Author"}}}})
+			defer libraryServer.Close()
+
+			keys := func(token *jwt.Token) (interface{}, error) { return publicKey, nil }
+			apiCfg := server.ApiConfig{DB: db, LibraryServiceHost: libraryServer.URL, Auth: &auth.LocalAuthenticator{Keys: keys}}
+			sm := http.NewServeMux()
+			server.Handle(sm, &apiCfg)
+			s := httptest.NewServer(sm)
+			defer s.Close()
+
+			request, err := http.NewRequest(http.MethodGet, s.URL+server.ApiUserReadingPath, nil)
+			assert.NoError(t, err)
+			request.Header.Add("Authorization", "Bearer "+tc.token)
+
+			response, err := http.DefaultClient.Do(request)
+			assert.NoError(t, err)
+			defer common.CloseResponseBody(response)
+			assert.Equal(t, tc.expectedStatusCode, response.StatusCode)
+
+			if response.StatusCode == http.StatusOK {
+				responseBody := []server.ResponseUserReading{}
+				err = json.NewDecoder(response.Body).Decode(&responseBody)
+				assert.NoError(t, err)
+				assert.Equal(t, responseBody, []server.ResponseUserReading{{ID: bookID.String(), Title: "Title", Authors: []string{"Author"}, Status: "finished", Rating: 4}})
+			}
+		})
+	}
+}
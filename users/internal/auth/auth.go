@@ -2,6 +2,7 @@ package auth
 
 import (
 	"crypto/rand"
+	"crypto/sha256"
 	"encoding/hex"
 	"errors"
 	"net/http"
@@ -22,38 +23,66 @@ func CheckPasswordHash(hash, password string) error {
 	return bcrypt.CompareHashAndPassword([]byte(hash), []byte(password))
 }
 
-func MakeJWT(userID uuid.UUID, tokenSecret string, expiresIn time.Duration) (string, error) {
-	const issuer = "mylib.users"
-	claims := jwt.RegisteredClaims{
-		Issuer:    issuer,
-		IssuedAt:  jwt.NewNumericDate(time.Now().UTC()),
-		ExpiresAt: jwt.NewNumericDate(time.Now().Add(expiresIn)),
-		Subject:   userID.String(),
+// Issuer is put into iss claim of every access token.
+const Issuer = "mylib.users"
+
+const (
+	RoleReader = "reader"
+	RoleEditor = "editor"
+	RoleAdmin  = "admin"
+)
+
+// Claims are JWT claims issued by users service. Role is one of RoleReader, RoleEditor or RoleAdmin.
+type Claims struct {
+	Role string `json:"role"`
+	jwt.RegisteredClaims
+}
+
+func IsValidRole(role string) bool {
+	return role == RoleReader || role == RoleEditor || role == RoleAdmin
+}
+
+// MakeJWT issues access token signed with the current key of the key set. Key ID is put into kid header
+// so that verifiers can pick the matching public key from JWKS.
+func (ks *KeySet) MakeJWT(userID uuid.UUID, role string, expiresIn time.Duration) (string, error) {
+	claims := Claims{
+		Role: role,
+		RegisteredClaims: jwt.RegisteredClaims{
+			Issuer:    Issuer,
+			IssuedAt:  jwt.NewNumericDate(time.Now().UTC()),
+			ExpiresAt: jwt.NewNumericDate(time.Now().Add(expiresIn)),
+			Subject:   userID.String(),
+		},
 	}
 
-	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
-	return token.SignedString([]byte(tokenSecret))
+	current := ks.Current()
+	token := jwt.NewWithClaims(jwt.SigningMethodEdDSA, claims)
+	token.Header["kid"] = current.ID
+	return token.SignedString(current.PrivateKey)
 }
 
-func ValidateJWT(tokenString, tokenSecret string) (uuid.UUID, error) {
-	claims := jwt.RegisteredClaims{}
+// ValidateJWT checks token against any key of the key set and returns user ID and role from it.
+func (ks *KeySet) ValidateJWT(tokenString string) (uuid.UUID, string, error) {
+	claims := Claims{}
 	_, err := jwt.ParseWithClaims(tokenString, &claims, func(token *jwt.Token) (interface{}, error) {
-		if _, ok := token.Method.(*jwt.SigningMethodHMAC); !ok {
-			return nil, errors.New("unexpected signing method")
+		kid, _ := token.Header["kid"].(string)
+		key, ok := ks.Get(kid)
+		if !ok {
+			return nil, errors.New("unknown key id")
 		}
-		return []byte(tokenSecret), nil
-	})
+		return key.PrivateKey.Public(), nil
+	}, jwt.WithValidMethods([]string{jwt.SigningMethodEdDSA.Alg()}), jwt.WithIssuer(Issuer))
 
 	if err != nil {
-		return uuid.Nil, err
+		return uuid.Nil, "", err
 	}
 
 	userID, err := uuid.Parse(claims.Subject)
 	if err != nil {
-		return uuid.Nil, err
+		return uuid.Nil, "", err
 	}
 
-	return userID, nil
+	return userID, claims.Role, nil
 }
 
 func GetBearerToken(headers http.Header) (string, error) {
@@ -69,6 +98,25 @@ func GetBearerToken(headers http.Header) (string, error) {
 }
 
 func MakeRefreshToken() (string, error) {
+	return makeRandomToken()
+}
+
+// MakeOneTimeToken makes a random token for links sent by email and its hash. Only the hash is stored in DB,
+// so the tokens can't be used by anyone who reads the table.
+func MakeOneTimeToken() (string, string, error) {
+	token, err := makeRandomToken()
+	if err != nil {
+		return "", "", err
+	}
+	return token, HashToken(token), nil
+}
+
+func HashToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
+
+func makeRandomToken() (string, error) {
 	const tokenLen = 32
 	buf := make([]byte, tokenLen)
 	_, err := rand.Read(buf)
@@ -1,11 +1,18 @@
 package auth
 
 import (
+	"crypto/ed25519"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/pem"
 	"errors"
 	"net/http"
+	"os"
+	"path/filepath"
 	"testing"
 	"time"
 
+	"github.com/golang-jwt/jwt/v5"
 	"github.com/google/uuid"
 	"github.com/stretchr/testify/assert"
 )
@@ -41,52 +48,102 @@ func TestHashPassword(t *testing.T) {
 }
 
 func TestMakeAndValidateJWT(t *testing.T) {
+	oldKey, _ := GenerateSigningKey()
+	currentKey, _ := GenerateSigningKey()
+	anotherKey, _ := GenerateSigningKey()
+	oldKeySet, _ := NewKeySet(oldKey)
+	keySet, _ := NewKeySet(currentKey, oldKey)
+	anotherKeySet, _ := NewKeySet(anotherKey)
+
 	type testCase struct {
-		name          string
-		correctSecret string
-		secretToCheck string
-		hasError      bool
-		expiresIn     time.Duration
+		name      string
+		signKeys  *KeySet
+		checkKeys *KeySet
+		hasError  bool
+		expiresIn time.Duration
 	}
 	testCases := []testCase{
 		{
-			name:          "valid",
-			correctSecret: "my-secret",
-			secretToCheck: "my-secret",
-			expiresIn:     time.Hour,
-			hasError:      false,
+			name:      "valid",
+			signKeys:  keySet,
+			checkKeys: keySet,
+			expiresIn: time.Hour,
+			hasError:  false,
+		},
+		{
+			name:      "previous_key",
+			signKeys:  oldKeySet,
+			checkKeys: keySet,
+			expiresIn: time.Hour,
+			hasError:  false,
 		},
 		{
-			name:          "expired",
-			correctSecret: "my-secret",
-			secretToCheck: "my-secret",
-			expiresIn:     -time.Hour,
-			hasError:      true,
+			name:      "expired",
+			signKeys:  keySet,
+			checkKeys: keySet,
+			expiresIn: -time.Hour,
+			hasError:  true,
 		},
 		{
-			name:          "invalid_secret",
-			correctSecret: "my-secret",
-			secretToCheck: "wrong-secret",
-			expiresIn:     time.Hour,
-			hasError:      true,
+			name:      "unknown_key",
+			signKeys:  anotherKeySet,
+			checkKeys: keySet,
+			expiresIn: time.Hour,
+			hasError:  true,
 		},
 	}
 	userID := uuid.New()
 	for _, tc := range testCases {
 		t.Run(tc.name, func(t *testing.T) {
-			token, err := MakeJWT(userID, tc.correctSecret, tc.expiresIn)
+			token, err := tc.signKeys.MakeJWT(userID, RoleEditor, tc.expiresIn)
 			assert.NoError(t, err)
 			assert.NotEmpty(t, token)
 
-			parsedID, err := ValidateJWT(token, tc.secretToCheck)
+			parsedID, role, err := tc.checkKeys.ValidateJWT(token)
 			assert.Equal(t, err != nil, tc.hasError)
 			if !tc.hasError {
 				assert.Equal(t, userID, parsedID)
+				assert.Equal(t, role, RoleEditor)
 			}
 		})
 	}
 }
 
+func TestValidateJWTRejectsHMAC(t *testing.T) {
+	key, _ := GenerateSigningKey()
+	keySet, _ := NewKeySet(key)
+	token := jwt.NewWithClaims(jwt.SigningMethodHS256, Claims{RegisteredClaims: jwt.RegisteredClaims{Issuer: Issuer, Subject: uuid.NewString()}})
+	token.Header["kid"] = key.ID
+	tokenString, _ := token.SignedString([]byte(key.PrivateKey.Public().(ed25519.PublicKey)))
+
+	_, _, err := keySet.ValidateJWT(tokenString)
+	assert.Error(t, err)
+}
+
+func TestLoadKeySet(t *testing.T) {
+	dir := t.TempDir()
+	oldKey, _ := GenerateSigningKey()
+	currentKey, _ := GenerateSigningKey()
+	for _, key := range []SigningKey{oldKey, currentKey} {
+		data, _ := x509.MarshalPKCS8PrivateKey(key.PrivateKey)
+		err := os.WriteFile(filepath.Join(dir, key.ID+".pem"), pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: data}), 0600)
+		assert.NoError(t, err)
+	}
+
+	keySet, err := LoadKeySet(dir, currentKey.ID)
+	assert.NoError(t, err)
+	assert.Equal(t, keySet.Current().ID, currentKey.ID)
+
+	jwks := keySet.JWKS()
+	assert.Equal(t, len(jwks.Keys), 2)
+	assert.Equal(t, jwks.Keys[0].Kid, currentKey.ID)
+	assert.Equal(t, jwks.Keys[1].Kid, oldKey.ID)
+	assert.Equal(t, jwks.Keys[1].X, base64.RawURLEncoding.EncodeToString(oldKey.PrivateKey.Public().(ed25519.PublicKey)))
+
+	_, err = LoadKeySet(dir, "unknown")
+	assert.Error(t, err)
+}
+
 func TestGetBearerToken(t *testing.T) {
 	token := "some_token"
 	type testCase struct {
@@ -127,3 +184,49 @@ func TestGetBearerToken(t *testing.T) {
 		})
 	}
 }
+
+func TestMakeOneTimeToken(t *testing.T) {
+	token, hash, err := MakeOneTimeToken()
+	assert.NoError(t, err)
+	assert.Equal(t, len(token), 64)
+	assert.Equal(t, hash, HashToken(token))
+	assert.NotEqual(t, hash, token)
+
+	anotherToken, anotherHash, err := MakeOneTimeToken()
+	assert.NoError(t, err)
+	assert.NotEqual(t, anotherToken, token)
+	assert.NotEqual(t, anotherHash, hash)
+}
+
+func TestMakeRecoveryCode(t *testing.T) {
+	code, err := MakeRecoveryCode()
+	assert.NoError(t, err)
+	assert.Regexp(t, "^[a-z2-7]{5}-[a-z2-7]{5}$", code)
+
+	anotherCode, _ := MakeRecoveryCode()
+	assert.NotEqual(t, anotherCode, code)
+}
+
+func TestNormalizeRecoveryCode(t *testing.T) {
+	assert.Equal(t, NormalizeRecoveryCode("abcde-fghij"), "abcdefghij")
+	assert.Equal(t, NormalizeRecoveryCode(" ABCDE FGHIJ "), "abcdefghij")
+}
+
+func TestMakePersonalToken(t *testing.T) {
+	token, hash, err := MakePersonalToken()
+	assert.NoError(t, err)
+	assert.True(t, IsPersonalToken(token))
+	assert.Equal(t, hash, HashToken(token))
+
+	anotherToken, _, _ := MakePersonalToken()
+	assert.NotEqual(t, anotherToken, token)
+
+	assert.False(t, IsPersonalToken("eyJhbGciOiJFZERTQSJ9.eyJzdWIiOiIxIn0.c2ln"))
+}
+
+func TestIsValidScope(t *testing.T) {
+	assert.True(t, IsValidScope(ScopeLibraryWrite))
+	assert.True(t, IsValidScope(ScopeReadingRead))
+	assert.False(t, IsValidScope("library:delete"))
+	assert.False(t, IsValidScope(""))
+}
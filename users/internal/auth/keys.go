@@ -0,0 +1,159 @@
+package auth
+
+import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+const keyFileExt = ".pem"
+
+// SigningKey is an Ed25519 private key with its key ID (kid).
+type SigningKey struct {
+	ID         string
+	PrivateKey ed25519.PrivateKey
+}
+
+// KeySet holds signing keys. Tokens are signed with the current key, all keys are published in JWKS and accepted
+// on validation, so a new key can be rolled out while tokens signed with the previous one are still valid.
+type KeySet struct {
+	current string
+	keys    map[string]SigningKey
+}
+
+// JWK is a public key in JSON Web Key format, see RFC 8037 for Ed25519 (OKP) keys.
+type JWK struct {
+	Kty string `json:"kty"`
+	Crv string `json:"crv"`
+	X   string `json:"x"`
+	Kid string `json:"kid"`
+	Alg string `json:"alg"`
+	Use string `json:"use"`
+}
+
+type JWKS struct {
+	Keys []JWK `json:"keys"`
+}
+
+// NewKeySet makes key set that signs tokens with current key and also accepts previous keys.
+func NewKeySet(current SigningKey, previous ...SigningKey) (*KeySet, error) {
+	ks := KeySet{current: current.ID, keys: map[string]SigningKey{}}
+	for _, key := range append([]SigningKey{current}, previous...) {
+		if key.ID == "" {
+			return nil, errors.New("empty key id")
+		}
+		if len(key.PrivateKey) != ed25519.PrivateKeySize {
+			return nil, fmt.Errorf("invalid private key %v", key.ID)
+		}
+		if _, ok := ks.keys[key.ID]; ok {
+			return nil, fmt.Errorf("duplicate key id %v", key.ID)
+		}
+		ks.keys[key.ID] = key
+	}
+	return &ks, nil
+}
+
+// GenerateSigningKey makes a new Ed25519 key with random key ID.
+func GenerateSigningKey() (SigningKey, error) {
+	_, privateKey, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		return SigningKey{}, err
+	}
+	buf := make([]byte, 8)
+	_, err = rand.Read(buf)
+	if err != nil {
+		return SigningKey{}, err
+	}
+	return SigningKey{ID: hex.EncodeToString(buf), PrivateKey: privateKey}, nil
+}
+
+// LoadKeySet reads PKCS #8 PEM encoded Ed25519 private keys from dir. File name without .pem extension is used as key ID,
+// the key with currentID signs new tokens.
+func LoadKeySet(dir string, currentID string) (*KeySet, error) {
+	paths, err := filepath.Glob(filepath.Join(dir, "*"+keyFileExt))
+	if err != nil {
+		return nil, err
+	}
+	sort.Strings(paths)
+
+	var current *SigningKey
+	previous := []SigningKey{}
+	for _, path := range paths {
+		key, err := loadSigningKey(path)
+		if err != nil {
+			return nil, err
+		}
+		if key.ID == currentID {
+			current = &key
+		} else {
+			previous = append(previous, key)
+		}
+	}
+	if current == nil {
+		return nil, fmt.Errorf("current key %v not found in %v", currentID, dir)
+	}
+	return NewKeySet(*current, previous...)
+}
+
+func loadSigningKey(path string) (SigningKey, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return SigningKey{}, err
+	}
+	block, _ := pem.Decode(data)
+	if block == nil {
+		return SigningKey{}, fmt.Errorf("no PEM data in %v", path)
+	}
+	key, err := x509.ParsePKCS8PrivateKey(block.Bytes)
+	if err != nil {
+		return SigningKey{}, fmt.Errorf("failed to parse %v: %w", path, err)
+	}
+	privateKey, ok := key.(ed25519.PrivateKey)
+	if !ok {
+		return SigningKey{}, fmt.Errorf("%v is not an Ed25519 key", path)
+	}
+	return SigningKey{ID: strings.TrimSuffix(filepath.Base(path), keyFileExt), PrivateKey: privateKey}, nil
+}
+
+func (ks *KeySet) Current() SigningKey {
+	return ks.keys[ks.current]
+}
+
+func (ks *KeySet) Get(id string) (SigningKey, bool) {
+	key, ok := ks.keys[id]
+	return key, ok
+}
+
+// JWKS returns public keys of the set, the current key goes first.
+func (ks *KeySet) JWKS() JWKS {
+	ids := make([]string, 0, len(ks.keys))
+	for id := range ks.keys {
+		if id != ks.current {
+			ids = append(ids, id)
+		}
+	}
+	sort.Strings(ids)
+
+	jwks := JWKS{Keys: make([]JWK, 0, len(ks.keys))}
+	for _, id := range append([]string{ks.current}, ids...) {
+		publicKey := ks.keys[id].PrivateKey.Public().(ed25519.PublicKey)
+		jwks.Keys = append(jwks.Keys, JWK{
+			Kty: "OKP",
+			Crv: "Ed25519",
+			X:   base64.RawURLEncoding.EncodeToString(publicKey),
+			Kid: id,
+			Alg: "EdDSA",
+			Use: "sig",
+		})
+	}
+	return jwks
+}
@@ -0,0 +1,37 @@
+package auth
+
+import (
+	"slices"
+	"strings"
+)
+
+// PersonalTokenPrefix starts every personal access token, so services can tell them from JWT access tokens
+// and secret scanners can find leaked ones.
+const PersonalTokenPrefix = "mylib_pat_"
+
+// Scopes of personal access tokens. A token gets only the permissions of its scopes that the user's role allows.
+const (
+	ScopeLibraryWrite = "library:write"
+	ScopeReadingRead  = "reading:read"
+	ScopeReadingWrite = "reading:write"
+)
+
+var scopes = []string{ScopeLibraryWrite, ScopeReadingRead, ScopeReadingWrite}
+
+func IsValidScope(scope string) bool {
+	return slices.Contains(scopes, scope)
+}
+
+// MakePersonalToken makes a personal access token and its hash. Only the hash is stored in DB.
+func MakePersonalToken() (string, string, error) {
+	token, err := makeRandomToken()
+	if err != nil {
+		return "", "", err
+	}
+	token = PersonalTokenPrefix + token
+	return token, HashToken(token), nil
+}
+
+func IsPersonalToken(token string) bool {
+	return strings.HasPrefix(token, PersonalTokenPrefix)
+}
@@ -0,0 +1,26 @@
+package auth
+
+import (
+	"crypto/rand"
+	"encoding/base32"
+	"strings"
+)
+
+const recoveryCodeLen = 10
+
+// MakeRecoveryCode makes a one-time code that replaces TOTP code when the authenticator app is lost.
+// The code is formatted as xxxxx-xxxxx to be easy to copy by hand.
+func MakeRecoveryCode() (string, error) {
+	buf := make([]byte, recoveryCodeLen)
+	_, err := rand.Read(buf)
+	if err != nil {
+		return "", err
+	}
+	code := strings.ToLower(base32.StdEncoding.EncodeToString(buf))[:recoveryCodeLen]
+	return code[:recoveryCodeLen/2] + "-" + code[recoveryCodeLen/2:], nil
+}
+
+// NormalizeRecoveryCode lets users type recovery code in any case, without the dash and with spaces.
+func NormalizeRecoveryCode(code string) string {
+	return strings.ToLower(strings.NewReplacer("-", "", " ", "").Replace(code))
+}
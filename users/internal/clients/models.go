@@ -0,0 +1,5 @@
+package clients
+
+const (
+	UserReadingApiExportPath = "/api/user-reading/export"
+)
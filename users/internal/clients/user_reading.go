@@ -0,0 +1,37 @@
+package clients
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+
+	common "github.com/bakurvik/mylib-common"
+)
+
+const requestTimeout = 10 * time.Second
+
+// GetUserReadingExport gets all reading data of the user from user-reading service. The data is returned as is,
+// so the export keeps everything user-reading stores without users service knowing its format.
+func GetUserReadingExport(host string, accessToken string) (int, json.RawMessage, error) {
+	request, err := http.NewRequest(http.MethodGet, fmt.Sprintf("%v%v", host, UserReadingApiExportPath), nil)
+	if err != nil {
+		return 0, nil, err
+	}
+	request.Header.Set("Authorization", "Bearer "+accessToken)
+	client := &http.Client{Timeout: requestTimeout}
+	response, err := client.Do(request)
+	if err != nil {
+		return 0, nil, err
+	}
+	defer common.CloseResponseBody(response)
+	if response.StatusCode != http.StatusOK {
+		return response.StatusCode, nil, nil
+	}
+	data := json.RawMessage{}
+	err = json.NewDecoder(response.Body).Decode(&data)
+	if err != nil {
+		return response.StatusCode, nil, err
+	}
+	return response.StatusCode, data, nil
+}
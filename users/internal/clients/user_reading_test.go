@@ -0,0 +1,41 @@
+package clients
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGetUserReadingExport(t *testing.T) {
+	type testCase struct {
+		name               string
+		status             int
+		body               string
+		expectedStatusCode int
+		expectedData       string
+		expectedError      bool
+	}
+	testCases := []testCase{
+		{name: "success", status: http.StatusOK, body: `{"books":[]}`, expectedStatusCode: http.StatusOK, expectedData: `{"books":[]}`},
+		{name: "unauthorized", status: http.StatusUnauthorized, body: `{"error":"Unauthorized"}`, expectedStatusCode: http.StatusUnauthorized},
+		{name: "invalid_body", status: http.StatusOK, body: `{`, expectedStatusCode: http.StatusOK, expectedError: true},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			s := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				assert.Equal(t, r.URL.Path, UserReadingApiExportPath)
+				assert.Equal(t, r.Header.Get("Authorization"), "Bearer token")
+				w.WriteHeader(tc.status)
+				w.Write([]byte(tc.body))
+			}))
+			defer s.Close()
+
+			statusCode, data, err := GetUserReadingExport(s.URL, "token")
+			assert.Equal(t, err != nil, tc.expectedError)
+			assert.Equal(t, statusCode, tc.expectedStatusCode)
+			assert.Equal(t, string(data), tc.expectedData)
+		})
+	}
+}
@@ -0,0 +1,33 @@
+// Code generated by sqlc. DO NOT EDIT.
+// versions:
+//   sqlc v1.28.0
+// source: add_login_failure.sql
+
+package database
+
+import (
+	"context"
+	"time"
+)
+
+const addLoginFailure = `-- name: AddLoginFailure :one
+INSERT INTO login_attempts (key, failures, last_failed_at, blocked_until)
+VALUES ($1, 1, $2, $2)
+ON CONFLICT (key) DO UPDATE SET
+    failures = CASE WHEN login_attempts.last_failed_at > $3 THEN login_attempts.failures + 1 ELSE 1 END,
+    last_failed_at = $2
+RETURNING failures
+`
+
+type AddLoginFailureParams struct {
+	Key          string
+	FailedAt     time.Time
+	ForgetBefore time.Time
+}
+
+func (q *Queries) AddLoginFailure(ctx context.Context, arg AddLoginFailureParams) (int32, error) {
+	row := q.db.QueryRowContext(ctx, addLoginFailure, arg.Key, arg.FailedAt, arg.ForgetBefore)
+	var failures int32
+	err := row.Scan(&failures)
+	return failures, err
+}
@@ -0,0 +1,26 @@
+// Code generated by sqlc. DO NOT EDIT.
+// versions:
+//   sqlc v1.28.0
+// source: block_login.sql
+
+package database
+
+import (
+	"context"
+	"time"
+)
+
+const blockLogin = `-- name: BlockLogin :exec
+UPDATE login_attempts SET blocked_until = GREATEST(blocked_until, $2)
+WHERE key = $1
+`
+
+type BlockLoginParams struct {
+	Key          string
+	BlockedUntil time.Time
+}
+
+func (q *Queries) BlockLogin(ctx context.Context, arg BlockLoginParams) error {
+	_, err := q.db.ExecContext(ctx, blockLogin, arg.Key, arg.BlockedUntil)
+	return err
+}
@@ -0,0 +1,35 @@
+// Code generated by sqlc. DO NOT EDIT.
+// versions:
+//   sqlc v1.28.0
+// source: create_oidc_state.sql
+
+package database
+
+import (
+	"context"
+	"time"
+)
+
+const createOIDCState = `-- name: CreateOIDCState :exec
+INSERT INTO oidc_states (state_hash, provider, code_verifier, nonce, expires_at)
+VALUES ($1, $2, $3, $4, $5)
+`
+
+type CreateOIDCStateParams struct {
+	StateHash    string
+	Provider     string
+	CodeVerifier string
+	Nonce        string
+	ExpiresAt    time.Time
+}
+
+func (q *Queries) CreateOIDCState(ctx context.Context, arg CreateOIDCStateParams) error {
+	_, err := q.db.ExecContext(ctx, createOIDCState,
+		arg.StateHash,
+		arg.Provider,
+		arg.CodeVerifier,
+		arg.Nonce,
+		arg.ExpiresAt,
+	)
+	return err
+}
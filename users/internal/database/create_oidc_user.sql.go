@@ -0,0 +1,44 @@
+// Code generated by sqlc. DO NOT EDIT.
+// versions:
+//   sqlc v1.28.0
+// source: create_oidc_user.sql
+
+package database
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+)
+
+const createOIDCUser = `-- name: CreateOIDCUser :one
+WITH new_user AS (
+    INSERT INTO users (id, login_name, email, hashed_password, email_verified, created_at, updated_at)
+    VALUES (gen_random_uuid(), $4, $3, $5, TRUE, NOW(), NOW())
+    RETURNING users.id
+)
+INSERT INTO user_identities (provider, subject, user_id, email, created_at)
+SELECT $1, $2, new_user.id, $3, NOW() FROM new_user
+RETURNING user_identities.user_id
+`
+
+type CreateOIDCUserParams struct {
+	Provider       string
+	Subject        string
+	Email          string
+	LoginName      string
+	HashedPassword string
+}
+
+func (q *Queries) CreateOIDCUser(ctx context.Context, arg CreateOIDCUserParams) (uuid.UUID, error) {
+	row := q.db.QueryRowContext(ctx, createOIDCUser,
+		arg.Provider,
+		arg.Subject,
+		arg.Email,
+		arg.LoginName,
+		arg.HashedPassword,
+	)
+	var user_id uuid.UUID
+	err := row.Scan(&user_id)
+	return user_id, err
+}
@@ -0,0 +1,46 @@
+// Code generated by sqlc. DO NOT EDIT.
+// versions:
+//   sqlc v1.28.0
+// source: create_personal_token.sql
+
+package database
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+)
+
+const createPersonalToken = `-- name: CreatePersonalToken :one
+INSERT INTO personal_tokens (id, user_id, name, token_hash, scopes, created_at, expires_at)
+VALUES (gen_random_uuid(), $1, $2, $3, $4, NOW(), $5)
+RETURNING id, created_at
+`
+
+type CreatePersonalTokenParams struct {
+	UserID    uuid.UUID
+	Name      string
+	TokenHash string
+	Scopes    []string
+	ExpiresAt time.Time
+}
+
+type CreatePersonalTokenRow struct {
+	ID        uuid.UUID
+	CreatedAt time.Time
+}
+
+func (q *Queries) CreatePersonalToken(ctx context.Context, arg CreatePersonalTokenParams) (CreatePersonalTokenRow, error) {
+	row := q.db.QueryRowContext(ctx, createPersonalToken,
+		arg.UserID,
+		arg.Name,
+		arg.TokenHash,
+		pq.Array(arg.Scopes),
+		arg.ExpiresAt,
+	)
+	var i CreatePersonalTokenRow
+	err := row.Scan(&i.ID, &i.CreatedAt)
+	return i, err
+}
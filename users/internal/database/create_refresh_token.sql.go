@@ -7,25 +7,38 @@ package database
 
 import (
 	"context"
+	"database/sql"
 	"time"
 
 	"github.com/google/uuid"
 )
 
 const createRefreshToken = `-- name: CreateRefreshToken :exec
-INSERT INTO refresh_tokens (token, user_id, expires_at, created_at, updated_at)
+INSERT INTO refresh_tokens (token, user_id, expires_at, family_id, parent_token, user_agent, ip_address, last_used_at, created_at, updated_at)
 VALUES (
-    $1, $2, $3, NOW(), NOW()
+    $1, $2, $3, $4, $5, $6, $7, NOW(), NOW(), NOW()
 )
 `
 
 type CreateRefreshTokenParams struct {
-	Token     string
-	UserID    uuid.UUID
-	ExpiresAt time.Time
+	Token       string
+	UserID      uuid.UUID
+	ExpiresAt   time.Time
+	FamilyID    uuid.UUID
+	ParentToken sql.NullString
+	UserAgent   string
+	IpAddress   string
 }
 
 func (q *Queries) CreateRefreshToken(ctx context.Context, arg CreateRefreshTokenParams) error {
-	_, err := q.db.ExecContext(ctx, createRefreshToken, arg.Token, arg.UserID, arg.ExpiresAt)
+	_, err := q.db.ExecContext(ctx, createRefreshToken,
+		arg.Token,
+		arg.UserID,
+		arg.ExpiresAt,
+		arg.FamilyID,
+		arg.ParentToken,
+		arg.UserAgent,
+		arg.IpAddress,
+	)
 	return err
 }
@@ -0,0 +1,20 @@
+// Code generated by sqlc. DO NOT EDIT.
+// versions:
+//   sqlc v1.28.0
+// source: delete_expired_oidc_states.sql
+
+package database
+
+import (
+	"context"
+)
+
+const deleteExpiredOIDCStates = `-- name: DeleteExpiredOIDCStates :exec
+DELETE FROM oidc_states
+WHERE expires_at <= NOW()
+`
+
+func (q *Queries) DeleteExpiredOIDCStates(ctx context.Context) error {
+	_, err := q.db.ExecContext(ctx, deleteExpiredOIDCStates)
+	return err
+}
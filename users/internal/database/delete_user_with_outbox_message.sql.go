@@ -0,0 +1,35 @@
+// Code generated by sqlc. DO NOT EDIT.
+// versions:
+//   sqlc v1.28.0
+// source: delete_user_with_outbox_message.sql
+
+package database
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+)
+
+const deleteUserWithOutboxMessage = `-- name: DeleteUserWithOutboxMessage :execrows
+WITH deleted AS (
+    DELETE FROM users WHERE users.id = $3
+    RETURNING users.id
+)
+INSERT INTO outbox (topic, message_key, payload, created_at)
+SELECT $1::TEXT, deleted.id::TEXT, $2::BYTEA, NOW() FROM deleted
+`
+
+type DeleteUserWithOutboxMessageParams struct {
+	Topic   string
+	Payload []byte
+	UserID  uuid.UUID
+}
+
+func (q *Queries) DeleteUserWithOutboxMessage(ctx context.Context, arg DeleteUserWithOutboxMessageParams) (int64, error) {
+	result, err := q.db.ExecContext(ctx, deleteUserWithOutboxMessage, arg.Topic, arg.Payload, arg.UserID)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
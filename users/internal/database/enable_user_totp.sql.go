@@ -0,0 +1,30 @@
+// Code generated by sqlc. DO NOT EDIT.
+// versions:
+//   sqlc v1.28.0
+// source: enable_user_totp.sql
+
+package database
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+)
+
+const enableUserTOTP = `-- name: EnableUserTOTP :execrows
+UPDATE users SET totp_enabled = TRUE, totp_last_used_step = $2, updated_at = NOW()
+WHERE id = $1 AND NOT totp_enabled AND totp_secret IS NOT NULL
+`
+
+type EnableUserTOTPParams struct {
+	ID               uuid.UUID
+	TotpLastUsedStep int64
+}
+
+func (q *Queries) EnableUserTOTP(ctx context.Context, arg EnableUserTOTPParams) (int64, error) {
+	result, err := q.db.ExecContext(ctx, enableUserTOTP, arg.ID, arg.TotpLastUsedStep)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
@@ -0,0 +1,29 @@
+// Code generated by sqlc. DO NOT EDIT.
+// versions:
+//   sqlc v1.28.0
+// source: get_active_user_token.sql
+
+package database
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+)
+
+const getActiveUserToken = `-- name: GetActiveUserToken :one
+SELECT user_id FROM user_tokens
+WHERE token_hash = $1 AND purpose = $2 AND expires_at > NOW() AND used_at IS NULL
+`
+
+type GetActiveUserTokenParams struct {
+	TokenHash string
+	Purpose   UserTokenPurpose
+}
+
+func (q *Queries) GetActiveUserToken(ctx context.Context, arg GetActiveUserTokenParams) (uuid.UUID, error) {
+	row := q.db.QueryRowContext(ctx, getActiveUserToken, arg.TokenHash, arg.Purpose)
+	var user_id uuid.UUID
+	err := row.Scan(&user_id)
+	return user_id, err
+}
@@ -0,0 +1,23 @@
+// Code generated by sqlc. DO NOT EDIT.
+// versions:
+//   sqlc v1.28.0
+// source: get_login_blocked_until.sql
+
+package database
+
+import (
+	"context"
+	"time"
+)
+
+const getLoginBlockedUntil = `-- name: GetLoginBlockedUntil :one
+SELECT blocked_until FROM login_attempts
+WHERE key = $1
+`
+
+func (q *Queries) GetLoginBlockedUntil(ctx context.Context, key string) (time.Time, error) {
+	row := q.db.QueryRowContext(ctx, getLoginBlockedUntil, key)
+	var blocked_until time.Time
+	err := row.Scan(&blocked_until)
+	return blocked_until, err
+}
@@ -0,0 +1,31 @@
+// Code generated by sqlc. DO NOT EDIT.
+// versions:
+//   sqlc v1.28.0
+// source: get_refresh_token.sql
+
+package database
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/google/uuid"
+)
+
+const getRefreshToken = `-- name: GetRefreshToken :one
+SELECT user_id, family_id, revoked_at FROM refresh_tokens
+WHERE token = $1
+`
+
+type GetRefreshTokenRow struct {
+	UserID    uuid.UUID
+	FamilyID  uuid.UUID
+	RevokedAt sql.NullTime
+}
+
+func (q *Queries) GetRefreshToken(ctx context.Context, token string) (GetRefreshTokenRow, error) {
+	row := q.db.QueryRowContext(ctx, getRefreshToken, token)
+	var i GetRefreshTokenRow
+	err := row.Scan(&i.UserID, &i.FamilyID, &i.RevokedAt)
+	return i, err
+}
@@ -12,18 +12,29 @@ import (
 )
 
 const getUserByEmail = `-- name: GetUserByEmail :one
-SELECT id, hashed_password FROM users
+SELECT id, login_name, hashed_password, role, totp_enabled, email_verified FROM users
 WHERE email = $1
 `
 
 type GetUserByEmailRow struct {
 	ID             uuid.UUID
+	LoginName      string
 	HashedPassword string
+	Role           UserRole
+	TotpEnabled    bool
+	EmailVerified  bool
 }
 
 func (q *Queries) GetUserByEmail(ctx context.Context, email string) (GetUserByEmailRow, error) {
 	row := q.db.QueryRowContext(ctx, getUserByEmail, email)
 	var i GetUserByEmailRow
-	err := row.Scan(&i.ID, &i.HashedPassword)
+	err := row.Scan(
+		&i.ID,
+		&i.LoginName,
+		&i.HashedPassword,
+		&i.Role,
+		&i.TotpEnabled,
+		&i.EmailVerified,
+	)
 	return i, err
 }
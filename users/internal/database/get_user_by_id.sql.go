@@ -8,24 +8,47 @@ package database
 import (
 	"context"
 	"database/sql"
+	"time"
 
 	"github.com/google/uuid"
 )
 
 const getUserByID = `-- name: GetUserByID :one
-SELECT login_name, email, birth_date FROM users
+SELECT login_name, email, birth_date, email_verified, totp_enabled,
+    display_name, bio, avatar_url, locale, reading_list_public, created_at
+FROM users
 WHERE id = $1
 `
 
 type GetUserByIDRow struct {
-	LoginName string
-	Email     string
-	BirthDate sql.NullTime
+	LoginName         string
+	Email             string
+	BirthDate         sql.NullTime
+	EmailVerified     bool
+	TotpEnabled       bool
+	DisplayName       string
+	Bio               string
+	AvatarUrl         string
+	Locale            string
+	ReadingListPublic bool
+	CreatedAt         time.Time
 }
 
 func (q *Queries) GetUserByID(ctx context.Context, id uuid.UUID) (GetUserByIDRow, error) {
 	row := q.db.QueryRowContext(ctx, getUserByID, id)
 	var i GetUserByIDRow
-	err := row.Scan(&i.LoginName, &i.Email, &i.BirthDate)
+	err := row.Scan(
+		&i.LoginName,
+		&i.Email,
+		&i.BirthDate,
+		&i.EmailVerified,
+		&i.TotpEnabled,
+		&i.DisplayName,
+		&i.Bio,
+		&i.AvatarUrl,
+		&i.Locale,
+		&i.ReadingListPublic,
+		&i.CreatedAt,
+	)
 	return i, err
 }
@@ -1,24 +0,0 @@
-// Code generated by sqlc. DO NOT EDIT.
-// versions:
-//   sqlc v1.28.0
-// source: get_user_by_refresh_token.sql
-
-package database
-
-import (
-	"context"
-
-	"github.com/google/uuid"
-)
-
-const getUserByRefreshToken = `-- name: GetUserByRefreshToken :one
-SELECT user_id FROM refresh_tokens
-WHERE token = $1 AND expires_at > NOW() AND revoked_at is NULL
-`
-
-func (q *Queries) GetUserByRefreshToken(ctx context.Context, token string) (uuid.UUID, error) {
-	row := q.db.QueryRowContext(ctx, getUserByRefreshToken, token)
-	var user_id uuid.UUID
-	err := row.Scan(&user_id)
-	return user_id, err
-}
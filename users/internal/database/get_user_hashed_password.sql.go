@@ -0,0 +1,29 @@
+// Code generated by sqlc. DO NOT EDIT.
+// versions:
+//   sqlc v1.28.0
+// source: get_user_hashed_password.sql
+
+package database
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+)
+
+const getUserHashedPassword = `-- name: GetUserHashedPassword :one
+SELECT hashed_password, email FROM users
+WHERE id = $1
+`
+
+type GetUserHashedPasswordRow struct {
+	HashedPassword string
+	Email          string
+}
+
+func (q *Queries) GetUserHashedPassword(ctx context.Context, id uuid.UUID) (GetUserHashedPasswordRow, error) {
+	row := q.db.QueryRowContext(ctx, getUserHashedPassword, id)
+	var i GetUserHashedPasswordRow
+	err := row.Scan(&i.HashedPassword, &i.Email)
+	return i, err
+}
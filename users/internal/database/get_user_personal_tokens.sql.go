@@ -0,0 +1,60 @@
+// Code generated by sqlc. DO NOT EDIT.
+// versions:
+//   sqlc v1.28.0
+// source: get_user_personal_tokens.sql
+
+package database
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+)
+
+const getUserPersonalTokens = `-- name: GetUserPersonalTokens :many
+SELECT id, name, scopes, created_at, expires_at, last_used_at FROM personal_tokens
+WHERE user_id = $1 AND revoked_at IS NULL AND expires_at > NOW()
+ORDER BY created_at DESC, id
+`
+
+type GetUserPersonalTokensRow struct {
+	ID         uuid.UUID
+	Name       string
+	Scopes     []string
+	CreatedAt  time.Time
+	ExpiresAt  time.Time
+	LastUsedAt sql.NullTime
+}
+
+func (q *Queries) GetUserPersonalTokens(ctx context.Context, userID uuid.UUID) ([]GetUserPersonalTokensRow, error) {
+	rows, err := q.db.QueryContext(ctx, getUserPersonalTokens, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []GetUserPersonalTokensRow
+	for rows.Next() {
+		var i GetUserPersonalTokensRow
+		if err := rows.Scan(
+			&i.ID,
+			&i.Name,
+			pq.Array(&i.Scopes),
+			&i.CreatedAt,
+			&i.ExpiresAt,
+			&i.LastUsedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
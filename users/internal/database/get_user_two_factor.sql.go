@@ -0,0 +1,39 @@
+// Code generated by sqlc. DO NOT EDIT.
+// versions:
+//   sqlc v1.28.0
+// source: get_user_two_factor.sql
+
+package database
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/google/uuid"
+)
+
+const getUserTwoFactor = `-- name: GetUserTwoFactor :one
+SELECT email, role, totp_secret, totp_enabled, totp_last_used_step FROM users
+WHERE id = $1
+`
+
+type GetUserTwoFactorRow struct {
+	Email            string
+	Role             UserRole
+	TotpSecret       sql.NullString
+	TotpEnabled      bool
+	TotpLastUsedStep int64
+}
+
+func (q *Queries) GetUserTwoFactor(ctx context.Context, id uuid.UUID) (GetUserTwoFactorRow, error) {
+	row := q.db.QueryRowContext(ctx, getUserTwoFactor, id)
+	var i GetUserTwoFactorRow
+	err := row.Scan(
+		&i.Email,
+		&i.Role,
+		&i.TotpSecret,
+		&i.TotpEnabled,
+		&i.TotpLastUsedStep,
+	)
+	return i, err
+}
@@ -0,0 +1,30 @@
+// Code generated by sqlc. DO NOT EDIT.
+// versions:
+//   sqlc v1.28.0
+// source: revoke_personal_token.sql
+
+package database
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+)
+
+const revokePersonalToken = `-- name: RevokePersonalToken :execrows
+UPDATE personal_tokens SET revoked_at = NOW()
+WHERE id = $1 AND user_id = $2 AND revoked_at IS NULL AND expires_at > NOW()
+`
+
+type RevokePersonalTokenParams struct {
+	ID     uuid.UUID
+	UserID uuid.UUID
+}
+
+func (q *Queries) RevokePersonalToken(ctx context.Context, arg RevokePersonalTokenParams) (int64, error) {
+	result, err := q.db.ExecContext(ctx, revokePersonalToken, arg.ID, arg.UserID)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
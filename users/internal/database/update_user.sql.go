@@ -13,7 +13,8 @@ import (
 )
 
 const updateUser = `-- name: UpdateUser :one
-UPDATE users SET login_name = $2, email = $3, birth_date = $4, hashed_password = $5, updated_at = NOW()
+UPDATE users SET login_name = $2, email = $3, birth_date = $4, hashed_password = $5,
+    email_verified = email_verified AND email = $3, updated_at = NOW()
 WHERE id = $1
 RETURNING 1
 `
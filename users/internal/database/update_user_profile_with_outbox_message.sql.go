@@ -0,0 +1,51 @@
+// Code generated by sqlc. DO NOT EDIT.
+// versions:
+//   sqlc v1.28.0
+// source: update_user_profile_with_outbox_message.sql
+
+package database
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+)
+
+const updateUserProfileWithOutboxMessage = `-- name: UpdateUserProfileWithOutboxMessage :execrows
+WITH updated AS (
+    UPDATE users SET display_name = $3, bio = $4, avatar_url = $5, locale = $6,
+        reading_list_public = $7, updated_at = NOW()
+    WHERE users.id = $8
+    RETURNING users.id
+)
+INSERT INTO outbox (topic, message_key, payload, created_at)
+SELECT $1::TEXT, updated.id::TEXT, $2::BYTEA, NOW() FROM updated
+`
+
+type UpdateUserProfileWithOutboxMessageParams struct {
+	Topic             string
+	Payload           []byte
+	DisplayName       string
+	Bio               string
+	AvatarUrl         string
+	Locale            string
+	ReadingListPublic bool
+	ID                uuid.UUID
+}
+
+func (q *Queries) UpdateUserProfileWithOutboxMessage(ctx context.Context, arg UpdateUserProfileWithOutboxMessageParams) (int64, error) {
+	result, err := q.db.ExecContext(ctx, updateUserProfileWithOutboxMessage,
+		arg.Topic,
+		arg.Payload,
+		arg.DisplayName,
+		arg.Bio,
+		arg.AvatarUrl,
+		arg.Locale,
+		arg.ReadingListPublic,
+		arg.ID,
+	)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
@@ -0,0 +1,33 @@
+// Code generated by sqlc. DO NOT EDIT.
+// versions:
+//   sqlc v1.28.0
+// source: use_oidc_state.sql
+
+package database
+
+import (
+	"context"
+)
+
+const useOIDCState = `-- name: UseOIDCState :one
+DELETE FROM oidc_states
+WHERE state_hash = $1 AND provider = $2 AND expires_at > NOW()
+RETURNING code_verifier, nonce
+`
+
+type UseOIDCStateParams struct {
+	StateHash string
+	Provider  string
+}
+
+type UseOIDCStateRow struct {
+	CodeVerifier string
+	Nonce        string
+}
+
+func (q *Queries) UseOIDCState(ctx context.Context, arg UseOIDCStateParams) (UseOIDCStateRow, error) {
+	row := q.db.QueryRowContext(ctx, useOIDCState, arg.StateHash, arg.Provider)
+	var i UseOIDCStateRow
+	err := row.Scan(&i.CodeVerifier, &i.Nonce)
+	return i, err
+}
@@ -0,0 +1,33 @@
+// Code generated by sqlc. DO NOT EDIT.
+// versions:
+//   sqlc v1.28.0
+// source: use_personal_token.sql
+
+package database
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+)
+
+const usePersonalToken = `-- name: UsePersonalToken :one
+UPDATE personal_tokens pt SET last_used_at = NOW()
+FROM users u
+WHERE pt.token_hash = $1 AND pt.revoked_at IS NULL AND pt.expires_at > NOW() AND u.id = pt.user_id
+RETURNING pt.user_id, u.role, pt.scopes
+`
+
+type UsePersonalTokenRow struct {
+	UserID uuid.UUID
+	Role   UserRole
+	Scopes []string
+}
+
+func (q *Queries) UsePersonalToken(ctx context.Context, tokenHash string) (UsePersonalTokenRow, error) {
+	row := q.db.QueryRowContext(ctx, usePersonalToken, tokenHash)
+	var i UsePersonalTokenRow
+	err := row.Scan(&i.UserID, &i.Role, pq.Array(&i.Scopes))
+	return i, err
+}
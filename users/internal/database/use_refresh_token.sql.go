@@ -0,0 +1,32 @@
+// Code generated by sqlc. DO NOT EDIT.
+// versions:
+//   sqlc v1.28.0
+// source: use_refresh_token.sql
+
+package database
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+)
+
+const useRefreshToken = `-- name: UseRefreshToken :one
+UPDATE refresh_tokens rt SET revoked_at = NOW(), updated_at = NOW()
+FROM users u
+WHERE rt.token = $1 AND u.id = rt.user_id AND rt.expires_at > NOW() AND rt.revoked_at IS NULL
+RETURNING rt.user_id, rt.family_id, u.role
+`
+
+type UseRefreshTokenRow struct {
+	UserID   uuid.UUID
+	FamilyID uuid.UUID
+	Role     UserRole
+}
+
+func (q *Queries) UseRefreshToken(ctx context.Context, token string) (UseRefreshTokenRow, error) {
+	row := q.db.QueryRowContext(ctx, useRefreshToken, token)
+	var i UseRefreshTokenRow
+	err := row.Scan(&i.UserID, &i.FamilyID, &i.Role)
+	return i, err
+}
@@ -0,0 +1,30 @@
+// Code generated by sqlc. DO NOT EDIT.
+// versions:
+//   sqlc v1.28.0
+// source: use_user_token.sql
+
+package database
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+)
+
+const useUserToken = `-- name: UseUserToken :one
+UPDATE user_tokens SET used_at = NOW()
+WHERE token_hash = $1 AND purpose = $2 AND expires_at > NOW() AND used_at IS NULL
+RETURNING user_id
+`
+
+type UseUserTokenParams struct {
+	TokenHash string
+	Purpose   UserTokenPurpose
+}
+
+func (q *Queries) UseUserToken(ctx context.Context, arg UseUserTokenParams) (uuid.UUID, error) {
+	row := q.db.QueryRowContext(ctx, useUserToken, arg.TokenHash, arg.Purpose)
+	var user_id uuid.UUID
+	err := row.Scan(&user_id)
+	return user_id, err
+}
@@ -0,0 +1,30 @@
+// Code generated by sqlc. DO NOT EDIT.
+// versions:
+//   sqlc v1.28.0
+// source: use_user_totp_step.sql
+
+package database
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+)
+
+const useUserTOTPStep = `-- name: UseUserTOTPStep :execrows
+UPDATE users SET totp_last_used_step = $2
+WHERE id = $1 AND totp_enabled AND totp_last_used_step < $2
+`
+
+type UseUserTOTPStepParams struct {
+	ID               uuid.UUID
+	TotpLastUsedStep int64
+}
+
+func (q *Queries) UseUserTOTPStep(ctx context.Context, arg UseUserTOTPStepParams) (int64, error) {
+	result, err := q.db.ExecContext(ctx, useUserTOTPStep, arg.ID, arg.TotpLastUsedStep)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
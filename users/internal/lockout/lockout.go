@@ -0,0 +1,39 @@
+package lockout
+
+import (
+	"math"
+	"time"
+)
+
+// Policy decides how long login is blocked after failed attempts. The first FreeAttempts failures don't block,
+// every next failure blocks for BaseDelay doubled with each failure up to MaxDelay. After LockoutAttempts failures
+// login is locked out for LockoutDuration. Failures are forgotten LockoutDuration after the last one.
+// Zero policy never blocks.
+type Policy struct {
+	FreeAttempts    int
+	BaseDelay       time.Duration
+	MaxDelay        time.Duration
+	LockoutAttempts int
+	LockoutDuration time.Duration
+}
+
+// Delay returns for how long login is blocked after the number of consecutive failures.
+func (p Policy) Delay(failures int) time.Duration {
+	if p.LockoutAttempts > 0 && failures >= p.LockoutAttempts {
+		return p.LockoutDuration
+	}
+	if failures <= p.FreeAttempts || p.BaseDelay <= 0 {
+		return 0
+	}
+	delay := p.BaseDelay
+	for i := p.FreeAttempts + 1; i < failures && delay < math.MaxInt64/2; i++ {
+		if p.MaxDelay > 0 && delay >= p.MaxDelay {
+			break
+		}
+		delay *= 2
+	}
+	if p.MaxDelay > 0 && delay > p.MaxDelay {
+		delay = p.MaxDelay
+	}
+	return delay
+}
@@ -0,0 +1,34 @@
+package lockout
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDelay(t *testing.T) {
+	policy := Policy{FreeAttempts: 3, BaseDelay: time.Second, MaxDelay: 10 * time.Second, LockoutAttempts: 10, LockoutDuration: 15 * time.Minute}
+	type testCase struct {
+		name          string
+		policy        Policy
+		failures      int
+		expectedDelay time.Duration
+	}
+	testCases := []testCase{
+		{name: "no_failures", policy: policy, failures: 0, expectedDelay: 0},
+		{name: "free_attempts", policy: policy, failures: 3, expectedDelay: 0},
+		{name: "first_delay", policy: policy, failures: 4, expectedDelay: time.Second},
+		{name: "doubled_delay", policy: policy, failures: 6, expectedDelay: 4 * time.Second},
+		{name: "max_delay", policy: policy, failures: 9, expectedDelay: 10 * time.Second},
+		{name: "lockout", policy: policy, failures: 10, expectedDelay: 15 * time.Minute},
+		{name: "after_lockout", policy: policy, failures: 100, expectedDelay: 15 * time.Minute},
+		{name: "no_lockout", policy: Policy{FreeAttempts: 3, BaseDelay: time.Second, MaxDelay: 10 * time.Second}, failures: 100, expectedDelay: 10 * time.Second},
+		{name: "zero_policy", policy: Policy{}, failures: 100, expectedDelay: 0},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Equal(t, tc.policy.Delay(tc.failures), tc.expectedDelay)
+		})
+	}
+}
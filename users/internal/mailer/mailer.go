@@ -0,0 +1,102 @@
+package mailer
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"log"
+	"mime"
+	"net"
+	"net/smtp"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+type Message struct {
+	To      string
+	Subject string
+	Body    string
+}
+
+// Mailer sends emails to users, e.g. password reset and email verification links.
+type Mailer interface {
+	Send(message Message) error
+}
+
+// SMTPMailer sends messages through an SMTP server. PLAIN auth is used if Username is set,
+// net/smtp allows it only over TLS or to localhost.
+type SMTPMailer struct {
+	Addr     string
+	From     string
+	Username string
+	Password string
+}
+
+func (m *SMTPMailer) Send(message Message) error {
+	data, err := formatMessage(m.From, message, time.Now())
+	if err != nil {
+		return err
+	}
+	var auth smtp.Auth
+	if m.Username != "" {
+		host, _, splitErr := net.SplitHostPort(m.Addr)
+		if splitErr != nil {
+			return splitErr
+		}
+		auth = smtp.PlainAuth("", m.Username, m.Password, host)
+	}
+	return smtp.SendMail(m.Addr, auth, m.From, []string{message.To}, data)
+}
+
+// FileMailer writes every message into a separate .eml file in Dir instead of sending it. It is meant for local
+// development, the files can be opened with any mail client.
+type FileMailer struct {
+	Dir  string
+	From string
+}
+
+func (m *FileMailer) Send(message Message) error {
+	now := time.Now()
+	data, err := formatMessage(m.From, message, now)
+	if err != nil {
+		return err
+	}
+	err = os.MkdirAll(m.Dir, 0o700)
+	if err != nil {
+		return err
+	}
+	name := fmt.Sprintf("%v-%v.eml", now.UTC().Format("20060102T150405.000000000"), strings.ReplaceAll(message.To, "@", "_at_"))
+	return os.WriteFile(filepath.Join(m.Dir, filepath.Base(name)), data, 0o600)
+}
+
+// LogMailer writes messages to the service log. Links with tokens end up in the log too, so it must not be used
+// in production.
+type LogMailer struct{}
+
+func (m *LogMailer) Send(message Message) error {
+	log.Printf("Mail to %v\nSubject: %v\n\n%v", message.To, message.Subject, message.Body)
+	return nil
+}
+
+// formatMessage makes a plain text RFC 5322 message with CRLF line endings.
+func formatMessage(from string, message Message, date time.Time) ([]byte, error) {
+	for _, header := range []string{from, message.To, message.Subject} {
+		if strings.ContainsAny(header, "\r\n") {
+			return nil, errors.New("line break in mail header")
+		}
+	}
+
+	buf := bytes.Buffer{}
+	fmt.Fprintf(&buf, "From: %v\r\n", from)
+	fmt.Fprintf(&buf, "To: %v\r\n", message.To)
+	fmt.Fprintf(&buf, "Subject: %v\r\n", mime.QEncoding.Encode("utf-8", message.Subject))
+	fmt.Fprintf(&buf, "Date: %v\r\n", date.Format(time.RFC1123Z))
+	buf.WriteString("MIME-Version: 1.0\r\n")
+	buf.WriteString("Content-Type: text/plain; charset=UTF-8\r\n")
+	buf.WriteString("\r\n")
+	body := strings.ReplaceAll(message.Body, "\r\n", "\n")
+	buf.WriteString(strings.ReplaceAll(body, "\n", "\r\n"))
+	return buf.Bytes(), nil
+}
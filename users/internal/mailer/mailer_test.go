@@ -0,0 +1,57 @@
+package mailer
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFormatMessage(t *testing.T) {
+	date := time.Date(2026, 10, 17, 12, 0, 0, 0, time.UTC)
+	type testCase struct {
+		name          string
+		message       Message
+		expectedData  string
+		expectedError bool
+	}
+	testCases := []testCase{
+		{
+			name:    "success",
+			message: Message{To: "user@email.com", Subject: "Reset password", Body: "Hello!\nLink"},
+			expectedData: "From: mylib@email.com\r\nTo: user@email.com\r\nSubject: Reset password\r\n" +
+				"Date: Sat, 17 Oct 2026 12:00:00 +0000\r\nMIME-Version: 1.0\r\nContent-Type: text/plain; charset=UTF-8\r\n\r\nHello!\r\nLink",
+		},
+		{
+			name:          "header_injection",
+			message:       Message{To: "user@email.com\r\nBcc: another@email.com", Subject: "Reset password", Body: "Hello!"},
+			expectedError: true,
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			data, err := formatMessage("mylib@email.com", tc.message, date)
+			assert.Equal(t, err != nil, tc.expectedError)
+			assert.Equal(t, string(data), tc.expectedData)
+		})
+	}
+}
+
+func TestFileMailer(t *testing.T) {
+	dir := filepath.Join(t.TempDir(), "mail")
+	m := FileMailer{Dir: dir, From: "mylib@email.com"}
+	err := m.Send(Message{To: "user@email.com", Subject: "Verify email", Body: "Link"})
+	assert.NoError(t, err)
+
+	files, err := os.ReadDir(dir)
+	assert.NoError(t, err)
+	assert.Equal(t, len(files), 1)
+	assert.True(t, strings.HasSuffix(files[0].Name(), "-user_at_email.com.eml"))
+	data, err := os.ReadFile(filepath.Join(dir, files[0].Name()))
+	assert.NoError(t, err)
+	assert.Contains(t, string(data), "To: user@email.com\r\n")
+	assert.True(t, strings.HasSuffix(string(data), "\r\n\r\nLink"))
+}
@@ -0,0 +1,214 @@
+// Package oidc implements login with an OpenID Connect provider by authorization code flow with PKCE.
+package oidc
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/bakurvik/mylib/shared/jwks"
+	"github.com/golang-jwt/jwt/v5"
+)
+
+const (
+	discoveryPath  = "/.well-known/openid-configuration"
+	requestTimeout = 10 * time.Second
+)
+
+var defaultScopes = []string{"openid", "email", "profile"}
+
+// Config is the client registration of the service at a provider.
+type Config struct {
+	Name         string
+	Issuer       string
+	ClientID     string
+	ClientSecret string
+	// RedirectURL is the callback of users service the provider sends the user back to.
+	RedirectURL string
+	Scopes      []string
+}
+
+type metadata struct {
+	Issuer                string `json:"issuer"`
+	AuthorizationEndpoint string `json:"authorization_endpoint"`
+	TokenEndpoint         string `json:"token_endpoint"`
+	JWKSURI               string `json:"jwks_uri"`
+}
+
+// Claims are the claims of ID token that identify the user.
+type Claims struct {
+	Email             string `json:"email"`
+	EmailVerified     bool   `json:"email_verified"`
+	Name              string `json:"name"`
+	PreferredUsername string `json:"preferred_username"`
+	Nonce             string `json:"nonce"`
+	jwt.RegisteredClaims
+}
+
+// Provider is an OpenID Connect provider. Its endpoints are discovered from the issuer on first use,
+// so the service starts even if the provider is unavailable.
+type Provider struct {
+	config Config
+	client *http.Client
+
+	mu       sync.Mutex
+	metadata *metadata
+	keys     *jwks.Cache
+}
+
+func NewProvider(config Config) *Provider {
+	if len(config.Scopes) == 0 {
+		config.Scopes = defaultScopes
+	}
+	return &Provider{config: config, client: &http.Client{Timeout: requestTimeout}}
+}
+
+func (p *Provider) Name() string {
+	return p.config.Name
+}
+
+// discover fetches provider metadata once. A failed attempt is repeated on the next call.
+func (p *Provider) discover(ctx context.Context) (*metadata, *jwks.Cache, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.metadata != nil {
+		return p.metadata, p.keys, nil
+	}
+
+	request, err := http.NewRequestWithContext(ctx, http.MethodGet, strings.TrimSuffix(p.config.Issuer, "/")+discoveryPath, nil)
+	if err != nil {
+		return nil, nil, err
+	}
+	response, err := p.client.Do(request)
+	if err != nil {
+		return nil, nil, err
+	}
+	defer response.Body.Close()
+	if response.StatusCode != http.StatusOK {
+		return nil, nil, fmt.Errorf("discovery: unexpected status %v", response.StatusCode)
+	}
+	m := metadata{}
+	err = json.NewDecoder(response.Body).Decode(&m)
+	if err != nil {
+		return nil, nil, err
+	}
+	if m.Issuer != p.config.Issuer {
+		return nil, nil, fmt.Errorf("discovery: issuer %v doesn't match %v", m.Issuer, p.config.Issuer)
+	}
+	if m.AuthorizationEndpoint == "" || m.TokenEndpoint == "" || m.JWKSURI == "" {
+		return nil, nil, errors.New("discovery: missing endpoints")
+	}
+
+	p.metadata = &m
+	p.keys = jwks.NewCache(m.JWKSURI, jwks.DefaultTTL)
+	return p.metadata, p.keys, nil
+}
+
+// AuthCodeURL returns the provider page where the user logs in. State and nonce bind the callback and the ID token
+// to this login attempt, code challenge is made from the PKCE code verifier.
+func (p *Provider) AuthCodeURL(ctx context.Context, state string, nonce string, codeVerifier string) (string, error) {
+	m, _, err := p.discover(ctx)
+	if err != nil {
+		return "", err
+	}
+	u, err := url.Parse(m.AuthorizationEndpoint)
+	if err != nil {
+		return "", err
+	}
+	query := u.Query()
+	query.Set("response_type", "code")
+	query.Set("client_id", p.config.ClientID)
+	query.Set("redirect_uri", p.config.RedirectURL)
+	query.Set("scope", strings.Join(p.config.Scopes, " "))
+	query.Set("state", state)
+	query.Set("nonce", nonce)
+	query.Set("code_challenge", CodeChallenge(codeVerifier))
+	query.Set("code_challenge_method", "S256")
+	u.RawQuery = query.Encode()
+	return u.String(), nil
+}
+
+// Exchange exchanges authorization code for tokens and returns verified claims of the ID token.
+func (p *Provider) Exchange(ctx context.Context, code string, codeVerifier string, nonce string) (*Claims, error) {
+	m, keys, err := p.discover(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	form := url.Values{}
+	form.Set("grant_type", "authorization_code")
+	form.Set("code", code)
+	form.Set("redirect_uri", p.config.RedirectURL)
+	form.Set("code_verifier", codeVerifier)
+	request, err := http.NewRequestWithContext(ctx, http.MethodPost, m.TokenEndpoint, strings.NewReader(form.Encode()))
+	if err != nil {
+		return nil, err
+	}
+	request.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	request.SetBasicAuth(url.QueryEscape(p.config.ClientID), url.QueryEscape(p.config.ClientSecret))
+	response, err := p.client.Do(request)
+	if err != nil {
+		return nil, err
+	}
+	defer response.Body.Close()
+	if response.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("token: unexpected status %v", response.StatusCode)
+	}
+	tokens := struct {
+		IDToken string `json:"id_token"`
+	}{}
+	err = json.NewDecoder(response.Body).Decode(&tokens)
+	if err != nil {
+		return nil, err
+	}
+	if tokens.IDToken == "" {
+		return nil, errors.New("token: no id_token in response")
+	}
+
+	return p.verifyIDToken(tokens.IDToken, keys, nonce)
+}
+
+// verifyIDToken checks signature, issuer, audience, expiration and nonce of ID token.
+func (p *Provider) verifyIDToken(idToken string, keys *jwks.Cache, nonce string) (*Claims, error) {
+	claims := Claims{}
+	_, err := jwt.ParseWithClaims(idToken, &claims, keys.Keyfunc,
+		jwt.WithValidMethods(jwks.Algorithms),
+		jwt.WithIssuer(p.config.Issuer),
+		jwt.WithAudience(p.config.ClientID),
+		jwt.WithExpirationRequired())
+	if err != nil {
+		return nil, err
+	}
+	if claims.Subject == "" {
+		return nil, errors.New("id token: no subject")
+	}
+	if claims.Nonce != nonce {
+		return nil, errors.New("id token: nonce doesn't match")
+	}
+	return &claims, nil
+}
+
+// MakeRandomString returns a random URL-safe string for state, nonce and PKCE code verifier.
+func MakeRandomString() (string, error) {
+	buf := make([]byte, 32)
+	_, err := rand.Read(buf)
+	if err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(buf), nil
+}
+
+// CodeChallenge returns S256 PKCE code challenge of the code verifier.
+func CodeChallenge(codeVerifier string) string {
+	sum := sha256.Sum256([]byte(codeVerifier))
+	return base64.RawURLEncoding.EncodeToString(sum[:])
+}
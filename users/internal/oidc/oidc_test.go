@@ -0,0 +1,161 @@
+package oidc
+
+import (
+	"context"
+	"crypto/ed25519"
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/stretchr/testify/assert"
+)
+
+// stubProvider is an OpenID Connect provider that issues ID token for code "code" if PKCE verifier matches challenge.
+type stubProvider struct {
+	server        *httptest.Server
+	privateKey    ed25519.PrivateKey
+	codeChallenge string
+	claims        Claims
+}
+
+func newStubProvider(t *testing.T) *stubProvider {
+	publicKey, privateKey, _ := ed25519.GenerateKey(rand.Reader)
+	stub := &stubProvider{privateKey: privateKey}
+	sm := http.NewServeMux()
+	sm.HandleFunc("GET "+discoveryPath, func(w http.ResponseWriter, r *http.Request) {
+		json.NewEncoder(w).Encode(metadata{
+			Issuer:                stub.server.URL,
+			AuthorizationEndpoint: stub.server.URL + "/authorize",
+			TokenEndpoint:         stub.server.URL + "/token",
+			JWKSURI:               stub.server.URL + "/jwks",
+		})
+	})
+	sm.HandleFunc("GET /jwks", func(w http.ResponseWriter, r *http.Request) {
+		json.NewEncoder(w).Encode(map[string]any{"keys": []map[string]string{
+			{"kty": "OKP", "crv": "Ed25519", "x": base64.RawURLEncoding.EncodeToString(publicKey), "kid": "stub", "use": "sig"},
+		}})
+	})
+	sm.HandleFunc("POST /token", func(w http.ResponseWriter, r *http.Request) {
+		clientID, clientSecret, _ := r.BasicAuth()
+		if clientID != "client" || clientSecret != "secret" || r.FormValue("code") != "code" ||
+			CodeChallenge(r.FormValue("code_verifier")) != stub.codeChallenge {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		token := jwt.NewWithClaims(jwt.SigningMethodEdDSA, stub.claims)
+		token.Header["kid"] = "stub"
+		idToken, err := token.SignedString(stub.privateKey)
+		assert.NoError(t, err)
+		json.NewEncoder(w).Encode(map[string]string{"id_token": idToken, "access_token": "access"})
+	})
+	stub.server = httptest.NewServer(sm)
+	return stub
+}
+
+func TestLoginFlow(t *testing.T) {
+	stub := newStubProvider(t)
+	defer stub.server.Close()
+	validClaims := func() Claims {
+		return Claims{
+			Email:         "user@email.com",
+			EmailVerified: true,
+			Nonce:         "nonce",
+			RegisteredClaims: jwt.RegisteredClaims{
+				Issuer:    stub.server.URL,
+				Subject:   "subject",
+				Audience:  jwt.ClaimStrings{"client"},
+				ExpiresAt: jwt.NewNumericDate(time.Now().Add(time.Minute)),
+			},
+		}
+	}
+
+	type testCase struct {
+		name          string
+		claims        func() Claims
+		codeVerifier  string
+		nonce         string
+		expectedError bool
+	}
+	testCases := []testCase{
+		{name: "success", claims: validClaims, codeVerifier: "verifier", nonce: "nonce"},
+		{name: "wrong_code_verifier", claims: validClaims, codeVerifier: "another", nonce: "nonce", expectedError: true},
+		{name: "wrong_nonce", claims: validClaims, codeVerifier: "verifier", nonce: "another", expectedError: true},
+		{
+			name: "wrong_audience",
+			claims: func() Claims {
+				claims := validClaims()
+				claims.Audience = jwt.ClaimStrings{"another"}
+				return claims
+			},
+			codeVerifier:  "verifier",
+			nonce:         "nonce",
+			expectedError: true,
+		},
+		{
+			name: "wrong_issuer",
+			claims: func() Claims {
+				claims := validClaims()
+				claims.Issuer = "https://another.example.com"
+				return claims
+			},
+			codeVerifier:  "verifier",
+			nonce:         "nonce",
+			expectedError: true,
+		},
+		{
+			name: "expired",
+			claims: func() Claims {
+				claims := validClaims()
+				claims.ExpiresAt = jwt.NewNumericDate(time.Now().Add(-time.Minute))
+				return claims
+			},
+			codeVerifier:  "verifier",
+			nonce:         "nonce",
+			expectedError: true,
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			provider := NewProvider(Config{Name: "stub", Issuer: stub.server.URL, ClientID: "client", ClientSecret: "secret", RedirectURL: "http://users.test/callback"})
+
+			authURL, err := provider.AuthCodeURL(context.Background(), "state", "nonce", "verifier")
+			assert.NoError(t, err)
+			u, _ := url.Parse(authURL)
+			assert.Equal(t, u.Path, "/authorize")
+			assert.Equal(t, u.Query().Get("client_id"), "client")
+			assert.Equal(t, u.Query().Get("redirect_uri"), "http://users.test/callback")
+			assert.Equal(t, u.Query().Get("scope"), "openid email profile")
+			assert.Equal(t, u.Query().Get("state"), "state")
+			assert.Equal(t, u.Query().Get("code_challenge_method"), "S256")
+			stub.codeChallenge = u.Query().Get("code_challenge")
+			stub.claims = tc.claims()
+
+			claims, err := provider.Exchange(context.Background(), "code", tc.codeVerifier, tc.nonce)
+			assert.Equal(t, err != nil, tc.expectedError)
+			if !tc.expectedError {
+				assert.Equal(t, claims.Subject, "subject")
+				assert.Equal(t, claims.Email, "user@email.com")
+				assert.True(t, claims.EmailVerified)
+			}
+		})
+	}
+}
+
+func TestDiscoveryIssuerMismatch(t *testing.T) {
+	stub := newStubProvider(t)
+	defer stub.server.Close()
+	provider := NewProvider(Config{Name: "stub", Issuer: stub.server.URL + "/another", ClientID: "client"})
+	_, err := provider.AuthCodeURL(context.Background(), "state", "nonce", "verifier")
+	assert.Error(t, err)
+}
+
+func TestCodeChallenge(t *testing.T) {
+	// Example from RFC 7636 appendix B.
+	assert.Equal(t, CodeChallenge("dBjftJeZ4CVP-mB92K27uhbUJU1p1r_wW1gFWFOEjXk"), "E9Melhoa2OwvFrEMTJguCHaoeK1t8URWbuGJSstw-cM")
+}
@@ -0,0 +1,237 @@
+package server
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"net/url"
+	"time"
+
+	"github.com/bakurvik/mylib/users/internal/auth"
+	"github.com/bakurvik/mylib/users/internal/database"
+	"github.com/bakurvik/mylib/users/internal/mailer"
+
+	common "github.com/bakurvik/mylib-common"
+	"github.com/google/uuid"
+)
+
+const (
+	passwordResetTokenExpiresIn     = time.Hour
+	emailVerificationTokenExpiresIn = 24 * time.Hour
+)
+
+// makeUserToken stores hash of a new one-time token and returns the token itself to be sent to the user.
+func makeUserToken(ctx context.Context, cfg *ApiConfig, userID uuid.UUID, purpose database.UserTokenPurpose, expiresIn time.Duration) (string, error) {
+	token, tokenHash, err := auth.MakeOneTimeToken()
+	if err != nil {
+		return "", err
+	}
+	err = cfg.DB.CreateUserToken(ctx, database.CreateUserTokenParams{
+		TokenHash: tokenHash,
+		UserID:    userID,
+		Purpose:   purpose,
+		ExpiresAt: time.Now().Add(expiresIn),
+	})
+	if err != nil {
+		return "", err
+	}
+	return token, nil
+}
+
+// withToken adds token query parameter to the link.
+func withToken(link string, token string) (string, error) {
+	u, err := url.Parse(link)
+	if err != nil {
+		return "", err
+	}
+	query := u.Query()
+	query.Set("token", token)
+	u.RawQuery = query.Encode()
+	return u.String(), nil
+}
+
+// sendMail sends the message in background, so the response time doesn't depend on the mail server
+// and doesn't reveal whether a message was sent at all.
+func sendMail(cfg *ApiConfig, message mailer.Message) {
+	if cfg.Mailer == nil {
+		log.Printf("No mailer configured, mail to %v is not sent", message.To)
+		return
+	}
+	go func() {
+		err := cfg.Mailer.Send(message)
+		if err != nil {
+			log.Printf("Failed to send mail to %v: %v", message.To, err)
+		}
+	}()
+}
+
+func sendVerificationEmail(cfg *ApiConfig, r *http.Request, userID uuid.UUID, email string) error {
+	token, tokenErr := makeUserToken(r.Context(), cfg, userID, database.UserTokenPurposeEmailVerification, emailVerificationTokenExpiresIn)
+	if tokenErr != nil {
+		return tokenErr
+	}
+	link, linkErr := withToken(cfg.PublicURL+AuthVerifyEmailPath, token)
+	if linkErr != nil {
+		return linkErr
+	}
+	sendMail(cfg, mailer.Message{
+		To:      email,
+		Subject: "Confirm your email",
+		Body:    fmt.Sprintf("Open the link to confirm your email:\n%v\n\nThe link is valid for %d hours.", link, int(emailVerificationTokenExpiresIn.Hours())),
+	})
+	return nil
+}
+
+// sendPasswordResetEmail replaces password reset token of the user with a new one and mails the link, unknown emails are ignored.
+func sendPasswordResetEmail(ctx context.Context, cfg *ApiConfig, email string) error {
+	user, err := cfg.DB.GetUserByEmail(ctx, email)
+	if err == sql.ErrNoRows {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	err = cfg.DB.InvalidateUserTokens(ctx, database.InvalidateUserTokensParams{UserID: user.ID, Purpose: database.UserTokenPurposePasswordReset})
+	if err != nil {
+		return err
+	}
+	token, err := makeUserToken(ctx, cfg, user.ID, database.UserTokenPurposePasswordReset, passwordResetTokenExpiresIn)
+	if err != nil {
+		return err
+	}
+	link, err := withToken(cfg.PasswordResetURL, token)
+	if err != nil {
+		return err
+	}
+
+	sendMail(cfg, mailer.Message{
+		To:      email,
+		Subject: "Reset password",
+		Body: fmt.Sprintf("Hello, %v!\n\nOpen the link to set a new password:\n%v\n\nThe link is valid for %d minutes. If you didn't ask to reset the password, ignore this email.",
+			user.LoginName, link, int(passwordResetTokenExpiresIn.Minutes())),
+	})
+	return nil
+}
+
+// @Summary Request password reset
+// @Description Sends a link with password reset token to user's email. Responds the same way whether the user exists or not. Only the latest link is valid
+// @Tags Auth
+// @Accept json
+// @Produce json
+// @Param request body RequestPasswordReset true "User's email"
+// @Success 202
+// @Failure 400 {object} ErrorResponse "Invalid request body"
+// @Router /auth/password-reset/request [post]
+func (cfg *ApiConfig) HandlePostAuthPasswordResetRequest(w http.ResponseWriter, r *http.Request) {
+	decoder := json.NewDecoder(r.Body)
+	request := RequestPasswordReset{}
+	requestErr := decoder.Decode(&request)
+	if requestErr != nil {
+		common.RespondWithError(w, http.StatusBadRequest, requestErr.Error())
+		return
+	}
+
+	// The user is looked up and the token is made in background too,
+	// so the response time doesn't reveal whether the email is registered.
+	go func() {
+		err := sendPasswordResetEmail(context.Background(), cfg, request.Email)
+		if err != nil {
+			log.Printf("Failed to send password reset email to %v: %v", request.Email, err)
+		}
+	}()
+	w.WriteHeader(http.StatusAccepted)
+}
+
+// @Summary Reset password
+// @Description Sets a new password by token from password reset link. The token can be used once, all sessions of the user are revoked
+// @Tags Auth
+// @Accept json
+// @Produce json
+// @Param request body RequestPasswordResetConfirm true "Reset token and new password"
+// @Success 204
+// @Failure 400 {object} ErrorResponse "Invalid or expired token"
+// @Failure 500 {object} ErrorResponse
+// @Router /auth/password-reset/confirm [post]
+func (cfg *ApiConfig) HandlePostAuthPasswordResetConfirm(w http.ResponseWriter, r *http.Request) {
+	decoder := json.NewDecoder(r.Body)
+	request := RequestPasswordResetConfirm{}
+	requestErr := decoder.Decode(&request)
+	if requestErr != nil {
+		common.RespondWithError(w, http.StatusBadRequest, requestErr.Error())
+		return
+	}
+	passwordErr := validatePassword(request.Password)
+	if passwordErr != nil {
+		common.RespondWithError(w, http.StatusBadRequest, passwordErr.Error())
+		return
+	}
+
+	// The token is marked as used in the same statement that checks it, so it can be used only once.
+	userID, useTokenErr := cfg.DB.UseUserToken(r.Context(), database.UseUserTokenParams{TokenHash: auth.HashToken(request.Token), Purpose: database.UserTokenPurposePasswordReset})
+	if useTokenErr == sql.ErrNoRows {
+		common.RespondWithError(w, http.StatusBadRequest, "Invalid or expired token")
+		return
+	}
+	if useTokenErr != nil {
+		common.RespondWithError(w, http.StatusInternalServerError, useTokenErr.Error())
+		return
+	}
+
+	hashedPassword, hashErr := auth.HashPassword(request.Password)
+	if hashErr != nil {
+		common.RespondWithError(w, http.StatusInternalServerError, hashErr.Error())
+		return
+	}
+	updateErr := cfg.DB.UpdateUserPassword(r.Context(), database.UpdateUserPasswordParams{ID: userID, HashedPassword: hashedPassword})
+	if updateErr != nil {
+		common.RespondWithError(w, http.StatusInternalServerError, updateErr.Error())
+		return
+	}
+
+	count, revokeErr := cfg.DB.RevokeUserRefreshTokens(r.Context(), database.RevokeUserRefreshTokensParams{UserID: userID})
+	if revokeErr != nil {
+		common.RespondWithError(w, http.StatusInternalServerError, revokeErr.Error())
+		return
+	}
+	log.Printf("Password of user %v is reset, revoked %d refresh tokens", userID, count)
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// @Summary Verify email
+// @Description Confirms user's email by token from email verification link. The token can be used once
+// @Tags Auth
+// @Produce json
+// @Param token query string true "Email verification token"
+// @Success 204
+// @Failure 400 {object} ErrorResponse "Invalid or expired token"
+// @Failure 500 {object} ErrorResponse
+// @Router /auth/verify-email [get]
+func (cfg *ApiConfig) HandleGetAuthVerifyEmail(w http.ResponseWriter, r *http.Request) {
+	token := r.URL.Query().Get("token")
+	if token == "" {
+		common.RespondWithError(w, http.StatusBadRequest, "No token in request")
+		return
+	}
+
+	userID, useTokenErr := cfg.DB.UseUserToken(r.Context(), database.UseUserTokenParams{TokenHash: auth.HashToken(token), Purpose: database.UserTokenPurposeEmailVerification})
+	if useTokenErr == sql.ErrNoRows {
+		common.RespondWithError(w, http.StatusBadRequest, "Invalid or expired token")
+		return
+	}
+	if useTokenErr != nil {
+		common.RespondWithError(w, http.StatusInternalServerError, useTokenErr.Error())
+		return
+	}
+
+	verifyErr := cfg.DB.VerifyUserEmail(r.Context(), userID)
+	if verifyErr != nil {
+		common.RespondWithError(w, http.StatusInternalServerError, verifyErr.Error())
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
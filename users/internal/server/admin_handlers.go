@@ -0,0 +1,83 @@
+package server
+
+import (
+	"database/sql"
+	"encoding/json"
+	"net/http"
+
+	"github.com/bakurvik/mylib/users/internal/auth"
+	"github.com/bakurvik/mylib/users/internal/database"
+
+	common "github.com/bakurvik/mylib-common"
+	"github.com/google/uuid"
+)
+
+// @Summary Set user role
+// @Description Sets user's role: reader, editor or admin. The role gets into user's access tokens after the next login or refresh. Editor and admin roles can be given only to users with enabled 2FA. Requires admin role
+// @Tags Admin
+// @Accept json
+// @Produce json
+// @Param userID path string true "User ID"
+// @Param request body RequestUserRole true "User's role"
+// @Success 204
+// @Failure 400 {object} ErrorResponse "Invalid user ID or role"
+// @Failure 401 {object} ErrorResponse "Unauthorized"
+// @Failure 403 {object} ErrorResponse "Forbidden"
+// @Failure 404 {object} ErrorResponse "User not found"
+// @Failure 409 {object} ErrorResponse "User must enable two-factor authentication first"
+// @Failure 500 {object} ErrorResponse
+// @Router /admin/users/{userID}/role [put]
+func (cfg *ApiConfig) HandlePutAdminUsersRole(w http.ResponseWriter, r *http.Request) {
+	_, role, authErr := checkAuthorization(cfg, r)
+	if authErr != nil {
+		common.RespondWithError(w, http.StatusUnauthorized, authErr.Error())
+		return
+	}
+	if role != auth.RoleAdmin {
+		common.RespondWithError(w, http.StatusForbidden, "Forbidden")
+		return
+	}
+
+	userID, parseErr := uuid.Parse(r.PathValue("userID"))
+	if parseErr != nil {
+		common.RespondWithError(w, http.StatusBadRequest, "Invalid user id")
+		return
+	}
+
+	decoder := json.NewDecoder(r.Body)
+	request := RequestUserRole{}
+	requestErr := decoder.Decode(&request)
+	if requestErr != nil || !auth.IsValidRole(request.Role) {
+		common.RespondWithError(w, http.StatusBadRequest, "Invalid role")
+		return
+	}
+
+	// Editors and admins change the catalogue and other users, so a stolen password must not be enough to act as them.
+	if request.Role != auth.RoleReader {
+		user, userErr := cfg.DB.GetUserTwoFactor(r.Context(), userID)
+		if userErr == sql.ErrNoRows {
+			common.RespondWithError(w, http.StatusNotFound, "User not found")
+			return
+		}
+		if userErr != nil {
+			common.RespondWithError(w, http.StatusInternalServerError, userErr.Error())
+			return
+		}
+		if !user.TotpEnabled {
+			common.RespondWithError(w, http.StatusConflict, "User must enable two-factor authentication first")
+			return
+		}
+	}
+
+	rowsCount, updateErr := cfg.DB.UpdateUserRole(r.Context(), database.UpdateUserRoleParams{ID: userID, Role: database.UserRole(request.Role)})
+	if updateErr != nil {
+		common.RespondWithError(w, http.StatusInternalServerError, updateErr.Error())
+		return
+	}
+	if rowsCount == 0 {
+		common.RespondWithError(w, http.StatusNotFound, "User not found")
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
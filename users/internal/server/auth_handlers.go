@@ -3,6 +3,7 @@ package server
 import (
 	"database/sql"
 	"encoding/json"
+	"fmt"
 	"net/http"
 	"time"
 
@@ -20,10 +21,17 @@ const (
 	tokenExpiresIn        = time.Hour
 	refreshTokenExpiresIn = 30 * 24 * time.Hour
 	refreshTokenName      = "refresh_token"
+	jwksMaxAge            = 5 * time.Minute
 )
 
-func makeTokensAndRespond(w http.ResponseWriter, r *http.Request, cfg *ApiConfig, userID uuid.UUID, status int) {
-	accessToken, accessTokenErr := auth.MakeJWT(userID, cfg.AuthSecretKey, tokenExpiresIn)
+// tokenFamily links a new refresh token to the token it replaces. Zero value starts a new family, e.g. on login.
+type tokenFamily struct {
+	id          uuid.UUID
+	parentToken sql.NullString
+}
+
+func makeTokensAndRespond(w http.ResponseWriter, r *http.Request, cfg *ApiConfig, userID uuid.UUID, role string, family tokenFamily, status int) {
+	accessToken, accessTokenErr := cfg.Keys.MakeJWT(userID, role, tokenExpiresIn)
 	if accessTokenErr != nil {
 		common.RespondWithError(w, http.StatusInternalServerError, accessTokenErr.Error())
 		return
@@ -35,7 +43,18 @@ func makeTokensAndRespond(w http.ResponseWriter, r *http.Request, cfg *ApiConfig
 		common.RespondWithError(w, http.StatusInternalServerError, refreshTokenErr.Error())
 		return
 	}
-	saveTokenErr := cfg.DB.CreateRefreshToken(r.Context(), database.CreateRefreshTokenParams{Token: refreshToken, UserID: userID, ExpiresAt: expiresAt})
+	if family.id == uuid.Nil {
+		family.id = uuid.New()
+	}
+	saveTokenErr := cfg.DB.CreateRefreshToken(r.Context(), database.CreateRefreshTokenParams{
+		Token:       refreshToken,
+		UserID:      userID,
+		ExpiresAt:   expiresAt,
+		FamilyID:    family.id,
+		ParentToken: family.parentToken,
+		UserAgent:   r.UserAgent(),
+		IpAddress:   clientIP(r),
+	})
 	if saveTokenErr != nil {
 		common.RespondWithError(w, http.StatusInternalServerError, saveTokenErr.Error())
 		return
@@ -60,17 +79,44 @@ func revokeRefreshToken(cfg *ApiConfig, r *http.Request, refreshToken string) {
 	}
 }
 
+// checkRefreshTokenReuse revokes the whole family of a revoked refresh token that is presented again.
+// Only the latest token of a family is valid, so reuse means that an old token was stolen and replayed
+// by either the attacker or the user, and all tokens derived from it can't be trusted anymore.
+func checkRefreshTokenReuse(cfg *ApiConfig, r *http.Request, refreshToken string) {
+	token, err := cfg.DB.GetRefreshToken(r.Context(), refreshToken)
+	if err != nil {
+		if err != sql.ErrNoRows {
+			log.Print("Failed to get refresh token: ", err)
+		}
+		return
+	}
+	if !token.RevokedAt.Valid {
+		return
+	}
+
+	count, err := cfg.DB.RevokeRefreshTokenFamily(r.Context(), token.FamilyID)
+	if err != nil {
+		log.Print("Failed to revoke refresh token family: ", err)
+		return
+	}
+	log.Printf("Security event: reuse of revoked refresh token of user %v from %v, revoked %d tokens of family %v",
+		token.UserID, r.RemoteAddr, count, token.FamilyID)
+}
+
 // @Summary Login user
-// @Description Checks password and returns access and refresh tokens
+// @Description Checks password and returns access and refresh tokens. If the user has enabled 2FA, returns a challenge token for /auth/2fa/verify instead. Failed attempts are counted per account and per IP address, after a few of them login is blocked for a growing delay and then locked out for a while
 // @Tags Auth
 // @Accept json
 // @Produce json
 // @Param request body RequestLogin true "User's login data"
 // @Success 200 {object} ResponseToken "Logined successfully"
 // @Header 200 {string} Set-Cookie "HTTP-only cookie named refresh_token"
+// @Success 202 {object} ResponseTwoFactorChallenge "Password is correct, two-factor code is required"
 // @Failure 400 {object} ErrorResponse "Invalid request body"
-// @Failure 401 {object} ErrorResponse "Invalid password"
-// @Failure 404 {object} ErrorResponse "User not found"
+// @Failure 401 {object} ErrorResponse "Invalid email or password"
+// @Header 401 {integer} Retry-After "Seconds until login is unblocked, if the attempt blocked it"
+// @Failure 429 {object} ErrorResponse "Too many failed login attempts"
+// @Header 429 {integer} Retry-After "Seconds until login is unblocked"
 // @Failure 500 {object} ErrorResponse
 // @Router /auth/login [post]
 func (cfg *ApiConfig) HandlePostAuthLogin(w http.ResponseWriter, r *http.Request) {
@@ -82,27 +128,56 @@ func (cfg *ApiConfig) HandlePostAuthLogin(w http.ResponseWriter, r *http.Request
 		return
 	}
 
-	user, getUserErr := cfg.DB.GetUserByEmail(r.Context(), request.Email)
-	if getUserErr == sql.ErrNoRows {
-		common.RespondWithError(w, http.StatusNotFound, "Not found user")
+	now := time.Now().UTC()
+	limits := loginLimits(cfg, r, request.Email)
+	blockedFor, blockedErr := loginBlockedFor(cfg, r, limits, now)
+	if blockedErr != nil {
+		common.RespondWithError(w, http.StatusInternalServerError, blockedErr.Error())
+		return
+	}
+	if blockedFor > 0 {
+		setRetryAfter(w, blockedFor)
+		common.RespondWithError(w, http.StatusTooManyRequests, "Too many failed login attempts, try again later")
 		return
 	}
-	if getUserErr != nil {
+
+	user, getUserErr := cfg.DB.GetUserByEmail(r.Context(), request.Email)
+	if getUserErr != nil && getUserErr != sql.ErrNoRows {
 		common.RespondWithError(w, http.StatusInternalServerError, getUserErr.Error())
 		return
 	}
+	hashedPassword := user.HashedPassword
+	if getUserErr == sql.ErrNoRows {
+		hashedPassword = dummyPasswordHash
+	}
 
-	checkPasswErr := auth.CheckPasswordHash(user.HashedPassword, request.Password)
-	if checkPasswErr != nil {
-		common.RespondWithError(w, http.StatusUnauthorized, "Invalid password")
+	checkPasswErr := auth.CheckPasswordHash(hashedPassword, request.Password)
+	if getUserErr == sql.ErrNoRows || checkPasswErr != nil {
+		blockedFor, failureErr := addLoginFailure(cfg, r, limits, now)
+		if failureErr != nil {
+			common.RespondWithError(w, http.StatusInternalServerError, failureErr.Error())
+			return
+		}
+		if blockedFor > 0 {
+			setRetryAfter(w, blockedFor)
+		}
+		common.RespondWithError(w, http.StatusUnauthorized, "Invalid email or password")
 		return
 	}
 
-	makeTokensAndRespond(w, r, cfg, user.ID, http.StatusOK)
+	// With 2FA failures are forgotten only after the code is checked, otherwise knowing the password
+	// would allow to reset them and guess codes without limits.
+	if user.TotpEnabled {
+		respondWithChallenge(w, r, cfg, user.ID)
+		return
+	}
+
+	resetLoginFailures(cfg, r, request.Email)
+	makeTokensAndRespond(w, r, cfg, user.ID, string(user.Role), tokenFamily{}, http.StatusOK)
 }
 
 // @Summary Refresh tokens
-// @Description Checks refresh token from an HTTP-only cookie and returns new access and refresh tokens
+// @Description Checks refresh token from an HTTP-only cookie and returns new access and refresh tokens. Every refresh token can be used once, reusing a revoked token revokes all tokens of its login session
 // @Tags Auth
 // @Accept json
 // @Produce json
@@ -119,19 +194,20 @@ func (cfg *ApiConfig) HandlePostAuthRefresh(w http.ResponseWriter, r *http.Reque
 	}
 	refreshToken := cookie.Value
 
-	userID, getUserErr := cfg.DB.GetUserByRefreshToken(r.Context(), refreshToken)
-	if getUserErr == sql.ErrNoRows {
-		common.RespondWithError(w, http.StatusUnauthorized, "Not found user")
+	// The token is revoked in the same statement that checks it, so it can be exchanged only once.
+	usedToken, useTokenErr := cfg.DB.UseRefreshToken(r.Context(), refreshToken)
+	if useTokenErr == sql.ErrNoRows {
+		checkRefreshTokenReuse(cfg, r, refreshToken)
+		common.RespondWithError(w, http.StatusUnauthorized, "Invalid refresh token")
 		return
 	}
-	if getUserErr != nil {
-		common.RespondWithError(w, http.StatusInternalServerError, getUserErr.Error())
+	if useTokenErr != nil {
+		common.RespondWithError(w, http.StatusInternalServerError, useTokenErr.Error())
 		return
 	}
 
-	revokeRefreshToken(cfg, r, refreshToken)
-
-	makeTokensAndRespond(w, r, cfg, userID, http.StatusOK)
+	family := tokenFamily{id: usedToken.FamilyID, parentToken: sql.NullString{String: refreshToken, Valid: true}}
+	makeTokensAndRespond(w, r, cfg, usedToken.UserID, string(usedToken.Role), family, http.StatusOK)
 }
 
 // @Summary Revoke token
@@ -155,20 +231,46 @@ func (cfg *ApiConfig) HandlePostAuthRevoke(w http.ResponseWriter, r *http.Reques
 }
 
 // @Summary Get user
-// @Description Gets user ID. Uses access token from an HTTP-only cookie
+// @Description Gets user ID and role. Uses access token from an HTTP-only cookie. Also accepts a personal access token, then returns its scopes, so services can check personal tokens with it
 // @Tags Auth
 // @Accept json
 // @Produce json
-// @Success 200 {object} ResponseUserID "User ID"
+// @Success 200 {object} ResponseUserID "User ID and role"
 // @Failure 401 {object} ErrorResponse "Unauthorized"
 // @Failure 500 {object} ErrorResponse
 // @Router /auth/whoami [get]
 func (cfg *ApiConfig) HandleGetAuthWhoami(w http.ResponseWriter, r *http.Request) {
-	userID, authErr := checkAuthorization(cfg, r)
+	token, tokenErr := auth.GetBearerToken(r.Header)
+	if tokenErr == nil && auth.IsPersonalToken(token) {
+		userID, role, scopes, personalTokenErr := checkPersonalToken(cfg, r, token)
+		if personalTokenErr == errInvalidPersonalToken {
+			common.RespondWithError(w, http.StatusUnauthorized, personalTokenErr.Error())
+			return
+		}
+		if personalTokenErr != nil {
+			common.RespondWithError(w, http.StatusInternalServerError, personalTokenErr.Error())
+			return
+		}
+		common.RespondWithJSON(w, http.StatusOK, ResponseUserID{ID: userID.String(), Role: role, Scopes: scopes}, nil)
+		return
+	}
+
+	userID, role, authErr := checkAuthorization(cfg, r)
 	if authErr != nil {
 		common.RespondWithError(w, http.StatusUnauthorized, authErr.Error())
 		return
 	}
 
-	common.RespondWithJSON(w, http.StatusOK, ResponseUserID{ID: userID.String()}, nil)
+	common.RespondWithJSON(w, http.StatusOK, ResponseUserID{ID: userID.String(), Role: role}, nil)
+}
+
+// @Summary Get public keys
+// @Description Returns public keys that verify access tokens in JWKS format. Tokens carry key ID in kid header, keys of the previous rotation are kept while their tokens may still be valid
+// @Tags Auth
+// @Produce json
+// @Success 200 {object} auth.JWKS "JSON Web Key Set"
+// @Router /.well-known/jwks.json [get]
+func (cfg *ApiConfig) HandleGetJWKS(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Cache-Control", fmt.Sprintf("public, max-age=%d", int(jwksMaxAge.Seconds())))
+	common.RespondWithJSON(w, http.StatusOK, cfg.Keys.JWKS(), nil)
 }
@@ -0,0 +1,67 @@
+package server
+
+import (
+	"fmt"
+	"log"
+	"net/http"
+	"time"
+
+	"github.com/bakurvik/mylib/users/internal/clients"
+
+	common "github.com/bakurvik/mylib-common"
+)
+
+// exportTokenExpiresIn is the lifetime of the access token users service sends to user-reading on behalf of the user.
+const exportTokenExpiresIn = time.Minute
+
+// @Summary Export own data
+// @Description Gathers profile, sessions and the full reading history from user-reading into one JSON file. Uses access token from an HTTP-only cookie
+// @Tags Users
+// @Produce json
+// @Success 200 {object} ResponseUserExport "All user's data"
+// @Header 200 {string} Content-Disposition "attachment; filename=mylib-export-<date>.json"
+// @Failure 401 {object} ErrorResponse "Unauthorized"
+// @Failure 404 {object} ErrorResponse "User not found"
+// @Failure 500 {object} ErrorResponse
+// @Failure 502 {object} ErrorResponse "Failed to get reading data"
+// @Router /api/users/me/export [get]
+func (cfg *ApiConfig) HandleGetApiUsersMeExport(w http.ResponseWriter, r *http.Request) {
+	userID, role, authErr := checkAuthorization(cfg, r)
+	if authErr != nil {
+		common.RespondWithError(w, http.StatusUnauthorized, authErr.Error())
+		return
+	}
+
+	profile, status, profileErr := getPrivateProfile(cfg, r, userID)
+	if profileErr != nil {
+		common.RespondWithError(w, status, profileErr.Error())
+		return
+	}
+	sessions, sessionsErr := getSessions(cfg, r, userID)
+	if sessionsErr != nil {
+		common.RespondWithError(w, http.StatusInternalServerError, sessionsErr.Error())
+		return
+	}
+
+	// A fresh token is made because the one in the request may expire before user-reading checks it.
+	accessToken, tokenErr := cfg.Keys.MakeJWT(userID, role, exportTokenExpiresIn)
+	if tokenErr != nil {
+		common.RespondWithError(w, http.StatusInternalServerError, tokenErr.Error())
+		return
+	}
+	readingStatus, reading, readingErr := clients.GetUserReadingExport(cfg.UserReadingServiceHost, accessToken)
+	if readingErr != nil || readingStatus != http.StatusOK {
+		log.Printf("Failed to get reading data of user %v: status %v, error %v", userID, readingStatus, readingErr)
+		common.RespondWithError(w, http.StatusBadGateway, "Failed to get reading data")
+		return
+	}
+
+	exportedAt := time.Now().UTC()
+	w.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=\"mylib-export-%v.json\"", exportedAt.Format(time.DateOnly)))
+	common.RespondWithJSON(w, http.StatusOK, ResponseUserExport{
+		ExportedAt: exportedAt,
+		Profile:    profile,
+		Sessions:   sessions,
+		Reading:    reading,
+	}, nil)
+}
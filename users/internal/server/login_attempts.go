@@ -0,0 +1,96 @@
+package server
+
+import (
+	"database/sql"
+	"log"
+	"math"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/bakurvik/mylib/users/internal/auth"
+	"github.com/bakurvik/mylib/users/internal/database"
+	"github.com/bakurvik/mylib/users/internal/lockout"
+)
+
+// dummyPasswordHash is checked when there is no user with the email, so that the response takes as long
+// as for a wrong password and doesn't reveal which emails are registered.
+var dummyPasswordHash, _ = auth.HashPassword("dummy password")
+
+// loginLimit is a counter of failed login attempts and the policy that blocks login by it.
+type loginLimit struct {
+	key    string
+	policy lockout.Policy
+}
+
+func accountLoginKey(email string) string {
+	return "account:" + strings.ToLower(email)
+}
+
+// loginLimits returns counters of failed attempts for the account and for the client IP address.
+// Failures are counted for unknown emails too, so blocking doesn't reveal which emails are registered.
+func loginLimits(cfg *ApiConfig, r *http.Request, email string) []loginLimit {
+	return []loginLimit{
+		{key: accountLoginKey(email), policy: cfg.AccountLockout},
+		{key: "ip:" + clientIP(r), policy: cfg.IPLockout},
+	}
+}
+
+// loginBlockedFor returns for how long login is still blocked by any of the limits.
+func loginBlockedFor(cfg *ApiConfig, r *http.Request, limits []loginLimit, now time.Time) (time.Duration, error) {
+	var blockedFor time.Duration
+	for _, limit := range limits {
+		blockedUntil, err := cfg.DB.GetLoginBlockedUntil(r.Context(), limit.key)
+		if err == sql.ErrNoRows {
+			continue
+		}
+		if err != nil {
+			return 0, err
+		}
+		blockedFor = max(blockedFor, blockedUntil.Sub(now))
+	}
+	return blockedFor, nil
+}
+
+// addLoginFailure counts a failed attempt for every limit, blocks login according to the limit policies
+// and returns for how long login is blocked after this failure.
+func addLoginFailure(cfg *ApiConfig, r *http.Request, limits []loginLimit, now time.Time) (time.Duration, error) {
+	var blockedFor time.Duration
+	for _, limit := range limits {
+		failures, err := cfg.DB.AddLoginFailure(r.Context(), database.AddLoginFailureParams{
+			Key:          limit.key,
+			FailedAt:     now,
+			ForgetBefore: now.Add(-limit.policy.LockoutDuration),
+		})
+		if err != nil {
+			return 0, err
+		}
+		delay := limit.policy.Delay(int(failures))
+		if delay <= 0 {
+			continue
+		}
+		err = cfg.DB.BlockLogin(r.Context(), database.BlockLoginParams{Key: limit.key, BlockedUntil: now.Add(delay)})
+		if err != nil {
+			return 0, err
+		}
+		if limit.policy.LockoutAttempts > 0 && int(failures) == limit.policy.LockoutAttempts {
+			log.Printf("Security event: login by %v is locked out for %v after %d failed attempts", limit.key, delay, failures)
+		}
+		blockedFor = max(blockedFor, delay)
+	}
+	return blockedFor, nil
+}
+
+// resetLoginFailures forgets failed attempts of the account after successful login. Failures from the IP address
+// are kept, otherwise an attacker could reset them by logging into their own account.
+func resetLoginFailures(cfg *ApiConfig, r *http.Request, email string) {
+	err := cfg.DB.DeleteLoginAttempt(r.Context(), accountLoginKey(email))
+	if err != nil {
+		log.Print("Failed to reset login attempts: ", err)
+	}
+}
+
+func setRetryAfter(w http.ResponseWriter, delay time.Duration) {
+	w.Header().Set("Retry-After", strconv.Itoa(int(math.Ceil(delay.Seconds()))))
+}
@@ -0,0 +1,20 @@
+package server
+
+// UsersTopic is the Kafka topic of user events.
+const UsersTopic = "users"
+
+const (
+	actionDeleted = "deleted"
+	actionUpdated = "updated"
+)
+
+// UserMessage is published to UsersTopic, so other services can drop data of deleted users
+// and know which profiles are public. DisplayName and ReadingListPublic are set for updated users.
+// Migration 12_publish_public_profiles builds the same payload in SQL for profiles that were public before,
+// fields changed here must be changed there too.
+type UserMessage struct {
+	ID                string `json:"id"`
+	Action            string `json:"action"`
+	DisplayName       string `json:"display_name,omitempty"`
+	ReadingListPublic bool   `json:"reading_list_public,omitempty"`
+}
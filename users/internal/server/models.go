@@ -1,5 +1,10 @@
 package server
 
+import (
+	"encoding/json"
+	"time"
+)
+
 type RequestLogin struct {
 	Password string `json:"password"`
 	Email    string `json:"email"`
@@ -17,14 +22,113 @@ type ResponseToken struct {
 	Token string `json:"token"`
 }
 
+// ResponseUser is the public profile of a user, it is shown to anyone.
 type ResponseUser struct {
-	LoginName string `json:"login"`
-	Email     string `json:"email"`
-	BirthDate string `json:"birth_date,omitempty"`
+	ID                string `json:"id"`
+	LoginName         string `json:"login"`
+	DisplayName       string `json:"display_name,omitempty"`
+	Bio               string `json:"bio,omitempty"`
+	AvatarURL         string `json:"avatar_url,omitempty"`
+	ReadingListPublic bool   `json:"reading_list_public"`
+}
+
+// ResponseUserMe is the private profile of a user, it is shown only to the user.
+type ResponseUserMe struct {
+	ResponseUser
+	Email            string    `json:"email"`
+	EmailVerified    bool      `json:"email_verified"`
+	BirthDate        string    `json:"birth_date,omitempty"`
+	Locale           string    `json:"locale"`
+	TwoFactorEnabled bool      `json:"two_factor_enabled"`
+	CreatedAt        time.Time `json:"created_at"`
+}
+
+// ResponseUserExport is all data stored about the user, Reading is the data of user-reading service as it returned it.
+type ResponseUserExport struct {
+	ExportedAt time.Time         `json:"exported_at"`
+	Profile    ResponseUserMe    `json:"profile"`
+	Sessions   []ResponseSession `json:"sessions"`
+	Reading    json.RawMessage   `json:"reading" swaggertype:"object"`
+}
+
+type RequestUserProfile struct {
+	DisplayName string `json:"display_name"`
+	Bio         string `json:"bio"`
+	// AvatarURL is an http or https link to the image, empty removes the avatar.
+	AvatarURL string `json:"avatar_url"`
+	// Locale is a language tag like en or pt-BR, en by default.
+	Locale            string `json:"locale"`
+	ReadingListPublic bool   `json:"reading_list_public"`
 }
 
 type ResponseUserID struct {
-	ID string `json:"user_id"`
+	ID   string `json:"user_id"`
+	Role string `json:"role"`
+	// Scopes limit a personal access token, they are empty for access tokens.
+	Scopes []string `json:"scopes,omitempty"`
+}
+
+type RequestUserRole struct {
+	Role string `json:"role"`
+}
+
+type ResponseSession struct {
+	ID         string    `json:"id"`
+	UserAgent  string    `json:"user_agent"`
+	IPAddress  string    `json:"ip_address"`
+	StartedAt  time.Time `json:"started_at"`
+	LastUsedAt time.Time `json:"last_used_at"`
+	Current    bool      `json:"current"`
+}
+
+type RequestPasswordReset struct {
+	Email string `json:"email"`
+}
+
+type RequestPasswordResetConfirm struct {
+	Token    string `json:"token"`
+	Password string `json:"password"`
+}
+
+type ResponseTwoFactorSetup struct {
+	Secret string `json:"secret"`
+	URI    string `json:"otpauth_uri"`
+}
+
+type RequestTwoFactorCode struct {
+	Code string `json:"code"`
+}
+
+type ResponseRecoveryCodes struct {
+	Codes []string `json:"recovery_codes"`
+}
+
+type ResponseTwoFactorChallenge struct {
+	ChallengeToken string `json:"challenge_token"`
+}
+
+type RequestTwoFactorVerify struct {
+	ChallengeToken string `json:"challenge_token"`
+	// Code is either a TOTP code from authenticator app or a recovery code.
+	Code string `json:"code"`
+}
+
+type RequestPersonalToken struct {
+	Name   string   `json:"name"`
+	Scopes []string `json:"scopes"`
+	// ExpiresInDays is the lifetime of the token, 30 days by default.
+	ExpiresInDays int `json:"expires_in_days,omitempty"`
+}
+
+type ResponsePersonalToken struct {
+	ID         string     `json:"id"`
+	Name       string     `json:"name"`
+	Scopes     []string   `json:"scopes"`
+	CreatedAt  time.Time  `json:"created_at"`
+	ExpiresAt  time.Time  `json:"expires_at"`
+	LastUsedAt *time.Time `json:"last_used_at,omitempty"`
+	// Token is returned only when the token is created.
+	Token string `json:"token,omitempty"`
 }
 
 type ErrorResponse struct {
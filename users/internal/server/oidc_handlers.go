@@ -0,0 +1,262 @@
+package server
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"log"
+	"math/rand/v2"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/bakurvik/mylib/users/internal/auth"
+	"github.com/bakurvik/mylib/users/internal/database"
+	"github.com/bakurvik/mylib/users/internal/oidc"
+
+	common "github.com/bakurvik/mylib-common"
+	"github.com/google/uuid"
+)
+
+const (
+	oidcStateExpiresIn   = 10 * time.Minute
+	oidcStateCookieName  = "oidc_state"
+	oidcLoginNameRetries = 5
+)
+
+var errEmailNotVerified = errors.New("user with this email exists, log in with password and verify the email to link the account")
+
+func getOIDCProvider(cfg *ApiConfig, r *http.Request) *oidc.Provider {
+	return cfg.OIDCProviders[r.PathValue("provider")]
+}
+
+// setOIDCStateCookie binds the login attempt to the browser that started it, so a callback link with someone else's
+// state and code can't log the user into another account.
+func setOIDCStateCookie(w http.ResponseWriter, provider string, state string, maxAge int) {
+	http.SetCookie(w, &http.Cookie{
+		Name:     oidcStateCookieName,
+		Value:    state,
+		Path:     fmt.Sprintf("%v/%v", AuthOIDCPath, provider),
+		MaxAge:   maxAge,
+		Secure:   false,
+		HttpOnly: true,
+		SameSite: http.SameSiteLaxMode,
+	})
+}
+
+// makeOIDCLoginName makes a unique login name for a new user from preferred username or email of the identity.
+func makeOIDCLoginName(cfg *ApiConfig, r *http.Request, claims *oidc.Claims) (string, error) {
+	const minLoginLen = 3
+	loginName := strings.TrimSpace(claims.PreferredUsername)
+	if loginName == "" {
+		loginName, _, _ = strings.Cut(claims.Email, "@")
+	}
+	if len(loginName) < minLoginLen {
+		loginName = "user"
+	}
+
+	candidate := loginName
+	for range oidcLoginNameRetries {
+		rows, err := cfg.DB.GetUser(r.Context(), database.GetUserParams{LoginName: candidate})
+		if err != nil {
+			return "", err
+		}
+		if len(rows) == 0 {
+			return candidate, nil
+		}
+		candidate = fmt.Sprintf("%v-%04x", loginName, rand.IntN(0x10000))
+	}
+	return "", errors.New("failed to make a unique login name")
+}
+
+// createOIDCUser creates a user with verified email and links the identity to it in one statement, so a failure
+// doesn't leave a user without the identity. The password is random, the user can set it by password reset.
+func createOIDCUser(cfg *ApiConfig, r *http.Request, provider string, claims *oidc.Claims) (uuid.UUID, error) {
+	loginName, err := makeOIDCLoginName(cfg, r, claims)
+	if err != nil {
+		return uuid.Nil, err
+	}
+	password, err := oidc.MakeRandomString()
+	if err != nil {
+		return uuid.Nil, err
+	}
+	hashedPassword, err := auth.HashPassword(password)
+	if err != nil {
+		return uuid.Nil, err
+	}
+	userID, err := cfg.DB.CreateOIDCUser(r.Context(), database.CreateOIDCUserParams{
+		Provider:       provider,
+		Subject:        claims.Subject,
+		Email:          claims.Email,
+		LoginName:      loginName,
+		HashedPassword: hashedPassword,
+	})
+	if err != nil {
+		return uuid.Nil, err
+	}
+	log.Printf("Created user %v for %v identity %v", userID, provider, claims.Subject)
+	return userID, nil
+}
+
+// findOIDCUser returns the user linked to the identity. An unknown identity is linked to the user with the same email
+// or to a new user. Both emails must be verified, otherwise anyone who registers the email first would get the account.
+func findOIDCUser(cfg *ApiConfig, r *http.Request, provider string, claims *oidc.Claims) (uuid.UUID, int, error) {
+	userID, identityErr := cfg.DB.GetUserIdentity(r.Context(), database.GetUserIdentityParams{Provider: provider, Subject: claims.Subject})
+	if identityErr == nil {
+		return userID, 0, nil
+	}
+	if identityErr != sql.ErrNoRows {
+		return uuid.Nil, http.StatusInternalServerError, identityErr
+	}
+
+	if claims.Email == "" || !claims.EmailVerified {
+		return uuid.Nil, http.StatusForbidden, errors.New("provider didn't confirm that the email is verified")
+	}
+	user, userErr := cfg.DB.GetUserByEmail(r.Context(), claims.Email)
+	switch {
+	case userErr == sql.ErrNoRows:
+		userID, userErr = createOIDCUser(cfg, r, provider, claims)
+		if userErr != nil {
+			return uuid.Nil, http.StatusInternalServerError, userErr
+		}
+		return userID, 0, nil
+	case userErr != nil:
+		return uuid.Nil, http.StatusInternalServerError, userErr
+	case !user.EmailVerified:
+		return uuid.Nil, http.StatusConflict, errEmailNotVerified
+	}
+
+	linkErr := cfg.DB.CreateUserIdentity(r.Context(), database.CreateUserIdentityParams{Provider: provider, Subject: claims.Subject, UserID: user.ID, Email: claims.Email})
+	if linkErr != nil {
+		return uuid.Nil, http.StatusInternalServerError, linkErr
+	}
+	log.Printf("Linked %v identity %v to user %v", provider, claims.Subject, user.ID)
+	return user.ID, 0, nil
+}
+
+// @Summary Start login with OpenID Connect provider
+// @Description Redirects to the login page of the provider. State and PKCE code verifier of the attempt are saved for the callback
+// @Tags OpenID Connect
+// @Param provider path string true "Provider name"
+// @Success 302 "Redirect to the provider"
+// @Header 302 {string} Set-Cookie "HTTP-only cookie named oidc_state"
+// @Failure 404 {object} ErrorResponse "Unknown provider"
+// @Failure 500 {object} ErrorResponse
+// @Failure 502 {object} ErrorResponse "Provider is unavailable"
+// @Router /auth/oidc/{provider}/start [get]
+func (cfg *ApiConfig) HandleGetAuthOIDCStart(w http.ResponseWriter, r *http.Request) {
+	provider := getOIDCProvider(cfg, r)
+	if provider == nil {
+		common.RespondWithError(w, http.StatusNotFound, "Unknown provider")
+		return
+	}
+
+	values := make([]string, 3)
+	for i := range values {
+		value, err := oidc.MakeRandomString()
+		if err != nil {
+			common.RespondWithError(w, http.StatusInternalServerError, err.Error())
+			return
+		}
+		values[i] = value
+	}
+	state, nonce, codeVerifier := values[0], values[1], values[2]
+
+	authURL, urlErr := provider.AuthCodeURL(r.Context(), state, nonce, codeVerifier)
+	if urlErr != nil {
+		common.RespondWithError(w, http.StatusBadGateway, urlErr.Error())
+		return
+	}
+
+	cleanupErr := cfg.DB.DeleteExpiredOIDCStates(r.Context())
+	if cleanupErr != nil {
+		log.Print("Failed to delete expired OIDC states: ", cleanupErr)
+	}
+	stateErr := cfg.DB.CreateOIDCState(r.Context(), database.CreateOIDCStateParams{
+		StateHash:    auth.HashToken(state),
+		Provider:     provider.Name(),
+		CodeVerifier: codeVerifier,
+		Nonce:        nonce,
+		ExpiresAt:    time.Now().UTC().Add(oidcStateExpiresIn),
+	})
+	if stateErr != nil {
+		common.RespondWithError(w, http.StatusInternalServerError, stateErr.Error())
+		return
+	}
+
+	setOIDCStateCookie(w, provider.Name(), state, int(oidcStateExpiresIn.Seconds()))
+	http.Redirect(w, r, authURL, http.StatusFound)
+}
+
+// @Summary Finish login with OpenID Connect provider
+// @Description The provider redirects here after login. Checks state, exchanges the code for ID token and returns access and refresh tokens of the linked user. An unknown identity is linked to the user with the same verified email or to a new user. If the user has enabled 2FA, returns a challenge token for /auth/2fa/verify instead
+// @Tags OpenID Connect
+// @Produce json
+// @Param provider path string true "Provider name"
+// @Param code query string true "Authorization code"
+// @Param state query string true "State from the start of login"
+// @Success 200 {object} ResponseToken "Logined successfully"
+// @Header 200 {string} Set-Cookie "HTTP-only cookie named refresh_token"
+// @Success 202 {object} ResponseTwoFactorChallenge "Two-factor code is required"
+// @Failure 400 {object} ErrorResponse "Invalid or expired state"
+// @Failure 401 {object} ErrorResponse "Login at provider failed"
+// @Failure 403 {object} ErrorResponse "Provider didn't verify the email"
+// @Failure 404 {object} ErrorResponse "Unknown provider"
+// @Failure 409 {object} ErrorResponse "User with this email exists and the email isn't verified"
+// @Failure 500 {object} ErrorResponse
+// @Router /auth/oidc/{provider}/callback [get]
+func (cfg *ApiConfig) HandleGetAuthOIDCCallback(w http.ResponseWriter, r *http.Request) {
+	provider := getOIDCProvider(cfg, r)
+	if provider == nil {
+		common.RespondWithError(w, http.StatusNotFound, "Unknown provider")
+		return
+	}
+
+	query := r.URL.Query()
+	if providerErr := query.Get("error"); providerErr != "" {
+		common.RespondWithError(w, http.StatusUnauthorized, "Login at provider failed: "+providerErr)
+		return
+	}
+	state := query.Get("state")
+	cookie, cookieErr := r.Cookie(oidcStateCookieName)
+	if state == "" || cookieErr != nil || cookie.Value != state {
+		common.RespondWithError(w, http.StatusBadRequest, "Invalid or expired state")
+		return
+	}
+	setOIDCStateCookie(w, provider.Name(), "", -1)
+
+	// The state is deleted in the same statement that checks it, so a callback can be used once.
+	attempt, attemptErr := cfg.DB.UseOIDCState(r.Context(), database.UseOIDCStateParams{StateHash: auth.HashToken(state), Provider: provider.Name()})
+	if attemptErr == sql.ErrNoRows {
+		common.RespondWithError(w, http.StatusBadRequest, "Invalid or expired state")
+		return
+	}
+	if attemptErr != nil {
+		common.RespondWithError(w, http.StatusInternalServerError, attemptErr.Error())
+		return
+	}
+
+	claims, exchangeErr := provider.Exchange(r.Context(), query.Get("code"), attempt.CodeVerifier, attempt.Nonce)
+	if exchangeErr != nil {
+		log.Printf("Failed login with %v: %v", provider.Name(), exchangeErr)
+		common.RespondWithError(w, http.StatusUnauthorized, "Login at provider failed")
+		return
+	}
+
+	userID, status, userErr := findOIDCUser(cfg, r, provider.Name(), claims)
+	if userErr != nil {
+		common.RespondWithError(w, status, userErr.Error())
+		return
+	}
+
+	user, twoFactorErr := cfg.DB.GetUserTwoFactor(r.Context(), userID)
+	if twoFactorErr != nil {
+		common.RespondWithError(w, http.StatusInternalServerError, twoFactorErr.Error())
+		return
+	}
+	if user.TotpEnabled {
+		respondWithChallenge(w, r, cfg, userID)
+		return
+	}
+	makeTokensAndRespond(w, r, cfg, userID, string(user.Role), tokenFamily{}, http.StatusOK)
+}
@@ -0,0 +1,196 @@
+package server
+
+import (
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"slices"
+	"time"
+
+	"github.com/bakurvik/mylib/users/internal/auth"
+	"github.com/bakurvik/mylib/users/internal/database"
+
+	common "github.com/bakurvik/mylib-common"
+	"github.com/google/uuid"
+)
+
+const (
+	defaultPersonalTokenExpiresInDays = 30
+	maxPersonalTokenExpiresInDays     = 365
+	maxPersonalTokenNameLen           = 100
+)
+
+var errInvalidPersonalToken = errors.New("invalid personal access token")
+
+func validatePersonalTokenRequest(request *RequestPersonalToken) error {
+	if request.Name == "" || len(request.Name) > maxPersonalTokenNameLen {
+		return fmt.Errorf("name must be from 1 to %d characters long", maxPersonalTokenNameLen)
+	}
+	if len(request.Scopes) == 0 {
+		return errors.New("no scopes in request")
+	}
+	for _, scope := range request.Scopes {
+		if !auth.IsValidScope(scope) {
+			return fmt.Errorf("unknown scope %v", scope)
+		}
+	}
+	if request.ExpiresInDays == 0 {
+		request.ExpiresInDays = defaultPersonalTokenExpiresInDays
+	}
+	if request.ExpiresInDays < 0 || request.ExpiresInDays > maxPersonalTokenExpiresInDays {
+		return fmt.Errorf("expires_in_days must be from 1 to %d", maxPersonalTokenExpiresInDays)
+	}
+	return nil
+}
+
+// checkPersonalToken returns user ID, current role of the user and scopes of an active personal access token
+// and saves the time it was used.
+func checkPersonalToken(cfg *ApiConfig, r *http.Request, token string) (uuid.UUID, string, []string, error) {
+	personalToken, err := cfg.DB.UsePersonalToken(r.Context(), auth.HashToken(token))
+	if err == sql.ErrNoRows {
+		return uuid.Nil, "", nil, errInvalidPersonalToken
+	}
+	if err != nil {
+		return uuid.Nil, "", nil, err
+	}
+	return personalToken.UserID, string(personalToken.Role), personalToken.Scopes, nil
+}
+
+// @Summary Create personal access token
+// @Description Creates a long-lived token for scripts and integrations. The token is limited to its scopes and is sent as Bearer token like an access token. It is returned only once, only its hash is stored. Requires an access token, a personal access token can't create other tokens
+// @Tags Personal access tokens
+// @Accept json
+// @Produce json
+// @Param request body RequestPersonalToken true "Name, scopes and lifetime of the token"
+// @Success 201 {object} ResponsePersonalToken "Created token"
+// @Failure 400 {object} ErrorResponse "Invalid name, scopes or lifetime"
+// @Failure 401 {object} ErrorResponse "Unauthorized"
+// @Failure 500 {object} ErrorResponse
+// @Router /auth/tokens [post]
+func (cfg *ApiConfig) HandlePostAuthTokens(w http.ResponseWriter, r *http.Request) {
+	userID, _, authErr := checkAuthorization(cfg, r)
+	if authErr != nil {
+		common.RespondWithError(w, http.StatusUnauthorized, authErr.Error())
+		return
+	}
+
+	decoder := json.NewDecoder(r.Body)
+	request := RequestPersonalToken{}
+	requestErr := decoder.Decode(&request)
+	if requestErr != nil {
+		common.RespondWithError(w, http.StatusBadRequest, requestErr.Error())
+		return
+	}
+	validateErr := validatePersonalTokenRequest(&request)
+	if validateErr != nil {
+		common.RespondWithError(w, http.StatusBadRequest, validateErr.Error())
+		return
+	}
+	slices.Sort(request.Scopes)
+	request.Scopes = slices.Compact(request.Scopes)
+
+	token, tokenHash, tokenErr := auth.MakePersonalToken()
+	if tokenErr != nil {
+		common.RespondWithError(w, http.StatusInternalServerError, tokenErr.Error())
+		return
+	}
+	expiresAt := time.Now().UTC().AddDate(0, 0, request.ExpiresInDays)
+	created, createErr := cfg.DB.CreatePersonalToken(r.Context(), database.CreatePersonalTokenParams{
+		UserID:    userID,
+		Name:      request.Name,
+		TokenHash: tokenHash,
+		Scopes:    request.Scopes,
+		ExpiresAt: expiresAt,
+	})
+	if createErr != nil {
+		common.RespondWithError(w, http.StatusInternalServerError, createErr.Error())
+		return
+	}
+
+	common.RespondWithJSON(w, http.StatusCreated, ResponsePersonalToken{
+		ID:        created.ID.String(),
+		Name:      request.Name,
+		Scopes:    request.Scopes,
+		CreatedAt: created.CreatedAt,
+		ExpiresAt: expiresAt,
+		Token:     token,
+	}, nil)
+}
+
+// @Summary Get personal access tokens
+// @Description Gets user's active personal access tokens without the token values
+// @Tags Personal access tokens
+// @Accept json
+// @Produce json
+// @Success 200 {array} ResponsePersonalToken "Active tokens"
+// @Failure 401 {object} ErrorResponse "Unauthorized"
+// @Failure 500 {object} ErrorResponse
+// @Router /auth/tokens [get]
+func (cfg *ApiConfig) HandleGetAuthTokens(w http.ResponseWriter, r *http.Request) {
+	userID, _, authErr := checkAuthorization(cfg, r)
+	if authErr != nil {
+		common.RespondWithError(w, http.StatusUnauthorized, authErr.Error())
+		return
+	}
+
+	tokens, tokensErr := cfg.DB.GetUserPersonalTokens(r.Context(), userID)
+	if tokensErr != nil {
+		common.RespondWithError(w, http.StatusInternalServerError, tokensErr.Error())
+		return
+	}
+
+	response := make([]ResponsePersonalToken, 0, len(tokens))
+	for _, token := range tokens {
+		responseToken := ResponsePersonalToken{
+			ID:        token.ID.String(),
+			Name:      token.Name,
+			Scopes:    token.Scopes,
+			CreatedAt: token.CreatedAt,
+			ExpiresAt: token.ExpiresAt,
+		}
+		if token.LastUsedAt.Valid {
+			responseToken.LastUsedAt = &token.LastUsedAt.Time
+		}
+		response = append(response, responseToken)
+	}
+	common.RespondWithJSON(w, http.StatusOK, response, nil)
+}
+
+// @Summary Revoke personal access token
+// @Description Revokes user's personal access token, services reject it right away
+// @Tags Personal access tokens
+// @Accept json
+// @Produce json
+// @Param tokenID path string true "Token ID"
+// @Success 204
+// @Failure 400 {object} ErrorResponse "Invalid token ID"
+// @Failure 401 {object} ErrorResponse "Unauthorized"
+// @Failure 404 {object} ErrorResponse "Token not found"
+// @Failure 500 {object} ErrorResponse
+// @Router /auth/tokens/{tokenID} [delete]
+func (cfg *ApiConfig) HandleDeleteAuthTokens(w http.ResponseWriter, r *http.Request) {
+	userID, _, authErr := checkAuthorization(cfg, r)
+	if authErr != nil {
+		common.RespondWithError(w, http.StatusUnauthorized, authErr.Error())
+		return
+	}
+
+	tokenID, parseErr := uuid.Parse(r.PathValue("tokenID"))
+	if parseErr != nil {
+		common.RespondWithError(w, http.StatusBadRequest, "Invalid token id")
+		return
+	}
+
+	count, revokeErr := cfg.DB.RevokePersonalToken(r.Context(), database.RevokePersonalTokenParams{ID: tokenID, UserID: userID})
+	if revokeErr != nil {
+		common.RespondWithError(w, http.StatusInternalServerError, revokeErr.Error())
+		return
+	}
+	if count == 0 {
+		common.RespondWithError(w, http.StatusNotFound, "Token not found")
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
@@ -0,0 +1,171 @@
+package server
+
+import (
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"regexp"
+	"unicode/utf8"
+
+	"github.com/bakurvik/mylib/users/internal/database"
+
+	common "github.com/bakurvik/mylib-common"
+	"github.com/google/uuid"
+)
+
+const (
+	maxDisplayNameLen = 50
+	maxBioLen         = 500
+	maxAvatarURLLen   = 2048
+	defaultLocale     = "en"
+)
+
+// localePattern matches simple BCP 47 language tags: language with optional script and region, e.g. en, pt-BR, zh-Hant-TW.
+var localePattern = regexp.MustCompile(`^[a-z]{2,3}(-[A-Z][a-z]{3})?(-([A-Z]{2}|[0-9]{3}))?$`)
+
+func publicProfile(userID uuid.UUID, user database.GetUserByIDRow) ResponseUser {
+	return ResponseUser{
+		ID:                userID.String(),
+		LoginName:         user.LoginName,
+		DisplayName:       user.DisplayName,
+		Bio:               user.Bio,
+		AvatarURL:         user.AvatarUrl,
+		ReadingListPublic: user.ReadingListPublic,
+	}
+}
+
+func getPrivateProfile(cfg *ApiConfig, r *http.Request, userID uuid.UUID) (ResponseUserMe, int, error) {
+	user, err := cfg.DB.GetUserByID(r.Context(), userID)
+	if err == sql.ErrNoRows {
+		return ResponseUserMe{}, http.StatusNotFound, errors.New("User not found")
+	}
+	if err != nil {
+		return ResponseUserMe{}, http.StatusInternalServerError, err
+	}
+	return ResponseUserMe{
+		ResponseUser:     publicProfile(userID, user),
+		Email:            user.Email,
+		EmailVerified:    user.EmailVerified,
+		BirthDate:        common.NullTimeToString(user.BirthDate),
+		Locale:           user.Locale,
+		TwoFactorEnabled: user.TotpEnabled,
+		CreatedAt:        user.CreatedAt,
+	}, 0, nil
+}
+
+func validateUserProfile(profile *RequestUserProfile) error {
+	if utf8.RuneCountInString(profile.DisplayName) > maxDisplayNameLen {
+		return fmt.Errorf("display name is longer than %d characters", maxDisplayNameLen)
+	}
+	if utf8.RuneCountInString(profile.Bio) > maxBioLen {
+		return fmt.Errorf("bio is longer than %d characters", maxBioLen)
+	}
+	if profile.AvatarURL != "" {
+		if len(profile.AvatarURL) > maxAvatarURLLen {
+			return errors.New("avatar url is too long")
+		}
+		avatarURL, err := url.Parse(profile.AvatarURL)
+		if err != nil || (avatarURL.Scheme != "http" && avatarURL.Scheme != "https") || avatarURL.Host == "" {
+			return errors.New("avatar url must be an http or https link")
+		}
+	}
+	if profile.Locale == "" {
+		profile.Locale = defaultLocale
+	}
+	if !localePattern.MatchString(profile.Locale) {
+		return errors.New("invalid locale")
+	}
+	return nil
+}
+
+// @Summary Get own profile
+// @Description Gets private profile of the user with email, birth date and settings. Uses access token from an HTTP-only cookie
+// @Tags Users
+// @Accept json
+// @Produce json
+// @Success 200 {object} ResponseUserMe "User's private profile"
+// @Failure 401 {object} ErrorResponse "Unauthorized"
+// @Failure 404 {object} ErrorResponse "User not found"
+// @Failure 500 {object} ErrorResponse
+// @Router /api/users/me [get]
+func (cfg *ApiConfig) HandleGetApiUsersMe(w http.ResponseWriter, r *http.Request) {
+	userID, _, authErr := checkAuthorization(cfg, r)
+	if authErr != nil {
+		common.RespondWithError(w, http.StatusUnauthorized, authErr.Error())
+		return
+	}
+
+	profile, status, profileErr := getPrivateProfile(cfg, r, userID)
+	if profileErr != nil {
+		common.RespondWithError(w, status, profileErr.Error())
+		return
+	}
+	common.RespondWithJSON(w, http.StatusOK, profile, nil)
+}
+
+// @Summary Update own profile
+// @Description Replaces display name, bio, avatar URL, locale and privacy settings of the user. Uses access token from an HTTP-only cookie
+// @Tags Users
+// @Accept json
+// @Produce json
+// @Param request body RequestUserProfile true "User's profile"
+// @Success 204
+// @Failure 400 {object} ErrorResponse "Invalid request body"
+// @Failure 401 {object} ErrorResponse "Unauthorized"
+// @Failure 404 {object} ErrorResponse "User not found"
+// @Failure 500 {object} ErrorResponse
+// @Router /api/users/me/profile [put]
+func (cfg *ApiConfig) HandlePutApiUsersMeProfile(w http.ResponseWriter, r *http.Request) {
+	userID, _, authErr := checkAuthorization(cfg, r)
+	if authErr != nil {
+		common.RespondWithError(w, http.StatusUnauthorized, authErr.Error())
+		return
+	}
+
+	decoder := json.NewDecoder(r.Body)
+	request := RequestUserProfile{}
+	requestErr := decoder.Decode(&request)
+	if requestErr != nil {
+		common.RespondWithError(w, http.StatusBadRequest, requestErr.Error())
+		return
+	}
+	validateErr := validateUserProfile(&request)
+	if validateErr != nil {
+		common.RespondWithError(w, http.StatusBadRequest, validateErr.Error())
+		return
+	}
+
+	// user-reading shows reviews of users with public profiles, so the update is published with the profile.
+	payload, payloadErr := json.Marshal(UserMessage{
+		ID:                userID.String(),
+		Action:            actionUpdated,
+		DisplayName:       request.DisplayName,
+		ReadingListPublic: request.ReadingListPublic,
+	})
+	if payloadErr != nil {
+		common.RespondWithError(w, http.StatusInternalServerError, payloadErr.Error())
+		return
+	}
+	count, updateErr := cfg.DB.UpdateUserProfileWithOutboxMessage(r.Context(), database.UpdateUserProfileWithOutboxMessageParams{
+		ID:                userID,
+		DisplayName:       request.DisplayName,
+		Bio:               request.Bio,
+		AvatarUrl:         request.AvatarURL,
+		Locale:            request.Locale,
+		ReadingListPublic: request.ReadingListPublic,
+		Topic:             UsersTopic,
+		Payload:           payload,
+	})
+	if updateErr != nil {
+		common.RespondWithError(w, http.StatusInternalServerError, updateErr.Error())
+		return
+	}
+	if count == 0 {
+		common.RespondWithError(w, http.StatusNotFound, "User not found")
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
@@ -4,22 +4,53 @@ import (
 	"fmt"
 	"net/http"
 
+	"github.com/bakurvik/mylib/users/internal/auth"
 	"github.com/bakurvik/mylib/users/internal/database"
+	"github.com/bakurvik/mylib/users/internal/lockout"
+	"github.com/bakurvik/mylib/users/internal/mailer"
+	"github.com/bakurvik/mylib/users/internal/oidc"
 	httpSwagger "github.com/swaggo/http-swagger"
 )
 
 const (
-	PingPath        = "/ping"
-	ApiUsersPath    = "/api/users"
-	AuthRevokePath  = "/auth/revoke"
-	AuthLoginPath   = "/auth/login"
-	AuthRefreshPath = "/auth/refresh"
-	AuthWhoamiPath  = "/auth/whoami"
+	PingPath                     = "/ping"
+	ApiUsersPath                 = "/api/users"
+	ApiUsersMePath               = "/api/users/me"
+	ApiUsersMeProfilePath        = "/api/users/me/profile"
+	ApiUsersMeExportPath         = "/api/users/me/export"
+	AuthRevokePath               = "/auth/revoke"
+	AuthLoginPath                = "/auth/login"
+	AuthRefreshPath              = "/auth/refresh"
+	AuthWhoamiPath               = "/auth/whoami"
+	AuthSessionsPath             = "/auth/sessions"
+	AuthLogoutAllPath            = "/auth/logout-all"
+	AuthPasswordResetRequestPath = "/auth/password-reset/request"
+	AuthPasswordResetConfirmPath = "/auth/password-reset/confirm"
+	AuthVerifyEmailPath          = "/auth/verify-email"
+	AuthTwoFactorSetupPath       = "/auth/2fa/setup"
+	AuthTwoFactorEnablePath      = "/auth/2fa/enable"
+	AuthTwoFactorVerifyPath      = "/auth/2fa/verify"
+	AuthOIDCPath                 = "/auth/oidc"
+	AuthTokensPath               = "/auth/tokens"
+	AdminUsersPath               = "/admin/users"
+	JWKSPath                     = "/.well-known/jwks.json"
 )
 
 type ApiConfig struct {
-	DB            *database.Queries
-	AuthSecretKey string
+	DB     *database.Queries
+	Keys   *auth.KeySet
+	Mailer mailer.Mailer
+	// PublicURL is the address of users service for links in emails, e.g. email verification link.
+	PublicURL string
+	// PasswordResetURL is the frontend page that asks for a new password, reset token is added to it as token parameter.
+	PasswordResetURL string
+	// AccountLockout and IPLockout block login after failed attempts for the same email and from the same IP address.
+	AccountLockout lockout.Policy
+	IPLockout      lockout.Policy
+	// OIDCProviders are OpenID Connect providers users can log in with, by provider name in the login path.
+	OIDCProviders map[string]*oidc.Provider
+	// UserReadingServiceHost is asked for reading history when the user exports account data.
+	UserReadingServiceHost string
 }
 
 func Handle(sm *http.ServeMux, apiCfg *ApiConfig) {
@@ -31,12 +62,41 @@ func Handle(sm *http.ServeMux, apiCfg *ApiConfig) {
 	sm.HandleFunc("PUT "+ApiUsersPath, apiCfg.HandlePutApiUsers)
 	sm.HandleFunc(fmt.Sprintf("GET %v/{userID}", ApiUsersPath), apiCfg.HandleGetApiUsers)
 	sm.HandleFunc("DELETE "+ApiUsersPath, apiCfg.HandleDeleteApiUsers)
+	sm.HandleFunc("GET "+ApiUsersMePath, apiCfg.HandleGetApiUsersMe)
+	sm.HandleFunc("PUT "+ApiUsersMeProfilePath, apiCfg.HandlePutApiUsersMeProfile)
+	sm.HandleFunc("GET "+ApiUsersMeExportPath, apiCfg.HandleGetApiUsersMeExport)
 
 	// Auth
 	sm.HandleFunc("POST "+AuthLoginPath, apiCfg.HandlePostAuthLogin)
 	sm.HandleFunc("POST "+AuthRefreshPath, apiCfg.HandlePostAuthRefresh)
 	sm.HandleFunc("POST "+AuthRevokePath, apiCfg.HandlePostAuthRevoke)
 	sm.HandleFunc("GET "+AuthWhoamiPath, apiCfg.HandleGetAuthWhoami)
+	sm.HandleFunc("GET "+JWKSPath, apiCfg.HandleGetJWKS)
+	sm.HandleFunc("POST "+AuthPasswordResetRequestPath, apiCfg.HandlePostAuthPasswordResetRequest)
+	sm.HandleFunc("POST "+AuthPasswordResetConfirmPath, apiCfg.HandlePostAuthPasswordResetConfirm)
+	sm.HandleFunc("GET "+AuthVerifyEmailPath, apiCfg.HandleGetAuthVerifyEmail)
+
+	// Two-factor authentication
+	sm.HandleFunc("POST "+AuthTwoFactorSetupPath, apiCfg.HandlePostAuthTwoFactorSetup)
+	sm.HandleFunc("POST "+AuthTwoFactorEnablePath, apiCfg.HandlePostAuthTwoFactorEnable)
+	sm.HandleFunc("POST "+AuthTwoFactorVerifyPath, apiCfg.HandlePostAuthTwoFactorVerify)
+
+	// OpenID Connect
+	sm.HandleFunc(fmt.Sprintf("GET %v/{provider}/start", AuthOIDCPath), apiCfg.HandleGetAuthOIDCStart)
+	sm.HandleFunc(fmt.Sprintf("GET %v/{provider}/callback", AuthOIDCPath), apiCfg.HandleGetAuthOIDCCallback)
+
+	// Sessions
+	sm.HandleFunc("GET "+AuthSessionsPath, apiCfg.HandleGetAuthSessions)
+	sm.HandleFunc(fmt.Sprintf("DELETE %v/{sessionID}", AuthSessionsPath), apiCfg.HandleDeleteAuthSessions)
+	sm.HandleFunc("POST "+AuthLogoutAllPath, apiCfg.HandlePostAuthLogoutAll)
+
+	// Personal access tokens
+	sm.HandleFunc("POST "+AuthTokensPath, apiCfg.HandlePostAuthTokens)
+	sm.HandleFunc("GET "+AuthTokensPath, apiCfg.HandleGetAuthTokens)
+	sm.HandleFunc(fmt.Sprintf("DELETE %v/{tokenID}", AuthTokensPath), apiCfg.HandleDeleteAuthTokens)
+
+	// Admin
+	sm.HandleFunc(fmt.Sprintf("PUT %v/{userID}/role", AdminUsersPath), apiCfg.HandlePutAdminUsersRole)
 
 	// Swagger
 	sm.Handle("/swagger/", httpSwagger.WrapHandler)
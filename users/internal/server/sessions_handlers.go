@@ -0,0 +1,154 @@
+package server
+
+import (
+	"log"
+	"net"
+	"net/http"
+
+	"github.com/bakurvik/mylib/users/internal/database"
+
+	common "github.com/bakurvik/mylib-common"
+	"github.com/google/uuid"
+)
+
+// clientIP returns IP address of the client that sent the request.
+func clientIP(r *http.Request) string {
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		return r.RemoteAddr
+	}
+	return host
+}
+
+// currentSessionID returns ID of the session whose refresh token is in the request cookie, if it is an active session of the user.
+func currentSessionID(cfg *ApiConfig, r *http.Request, userID uuid.UUID) uuid.NullUUID {
+	cookie, cookieErr := r.Cookie(refreshTokenName)
+	if cookieErr != nil {
+		return uuid.NullUUID{}
+	}
+	token, tokenErr := cfg.DB.GetRefreshToken(r.Context(), cookie.Value)
+	if tokenErr != nil || token.UserID != userID || token.RevokedAt.Valid {
+		return uuid.NullUUID{}
+	}
+	return uuid.NullUUID{UUID: token.FamilyID, Valid: true}
+}
+
+// revokeOtherSessions revokes refresh tokens of all user's sessions except the current one.
+func revokeOtherSessions(cfg *ApiConfig, r *http.Request, userID uuid.UUID) error {
+	count, err := cfg.DB.RevokeUserRefreshTokens(r.Context(), database.RevokeUserRefreshTokensParams{UserID: userID, KeepFamilyID: currentSessionID(cfg, r, userID)})
+	if err != nil {
+		return err
+	}
+	log.Printf("Revoked %d refresh tokens of other sessions of user %v", count, userID)
+	return nil
+}
+
+// getSessions returns user's active sessions, the one with refresh token from the request cookie is marked as current.
+func getSessions(cfg *ApiConfig, r *http.Request, userID uuid.UUID) ([]ResponseSession, error) {
+	currentToken := ""
+	if cookie, cookieErr := r.Cookie(refreshTokenName); cookieErr == nil {
+		currentToken = cookie.Value
+	}
+	sessions, err := cfg.DB.GetUserSessions(r.Context(), database.GetUserSessionsParams{CurrentToken: currentToken, UserID: userID})
+	if err != nil {
+		return nil, err
+	}
+
+	response := make([]ResponseSession, 0, len(sessions))
+	for _, session := range sessions {
+		response = append(response, ResponseSession{
+			ID:         session.FamilyID.String(),
+			UserAgent:  session.UserAgent,
+			IPAddress:  session.IpAddress,
+			StartedAt:  session.StartedAt,
+			LastUsedAt: session.LastUsedAt,
+			Current:    session.Current,
+		})
+	}
+	return response, nil
+}
+
+// @Summary Get sessions
+// @Description Gets user's active sessions: devices where the user is logged in. A session lasts from login while its refresh token is renewed
+// @Tags Sessions
+// @Accept json
+// @Produce json
+// @Success 200 {array} ResponseSession "Active sessions"
+// @Failure 401 {object} ErrorResponse "Unauthorized"
+// @Failure 500 {object} ErrorResponse
+// @Router /auth/sessions [get]
+func (cfg *ApiConfig) HandleGetAuthSessions(w http.ResponseWriter, r *http.Request) {
+	userID, _, authErr := checkAuthorization(cfg, r)
+	if authErr != nil {
+		common.RespondWithError(w, http.StatusUnauthorized, authErr.Error())
+		return
+	}
+
+	sessions, sessionsErr := getSessions(cfg, r, userID)
+	if sessionsErr != nil {
+		common.RespondWithError(w, http.StatusInternalServerError, sessionsErr.Error())
+		return
+	}
+	common.RespondWithJSON(w, http.StatusOK, sessions, nil)
+}
+
+// @Summary Revoke session
+// @Description Revokes refresh tokens of user's session, so the device has to log in again after its access token expires
+// @Tags Sessions
+// @Accept json
+// @Produce json
+// @Param sessionID path string true "Session ID"
+// @Success 204
+// @Failure 400 {object} ErrorResponse "Invalid session ID"
+// @Failure 401 {object} ErrorResponse "Unauthorized"
+// @Failure 404 {object} ErrorResponse "Session not found"
+// @Failure 500 {object} ErrorResponse
+// @Router /auth/sessions/{sessionID} [delete]
+func (cfg *ApiConfig) HandleDeleteAuthSessions(w http.ResponseWriter, r *http.Request) {
+	userID, _, authErr := checkAuthorization(cfg, r)
+	if authErr != nil {
+		common.RespondWithError(w, http.StatusUnauthorized, authErr.Error())
+		return
+	}
+
+	sessionID, parseErr := uuid.Parse(r.PathValue("sessionID"))
+	if parseErr != nil {
+		common.RespondWithError(w, http.StatusBadRequest, "Invalid session id")
+		return
+	}
+
+	count, revokeErr := cfg.DB.RevokeUserRefreshTokenFamily(r.Context(), database.RevokeUserRefreshTokenFamilyParams{UserID: userID, FamilyID: sessionID})
+	if revokeErr != nil {
+		common.RespondWithError(w, http.StatusInternalServerError, revokeErr.Error())
+		return
+	}
+	if count == 0 {
+		common.RespondWithError(w, http.StatusNotFound, "Session not found")
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// @Summary Log out everywhere
+// @Description Revokes refresh tokens of all user's sessions including the current one
+// @Tags Sessions
+// @Accept json
+// @Produce json
+// @Success 204
+// @Failure 401 {object} ErrorResponse "Unauthorized"
+// @Failure 500 {object} ErrorResponse
+// @Router /auth/logout-all [post]
+func (cfg *ApiConfig) HandlePostAuthLogoutAll(w http.ResponseWriter, r *http.Request) {
+	userID, _, authErr := checkAuthorization(cfg, r)
+	if authErr != nil {
+		common.RespondWithError(w, http.StatusUnauthorized, authErr.Error())
+		return
+	}
+
+	_, revokeErr := cfg.DB.RevokeUserRefreshTokens(r.Context(), database.RevokeUserRefreshTokensParams{UserID: userID})
+	if revokeErr != nil {
+		common.RespondWithError(w, http.StatusInternalServerError, revokeErr.Error())
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
@@ -0,0 +1,276 @@
+package server
+
+import (
+	"database/sql"
+	"encoding/json"
+	"log"
+	"net/http"
+	"time"
+
+	"github.com/bakurvik/mylib/users/internal/auth"
+	"github.com/bakurvik/mylib/users/internal/database"
+	"github.com/bakurvik/mylib/users/internal/totp"
+
+	common "github.com/bakurvik/mylib-common"
+	"github.com/google/uuid"
+)
+
+const (
+	challengeTokenExpiresIn = 5 * time.Minute
+	recoveryCodesCount      = 10
+	totpIssuer              = "MyLib"
+)
+
+// respondWithChallenge issues a short-lived challenge token that proves the password was checked.
+// It is an opaque one-time token, not a JWT, so it can't be mistaken for an access token by other services.
+func respondWithChallenge(w http.ResponseWriter, r *http.Request, cfg *ApiConfig, userID uuid.UUID) {
+	token, tokenErr := makeUserToken(r.Context(), cfg, userID, database.UserTokenPurposeTwoFactorChallenge, challengeTokenExpiresIn)
+	if tokenErr != nil {
+		common.RespondWithError(w, http.StatusInternalServerError, tokenErr.Error())
+		return
+	}
+	common.RespondWithJSON(w, http.StatusAccepted, ResponseTwoFactorChallenge{ChallengeToken: token}, nil)
+}
+
+// checkTwoFactorCode accepts either a TOTP code newer than the last used one or an unused recovery code.
+func checkTwoFactorCode(cfg *ApiConfig, r *http.Request, userID uuid.UUID, user database.GetUserTwoFactorRow, code string) (bool, error) {
+	if user.TotpSecret.Valid {
+		step, ok := totp.Validate(user.TotpSecret.String, code, time.Now())
+		if ok {
+			// The step is saved only if it is after the last used one, so a code can't be replayed.
+			count, err := cfg.DB.UseUserTOTPStep(r.Context(), database.UseUserTOTPStepParams{ID: userID, TotpLastUsedStep: step})
+			return count > 0, err
+		}
+	}
+
+	count, err := cfg.DB.UseRecoveryCode(r.Context(), database.UseRecoveryCodeParams{UserID: userID, CodeHash: auth.HashToken(auth.NormalizeRecoveryCode(code))})
+	if err != nil {
+		return false, err
+	}
+	if count > 0 {
+		log.Printf("User %v logged in with a recovery code", userID)
+	}
+	return count > 0, nil
+}
+
+// makeRecoveryCodes replaces user's recovery codes with new ones and returns them. Only hashes of the codes are stored.
+func makeRecoveryCodes(cfg *ApiConfig, r *http.Request, userID uuid.UUID) ([]string, error) {
+	err := cfg.DB.DeleteRecoveryCodes(r.Context(), userID)
+	if err != nil {
+		return nil, err
+	}
+	codes := make([]string, 0, recoveryCodesCount)
+	for range recoveryCodesCount {
+		code, codeErr := auth.MakeRecoveryCode()
+		if codeErr != nil {
+			return nil, codeErr
+		}
+		err = cfg.DB.CreateRecoveryCode(r.Context(), database.CreateRecoveryCodeParams{UserID: userID, CodeHash: auth.HashToken(auth.NormalizeRecoveryCode(code))})
+		if err != nil {
+			return nil, err
+		}
+		codes = append(codes, code)
+	}
+	return codes, nil
+}
+
+// @Summary Set up two-factor authentication
+// @Description Generates a new TOTP secret for the user. The secret is added to an authenticator app by otpauth URI, 2FA is turned on by /auth/2fa/enable
+// @Tags Two-factor authentication
+// @Accept json
+// @Produce json
+// @Success 200 {object} ResponseTwoFactorSetup "TOTP secret and otpauth URI"
+// @Failure 401 {object} ErrorResponse "Unauthorized"
+// @Failure 404 {object} ErrorResponse "User not found"
+// @Failure 409 {object} ErrorResponse "Two-factor authentication is already enabled"
+// @Failure 500 {object} ErrorResponse
+// @Router /auth/2fa/setup [post]
+func (cfg *ApiConfig) HandlePostAuthTwoFactorSetup(w http.ResponseWriter, r *http.Request) {
+	userID, _, authErr := checkAuthorization(cfg, r)
+	if authErr != nil {
+		common.RespondWithError(w, http.StatusUnauthorized, authErr.Error())
+		return
+	}
+
+	user, userErr := cfg.DB.GetUserTwoFactor(r.Context(), userID)
+	if userErr == sql.ErrNoRows {
+		common.RespondWithError(w, http.StatusNotFound, "User not found")
+		return
+	}
+	if userErr != nil {
+		common.RespondWithError(w, http.StatusInternalServerError, userErr.Error())
+		return
+	}
+
+	secret, secretErr := totp.GenerateSecret()
+	if secretErr != nil {
+		common.RespondWithError(w, http.StatusInternalServerError, secretErr.Error())
+		return
+	}
+	count, setErr := cfg.DB.SetUserTOTPSecret(r.Context(), database.SetUserTOTPSecretParams{ID: userID, TotpSecret: sql.NullString{String: secret, Valid: true}})
+	if setErr != nil {
+		common.RespondWithError(w, http.StatusInternalServerError, setErr.Error())
+		return
+	}
+	if count == 0 {
+		common.RespondWithError(w, http.StatusConflict, "Two-factor authentication is already enabled")
+		return
+	}
+
+	common.RespondWithJSON(w, http.StatusOK, ResponseTwoFactorSetup{Secret: secret, URI: totp.URI(totpIssuer, user.Email, secret)}, nil)
+}
+
+// @Summary Enable two-factor authentication
+// @Description Checks TOTP code for the secret from /auth/2fa/setup and turns on 2FA. Returns one-time recovery codes, they are shown only once
+// @Tags Two-factor authentication
+// @Accept json
+// @Produce json
+// @Param request body RequestTwoFactorCode true "TOTP code"
+// @Success 200 {object} ResponseRecoveryCodes "Recovery codes"
+// @Failure 400 {object} ErrorResponse "Invalid code or 2FA is not set up"
+// @Failure 401 {object} ErrorResponse "Unauthorized"
+// @Failure 404 {object} ErrorResponse "User not found"
+// @Failure 409 {object} ErrorResponse "Two-factor authentication is already enabled"
+// @Failure 500 {object} ErrorResponse
+// @Router /auth/2fa/enable [post]
+func (cfg *ApiConfig) HandlePostAuthTwoFactorEnable(w http.ResponseWriter, r *http.Request) {
+	userID, _, authErr := checkAuthorization(cfg, r)
+	if authErr != nil {
+		common.RespondWithError(w, http.StatusUnauthorized, authErr.Error())
+		return
+	}
+
+	decoder := json.NewDecoder(r.Body)
+	request := RequestTwoFactorCode{}
+	requestErr := decoder.Decode(&request)
+	if requestErr != nil {
+		common.RespondWithError(w, http.StatusBadRequest, requestErr.Error())
+		return
+	}
+
+	user, userErr := cfg.DB.GetUserTwoFactor(r.Context(), userID)
+	if userErr == sql.ErrNoRows {
+		common.RespondWithError(w, http.StatusNotFound, "User not found")
+		return
+	}
+	if userErr != nil {
+		common.RespondWithError(w, http.StatusInternalServerError, userErr.Error())
+		return
+	}
+	if user.TotpEnabled {
+		common.RespondWithError(w, http.StatusConflict, "Two-factor authentication is already enabled")
+		return
+	}
+	if !user.TotpSecret.Valid {
+		common.RespondWithError(w, http.StatusBadRequest, "Two-factor authentication is not set up")
+		return
+	}
+
+	step, ok := totp.Validate(user.TotpSecret.String, request.Code, time.Now())
+	if !ok {
+		common.RespondWithError(w, http.StatusBadRequest, "Invalid code")
+		return
+	}
+	count, enableErr := cfg.DB.EnableUserTOTP(r.Context(), database.EnableUserTOTPParams{ID: userID, TotpLastUsedStep: step})
+	if enableErr != nil {
+		common.RespondWithError(w, http.StatusInternalServerError, enableErr.Error())
+		return
+	}
+	if count == 0 {
+		common.RespondWithError(w, http.StatusConflict, "Two-factor authentication is already enabled")
+		return
+	}
+
+	codes, codesErr := makeRecoveryCodes(cfg, r, userID)
+	if codesErr != nil {
+		common.RespondWithError(w, http.StatusInternalServerError, codesErr.Error())
+		return
+	}
+	common.RespondWithJSON(w, http.StatusOK, ResponseRecoveryCodes{Codes: codes}, nil)
+}
+
+// @Summary Verify two-factor code
+// @Description Second step of login with 2FA: exchanges challenge token from /auth/login and a TOTP or recovery code for access and refresh tokens. Failed attempts are limited like login attempts
+// @Tags Two-factor authentication
+// @Accept json
+// @Produce json
+// @Param request body RequestTwoFactorVerify true "Challenge token and code"
+// @Success 200 {object} ResponseToken "Logined successfully"
+// @Header 200 {string} Set-Cookie "HTTP-only cookie named refresh_token"
+// @Failure 400 {object} ErrorResponse "Invalid request body"
+// @Failure 401 {object} ErrorResponse "Invalid or expired challenge or invalid code"
+// @Header 401 {integer} Retry-After "Seconds until login is unblocked, if the attempt blocked it"
+// @Failure 429 {object} ErrorResponse "Too many failed login attempts"
+// @Header 429 {integer} Retry-After "Seconds until login is unblocked"
+// @Failure 500 {object} ErrorResponse
+// @Router /auth/2fa/verify [post]
+func (cfg *ApiConfig) HandlePostAuthTwoFactorVerify(w http.ResponseWriter, r *http.Request) {
+	decoder := json.NewDecoder(r.Body)
+	request := RequestTwoFactorVerify{}
+	requestErr := decoder.Decode(&request)
+	if requestErr != nil {
+		common.RespondWithError(w, http.StatusBadRequest, requestErr.Error())
+		return
+	}
+
+	challengeHash := auth.HashToken(request.ChallengeToken)
+	userID, challengeErr := cfg.DB.GetActiveUserToken(r.Context(), database.GetActiveUserTokenParams{TokenHash: challengeHash, Purpose: database.UserTokenPurposeTwoFactorChallenge})
+	if challengeErr == sql.ErrNoRows {
+		common.RespondWithError(w, http.StatusUnauthorized, "Invalid or expired challenge")
+		return
+	}
+	if challengeErr != nil {
+		common.RespondWithError(w, http.StatusInternalServerError, challengeErr.Error())
+		return
+	}
+	user, userErr := cfg.DB.GetUserTwoFactor(r.Context(), userID)
+	if userErr != nil {
+		common.RespondWithError(w, http.StatusInternalServerError, userErr.Error())
+		return
+	}
+
+	now := time.Now().UTC()
+	limits := loginLimits(cfg, r, user.Email)
+	blockedFor, blockedErr := loginBlockedFor(cfg, r, limits, now)
+	if blockedErr != nil {
+		common.RespondWithError(w, http.StatusInternalServerError, blockedErr.Error())
+		return
+	}
+	if blockedFor > 0 {
+		setRetryAfter(w, blockedFor)
+		common.RespondWithError(w, http.StatusTooManyRequests, "Too many failed login attempts, try again later")
+		return
+	}
+
+	ok, codeErr := checkTwoFactorCode(cfg, r, userID, user, request.Code)
+	if codeErr != nil {
+		common.RespondWithError(w, http.StatusInternalServerError, codeErr.Error())
+		return
+	}
+	if !ok {
+		blockedFor, failureErr := addLoginFailure(cfg, r, limits, now)
+		if failureErr != nil {
+			common.RespondWithError(w, http.StatusInternalServerError, failureErr.Error())
+			return
+		}
+		if blockedFor > 0 {
+			setRetryAfter(w, blockedFor)
+		}
+		common.RespondWithError(w, http.StatusUnauthorized, "Invalid code")
+		return
+	}
+
+	// The challenge is consumed only after a valid code, so a typo doesn't require entering the password again.
+	_, useErr := cfg.DB.UseUserToken(r.Context(), database.UseUserTokenParams{TokenHash: challengeHash, Purpose: database.UserTokenPurposeTwoFactorChallenge})
+	if useErr == sql.ErrNoRows {
+		common.RespondWithError(w, http.StatusUnauthorized, "Invalid or expired challenge")
+		return
+	}
+	if useErr != nil {
+		common.RespondWithError(w, http.StatusInternalServerError, useErr.Error())
+		return
+	}
+
+	resetLoginFailures(cfg, r, user.Email)
+	makeTokensAndRespond(w, r, cfg, userID, string(user.Role), tokenFamily{}, http.StatusOK)
+}
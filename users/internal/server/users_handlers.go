@@ -4,8 +4,9 @@ import (
 	"database/sql"
 	"encoding/json"
 	"errors"
+	"log"
 	"net/http"
-	"strings"
+	"net/mail"
 
 	"github.com/bakurvik/mylib/users/internal/database"
 
@@ -20,12 +21,12 @@ func validateUserData(cfg *ApiConfig, r *http.Request, requestBody RequestUser)
 	if len(requestBody.LoginName) < minLoginLen {
 		return http.StatusBadRequest, errors.New("login name is too short")
 	}
-	if !strings.Contains(requestBody.Email, "@") {
+	if !isValidEmail(requestBody.Email) {
 		return http.StatusBadRequest, errors.New("invalid email")
 	}
-	const minPasswordLen = 5
-	if len(requestBody.Password) < minPasswordLen {
-		return http.StatusBadRequest, errors.New("password is too short")
+	passwordErr := validatePassword(requestBody.Password)
+	if passwordErr != nil {
+		return http.StatusBadRequest, passwordErr
 	}
 
 	rows, getUserErr := cfg.DB.GetUser(r.Context(), database.GetUserParams{LoginName: requestBody.LoginName, Email: requestBody.Email})
@@ -44,16 +45,31 @@ func validateUserData(cfg *ApiConfig, r *http.Request, requestBody RequestUser)
 	return 0, nil
 }
 
-func checkAuthorization(cfg *ApiConfig, r *http.Request) (uuid.UUID, error) {
+// isValidEmail checks that email is a bare address like user@example.com, without a display name.
+func isValidEmail(email string) bool {
+	address, err := mail.ParseAddress(email)
+	return err == nil && address.Address == email
+}
+
+func validatePassword(password string) error {
+	const minPasswordLen = 5
+	if len(password) < minPasswordLen {
+		return errors.New("password is too short")
+	}
+	return nil
+}
+
+// checkAuthorization validates access token from Authorization header and returns user ID and role from it.
+func checkAuthorization(cfg *ApiConfig, r *http.Request) (uuid.UUID, string, error) {
 	token, tokenErr := auth.GetBearerToken(r.Header)
 	if tokenErr != nil {
-		return uuid.UUID{}, tokenErr
+		return uuid.UUID{}, "", tokenErr
 	}
-	userID, authErr := auth.ValidateJWT(token, cfg.AuthSecretKey)
+	userID, role, authErr := cfg.Keys.ValidateJWT(token)
 	if authErr != nil {
-		return uuid.UUID{}, authErr
+		return uuid.UUID{}, "", authErr
 	}
-	return userID, nil
+	return userID, role, nil
 }
 
 // @Summary Ping the server
@@ -68,7 +84,7 @@ func (cfg *ApiConfig) HandlePing(w http.ResponseWriter, r *http.Request) {
 }
 
 // @Summary Create new user
-// @Description Creates new user and stores it in DB
+// @Description Creates new user and stores it in DB. Sends a link that verifies user's email
 // @Tags Users
 // @Accept json
 // @Produce json
@@ -105,11 +121,17 @@ func (cfg *ApiConfig) HandlePostApiUsers(w http.ResponseWriter, r *http.Request)
 		return
 	}
 
-	makeTokensAndRespond(w, r, cfg, userID, http.StatusCreated)
+	// The user is already created, so a failure to send the link doesn't fail the request.
+	verifyErr := sendVerificationEmail(cfg, r, userID, request.Email)
+	if verifyErr != nil {
+		log.Print("Failed to send email verification link: ", verifyErr)
+	}
+
+	makeTokensAndRespond(w, r, cfg, userID, auth.RoleReader, tokenFamily{}, http.StatusCreated)
 }
 
 // @Summary Update user
-// @Description Updates existing user's info in DB. Uses access token from an HTTP-only cookie
+// @Description Updates existing user's info in DB. Uses access token from an HTTP-only cookie. Changing password revokes all other sessions of the user, changing email sends a new verification link
 // @Tags Users
 // @Accept json
 // @Produce json
@@ -120,7 +142,7 @@ func (cfg *ApiConfig) HandlePostApiUsers(w http.ResponseWriter, r *http.Request)
 // @Failure 500 {object} ErrorResponse
 // @Router /api/users [put]
 func (cfg *ApiConfig) HandlePutApiUsers(w http.ResponseWriter, r *http.Request) {
-	userID, authErr := checkAuthorization(cfg, r)
+	userID, _, authErr := checkAuthorization(cfg, r)
 	if authErr != nil {
 		common.RespondWithError(w, http.StatusUnauthorized, authErr.Error())
 		return
@@ -139,6 +161,16 @@ func (cfg *ApiConfig) HandlePutApiUsers(w http.ResponseWriter, r *http.Request)
 		return
 	}
 
+	oldUser, oldUserErr := cfg.DB.GetUserHashedPassword(r.Context(), userID)
+	if oldUserErr == sql.ErrNoRows {
+		common.RespondWithError(w, http.StatusNotFound, oldUserErr.Error())
+		return
+	}
+	if oldUserErr != nil {
+		common.RespondWithError(w, http.StatusInternalServerError, oldUserErr.Error())
+		return
+	}
+
 	hashedPassword, hashErr := auth.HashPassword(request.Password)
 	if hashErr != nil {
 		common.RespondWithError(w, http.StatusInternalServerError, hashErr.Error())
@@ -151,21 +183,42 @@ func (cfg *ApiConfig) HandlePutApiUsers(w http.ResponseWriter, r *http.Request)
 		return
 	}
 
+	// Whoever knew the old password must not stay logged in on other devices.
+	if auth.CheckPasswordHash(oldUser.HashedPassword, request.Password) != nil {
+		revokeErr := revokeOtherSessions(cfg, r, userID)
+		if revokeErr != nil {
+			common.RespondWithError(w, http.StatusInternalServerError, revokeErr.Error())
+			return
+		}
+	}
+
+	// UpdateUser resets verification of a changed email, links sent to the old email must not verify the new one.
+	if oldUser.Email != request.Email {
+		invalidateErr := cfg.DB.InvalidateUserTokens(r.Context(), database.InvalidateUserTokensParams{UserID: userID, Purpose: database.UserTokenPurposeEmailVerification})
+		if invalidateErr != nil {
+			common.RespondWithError(w, http.StatusInternalServerError, invalidateErr.Error())
+			return
+		}
+		verifyErr := sendVerificationEmail(cfg, r, userID, request.Email)
+		if verifyErr != nil {
+			log.Print("Failed to send email verification link: ", verifyErr)
+		}
+	}
+
 	w.WriteHeader(http.StatusNoContent)
 }
 
-// @Summary Get user info
-// @Description Gets user from DB
+// @Summary Get user's public profile
+// @Description Gets public profile of any user. Email and birth date are shown only to the user by /api/users/me
 // @Tags Users
 // @Accept json
 // @Produce json
-// @Param userID path string true "Author ID"
-// @Success 200 {object} ResponseUser "User's full info"
-// @Success 400 {object} ErrorResponse "Invalid user ID"
-// @Failure 401 {object} ErrorResponse "Unauthorized"
+// @Param userID path string true "User ID"
+// @Success 200 {object} ResponseUser "User's public profile"
+// @Failure 400 {object} ErrorResponse "Invalid user ID"
 // @Failure 404 {object} ErrorResponse "User not found"
 // @Failure 500 {object} ErrorResponse
-// @Router /api/users/{id} [get]
+// @Router /api/users/{userID} [get]
 func (cfg *ApiConfig) HandleGetApiUsers(w http.ResponseWriter, r *http.Request) {
 	requestUserID := r.PathValue("userID")
 	if len(requestUserID) == 0 {
@@ -178,11 +231,9 @@ func (cfg *ApiConfig) HandleGetApiUsers(w http.ResponseWriter, r *http.Request)
 		return
 	}
 
-	authUserID, _ := checkAuthorization(cfg, r)
-
 	user, userErr := cfg.DB.GetUserByID(r.Context(), requestUserUUID)
 	if userErr == sql.ErrNoRows {
-		common.RespondWithError(w, http.StatusNotFound, userErr.Error())
+		common.RespondWithError(w, http.StatusNotFound, "User not found")
 		return
 	}
 	if userErr != nil {
@@ -190,16 +241,11 @@ func (cfg *ApiConfig) HandleGetApiUsers(w http.ResponseWriter, r *http.Request)
 		return
 	}
 
-	response := ResponseUser{LoginName: user.LoginName}
-	if authUserID == requestUserUUID {
-		response.BirthDate = common.NullTimeToString(user.BirthDate)
-		response.Email = user.Email
-	}
-	common.RespondWithJSON(w, http.StatusOK, response, nil)
+	common.RespondWithJSON(w, http.StatusOK, publicProfile(requestUserUUID, user), nil)
 }
 
 // @Summary Delete user
-// @Description Deletes user from DB. Uses access token from an HTTP-only cookie
+// @Description Deletes user from DB and publishes user deleted event, so user-reading deletes user's reading data. Uses access token from an HTTP-only cookie
 // @Tags Users
 // @Accept json
 // @Produce json
@@ -208,13 +254,19 @@ func (cfg *ApiConfig) HandleGetApiUsers(w http.ResponseWriter, r *http.Request)
 // @Failure 500 {object} ErrorResponse
 // @Router /api/users [delete]
 func (cfg *ApiConfig) HandleDeleteApiUsers(w http.ResponseWriter, r *http.Request) {
-	userID, authErr := checkAuthorization(cfg, r)
+	userID, _, authErr := checkAuthorization(cfg, r)
 	if authErr != nil {
 		common.RespondWithError(w, http.StatusUnauthorized, authErr.Error())
 		return
 	}
 
-	deleteErr := cfg.DB.DeleteUser(r.Context(), userID)
+	// The user and the message are stored by one statement, so user-reading is told about every deleted user.
+	payload, payloadErr := json.Marshal(UserMessage{ID: userID.String(), Action: actionDeleted})
+	if payloadErr != nil {
+		common.RespondWithError(w, http.StatusInternalServerError, payloadErr.Error())
+		return
+	}
+	_, deleteErr := cfg.DB.DeleteUserWithOutboxMessage(r.Context(), database.DeleteUserWithOutboxMessageParams{UserID: userID, Topic: UsersTopic, Payload: payload})
 	if deleteErr != nil {
 		common.RespondWithError(w, http.StatusInternalServerError, deleteErr.Error())
 		return
@@ -2,6 +2,7 @@ package server
 
 import (
 	"net/http"
+	"strings"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -27,6 +28,18 @@ func TestValidateUserData(t *testing.T) {
 			expectedError:      "invalid email",
 			expectedStatusCode: http.StatusBadRequest,
 		},
+		{
+			name:               "email_with_display_name",
+			user:               RequestUser{LoginName: "login", Email: "Name <email@email.ru>", BirthDate: "04.02.2004", Password: "password"},
+			expectedError:      "invalid email",
+			expectedStatusCode: http.StatusBadRequest,
+		},
+		{
+			name:               "email_without_domain",
+			user:               RequestUser{LoginName: "login", Email: "email@", BirthDate: "04.02.2004", Password: "password"},
+			expectedError:      "invalid email",
+			expectedStatusCode: http.StatusBadRequest,
+		},
 		{
 			name:               "invalid_password",
 			user:               RequestUser{LoginName: "login", Email: "email@email.ru", BirthDate: "04.02.2004", Password: "pas"},
@@ -43,3 +56,31 @@ func TestValidateUserData(t *testing.T) {
 		})
 	}
 }
+
+func TestValidateUserProfile(t *testing.T) {
+	type testCase struct {
+		name           string
+		profile        RequestUserProfile
+		expectedError  bool
+		expectedLocale string
+	}
+	testCases := []testCase{
+		{name: "empty", profile: RequestUserProfile{}, expectedLocale: "en"},
+		{name: "full", profile: RequestUserProfile{DisplayName: "Name", Bio: "Bio", AvatarURL: "https://example.com/a.png", Locale: "pt-BR"}, expectedLocale: "pt-BR"},
+		{name: "locale_with_script", profile: RequestUserProfile{Locale: "zh-Hant-TW"}, expectedLocale: "zh-Hant-TW"},
+		{name: "long_display_name", profile: RequestUserProfile{DisplayName: strings.Repeat("a", maxDisplayNameLen+1)}, expectedError: true},
+		{name: "long_bio", profile: RequestUserProfile{Bio: strings.Repeat("a", maxBioLen+1)}, expectedError: true},
+		{name: "avatar_not_http", profile: RequestUserProfile{AvatarURL: "javascript:alert(1)"}, expectedError: true},
+		{name: "avatar_relative", profile: RequestUserProfile{AvatarURL: "/avatar.png"}, expectedError: true},
+		{name: "invalid_locale", profile: RequestUserProfile{Locale: "english"}, expectedError: true},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			err := validateUserProfile(&tc.profile)
+			assert.Equal(t, err != nil, tc.expectedError)
+			if !tc.expectedError {
+				assert.Equal(t, tc.profile.Locale, tc.expectedLocale)
+			}
+		})
+	}
+}
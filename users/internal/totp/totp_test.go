@@ -0,0 +1,86 @@
+package totp
+
+import (
+	"encoding/base32"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// rfcSecret is the SHA-1 key of RFC 6238 test vectors.
+var rfcSecret = base32.StdEncoding.WithPadding(base32.NoPadding).EncodeToString([]byte("12345678901234567890"))
+
+func TestCode(t *testing.T) {
+	type testCase struct {
+		name         string
+		time         int64
+		expectedCode string
+	}
+	// RFC 6238 test vectors truncated to 6 digits.
+	testCases := []testCase{
+		{name: "59", time: 59, expectedCode: "287082"},
+		{name: "1111111109", time: 1111111109, expectedCode: "081804"},
+		{name: "1111111111", time: 1111111111, expectedCode: "050471"},
+		{name: "1234567890", time: 1234567890, expectedCode: "005924"},
+		{name: "2000000000", time: 2000000000, expectedCode: "279037"},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			code, err := Code(rfcSecret, Step(time.Unix(tc.time, 0)))
+			assert.NoError(t, err)
+			assert.Equal(t, code, tc.expectedCode)
+		})
+	}
+}
+
+func TestValidate(t *testing.T) {
+	now := time.Unix(1111111109, 0)
+	current := Step(now)
+	previousCode, _ := Code(rfcSecret, current-1)
+	oldCode, _ := Code(rfcSecret, current-2)
+	type testCase struct {
+		name         string
+		code         string
+		expectedStep int64
+		expectedOK   bool
+	}
+	testCases := []testCase{
+		{name: "current_step", code: "081804", expectedStep: current, expectedOK: true},
+		{name: "previous_step", code: previousCode, expectedStep: current - 1, expectedOK: true},
+		{name: "too_old", code: oldCode, expectedOK: false},
+		{name: "wrong_code", code: "123456", expectedOK: false},
+		{name: "wrong_length", code: "81804", expectedOK: false},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			step, ok := Validate(rfcSecret, tc.code, now)
+			assert.Equal(t, ok, tc.expectedOK)
+			assert.Equal(t, step, tc.expectedStep)
+		})
+	}
+}
+
+func TestGenerateSecret(t *testing.T) {
+	secret, err := GenerateSecret()
+	assert.NoError(t, err)
+	assert.Equal(t, len(secret), 32)
+	_, err = Code(secret, 1)
+	assert.NoError(t, err)
+
+	anotherSecret, _ := GenerateSecret()
+	assert.NotEqual(t, anotherSecret, secret)
+}
+
+func TestURI(t *testing.T) {
+	uri, err := url.Parse(URI("MyLib", "user@email.com", "SECRET"))
+	assert.NoError(t, err)
+	assert.Equal(t, uri.Scheme, "otpauth")
+	assert.Equal(t, uri.Host, "totp")
+	assert.Equal(t, uri.Path, "/MyLib:user@email.com")
+	assert.Equal(t, uri.Query().Get("secret"), "SECRET")
+	assert.Equal(t, uri.Query().Get("issuer"), "MyLib")
+	assert.Equal(t, uri.Query().Get("digits"), "6")
+	assert.Equal(t, uri.Query().Get("period"), "30")
+}
@@ -1,17 +1,42 @@
 package main
 
 import (
+	"context"
+	"fmt"
 	"log"
 	"net/http"
 	"os"
+	"strconv"
+	"strings"
+	"time"
 
 	common "github.com/bakurvik/mylib-common"
+	"github.com/bakurvik/mylib/shared/outbox"
+	"github.com/bakurvik/mylib/users/internal/auth"
 	"github.com/bakurvik/mylib/users/internal/database"
+	"github.com/bakurvik/mylib/users/internal/lockout"
+	"github.com/bakurvik/mylib/users/internal/mailer"
+	"github.com/bakurvik/mylib/users/internal/oidc"
 	"github.com/bakurvik/mylib/users/internal/server"
 
 	_ "github.com/bakurvik/mylib/users/docs"
 
 	_ "github.com/lib/pq"
+	"github.com/segmentio/kafka-go"
+)
+
+const (
+	defaultLoginBackoffBase       = time.Second
+	defaultLoginBackoffMax        = time.Minute
+	defaultLoginLockoutDuration   = 15 * time.Minute
+	defaultAccountFreeAttempts    = 3
+	defaultAccountLockoutAttempts = 10
+	defaultIPFreeAttempts         = 20
+	defaultIPLockoutAttempts      = 100
+	defaultOutboxBatchSize        = 100
+	defaultOutboxRelayPeriod      = time.Second
+	outboxBatchTimeout            = 10 * time.Millisecond
+	defaultOutboxRetention        = 7 * 24 * time.Hour
 )
 
 // @title Users Service API
@@ -30,8 +55,43 @@ func main() {
 		log.Fatal("Failed setup db ", err)
 	}
 
+	keys, err := loadKeySet()
+	if err != nil {
+		log.Fatal("Failed to load JWT keys: ", err)
+	}
+
+	usersKafkaWriter := kafka.NewWriter(kafka.WriterConfig{
+		Brokers:      []string{"localhost:9092"},
+		Topic:        server.UsersTopic,
+		BatchTimeout: outboxBatchTimeout,
+	})
+	defer usersKafkaWriter.Close()
+
+	relay := outbox.Relay{
+		DB:        db,
+		Writers:   map[string]outbox.KafkaWriter{server.UsersTopic: usersKafkaWriter},
+		BatchSize: getLimit("OUTBOX_BATCH_SIZE", defaultOutboxBatchSize),
+		Retention: getDuration("OUTBOX_RETENTION", defaultOutboxRetention),
+	}
+	relayPeriod := getDuration("OUTBOX_RELAY_PERIOD", defaultOutboxRelayPeriod)
+	if relayPeriod == 0 {
+		relayPeriod = defaultOutboxRelayPeriod
+	}
+	go relay.Run(context.Background(), &outbox.TimeTicker{T: time.NewTicker(relayPeriod)})
+
+	publicURL := getEnv("USERS_PUBLIC_URL", "http://localhost:8081")
 	sm := http.NewServeMux()
-	apiCfg := server.ApiConfig{DB: database.New(db), AuthSecretKey: os.Getenv("AUTH_SECRET_KEY")}
+	apiCfg := server.ApiConfig{
+		DB:                     database.New(db),
+		Keys:                   keys,
+		Mailer:                 getMailer(),
+		PublicURL:              publicURL,
+		PasswordResetURL:       getEnv("PASSWORD_RESET_URL", "http://localhost:5173/reset-password"),
+		AccountLockout:         getLockoutPolicy("ACCOUNT", defaultAccountFreeAttempts, defaultAccountLockoutAttempts),
+		IPLockout:              getLockoutPolicy("IP", defaultIPFreeAttempts, defaultIPLockoutAttempts),
+		OIDCProviders:          getOIDCProviders(publicURL),
+		UserReadingServiceHost: os.Getenv("USER_READING_SERVICE_HOST"),
+	}
 	server.Handle(sm, &apiCfg)
 
 	s := http.Server{
@@ -43,3 +103,105 @@ func main() {
 		log.Fatal("Failed starting server: ", serverErr)
 	}
 }
+
+// loadKeySet loads token signing keys from JWT_KEYS_DIR, JWT_KEY_ID selects the key that signs new tokens.
+// Without JWT_KEYS_DIR a temporary key is generated, so issued tokens become invalid after restart.
+func loadKeySet() (*auth.KeySet, error) {
+	dir := os.Getenv("JWT_KEYS_DIR")
+	if dir != "" {
+		return auth.LoadKeySet(dir, os.Getenv("JWT_KEY_ID"))
+	}
+	log.Print("JWT_KEYS_DIR is not set, using a temporary signing key")
+	key, err := auth.GenerateSigningKey()
+	if err != nil {
+		return nil, err
+	}
+	return auth.NewKeySet(key)
+}
+
+// getMailer sends emails through SMTP_ADDR if it is set. Otherwise emails are written to files in MAIL_DIR
+// or to the log, so password reset and email verification work locally without a mail server.
+func getMailer() mailer.Mailer {
+	from := getEnv("MAIL_FROM", "mylib@localhost")
+	if addr := os.Getenv("SMTP_ADDR"); addr != "" {
+		return &mailer.SMTPMailer{Addr: addr, From: from, Username: os.Getenv("SMTP_USERNAME"), Password: os.Getenv("SMTP_PASSWORD")}
+	}
+	if dir := os.Getenv("MAIL_DIR"); dir != "" {
+		log.Print("SMTP_ADDR is not set, writing emails to ", dir)
+		return &mailer.FileMailer{Dir: dir, From: from}
+	}
+	log.Print("SMTP_ADDR and MAIL_DIR are not set, writing emails to the log")
+	return &mailer.LogMailer{}
+}
+
+// getOIDCProviders reads OpenID Connect providers listed in OIDC_PROVIDERS, e.g. "google,gitlab".
+// Each provider is configured by OIDC_<NAME>_ISSUER, OIDC_<NAME>_CLIENT_ID, OIDC_<NAME>_CLIENT_SECRET
+// and optional OIDC_<NAME>_SCOPES, its callback is <USERS_PUBLIC_URL>/auth/oidc/<name>/callback.
+func getOIDCProviders(publicURL string) map[string]*oidc.Provider {
+	providers := map[string]*oidc.Provider{}
+	for _, name := range strings.Split(os.Getenv("OIDC_PROVIDERS"), ",") {
+		name = strings.ToLower(strings.TrimSpace(name))
+		if name == "" {
+			continue
+		}
+		prefix := "OIDC_" + strings.ToUpper(name) + "_"
+		config := oidc.Config{
+			Name:         name,
+			Issuer:       os.Getenv(prefix + "ISSUER"),
+			ClientID:     os.Getenv(prefix + "CLIENT_ID"),
+			ClientSecret: os.Getenv(prefix + "CLIENT_SECRET"),
+			RedirectURL:  fmt.Sprintf("%v%v/%v/callback", publicURL, server.AuthOIDCPath, name),
+			Scopes:       strings.Fields(os.Getenv(prefix + "SCOPES")),
+		}
+		if config.Issuer == "" || config.ClientID == "" {
+			log.Printf("%vISSUER or %vCLIENT_ID is not set, provider %v is disabled", prefix, prefix, name)
+			continue
+		}
+		providers[name] = oidc.NewProvider(config)
+	}
+	return providers
+}
+
+func getEnv(key string, defaultValue string) string {
+	value := os.Getenv(key)
+	if value == "" {
+		return defaultValue
+	}
+	return value
+}
+
+// getLockoutPolicy reads limits of failed login attempts for the scope (ACCOUNT or IP) from LOGIN_<scope>_* variables,
+// backoff delays are shared by both scopes.
+func getLockoutPolicy(scope string, defaultFreeAttempts int, defaultLockoutAttempts int) lockout.Policy {
+	return lockout.Policy{
+		FreeAttempts:    getLimit(fmt.Sprintf("LOGIN_%v_FREE_ATTEMPTS", scope), defaultFreeAttempts),
+		BaseDelay:       getDuration("LOGIN_BACKOFF_BASE", defaultLoginBackoffBase),
+		MaxDelay:        getDuration("LOGIN_BACKOFF_MAX", defaultLoginBackoffMax),
+		LockoutAttempts: getLimit(fmt.Sprintf("LOGIN_%v_LOCKOUT_ATTEMPTS", scope), defaultLockoutAttempts),
+		LockoutDuration: getDuration(fmt.Sprintf("LOGIN_%v_LOCKOUT_DURATION", scope), defaultLoginLockoutDuration),
+	}
+}
+
+func getLimit(varName string, defaultValue int) int {
+	if os.Getenv(varName) == "" {
+		return defaultValue
+	}
+	limit, err := strconv.Atoi(os.Getenv(varName))
+	if err != nil || limit < 0 {
+		log.Printf("Invalid limit %v value: %v", varName, os.Getenv(varName))
+		return defaultValue
+	}
+	return limit
+}
+
+func getDuration(varName string, defaultValue time.Duration) time.Duration {
+	if os.Getenv(varName) == "" {
+		return defaultValue
+	}
+	duration, err := time.ParseDuration(os.Getenv(varName))
+	if err != nil || duration < 0 {
+		log.Printf("Invalid duration %v value: %v", varName, os.Getenv(varName))
+		return defaultValue
+	}
+	return duration
+}
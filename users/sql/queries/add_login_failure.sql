@@ -0,0 +1,7 @@
+-- name: AddLoginFailure :one
+INSERT INTO login_attempts (key, failures, last_failed_at, blocked_until)
+VALUES (sqlc.arg(key), 1, sqlc.arg(failed_at), sqlc.arg(failed_at))
+ON CONFLICT (key) DO UPDATE SET
+    failures = CASE WHEN login_attempts.last_failed_at > sqlc.arg(forget_before) THEN login_attempts.failures + 1 ELSE 1 END,
+    last_failed_at = sqlc.arg(failed_at)
+RETURNING failures;
@@ -0,0 +1,3 @@
+-- name: BlockLogin :exec
+UPDATE login_attempts SET blocked_until = GREATEST(blocked_until, $2)
+WHERE key = $1;
@@ -0,0 +1,3 @@
+-- name: CreateOIDCState :exec
+INSERT INTO oidc_states (state_hash, provider, code_verifier, nonce, expires_at)
+VALUES ($1, $2, $3, $4, $5);
@@ -0,0 +1,9 @@
+-- name: CreateOIDCUser :one
+WITH new_user AS (
+    INSERT INTO users (id, login_name, email, hashed_password, email_verified, created_at, updated_at)
+    VALUES (gen_random_uuid(), @login_name, @email, @hashed_password, TRUE, NOW(), NOW())
+    RETURNING users.id
+)
+INSERT INTO user_identities (provider, subject, user_id, email, created_at)
+SELECT @provider, @subject, new_user.id, @email, NOW() FROM new_user
+RETURNING user_identities.user_id;
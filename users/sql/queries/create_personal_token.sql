@@ -0,0 +1,4 @@
+-- name: CreatePersonalToken :one
+INSERT INTO personal_tokens (id, user_id, name, token_hash, scopes, created_at, expires_at)
+VALUES (gen_random_uuid(), $1, $2, $3, $4, NOW(), $5)
+RETURNING id, created_at;
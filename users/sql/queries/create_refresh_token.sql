@@ -1,5 +1,5 @@
 -- name: CreateRefreshToken :exec
-INSERT INTO refresh_tokens (token, user_id, expires_at, created_at, updated_at)
+INSERT INTO refresh_tokens (token, user_id, expires_at, family_id, parent_token, user_agent, ip_address, last_used_at, created_at, updated_at)
 VALUES (
-    $1, $2, $3, NOW(), NOW()
+    $1, $2, $3, $4, $5, $6, $7, NOW(), NOW(), NOW()
 );
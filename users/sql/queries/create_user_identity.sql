@@ -0,0 +1,3 @@
+-- name: CreateUserIdentity :exec
+INSERT INTO user_identities (provider, subject, user_id, email, created_at)
+VALUES ($1, $2, $3, $4, NOW());
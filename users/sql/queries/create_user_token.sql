@@ -0,0 +1,3 @@
+-- name: CreateUserToken :exec
+INSERT INTO user_tokens (token_hash, user_id, purpose, created_at, expires_at)
+VALUES ($1, $2, $3, NOW(), $4);
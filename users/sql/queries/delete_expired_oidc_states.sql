@@ -0,0 +1,3 @@
+-- name: DeleteExpiredOIDCStates :exec
+DELETE FROM oidc_states
+WHERE expires_at <= NOW();
@@ -1,2 +0,0 @@
--- name: DeleteUser :exec
-DELETE FROM users WHERE id = $1;
\ No newline at end of file
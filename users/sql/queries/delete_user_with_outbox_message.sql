@@ -0,0 +1,7 @@
+-- name: DeleteUserWithOutboxMessage :execrows
+WITH deleted AS (
+    DELETE FROM users WHERE users.id = @user_id
+    RETURNING users.id
+)
+INSERT INTO outbox (topic, message_key, payload, created_at)
+SELECT @topic::TEXT, deleted.id::TEXT, @payload::BYTEA, NOW() FROM deleted;
@@ -0,0 +1,3 @@
+-- name: EnableUserTOTP :execrows
+UPDATE users SET totp_enabled = TRUE, totp_last_used_step = $2, updated_at = NOW()
+WHERE id = $1 AND NOT totp_enabled AND totp_secret IS NOT NULL;
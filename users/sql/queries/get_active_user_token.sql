@@ -0,0 +1,3 @@
+-- name: GetActiveUserToken :one
+SELECT user_id FROM user_tokens
+WHERE token_hash = $1 AND purpose = $2 AND expires_at > NOW() AND used_at IS NULL;
@@ -0,0 +1,3 @@
+-- name: GetLoginBlockedUntil :one
+SELECT blocked_until FROM login_attempts
+WHERE key = $1;
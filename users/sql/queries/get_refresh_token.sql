@@ -0,0 +1,3 @@
+-- name: GetRefreshToken :one
+SELECT user_id, family_id, revoked_at FROM refresh_tokens
+WHERE token = $1;
@@ -1,3 +1,3 @@
 -- name: GetUserByEmail :one
-SELECT id, hashed_password FROM users
-WHERE email = $1;
\ No newline at end of file
+SELECT id, login_name, hashed_password, role, totp_enabled, email_verified FROM users
+WHERE email = $1;
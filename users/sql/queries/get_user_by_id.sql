@@ -1,3 +1,5 @@
 -- name: GetUserByID :one
-SELECT login_name, email, birth_date FROM users
+SELECT login_name, email, birth_date, email_verified, totp_enabled,
+    display_name, bio, avatar_url, locale, reading_list_public, created_at
+FROM users
 WHERE id = $1;
@@ -0,0 +1,3 @@
+-- name: GetUserHashedPassword :one
+SELECT hashed_password, email FROM users
+WHERE id = $1;
@@ -0,0 +1,3 @@
+-- name: GetUserIdentity :one
+SELECT user_id FROM user_identities
+WHERE provider = $1 AND subject = $2;
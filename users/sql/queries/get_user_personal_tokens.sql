@@ -0,0 +1,4 @@
+-- name: GetUserPersonalTokens :many
+SELECT id, name, scopes, created_at, expires_at, last_used_at FROM personal_tokens
+WHERE user_id = $1 AND revoked_at IS NULL AND expires_at > NOW()
+ORDER BY created_at DESC, id;
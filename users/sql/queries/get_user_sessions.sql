@@ -0,0 +1,7 @@
+-- name: GetUserSessions :many
+SELECT rt.family_id, rt.user_agent, rt.ip_address, rt.last_used_at,
+    (SELECT MIN(f.created_at) FROM refresh_tokens f WHERE f.family_id = rt.family_id)::TIMESTAMP AS started_at,
+    rt.token = sqlc.arg(current_token)::TEXT AS current
+FROM refresh_tokens rt
+WHERE rt.user_id = sqlc.arg(user_id) AND rt.revoked_at IS NULL AND rt.expires_at > NOW()
+ORDER BY rt.last_used_at DESC, rt.family_id;
@@ -0,0 +1,3 @@
+-- name: GetUserTwoFactor :one
+SELECT email, role, totp_secret, totp_enabled, totp_last_used_step FROM users
+WHERE id = $1;
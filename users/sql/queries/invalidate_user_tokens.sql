@@ -0,0 +1,3 @@
+-- name: InvalidateUserTokens :exec
+UPDATE user_tokens SET used_at = NOW()
+WHERE user_id = $1 AND purpose = $2 AND used_at IS NULL;
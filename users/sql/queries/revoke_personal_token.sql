@@ -0,0 +1,3 @@
+-- name: RevokePersonalToken :execrows
+UPDATE personal_tokens SET revoked_at = NOW()
+WHERE id = $1 AND user_id = $2 AND revoked_at IS NULL AND expires_at > NOW();
@@ -0,0 +1,3 @@
+-- name: RevokeUserRefreshTokens :execrows
+UPDATE refresh_tokens SET revoked_at = NOW(), updated_at = NOW()
+WHERE user_id = sqlc.arg(user_id) AND revoked_at IS NULL AND family_id IS DISTINCT FROM sqlc.narg(keep_family_id);
@@ -0,0 +1,3 @@
+-- name: SetUserTOTPSecret :execrows
+UPDATE users SET totp_secret = $2, totp_last_used_step = 0, updated_at = NOW()
+WHERE id = $1 AND NOT totp_enabled;
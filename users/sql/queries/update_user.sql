@@ -1,4 +1,5 @@
 -- name: UpdateUser :one
-UPDATE users SET login_name = $2, email = $3, birth_date = $4, hashed_password = $5, updated_at = NOW()
+UPDATE users SET login_name = $2, email = $3, birth_date = $4, hashed_password = $5,
+    email_verified = email_verified AND email = $3, updated_at = NOW()
 WHERE id = $1
-RETURNING 1;
\ No newline at end of file
+RETURNING 1;
@@ -0,0 +1,9 @@
+-- name: UpdateUserProfileWithOutboxMessage :execrows
+WITH updated AS (
+    UPDATE users SET display_name = @display_name, bio = @bio, avatar_url = @avatar_url, locale = @locale,
+        reading_list_public = @reading_list_public, updated_at = NOW()
+    WHERE users.id = @id
+    RETURNING users.id
+)
+INSERT INTO outbox (topic, message_key, payload, created_at)
+SELECT @topic::TEXT, updated.id::TEXT, @payload::BYTEA, NOW() FROM updated;
@@ -0,0 +1,3 @@
+-- name: UpdateUserRole :execrows
+UPDATE users SET role = $2, updated_at = NOW()
+WHERE id = $1;
\ No newline at end of file
@@ -0,0 +1,4 @@
+-- name: UseOIDCState :one
+DELETE FROM oidc_states
+WHERE state_hash = $1 AND provider = $2 AND expires_at > NOW()
+RETURNING code_verifier, nonce;
@@ -0,0 +1,5 @@
+-- name: UsePersonalToken :one
+UPDATE personal_tokens pt SET last_used_at = NOW()
+FROM users u
+WHERE pt.token_hash = $1 AND pt.revoked_at IS NULL AND pt.expires_at > NOW() AND u.id = pt.user_id
+RETURNING pt.user_id, u.role, pt.scopes;
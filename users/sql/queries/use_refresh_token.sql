@@ -0,0 +1,5 @@
+-- name: UseRefreshToken :one
+UPDATE refresh_tokens rt SET revoked_at = NOW(), updated_at = NOW()
+FROM users u
+WHERE rt.token = $1 AND u.id = rt.user_id AND rt.expires_at > NOW() AND rt.revoked_at IS NULL
+RETURNING rt.user_id, rt.family_id, u.role;
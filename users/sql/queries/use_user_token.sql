@@ -0,0 +1,4 @@
+-- name: UseUserToken :one
+UPDATE user_tokens SET used_at = NOW()
+WHERE token_hash = $1 AND purpose = $2 AND expires_at > NOW() AND used_at IS NULL
+RETURNING user_id;
@@ -0,0 +1,3 @@
+-- name: UseUserTOTPStep :execrows
+UPDATE users SET totp_last_used_step = $2
+WHERE id = $1 AND totp_enabled AND totp_last_used_step < $2;
@@ -0,0 +1,3 @@
+-- name: VerifyUserEmail :exec
+UPDATE users SET email_verified = TRUE, updated_at = NOW()
+WHERE id = $1;
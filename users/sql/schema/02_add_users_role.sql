@@ -0,0 +1,9 @@
+-- +goose Up
+CREATE TYPE user_role AS ENUM ('reader', 'editor', 'admin');
+
+ALTER TABLE users ADD COLUMN role user_role NOT NULL DEFAULT 'reader';
+
+-- +goose Down
+ALTER TABLE users DROP COLUMN role;
+
+DROP TYPE IF EXISTS user_role;
@@ -0,0 +1,24 @@
+-- +goose Up
+ALTER TABLE users ADD COLUMN email_verified BOOLEAN NOT NULL DEFAULT FALSE;
+
+CREATE TYPE user_token_purpose AS ENUM ('password_reset', 'email_verification');
+
+CREATE TABLE IF NOT EXISTS user_tokens (
+    token_hash TEXT PRIMARY KEY,
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    purpose user_token_purpose NOT NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    expires_at TIMESTAMP NOT NULL,
+    used_at TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_user_tokens_user_id ON user_tokens(user_id);
+
+-- +goose Down
+DROP INDEX IF EXISTS idx_user_tokens_user_id;
+
+DROP TABLE IF EXISTS user_tokens;
+
+DROP TYPE IF EXISTS user_token_purpose;
+
+ALTER TABLE users DROP COLUMN email_verified;
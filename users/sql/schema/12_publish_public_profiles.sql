@@ -0,0 +1,13 @@
+-- +goose Up
+-- Profiles that were made public before user events carried profiles are published once,
+-- so that user-reading shows reviews of these users too.
+-- The payload is UserMessage from internal/server/messages.go written in SQL, keep the fields in sync.
+INSERT INTO outbox (topic, message_key, payload, created_at)
+SELECT 'users', id::TEXT,
+    convert_to(json_build_object('id', id, 'action', 'updated', 'display_name', display_name, 'reading_list_public', TRUE)::TEXT, 'UTF8'),
+    NOW()
+FROM users WHERE reading_list_public
+ORDER BY created_at;
+
+-- +goose Down
+-- Messages may already be published to Kafka, so they are kept.
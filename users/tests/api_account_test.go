@@ -0,0 +1,294 @@
+package tests
+
+import (
+	"bytes"
+	"database/sql"
+	"encoding/json"
+	"log"
+	"net/http"
+	"regexp"
+	"testing"
+	"time"
+
+	common "github.com/bakurvik/mylib-common"
+	"github.com/bakurvik/mylib/users/internal/auth"
+	"github.com/bakurvik/mylib/users/internal/mailer"
+	"github.com/bakurvik/mylib/users/internal/server"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+const (
+	insertUserToken          = "INSERT INTO user_tokens(token_hash, user_id, purpose, expires_at, used_at) VALUES ($1, $2, $3, $4, $5)"
+	selectUserToken          = "SELECT used_at FROM user_tokens WHERE token_hash = $1"
+	selectEmailVerified      = "SELECT email_verified FROM users WHERE id = $1"
+	updateEmailVerified      = "UPDATE users SET email_verified = TRUE WHERE id = $1"
+	purposePasswordReset     = "password_reset"
+	purposeEmailVerification = "email_verification"
+)
+
+var tokenInLink = regexp.MustCompile(`token=([0-9a-f]+)`)
+
+type UserToken struct {
+	token     string
+	userID    string
+	purpose   string
+	expiresAt time.Time
+	usedAt    sql.NullTime
+}
+
+func addDBUserToken(db *sql.DB, userToken UserToken) {
+	if userToken.expiresAt.IsZero() {
+		userToken.expiresAt = time.Now().Add(time.Hour)
+	}
+	_, err := db.Exec(insertUserToken, auth.HashToken(userToken.token), userToken.userID, userToken.purpose, userToken.expiresAt, userToken.usedAt)
+	if err != nil {
+		log.Print("Failed to add user token: ", err)
+	}
+}
+
+func isDBUserTokenUsed(db *sql.DB, token string) bool {
+	usedAt := sql.NullTime{}
+	err := db.QueryRow(selectUserToken, auth.HashToken(token)).Scan(&usedAt)
+	if err != nil {
+		log.Print("Failed to get user token: ", err)
+	}
+	return usedAt.Valid
+}
+
+func isDBEmailVerified(db *sql.DB, userID string) bool {
+	verified := false
+	err := db.QueryRow(selectEmailVerified, userID).Scan(&verified)
+	if err != nil {
+		log.Print("Failed to get user: ", err)
+	}
+	return verified
+}
+
+// waitForMessage waits for the message sent in background to the email and returns it.
+func waitForMessage(t *testing.T, m *testMailer, email string) mailer.Message {
+	message := mailer.Message{}
+	assert.Eventually(t, func() bool {
+		for _, sent := range m.Messages() {
+			if sent.To == email {
+				message = sent
+				return true
+			}
+		}
+		return false
+	}, time.Second, 10*time.Millisecond)
+	return message
+}
+
+func tokenFromMessage(t *testing.T, message mailer.Message) string {
+	match := tokenInLink.FindStringSubmatch(message.Body)
+	if !assert.NotNil(t, match) {
+		return ""
+	}
+	return match[1]
+}
+
+func postJSON(t *testing.T, url string, payload any) *http.Response {
+	body, _ := json.Marshal(payload)
+	response, err := http.Post(url, "application/json", bytes.NewBuffer(body))
+	assert.NoError(t, err)
+	return response
+}
+
+func TestPasswordReset(t *testing.T) {
+	db, err := common.SetupDBByURL("../.env", "TEST_DB_URL")
+	assert.NoError(t, err)
+	defer common.CloseDB(db)
+	cleanupDB(db)
+	hashedPassword, _ := auth.HashPassword("password")
+	userID := addDBUser(db, User{loginName: "login", email: "some_email@email.com", hashedPassword: hashedPassword})
+	sessionToken := addDBSession(db, Session{userID: userID, familyID: uuid.New()})
+
+	m := &testMailer{}
+	s := setupTestServerWithMailer(db, m)
+	defer s.Close()
+
+	response := postJSON(t, s.URL+server.AuthPasswordResetRequestPath, server.RequestPasswordReset{Email: "some_email@email.com"})
+	defer common.CloseResponseBody(response)
+	assert.Equal(t, response.StatusCode, http.StatusAccepted)
+	message := waitForMessage(t, m, "some_email@email.com")
+	assert.Contains(t, message.Body, testResetURL+"?token=")
+	token := tokenFromMessage(t, message)
+
+	response = postJSON(t, s.URL+server.AuthPasswordResetConfirmPath, server.RequestPasswordResetConfirm{Token: token, Password: "new_password"})
+	defer common.CloseResponseBody(response)
+	assert.Equal(t, response.StatusCode, http.StatusNoContent)
+
+	assert.NoError(t, auth.CheckPasswordHash(getDBUser(db, userID).hashedPassword, "new_password"))
+	assert.True(t, getDBToken(db, sessionToken).revokedAt.Valid)
+	assert.True(t, isDBUserTokenUsed(db, token))
+
+	response = postJSON(t, s.URL+server.AuthPasswordResetConfirmPath, server.RequestPasswordResetConfirm{Token: token, Password: "another_password"})
+	defer common.CloseResponseBody(response)
+	assert.Equal(t, response.StatusCode, http.StatusBadRequest)
+	assert.NoError(t, auth.CheckPasswordHash(getDBUser(db, userID).hashedPassword, "new_password"))
+}
+
+func TestPasswordResetRequest(t *testing.T) {
+	db, err := common.SetupDBByURL("../.env", "TEST_DB_URL")
+	assert.NoError(t, err)
+	defer common.CloseDB(db)
+	cleanupDB(db)
+	userID := addDBUser(db, User{loginName: "login", email: "some_email@email.com", hashedPassword: "304854e2e79de0f96dc5477fef38a18f"})
+	addDBUserToken(db, UserToken{token: "old_token", userID: userID, purpose: purposePasswordReset})
+
+	m := &testMailer{}
+	s := setupTestServerWithMailer(db, m)
+	defer s.Close()
+
+	response := postJSON(t, s.URL+server.AuthPasswordResetRequestPath, server.RequestPasswordReset{Email: "unknown@email.com"})
+	defer common.CloseResponseBody(response)
+	assert.Equal(t, response.StatusCode, http.StatusAccepted)
+
+	response = postJSON(t, s.URL+server.AuthPasswordResetRequestPath, server.RequestPasswordReset{Email: "some_email@email.com"})
+	defer common.CloseResponseBody(response)
+	assert.Equal(t, response.StatusCode, http.StatusAccepted)
+	waitForMessage(t, m, "some_email@email.com")
+
+	// Only the latest link is valid.
+	assert.True(t, isDBUserTokenUsed(db, "old_token"))
+	for _, message := range m.Messages() {
+		assert.NotEqual(t, message.To, "unknown@email.com")
+	}
+}
+
+func TestPasswordResetConfirm(t *testing.T) {
+	type testCase struct {
+		name               string
+		dbToken            UserToken
+		request            server.RequestPasswordResetConfirm
+		expectedStatusCode int
+	}
+	testCases := []testCase{
+		{
+			name:               "success",
+			dbToken:            UserToken{token: "token", purpose: purposePasswordReset},
+			request:            server.RequestPasswordResetConfirm{Token: "token", Password: "new_password"},
+			expectedStatusCode: http.StatusNoContent,
+		},
+		{
+			name:               "unknown_token",
+			dbToken:            UserToken{token: "token", purpose: purposePasswordReset},
+			request:            server.RequestPasswordResetConfirm{Token: "unknown", Password: "new_password"},
+			expectedStatusCode: http.StatusBadRequest,
+		},
+		{
+			name:               "expired_token",
+			dbToken:            UserToken{token: "token", purpose: purposePasswordReset, expiresAt: time.Now().Add(-time.Minute)},
+			request:            server.RequestPasswordResetConfirm{Token: "token", Password: "new_password"},
+			expectedStatusCode: http.StatusBadRequest,
+		},
+		{
+			name:               "used_token",
+			dbToken:            UserToken{token: "token", purpose: purposePasswordReset, usedAt: sql.NullTime{Time: time.Now(), Valid: true}},
+			request:            server.RequestPasswordResetConfirm{Token: "token", Password: "new_password"},
+			expectedStatusCode: http.StatusBadRequest,
+		},
+		{
+			name:               "email_verification_token",
+			dbToken:            UserToken{token: "token", purpose: purposeEmailVerification},
+			request:            server.RequestPasswordResetConfirm{Token: "token", Password: "new_password"},
+			expectedStatusCode: http.StatusBadRequest,
+		},
+		{
+			name:               "short_password",
+			dbToken:            UserToken{token: "token", purpose: purposePasswordReset},
+			request:            server.RequestPasswordResetConfirm{Token: "token", Password: "new"},
+			expectedStatusCode: http.StatusBadRequest,
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			db, err := common.SetupDBByURL("../.env", "TEST_DB_URL")
+			assert.NoError(t, err)
+			defer common.CloseDB(db)
+			cleanupDB(db)
+			hashedPassword, _ := auth.HashPassword("password")
+			userID := addDBUser(db, User{loginName: "login", email: "some_email@email.com", hashedPassword: hashedPassword})
+			tc.dbToken.userID = userID
+			addDBUserToken(db, tc.dbToken)
+
+			s := setupTestServer(db)
+			defer s.Close()
+
+			response := postJSON(t, s.URL+server.AuthPasswordResetConfirmPath, tc.request)
+			defer common.CloseResponseBody(response)
+			assert.Equal(t, response.StatusCode, tc.expectedStatusCode)
+
+			expectedPassword := "password"
+			if tc.expectedStatusCode == http.StatusNoContent {
+				expectedPassword = tc.request.Password
+			}
+			assert.NoError(t, auth.CheckPasswordHash(getDBUser(db, userID).hashedPassword, expectedPassword))
+		})
+	}
+}
+
+func TestVerifyEmail(t *testing.T) {
+	db, err := common.SetupDBByURL("../.env", "TEST_DB_URL")
+	assert.NoError(t, err)
+	defer common.CloseDB(db)
+	cleanupDB(db)
+
+	m := &testMailer{}
+	s := setupTestServerWithMailer(db, m)
+	defer s.Close()
+
+	response := postJSON(t, s.URL+server.ApiUsersPath, server.RequestUser{LoginName: "login", Email: "some_email@email.com", Password: "password"})
+	defer common.CloseResponseBody(response)
+	assert.Equal(t, response.StatusCode, http.StatusCreated)
+	responseToken := server.ResponseToken{}
+	err = json.NewDecoder(response.Body).Decode(&responseToken)
+	assert.NoError(t, err)
+	assert.False(t, isDBEmailVerified(db, responseToken.ID))
+
+	message := waitForMessage(t, m, "some_email@email.com")
+	assert.Contains(t, message.Body, testPublicURL+server.AuthVerifyEmailPath+"?token=")
+	token := tokenFromMessage(t, message)
+
+	response, err = http.Get(s.URL + server.AuthVerifyEmailPath + "?token=" + token)
+	assert.NoError(t, err)
+	defer common.CloseResponseBody(response)
+	assert.Equal(t, response.StatusCode, http.StatusNoContent)
+	assert.True(t, isDBEmailVerified(db, responseToken.ID))
+
+	response, err = http.Get(s.URL + server.AuthVerifyEmailPath + "?token=" + token)
+	assert.NoError(t, err)
+	defer common.CloseResponseBody(response)
+	assert.Equal(t, response.StatusCode, http.StatusBadRequest)
+
+	response, err = http.Get(s.URL + server.AuthVerifyEmailPath)
+	assert.NoError(t, err)
+	defer common.CloseResponseBody(response)
+	assert.Equal(t, response.StatusCode, http.StatusBadRequest)
+}
+
+func TestEmailChangeResetsVerification(t *testing.T) {
+	db, err := common.SetupDBByURL("../.env", "TEST_DB_URL")
+	assert.NoError(t, err)
+	defer common.CloseDB(db)
+	cleanupDB(db)
+	userID := addDBUser(db, User{loginName: "login", email: "some_email@email.com", hashedPassword: "304854e2e79de0f96dc5477fef38a18f"})
+	_, err = db.Exec(updateEmailVerified, userID)
+	assert.NoError(t, err)
+	addDBUserToken(db, UserToken{token: "old_token", userID: userID, purpose: purposeEmailVerification})
+
+	m := &testMailer{}
+	s := setupTestServerWithMailer(db, m)
+	defer s.Close()
+
+	body, _ := json.Marshal(server.RequestUser{LoginName: "new_login", Email: "new_email@email.com", Password: "password"})
+	response := sendSessionsRequest(t, http.MethodPut, s.URL+server.ApiUsersPath, userID, "", body)
+	defer common.CloseResponseBody(response)
+	assert.Equal(t, response.StatusCode, http.StatusNoContent)
+
+	assert.False(t, isDBEmailVerified(db, userID))
+	assert.True(t, isDBUserTokenUsed(db, "old_token"))
+	message := waitForMessage(t, m, "new_email@email.com")
+	assert.Contains(t, message.Body, testPublicURL+server.AuthVerifyEmailPath+"?token=")
+}
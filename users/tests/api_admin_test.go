@@ -0,0 +1,135 @@
+package tests
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"testing"
+	"time"
+
+	common "github.com/bakurvik/mylib-common"
+	"github.com/bakurvik/mylib/users/internal/auth"
+	"github.com/bakurvik/mylib/users/internal/server"
+	"github.com/google/uuid"
+
+	_ "github.com/lib/pq"
+	"github.com/stretchr/testify/assert"
+)
+
+const (
+	selectUserRole = "SELECT role FROM users WHERE id = $1"
+	enableUserTOTP = "UPDATE users SET totp_secret = $2, totp_enabled = TRUE WHERE id = $1"
+	testTOTPSecret = "GEZDGNBVGY3TQOJQGEZDGNBVGY3TQOJQ"
+)
+
+func TestSetUserRole(t *testing.T) {
+	type testCase struct {
+		name               string
+		tokenRole          string
+		hasToken           bool
+		unknownUser        bool
+		twoFactorEnabled   bool
+		request            server.RequestUserRole
+		expectedStatusCode int
+		expectedRole       string
+	}
+	testCases := []testCase{
+		{
+			name:               "success",
+			tokenRole:          auth.RoleAdmin,
+			hasToken:           true,
+			twoFactorEnabled:   true,
+			request:            server.RequestUserRole{Role: auth.RoleEditor},
+			expectedStatusCode: http.StatusNoContent,
+			expectedRole:       auth.RoleEditor,
+		},
+		{
+			name:               "editor_without_two_factor",
+			tokenRole:          auth.RoleAdmin,
+			hasToken:           true,
+			request:            server.RequestUserRole{Role: auth.RoleEditor},
+			expectedStatusCode: http.StatusConflict,
+			expectedRole:       auth.RoleReader,
+		},
+		{
+			name:               "reader_without_two_factor",
+			tokenRole:          auth.RoleAdmin,
+			hasToken:           true,
+			request:            server.RequestUserRole{Role: auth.RoleReader},
+			expectedStatusCode: http.StatusNoContent,
+			expectedRole:       auth.RoleReader,
+		},
+		{
+			name:               "unauthorized",
+			hasToken:           false,
+			request:            server.RequestUserRole{Role: auth.RoleEditor},
+			expectedStatusCode: http.StatusUnauthorized,
+			expectedRole:       auth.RoleReader,
+		},
+		{
+			name:               "not_admin",
+			tokenRole:          auth.RoleEditor,
+			hasToken:           true,
+			request:            server.RequestUserRole{Role: auth.RoleAdmin},
+			expectedStatusCode: http.StatusForbidden,
+			expectedRole:       auth.RoleReader,
+		},
+		{
+			name:               "invalid_role",
+			tokenRole:          auth.RoleAdmin,
+			hasToken:           true,
+			request:            server.RequestUserRole{Role: "owner"},
+			expectedStatusCode: http.StatusBadRequest,
+			expectedRole:       auth.RoleReader,
+		},
+		{
+			name:               "unknown_user",
+			tokenRole:          auth.RoleAdmin,
+			hasToken:           true,
+			unknownUser:        true,
+			request:            server.RequestUserRole{Role: auth.RoleEditor},
+			expectedStatusCode: http.StatusNotFound,
+			expectedRole:       auth.RoleReader,
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			db, err := common.SetupDBByURL("../.env", "TEST_DB_URL")
+			assert.NoError(t, err)
+			defer common.CloseDB(db)
+			cleanupDB(db)
+			userID := addDBUser(db, User{loginName: "login", email: "some_email@email.com", hashedPassword: "304854e2e79de0f96dc5477fef38a18f"})
+			if tc.twoFactorEnabled {
+				_, err = db.Exec(enableUserTOTP, userID, testTOTPSecret)
+				assert.NoError(t, err)
+			}
+
+			s := setupTestServer(db)
+			defer s.Close()
+
+			requestUserID := userID
+			if tc.unknownUser {
+				requestUserID = uuid.New().String()
+			}
+			requestJson, _ := json.Marshal(tc.request)
+			request, requestErr := http.NewRequest(http.MethodPut, fmt.Sprintf("%v%v/%v/role", s.URL, server.AdminUsersPath, requestUserID), bytes.NewBuffer(requestJson))
+			assert.NoError(t, requestErr)
+			if tc.hasToken {
+				accessToken, _ := testKeys.MakeJWT(uuid.New(), tc.tokenRole, time.Hour)
+				request.Header.Add("Authorization", "Bearer "+accessToken)
+			}
+
+			client := &http.Client{}
+			response, err := client.Do(request)
+			assert.NoError(t, err)
+			defer common.CloseResponseBody(response)
+			assert.Equal(t, tc.expectedStatusCode, response.StatusCode)
+
+			role := ""
+			err = db.QueryRow(selectUserRole, userID).Scan(&role)
+			assert.NoError(t, err)
+			assert.Equal(t, role, tc.expectedRole)
+		})
+	}
+}
@@ -2,8 +2,11 @@ package tests
 
 import (
 	"bytes"
+	"crypto/ed25519"
 	"database/sql"
+	"encoding/base64"
 	"encoding/json"
+	"errors"
 	"io"
 	"log"
 	"net/http"
@@ -12,7 +15,9 @@ import (
 
 	common "github.com/bakurvik/mylib-common"
 	"github.com/bakurvik/mylib/users/internal/auth"
+	"github.com/bakurvik/mylib/users/internal/lockout"
 	"github.com/bakurvik/mylib/users/internal/server"
+	"github.com/golang-jwt/jwt/v5"
 	"github.com/google/uuid"
 
 	_ "github.com/lib/pq"
@@ -56,7 +61,7 @@ func TestLogin(t *testing.T) {
 		{
 			name:               "unknown_user",
 			request:            server.RequestLogin{Password: password, Email: "unknown_email@email.com"},
-			expectedStatusCode: http.StatusNotFound,
+			expectedStatusCode: http.StatusUnauthorized,
 		},
 	}
 	for _, tc := range testCases {
@@ -78,12 +83,22 @@ func TestLogin(t *testing.T) {
 			defer common.CloseResponseBody(response)
 			assert.Equal(t, tc.expectedStatusCode, response.StatusCode)
 
+			if response.StatusCode == http.StatusUnauthorized {
+				responseBody := server.ErrorResponse{}
+				err = json.NewDecoder(response.Body).Decode(&responseBody)
+				assert.NoError(t, err)
+				assert.Equal(t, responseBody.Error, "Invalid email or password")
+			}
+
 			if response.StatusCode == http.StatusOK {
 				decoder := json.NewDecoder(response.Body)
 				responseBody := server.ResponseToken{}
 				err = decoder.Decode(&responseBody)
 				assert.NoError(t, err)
 				assert.NotEqual(t, responseBody.Token, "")
+				_, role, err := testKeys.ValidateJWT(responseBody.Token)
+				assert.NoError(t, err)
+				assert.Equal(t, role, auth.RoleReader)
 
 				cookies := response.Cookies()
 				assert.Equal(t, len(cookies), 1)
@@ -99,12 +114,111 @@ func TestLogin(t *testing.T) {
 	}
 }
 
+func postLogin(t *testing.T, url string, email string, password string) *http.Response {
+	requestJson, _ := json.Marshal(server.RequestLogin{Email: email, Password: password})
+	response, err := http.Post(url+server.AuthLoginPath, "application/json", bytes.NewBuffer(requestJson))
+	assert.NoError(t, err)
+	return response
+}
+
+func TestLoginLockout(t *testing.T) {
+	email := "some_email@email.com"
+	password := "some_password"
+	type attempt struct {
+		email              string
+		password           string
+		expectedStatusCode int
+		expectedRetryAfter string
+	}
+	type testCase struct {
+		name           string
+		accountLockout lockout.Policy
+		ipLockout      lockout.Policy
+		attempts       []attempt
+	}
+	testCases := []testCase{
+		{
+			name:           "account_backoff",
+			accountLockout: lockout.Policy{FreeAttempts: 2, BaseDelay: time.Minute, MaxDelay: time.Hour, LockoutAttempts: 10, LockoutDuration: time.Hour},
+			attempts: []attempt{
+				{email: email, password: "wrong", expectedStatusCode: http.StatusUnauthorized},
+				{email: email, password: "wrong", expectedStatusCode: http.StatusUnauthorized},
+				{email: email, password: "wrong", expectedStatusCode: http.StatusUnauthorized, expectedRetryAfter: "60"},
+				{email: email, password: password, expectedStatusCode: http.StatusTooManyRequests, expectedRetryAfter: "60"},
+				{email: "another_email@email.com", password: password, expectedStatusCode: http.StatusUnauthorized},
+			},
+		},
+		{
+			name:           "account_lockout",
+			accountLockout: lockout.Policy{FreeAttempts: 5, BaseDelay: time.Minute, MaxDelay: time.Hour, LockoutAttempts: 2, LockoutDuration: time.Hour},
+			attempts: []attempt{
+				{email: email, password: "wrong", expectedStatusCode: http.StatusUnauthorized},
+				{email: email, password: "wrong", expectedStatusCode: http.StatusUnauthorized, expectedRetryAfter: "3600"},
+				{email: email, password: password, expectedStatusCode: http.StatusTooManyRequests, expectedRetryAfter: "3600"},
+			},
+		},
+		{
+			name:           "unknown_email_counts",
+			accountLockout: lockout.Policy{FreeAttempts: 1, BaseDelay: time.Minute, MaxDelay: time.Hour, LockoutAttempts: 10, LockoutDuration: time.Hour},
+			attempts: []attempt{
+				{email: "unknown@email.com", password: "wrong", expectedStatusCode: http.StatusUnauthorized},
+				{email: "unknown@email.com", password: "wrong", expectedStatusCode: http.StatusUnauthorized, expectedRetryAfter: "60"},
+				{email: "unknown@email.com", password: "wrong", expectedStatusCode: http.StatusTooManyRequests, expectedRetryAfter: "60"},
+			},
+		},
+		{
+			name:           "success_resets_account_failures",
+			accountLockout: lockout.Policy{FreeAttempts: 1, BaseDelay: time.Minute, MaxDelay: time.Hour, LockoutAttempts: 10, LockoutDuration: time.Hour},
+			attempts: []attempt{
+				{email: email, password: "wrong", expectedStatusCode: http.StatusUnauthorized},
+				{email: email, password: password, expectedStatusCode: http.StatusOK},
+				{email: email, password: "wrong", expectedStatusCode: http.StatusUnauthorized},
+			},
+		},
+		{
+			name:      "ip_backoff",
+			ipLockout: lockout.Policy{FreeAttempts: 2, BaseDelay: time.Minute, MaxDelay: time.Hour, LockoutAttempts: 10, LockoutDuration: time.Hour},
+			attempts: []attempt{
+				{email: "first@email.com", password: "wrong", expectedStatusCode: http.StatusUnauthorized},
+				{email: "second@email.com", password: "wrong", expectedStatusCode: http.StatusUnauthorized},
+				{email: email, password: password, expectedStatusCode: http.StatusOK},
+				{email: "third@email.com", password: "wrong", expectedStatusCode: http.StatusUnauthorized, expectedRetryAfter: "60"},
+				{email: email, password: password, expectedStatusCode: http.StatusTooManyRequests, expectedRetryAfter: "60"},
+			},
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			db, err := common.SetupDBByURL("../.env", "TEST_DB_URL")
+			assert.NoError(t, err)
+			defer common.CloseDB(db)
+			cleanupDB(db)
+			hash, _ := auth.HashPassword(password)
+			addDBUser(db, User{loginName: "login", email: email, hashedPassword: hash})
+
+			apiCfg := newTestConfig(db)
+			apiCfg.AccountLockout = tc.accountLockout
+			apiCfg.IPLockout = tc.ipLockout
+			s := startTestServer(apiCfg)
+			defer s.Close()
+
+			for i, attempt := range tc.attempts {
+				response := postLogin(t, s.URL, attempt.email, attempt.password)
+				defer common.CloseResponseBody(response)
+				assert.Equal(t, response.StatusCode, attempt.expectedStatusCode, "attempt %d", i)
+				assert.Equal(t, response.Header.Get("Retry-After"), attempt.expectedRetryAfter, "attempt %d", i)
+			}
+		})
+	}
+}
+
 func TestRefresh(t *testing.T) {
 	type testCase struct {
 		name               string
 		expectedStatusCode int
 		hasCookie          bool
 		cookieToken        string
+		expiresIn          time.Duration
 	}
 	testCases := []testCase{
 		{
@@ -123,6 +237,12 @@ func TestRefresh(t *testing.T) {
 			hasCookie:          false,
 			cookieToken:        "4500f6128a7209ebdc18de559daf74f5",
 		},
+		{
+			name:               "expired_token",
+			expectedStatusCode: http.StatusUnauthorized,
+			hasCookie:          true,
+			expiresIn:          -time.Hour,
+		},
 	}
 
 	for _, tc := range testCases {
@@ -132,7 +252,11 @@ func TestRefresh(t *testing.T) {
 			defer common.CloseDB(db)
 			cleanupDB(db)
 			userID := addDBUser(db, User{loginName: "login", email: "some_email@email.com", birthDate: toSqlNullTime("09.05.1956"), hashedPassword: "304854e2e79de0f96dc5477fef38a18f"})
-			expiresAt := time.Now().Add(time.Hour)
+			expiresIn := time.Hour
+			if tc.expiresIn != 0 {
+				expiresIn = tc.expiresIn
+			}
+			expiresAt := time.Now().Add(expiresIn)
 			token := addDBToken(db, RefreshToken{userID: userID, expiresAt: expiresAt})
 			if tc.cookieToken != "" {
 				token = tc.cookieToken
@@ -161,6 +285,13 @@ func TestRefresh(t *testing.T) {
 			defer common.CloseResponseBody(response)
 			assert.Equal(t, tc.expectedStatusCode, response.StatusCode)
 
+			if response.StatusCode == http.StatusUnauthorized {
+				responseBody := server.ErrorResponse{}
+				err = json.NewDecoder(response.Body).Decode(&responseBody)
+				assert.NoError(t, err)
+				assert.Equal(t, responseBody.Error, "Invalid email or password")
+			}
+
 			if response.StatusCode == http.StatusOK {
 				decoder := json.NewDecoder(response.Body)
 				responseBody := server.ResponseToken{}
@@ -181,11 +312,61 @@ func TestRefresh(t *testing.T) {
 
 				oldRefreshToken := getDBToken(db, token)
 				assert.True(t, oldRefreshToken.revokedAt.Valid)
+				assert.Equal(t, newRefreshToken.familyID, oldRefreshToken.familyID)
+				assert.Equal(t, newRefreshToken.parentToken, sql.NullString{String: token, Valid: true})
 			}
 		})
 	}
 }
 
+func postRefresh(t *testing.T, url string, token string) (int, string) {
+	request, err := http.NewRequest(http.MethodPost, url+server.AuthRefreshPath, nil)
+	assert.NoError(t, err)
+	request.AddCookie(&http.Cookie{Name: cookieRefreshToken, Value: token})
+
+	response, err := http.DefaultClient.Do(request)
+	assert.NoError(t, err)
+	defer common.CloseResponseBody(response)
+	for _, cookie := range response.Cookies() {
+		if cookie.Name == cookieRefreshToken {
+			return response.StatusCode, cookie.Value
+		}
+	}
+	return response.StatusCode, ""
+}
+
+func TestRefreshTokenReuse(t *testing.T) {
+	db, err := common.SetupDBByURL("../.env", "TEST_DB_URL")
+	assert.NoError(t, err)
+	defer common.CloseDB(db)
+	cleanupDB(db)
+	userID := addDBUser(db, User{loginName: "login", email: "some_email@email.com", birthDate: toSqlNullTime("09.05.1956"), hashedPassword: "304854e2e79de0f96dc5477fef38a18f"})
+	firstToken := addDBToken(db, RefreshToken{userID: userID, expiresAt: time.Now().Add(time.Hour)})
+	anotherSessionToken := addDBToken(db, RefreshToken{userID: userID, expiresAt: time.Now().Add(time.Hour)})
+
+	s := setupTestServer(db)
+	defer s.Close()
+
+	statusCode, secondToken := postRefresh(t, s.URL, firstToken)
+	assert.Equal(t, statusCode, http.StatusOK)
+	statusCode, thirdToken := postRefresh(t, s.URL, secondToken)
+	assert.Equal(t, statusCode, http.StatusOK)
+
+	// Replaying an already rotated token revokes every token of its family.
+	statusCode, newToken := postRefresh(t, s.URL, firstToken)
+	assert.Equal(t, statusCode, http.StatusUnauthorized)
+	assert.Equal(t, newToken, "")
+	assert.True(t, getDBToken(db, thirdToken).revokedAt.Valid)
+
+	statusCode, _ = postRefresh(t, s.URL, thirdToken)
+	assert.Equal(t, statusCode, http.StatusUnauthorized)
+
+	// Tokens of other sessions stay valid.
+	assert.False(t, getDBToken(db, anotherSessionToken).revokedAt.Valid)
+	statusCode, _ = postRefresh(t, s.URL, anotherSessionToken)
+	assert.Equal(t, statusCode, http.StatusOK)
+}
+
 func TestRevoke(t *testing.T) {
 	type testCase struct {
 		name                   string
@@ -308,7 +489,7 @@ func TestWhoami(t *testing.T) {
 			assert.NoError(t, requestErr)
 			if tc.hasToken {
 				uuid, _ := uuid.Parse(userID)
-				accessToken, _ := auth.MakeJWT(uuid, authSecretKey, time.Hour)
+				accessToken, _ := testKeys.MakeJWT(uuid, auth.RoleReader, time.Hour)
 				request.Header.Add("Authorization", "Bearer "+accessToken)
 			}
 
@@ -324,7 +505,41 @@ func TestWhoami(t *testing.T) {
 				err := json.Unmarshal(body, &responseData)
 				assert.NoError(t, err)
 				assert.Equal(t, responseData.ID, userID)
+				assert.Equal(t, responseData.Role, auth.RoleReader)
 			}
 		})
 	}
 }
+
+func TestJWKS(t *testing.T) {
+	s := setupTestServer(nil)
+	defer s.Close()
+
+	response, err := http.Get(s.URL + server.JWKSPath)
+	assert.NoError(t, err)
+	defer common.CloseResponseBody(response)
+	assert.Equal(t, response.StatusCode, http.StatusOK)
+
+	body, _ := io.ReadAll(response.Body)
+	jwks := auth.JWKS{}
+	err = json.Unmarshal(body, &jwks)
+	assert.NoError(t, err)
+	assert.Equal(t, jwks, testKeys.JWKS())
+
+	// A token must be verifiable with the published key that matches its kid.
+	userID := uuid.New()
+	accessToken, _ := testKeys.MakeJWT(userID, auth.RoleEditor, time.Hour)
+	claims := auth.Claims{}
+	_, err = jwt.ParseWithClaims(accessToken, &claims, func(token *jwt.Token) (interface{}, error) {
+		for _, key := range jwks.Keys {
+			if key.Kid == token.Header["kid"] {
+				x, err := base64.RawURLEncoding.DecodeString(key.X)
+				return ed25519.PublicKey(x), err
+			}
+		}
+		return nil, errors.New("unknown kid")
+	}, jwt.WithValidMethods([]string{"EdDSA"}))
+	assert.NoError(t, err)
+	assert.Equal(t, claims.Subject, userID.String())
+	assert.Equal(t, claims.Role, auth.RoleEditor)
+}
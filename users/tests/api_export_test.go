@@ -0,0 +1,107 @@
+package tests
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	common "github.com/bakurvik/mylib-common"
+	"github.com/bakurvik/mylib/users/internal/auth"
+	"github.com/bakurvik/mylib/users/internal/clients"
+	"github.com/bakurvik/mylib/users/internal/server"
+
+	"github.com/google/uuid"
+	_ "github.com/lib/pq"
+	"github.com/stretchr/testify/assert"
+)
+
+// mockUserReadingServer returns the reading data to the user the access token was issued for.
+func mockUserReadingServer(t *testing.T, userID string, statusCode int, reading string) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != clients.UserReadingApiExportPath {
+			http.NotFound(w, r)
+			return
+		}
+		token, err := auth.GetBearerToken(r.Header)
+		assert.NoError(t, err)
+		tokenUserID, _, err := testKeys.ValidateJWT(token)
+		assert.NoError(t, err)
+		assert.Equal(t, tokenUserID.String(), userID)
+		w.WriteHeader(statusCode)
+		w.Write([]byte(reading))
+	}))
+}
+
+func TestExportUser(t *testing.T) {
+	reading := `{"books":[{"book_id":"4fc40366-ff15-4653-be30-1bba21f016c1","status":"finished","rating":5}]}`
+	type testCase struct {
+		name               string
+		token              string
+		readingStatusCode  int
+		expectedStatusCode int
+	}
+	testCases := []testCase{
+		{
+			name:               "success",
+			readingStatusCode:  http.StatusOK,
+			expectedStatusCode: http.StatusOK,
+		},
+		{
+			name:               "user_reading_failed",
+			readingStatusCode:  http.StatusInternalServerError,
+			expectedStatusCode: http.StatusBadGateway,
+		},
+		{
+			name:               "unauthorized",
+			token:              "invalid_token",
+			readingStatusCode:  http.StatusOK,
+			expectedStatusCode: http.StatusUnauthorized,
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			db, err := common.SetupDBByURL("../.env", "TEST_DB_URL")
+			assert.NoError(t, err)
+			defer common.CloseDB(db)
+			cleanupDB(db)
+			userID := addDBUser(db, User{loginName: "login", email: "some_email@email.com", birthDate: toSqlNullTime("09.05.1956"), hashedPassword: "304854e2e79de0f96dc5477fef38a18f"})
+
+			readingServer := mockUserReadingServer(t, userID, tc.readingStatusCode, reading)
+			defer readingServer.Close()
+			apiCfg := newTestConfig(db)
+			apiCfg.UserReadingServiceHost = readingServer.URL
+			s := startTestServer(apiCfg)
+			defer s.Close()
+
+			request, requestErr := http.NewRequest(http.MethodGet, s.URL+server.ApiUsersMeExportPath, nil)
+			assert.NoError(t, requestErr)
+			uuid, _ := uuid.Parse(userID)
+			accessToken, _ := testKeys.MakeJWT(uuid, auth.RoleReader, time.Hour)
+			if tc.token != "" {
+				accessToken = tc.token
+			}
+			request.Header.Add("Authorization", "Bearer "+accessToken)
+
+			client := &http.Client{}
+			response, err := client.Do(request)
+			assert.NoError(t, err)
+			defer common.CloseResponseBody(response)
+			assert.Equal(t, tc.expectedStatusCode, response.StatusCode)
+
+			if tc.expectedStatusCode == http.StatusOK {
+				assert.True(t, strings.HasPrefix(response.Header.Get("Content-Disposition"), "attachment"))
+				export := server.ResponseUserExport{}
+				err = json.NewDecoder(response.Body).Decode(&export)
+				assert.NoError(t, err)
+				assert.Equal(t, export.Profile.ID, userID)
+				assert.Equal(t, export.Profile.Email, "some_email@email.com")
+				assert.Equal(t, export.Profile.BirthDate, "09.05.1956")
+				assert.Empty(t, export.Sessions)
+				assert.JSONEq(t, string(export.Reading), reading)
+			}
+		})
+	}
+}
@@ -0,0 +1,340 @@
+package tests
+
+import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"database/sql"
+	"encoding/base64"
+	"encoding/json"
+	"log"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"sync"
+	"testing"
+	"time"
+
+	common "github.com/bakurvik/mylib-common"
+	"github.com/bakurvik/mylib/users/internal/oidc"
+	"github.com/bakurvik/mylib/users/internal/server"
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/stretchr/testify/assert"
+)
+
+const (
+	selectUserIdentity = "SELECT user_id FROM user_identities WHERE provider = $1 AND subject = $2"
+	selectUserByEmail  = "SELECT id, login_name FROM users WHERE email = $1"
+	stubProviderName   = "stub"
+	stubClientID       = "client"
+	stubClientSecret   = "secret"
+	cookieOIDCState    = "oidc_state"
+)
+
+// stubIdentity is a user of the stub OpenID Connect provider.
+type stubIdentity struct {
+	subject           string
+	email             string
+	emailVerified     bool
+	preferredUsername string
+}
+
+type stubAuthorization struct {
+	identity      stubIdentity
+	nonce         string
+	codeChallenge string
+}
+
+// stubOIDCServer is an OpenID Connect provider that authorizes any identity the test asks for.
+type stubOIDCServer struct {
+	*httptest.Server
+	privateKey ed25519.PrivateKey
+
+	mu    sync.Mutex
+	codes map[string]stubAuthorization
+}
+
+func newStubOIDCServer(t *testing.T) *stubOIDCServer {
+	publicKey, privateKey, err := ed25519.GenerateKey(rand.Reader)
+	assert.NoError(t, err)
+	stub := &stubOIDCServer{privateKey: privateKey, codes: map[string]stubAuthorization{}}
+
+	sm := http.NewServeMux()
+	sm.HandleFunc("GET /.well-known/openid-configuration", func(w http.ResponseWriter, r *http.Request) {
+		common.RespondWithJSON(w, http.StatusOK, map[string]string{
+			"issuer":                 stub.URL,
+			"authorization_endpoint": stub.URL + "/authorize",
+			"token_endpoint":         stub.URL + "/token",
+			"jwks_uri":               stub.URL + "/jwks",
+		}, nil)
+	})
+	sm.HandleFunc("GET /jwks", func(w http.ResponseWriter, r *http.Request) {
+		common.RespondWithJSON(w, http.StatusOK, map[string]any{"keys": []map[string]string{
+			{"kty": "OKP", "crv": "Ed25519", "x": base64.RawURLEncoding.EncodeToString(publicKey), "kid": "stub", "use": "sig"},
+		}}, nil)
+	})
+	sm.HandleFunc("POST /token", func(w http.ResponseWriter, r *http.Request) {
+		clientID, clientSecret, _ := r.BasicAuth()
+		stub.mu.Lock()
+		authorization, ok := stub.codes[r.FormValue("code")]
+		delete(stub.codes, r.FormValue("code"))
+		stub.mu.Unlock()
+		if !ok || clientID != stubClientID || clientSecret != stubClientSecret ||
+			oidc.CodeChallenge(r.FormValue("code_verifier")) != authorization.codeChallenge {
+			common.RespondWithError(w, http.StatusBadRequest, "invalid_grant")
+			return
+		}
+		token := jwt.NewWithClaims(jwt.SigningMethodEdDSA, oidc.Claims{
+			Email:             authorization.identity.email,
+			EmailVerified:     authorization.identity.emailVerified,
+			PreferredUsername: authorization.identity.preferredUsername,
+			Nonce:             authorization.nonce,
+			RegisteredClaims: jwt.RegisteredClaims{
+				Issuer:    stub.URL,
+				Subject:   authorization.identity.subject,
+				Audience:  jwt.ClaimStrings{stubClientID},
+				ExpiresAt: jwt.NewNumericDate(time.Now().Add(time.Minute)),
+			},
+		})
+		token.Header["kid"] = "stub"
+		idToken, signErr := token.SignedString(stub.privateKey)
+		assert.NoError(t, signErr)
+		common.RespondWithJSON(w, http.StatusOK, map[string]string{"id_token": idToken, "access_token": "access"}, nil)
+	})
+	stub.Server = httptest.NewServer(sm)
+	return stub
+}
+
+// authorize plays the login at the provider page: it checks the request and returns an authorization code for the identity.
+func (stub *stubOIDCServer) authorize(t *testing.T, authURL *url.URL, identity stubIdentity) string {
+	query := authURL.Query()
+	assert.Equal(t, authURL.Path, "/authorize")
+	assert.Equal(t, query.Get("client_id"), stubClientID)
+	assert.Equal(t, query.Get("code_challenge_method"), "S256")
+	code, err := oidc.MakeRandomString()
+	assert.NoError(t, err)
+	stub.mu.Lock()
+	defer stub.mu.Unlock()
+	stub.codes[code] = stubAuthorization{identity: identity, nonce: query.Get("nonce"), codeChallenge: query.Get("code_challenge")}
+	return code
+}
+
+func setupOIDCTestServer(db *sql.DB, stub *stubOIDCServer) *httptest.Server {
+	apiCfg := newTestConfig(db)
+	apiCfg.OIDCProviders = map[string]*oidc.Provider{
+		stubProviderName: oidc.NewProvider(oidc.Config{
+			Name:         stubProviderName,
+			Issuer:       stub.URL,
+			ClientID:     stubClientID,
+			ClientSecret: stubClientSecret,
+			RedirectURL:  testPublicURL + server.AuthOIDCPath + "/stub/callback",
+		}),
+	}
+	return startTestServer(apiCfg)
+}
+
+var noRedirectClient = &http.Client{
+	CheckRedirect: func(*http.Request, []*http.Request) error {
+		return http.ErrUseLastResponse
+	},
+}
+
+// startOIDCLogin starts login and returns the provider page URL and the state cookie.
+func startOIDCLogin(t *testing.T, serverURL string) (*url.URL, *http.Cookie) {
+	response, err := noRedirectClient.Get(serverURL + server.AuthOIDCPath + "/stub/start")
+	assert.NoError(t, err)
+	defer common.CloseResponseBody(response)
+	assert.Equal(t, response.StatusCode, http.StatusFound)
+	authURL, err := url.Parse(response.Header.Get("Location"))
+	assert.NoError(t, err)
+	for _, cookie := range response.Cookies() {
+		if cookie.Name == cookieOIDCState {
+			assert.True(t, cookie.HttpOnly)
+			assert.Equal(t, cookie.Value, authURL.Query().Get("state"))
+			return authURL, cookie
+		}
+	}
+	t.Fatal("No state cookie in response")
+	return nil, nil
+}
+
+func sendOIDCCallback(t *testing.T, serverURL string, query url.Values, cookie *http.Cookie) *http.Response {
+	request, err := http.NewRequest(http.MethodGet, serverURL+server.AuthOIDCPath+"/stub/callback?"+query.Encode(), nil)
+	assert.NoError(t, err)
+	if cookie != nil {
+		request.AddCookie(&http.Cookie{Name: cookie.Name, Value: cookie.Value})
+	}
+	response, err := noRedirectClient.Do(request)
+	assert.NoError(t, err)
+	return response
+}
+
+// loginWithOIDC goes through the whole login flow for the identity and returns the callback response.
+func loginWithOIDC(t *testing.T, serverURL string, stub *stubOIDCServer, identity stubIdentity) *http.Response {
+	authURL, cookie := startOIDCLogin(t, serverURL)
+	code := stub.authorize(t, authURL, identity)
+	return sendOIDCCallback(t, serverURL, url.Values{"code": {code}, "state": {authURL.Query().Get("state")}}, cookie)
+}
+
+func getDBUserIdentity(db *sql.DB, subject string) string {
+	userID := ""
+	err := db.QueryRow(selectUserIdentity, stubProviderName, subject).Scan(&userID)
+	if err != nil && err != sql.ErrNoRows {
+		log.Print("Failed to get user identity: ", err)
+	}
+	return userID
+}
+
+func TestOIDCLogin(t *testing.T) {
+	db, err := common.SetupDBByURL("../.env", "TEST_DB_URL")
+	assert.NoError(t, err)
+	defer common.CloseDB(db)
+	cleanupDB(db)
+	verifiedUserID := addDBUser(db, User{loginName: "verified", email: "verified@email.com", hashedPassword: "304854e2e79de0f96dc5477fef38a18f"})
+	_, err = db.Exec(updateEmailVerified, verifiedUserID)
+	assert.NoError(t, err)
+	addDBUser(db, User{loginName: "unverified", email: "unverified@email.com", hashedPassword: "304854e2e79de0f96dc5477fef38a18f"})
+	twoFactorUserID := addDBTwoFactorUser(t, db, "two_factor@email.com")
+	_, err = db.Exec(updateEmailVerified, twoFactorUserID)
+	assert.NoError(t, err)
+
+	stub := newStubOIDCServer(t)
+	defer stub.Close()
+	s := setupOIDCTestServer(db, stub)
+	defer s.Close()
+
+	type testCase struct {
+		name           string
+		identity       stubIdentity
+		expectedStatus int
+		expectedUserID string
+	}
+	testCases := []testCase{
+		{
+			name:           "link_verified_email",
+			identity:       stubIdentity{subject: "1", email: "verified@email.com", emailVerified: true},
+			expectedStatus: http.StatusOK,
+			expectedUserID: verifiedUserID,
+		},
+		{
+			name:           "linked_identity",
+			identity:       stubIdentity{subject: "1", email: "changed@email.com", emailVerified: false},
+			expectedStatus: http.StatusOK,
+			expectedUserID: verifiedUserID,
+		},
+		{
+			name:           "local_email_not_verified",
+			identity:       stubIdentity{subject: "2", email: "unverified@email.com", emailVerified: true},
+			expectedStatus: http.StatusConflict,
+		},
+		{
+			name:           "provider_email_not_verified",
+			identity:       stubIdentity{subject: "3", email: "verified@email.com", emailVerified: false},
+			expectedStatus: http.StatusForbidden,
+		},
+		{
+			name:           "two_factor",
+			identity:       stubIdentity{subject: "4", email: "two_factor@email.com", emailVerified: true},
+			expectedStatus: http.StatusAccepted,
+			expectedUserID: twoFactorUserID,
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			response := loginWithOIDC(t, s.URL, stub, tc.identity)
+			defer common.CloseResponseBody(response)
+			assert.Equal(t, response.StatusCode, tc.expectedStatus)
+			assert.Equal(t, getDBUserIdentity(db, tc.identity.subject), tc.expectedUserID)
+			if tc.expectedStatus == http.StatusOK {
+				responseToken := server.ResponseToken{}
+				err := json.NewDecoder(response.Body).Decode(&responseToken)
+				assert.NoError(t, err)
+				assert.Equal(t, responseToken.ID, tc.expectedUserID)
+				_, _, err = testKeys.ValidateJWT(responseToken.Token)
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestOIDCLoginCreatesUser(t *testing.T) {
+	db, err := common.SetupDBByURL("../.env", "TEST_DB_URL")
+	assert.NoError(t, err)
+	defer common.CloseDB(db)
+	cleanupDB(db)
+	addDBUser(db, User{loginName: "reader", email: "some_email@email.com", hashedPassword: "304854e2e79de0f96dc5477fef38a18f"})
+
+	stub := newStubOIDCServer(t)
+	defer stub.Close()
+	s := setupOIDCTestServer(db, stub)
+	defer s.Close()
+
+	response := loginWithOIDC(t, s.URL, stub, stubIdentity{subject: "1", email: "new@email.com", emailVerified: true, preferredUsername: "reader"})
+	defer common.CloseResponseBody(response)
+	assert.Equal(t, response.StatusCode, http.StatusOK)
+	responseToken := server.ResponseToken{}
+	err = json.NewDecoder(response.Body).Decode(&responseToken)
+	assert.NoError(t, err)
+	assert.Equal(t, len(response.Cookies()), 1)
+
+	userID, loginName := "", ""
+	err = db.QueryRow(selectUserByEmail, "new@email.com").Scan(&userID, &loginName)
+	assert.NoError(t, err)
+	assert.Equal(t, userID, responseToken.ID)
+	assert.Equal(t, getDBUserIdentity(db, "1"), userID)
+	assert.True(t, isDBEmailVerified(db, userID))
+	// The preferred username is taken, so a suffix is added.
+	assert.Regexp(t, `^reader-[0-9a-f]{4}$`, loginName)
+}
+
+func TestOIDCCallbackState(t *testing.T) {
+	db, err := common.SetupDBByURL("../.env", "TEST_DB_URL")
+	assert.NoError(t, err)
+	defer common.CloseDB(db)
+	cleanupDB(db)
+
+	stub := newStubOIDCServer(t)
+	defer stub.Close()
+	s := setupOIDCTestServer(db, stub)
+	defer s.Close()
+	identity := stubIdentity{subject: "1", email: "some_email@email.com", emailVerified: true}
+
+	response, err := noRedirectClient.Get(s.URL + server.AuthOIDCPath + "/unknown/start")
+	assert.NoError(t, err)
+	defer common.CloseResponseBody(response)
+	assert.Equal(t, response.StatusCode, http.StatusNotFound)
+
+	// The callback must come to the browser that started the login.
+	authURL, cookie := startOIDCLogin(t, s.URL)
+	query := url.Values{"code": {stub.authorize(t, authURL, identity)}, "state": {authURL.Query().Get("state")}}
+	response = sendOIDCCallback(t, s.URL, query, nil)
+	defer common.CloseResponseBody(response)
+	assert.Equal(t, response.StatusCode, http.StatusBadRequest)
+
+	_, anotherCookie := startOIDCLogin(t, s.URL)
+	response = sendOIDCCallback(t, s.URL, query, anotherCookie)
+	defer common.CloseResponseBody(response)
+	assert.Equal(t, response.StatusCode, http.StatusBadRequest)
+
+	response = sendOIDCCallback(t, s.URL, query, cookie)
+	defer common.CloseResponseBody(response)
+	assert.Equal(t, response.StatusCode, http.StatusOK)
+
+	// The state can be used once.
+	query.Set("code", stub.authorize(t, authURL, identity))
+	response = sendOIDCCallback(t, s.URL, query, cookie)
+	defer common.CloseResponseBody(response)
+	assert.Equal(t, response.StatusCode, http.StatusBadRequest)
+
+	authURL, cookie = startOIDCLogin(t, s.URL)
+	response = sendOIDCCallback(t, s.URL, url.Values{"error": {"access_denied"}, "state": {authURL.Query().Get("state")}}, cookie)
+	defer common.CloseResponseBody(response)
+	assert.Equal(t, response.StatusCode, http.StatusUnauthorized)
+
+	// The code is bound to the PKCE code verifier of the login attempt that requested it.
+	authURL, _ = startOIDCLogin(t, s.URL)
+	anotherAuthURL, anotherCookie := startOIDCLogin(t, s.URL)
+	query = url.Values{"code": {stub.authorize(t, authURL, identity)}, "state": {anotherAuthURL.Query().Get("state")}}
+	response = sendOIDCCallback(t, s.URL, query, anotherCookie)
+	defer common.CloseResponseBody(response)
+	assert.Equal(t, response.StatusCode, http.StatusUnauthorized)
+}
@@ -0,0 +1,255 @@
+package tests
+
+import (
+	"database/sql"
+	"encoding/json"
+	"log"
+	"net/http"
+	"testing"
+	"time"
+
+	common "github.com/bakurvik/mylib-common"
+	"github.com/bakurvik/mylib/users/internal/auth"
+	"github.com/bakurvik/mylib/users/internal/server"
+	"github.com/lib/pq"
+	"github.com/stretchr/testify/assert"
+)
+
+const (
+	insertPersonalToken = "INSERT INTO personal_tokens(id, user_id, name, token_hash, scopes, expires_at, revoked_at) VALUES (gen_random_uuid(), $1, $2, $3, $4, $5, $6) RETURNING id"
+	selectPersonalToken = "SELECT last_used_at FROM personal_tokens WHERE id = $1"
+)
+
+type PersonalToken struct {
+	userID    string
+	name      string
+	token     string
+	scopes    []string
+	expiresAt time.Time
+	revokedAt sql.NullTime
+}
+
+func addDBPersonalToken(db *sql.DB, token PersonalToken) string {
+	if token.expiresAt.IsZero() {
+		token.expiresAt = time.Now().Add(time.Hour)
+	}
+	tokenID := ""
+	err := db.QueryRow(insertPersonalToken, token.userID, token.name, auth.HashToken(token.token), pq.Array(token.scopes), token.expiresAt, token.revokedAt).Scan(&tokenID)
+	if err != nil {
+		log.Print("Failed to add personal token: ", err)
+	}
+	return tokenID
+}
+
+func getDBPersonalTokenLastUsedAt(db *sql.DB, tokenID string) sql.NullTime {
+	lastUsedAt := sql.NullTime{}
+	err := db.QueryRow(selectPersonalToken, tokenID).Scan(&lastUsedAt)
+	if err != nil {
+		log.Print("Failed to get personal token: ", err)
+	}
+	return lastUsedAt
+}
+
+func sendWhoamiRequest(t *testing.T, url string, token string) *http.Response {
+	request, err := http.NewRequest(http.MethodGet, url+server.AuthWhoamiPath, nil)
+	assert.NoError(t, err)
+	request.Header.Add("Authorization", "Bearer "+token)
+	response, err := http.DefaultClient.Do(request)
+	assert.NoError(t, err)
+	return response
+}
+
+func TestCreatePersonalToken(t *testing.T) {
+	db, err := common.SetupDBByURL("../.env", "TEST_DB_URL")
+	assert.NoError(t, err)
+	defer common.CloseDB(db)
+	cleanupDB(db)
+	userID := addDBUser(db, User{loginName: "login", email: "some_email@email.com", hashedPassword: "304854e2e79de0f96dc5477fef38a18f"})
+
+	s := setupTestServer(db)
+	defer s.Close()
+
+	type testCase struct {
+		name               string
+		request            server.RequestPersonalToken
+		expectedStatusCode int
+		expectedExpiresIn  time.Duration
+	}
+	testCases := []testCase{
+		{
+			name:               "default_expiry",
+			request:            server.RequestPersonalToken{Name: "import", Scopes: []string{auth.ScopeLibraryWrite, auth.ScopeReadingRead, auth.ScopeLibraryWrite}},
+			expectedStatusCode: http.StatusCreated,
+			expectedExpiresIn:  30 * 24 * time.Hour,
+		},
+		{
+			name:               "custom_expiry",
+			request:            server.RequestPersonalToken{Name: "import", Scopes: []string{auth.ScopeReadingRead}, ExpiresInDays: 7},
+			expectedStatusCode: http.StatusCreated,
+			expectedExpiresIn:  7 * 24 * time.Hour,
+		},
+		{
+			name:               "no_name",
+			request:            server.RequestPersonalToken{Scopes: []string{auth.ScopeReadingRead}},
+			expectedStatusCode: http.StatusBadRequest,
+		},
+		{
+			name:               "no_scopes",
+			request:            server.RequestPersonalToken{Name: "import"},
+			expectedStatusCode: http.StatusBadRequest,
+		},
+		{
+			name:               "unknown_scope",
+			request:            server.RequestPersonalToken{Name: "import", Scopes: []string{"users:write"}},
+			expectedStatusCode: http.StatusBadRequest,
+		},
+		{
+			name:               "too_long_expiry",
+			request:            server.RequestPersonalToken{Name: "import", Scopes: []string{auth.ScopeReadingRead}, ExpiresInDays: 366},
+			expectedStatusCode: http.StatusBadRequest,
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			body, _ := json.Marshal(tc.request)
+			response := sendSessionsRequest(t, http.MethodPost, s.URL+server.AuthTokensPath, userID, "", body)
+			defer common.CloseResponseBody(response)
+			assert.Equal(t, response.StatusCode, tc.expectedStatusCode)
+			if tc.expectedStatusCode != http.StatusCreated {
+				return
+			}
+
+			created := server.ResponsePersonalToken{}
+			err := json.NewDecoder(response.Body).Decode(&created)
+			assert.NoError(t, err)
+			assert.True(t, auth.IsPersonalToken(created.Token))
+			assert.Equal(t, created.Name, tc.request.Name)
+			assert.WithinDuration(t, created.ExpiresAt, time.Now().Add(tc.expectedExpiresIn), time.Minute)
+
+			// Scopes are deduplicated.
+			for i, scope := range created.Scopes {
+				assert.NotContains(t, created.Scopes[i+1:], scope)
+			}
+		})
+	}
+
+	// A personal token can't create other tokens.
+	token := "mylib_pat_token"
+	addDBPersonalToken(db, PersonalToken{userID: userID, name: "import", token: token, scopes: []string{auth.ScopeReadingRead}})
+	request, err := http.NewRequest(http.MethodPost, s.URL+server.AuthTokensPath, nil)
+	assert.NoError(t, err)
+	request.Header.Add("Authorization", "Bearer "+token)
+	response, err := http.DefaultClient.Do(request)
+	assert.NoError(t, err)
+	defer common.CloseResponseBody(response)
+	assert.Equal(t, response.StatusCode, http.StatusUnauthorized)
+}
+
+func TestGetPersonalTokens(t *testing.T) {
+	db, err := common.SetupDBByURL("../.env", "TEST_DB_URL")
+	assert.NoError(t, err)
+	defer common.CloseDB(db)
+	cleanupDB(db)
+	userID := addDBUser(db, User{loginName: "login", email: "some_email@email.com", hashedPassword: "304854e2e79de0f96dc5477fef38a18f"})
+	anotherUserID := addDBUser(db, User{loginName: "another", email: "another@email.com", hashedPassword: "304854e2e79de0f96dc5477fef38a18f"})
+	activeID := addDBPersonalToken(db, PersonalToken{userID: userID, name: "import", token: "mylib_pat_active", scopes: []string{auth.ScopeLibraryWrite}})
+	addDBPersonalToken(db, PersonalToken{userID: userID, name: "expired", token: "mylib_pat_expired", scopes: []string{auth.ScopeLibraryWrite}, expiresAt: time.Now().Add(-time.Hour)})
+	addDBPersonalToken(db, PersonalToken{userID: userID, name: "revoked", token: "mylib_pat_revoked", scopes: []string{auth.ScopeLibraryWrite}, revokedAt: sql.NullTime{Time: time.Now(), Valid: true}})
+	addDBPersonalToken(db, PersonalToken{userID: anotherUserID, name: "another", token: "mylib_pat_another", scopes: []string{auth.ScopeLibraryWrite}})
+
+	s := setupTestServer(db)
+	defer s.Close()
+
+	response := sendSessionsRequest(t, http.MethodGet, s.URL+server.AuthTokensPath, "", "", nil)
+	defer common.CloseResponseBody(response)
+	assert.Equal(t, response.StatusCode, http.StatusUnauthorized)
+
+	response = sendSessionsRequest(t, http.MethodGet, s.URL+server.AuthTokensPath, userID, "", nil)
+	defer common.CloseResponseBody(response)
+	assert.Equal(t, response.StatusCode, http.StatusOK)
+	tokens := []server.ResponsePersonalToken{}
+	err = json.NewDecoder(response.Body).Decode(&tokens)
+	assert.NoError(t, err)
+	assert.Equal(t, len(tokens), 1)
+	assert.Equal(t, tokens[0].ID, activeID)
+	assert.Equal(t, tokens[0].Name, "import")
+	assert.Equal(t, tokens[0].Scopes, []string{auth.ScopeLibraryWrite})
+	assert.Equal(t, tokens[0].Token, "")
+}
+
+func TestWhoamiWithPersonalToken(t *testing.T) {
+	db, err := common.SetupDBByURL("../.env", "TEST_DB_URL")
+	assert.NoError(t, err)
+	defer common.CloseDB(db)
+	cleanupDB(db)
+	userID := addDBUser(db, User{loginName: "login", email: "some_email@email.com", hashedPassword: "304854e2e79de0f96dc5477fef38a18f"})
+	tokenID := addDBPersonalToken(db, PersonalToken{userID: userID, name: "import", token: "mylib_pat_active", scopes: []string{auth.ScopeReadingRead, auth.ScopeReadingWrite}})
+	addDBPersonalToken(db, PersonalToken{userID: userID, name: "expired", token: "mylib_pat_expired", scopes: []string{auth.ScopeReadingRead}, expiresAt: time.Now().Add(-time.Hour)})
+
+	s := setupTestServer(db)
+	defer s.Close()
+
+	type testCase struct {
+		name               string
+		token              string
+		expectedStatusCode int
+	}
+	testCases := []testCase{
+		{name: "active", token: "mylib_pat_active", expectedStatusCode: http.StatusOK},
+		{name: "expired", token: "mylib_pat_expired", expectedStatusCode: http.StatusUnauthorized},
+		{name: "unknown", token: "mylib_pat_unknown", expectedStatusCode: http.StatusUnauthorized},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			response := sendWhoamiRequest(t, s.URL, tc.token)
+			defer common.CloseResponseBody(response)
+			assert.Equal(t, response.StatusCode, tc.expectedStatusCode)
+			if tc.expectedStatusCode == http.StatusOK {
+				responseData := server.ResponseUserID{}
+				err := json.NewDecoder(response.Body).Decode(&responseData)
+				assert.NoError(t, err)
+				assert.Equal(t, responseData.ID, userID)
+				assert.Equal(t, responseData.Role, auth.RoleReader)
+				assert.Equal(t, responseData.Scopes, []string{auth.ScopeReadingRead, auth.ScopeReadingWrite})
+			}
+		})
+	}
+	assert.True(t, getDBPersonalTokenLastUsedAt(db, tokenID).Valid)
+}
+
+func TestRevokePersonalToken(t *testing.T) {
+	db, err := common.SetupDBByURL("../.env", "TEST_DB_URL")
+	assert.NoError(t, err)
+	defer common.CloseDB(db)
+	cleanupDB(db)
+	userID := addDBUser(db, User{loginName: "login", email: "some_email@email.com", hashedPassword: "304854e2e79de0f96dc5477fef38a18f"})
+	anotherUserID := addDBUser(db, User{loginName: "another", email: "another@email.com", hashedPassword: "304854e2e79de0f96dc5477fef38a18f"})
+	tokenID := addDBPersonalToken(db, PersonalToken{userID: userID, name: "import", token: "mylib_pat_token", scopes: []string{auth.ScopeReadingRead}})
+
+	s := setupTestServer(db)
+	defer s.Close()
+
+	response := sendSessionsRequest(t, http.MethodDelete, s.URL+server.AuthTokensPath+"/"+tokenID, anotherUserID, "", nil)
+	defer common.CloseResponseBody(response)
+	assert.Equal(t, response.StatusCode, http.StatusNotFound)
+
+	response = sendSessionsRequest(t, http.MethodDelete, s.URL+server.AuthTokensPath+"/invalid", userID, "", nil)
+	defer common.CloseResponseBody(response)
+	assert.Equal(t, response.StatusCode, http.StatusBadRequest)
+
+	response = sendWhoamiRequest(t, s.URL, "mylib_pat_token")
+	defer common.CloseResponseBody(response)
+	assert.Equal(t, response.StatusCode, http.StatusOK)
+
+	response = sendSessionsRequest(t, http.MethodDelete, s.URL+server.AuthTokensPath+"/"+tokenID, userID, "", nil)
+	defer common.CloseResponseBody(response)
+	assert.Equal(t, response.StatusCode, http.StatusNoContent)
+
+	response = sendWhoamiRequest(t, s.URL, "mylib_pat_token")
+	defer common.CloseResponseBody(response)
+	assert.Equal(t, response.StatusCode, http.StatusUnauthorized)
+
+	response = sendSessionsRequest(t, http.MethodDelete, s.URL+server.AuthTokensPath+"/"+tokenID, userID, "", nil)
+	defer common.CloseResponseBody(response)
+	assert.Equal(t, response.StatusCode, http.StatusNotFound)
+}
@@ -0,0 +1,265 @@
+package tests
+
+import (
+	"bytes"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"testing"
+	"time"
+
+	common "github.com/bakurvik/mylib-common"
+	"github.com/bakurvik/mylib/users/internal/auth"
+	"github.com/bakurvik/mylib/users/internal/server"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+const (
+	insertSessionToken = "INSERT INTO refresh_tokens(token, user_id, expires_at, revoked_at, family_id, user_agent, ip_address) VALUES (gen_random_uuid(), $1, $2, $3, $4, $5, $6) RETURNING token"
+)
+
+type Session struct {
+	userID    string
+	familyID  uuid.UUID
+	userAgent string
+	ipAddress string
+	expiresAt time.Time
+	revokedAt sql.NullTime
+}
+
+func addDBSession(db *sql.DB, session Session) string {
+	if session.expiresAt.IsZero() {
+		session.expiresAt = time.Now().Add(time.Hour)
+	}
+	row := db.QueryRow(insertSessionToken, session.userID, session.expiresAt, session.revokedAt, session.familyID, session.userAgent, session.ipAddress)
+	token := ""
+	err := row.Scan(&token)
+	if err != nil {
+		log.Print("Failed to add session: ", err)
+	}
+	return token
+}
+
+func sendSessionsRequest(t *testing.T, method string, url string, userID string, refreshToken string, body []byte) *http.Response {
+	request, err := http.NewRequest(method, url, bytes.NewBuffer(body))
+	assert.NoError(t, err)
+	if userID != "" {
+		accessToken, _ := testKeys.MakeJWT(uuid.MustParse(userID), auth.RoleReader, time.Hour)
+		request.Header.Add("Authorization", "Bearer "+accessToken)
+	}
+	if refreshToken != "" {
+		request.AddCookie(&http.Cookie{Name: cookieRefreshToken, Value: refreshToken})
+	}
+	response, err := http.DefaultClient.Do(request)
+	assert.NoError(t, err)
+	return response
+}
+
+func TestGetSessions(t *testing.T) {
+	db, err := common.SetupDBByURL("../.env", "TEST_DB_URL")
+	assert.NoError(t, err)
+	defer common.CloseDB(db)
+	cleanupDB(db)
+	userID := addDBUser(db, User{loginName: "login", email: "some_email@email.com", hashedPassword: "304854e2e79de0f96dc5477fef38a18f"})
+	anotherUserID := addDBUser(db, User{loginName: "another", email: "another@email.com", hashedPassword: "304854e2e79de0f96dc5477fef38a18f"})
+	currentFamily := uuid.New()
+	laptopFamily := uuid.New()
+	currentToken := addDBSession(db, Session{userID: userID, familyID: currentFamily, userAgent: "Firefox", ipAddress: "10.0.0.1"})
+	addDBSession(db, Session{userID: userID, familyID: laptopFamily, userAgent: "Chrome", ipAddress: "10.0.0.2"})
+	addDBSession(db, Session{userID: userID, familyID: uuid.New(), userAgent: "Revoked", revokedAt: sql.NullTime{Time: time.Now(), Valid: true}})
+	addDBSession(db, Session{userID: userID, familyID: uuid.New(), userAgent: "Expired", expiresAt: time.Now().Add(-time.Hour)})
+	addDBSession(db, Session{userID: anotherUserID, familyID: uuid.New(), userAgent: "Another user"})
+
+	s := setupTestServer(db)
+	defer s.Close()
+
+	response := sendSessionsRequest(t, http.MethodGet, s.URL+server.AuthSessionsPath, "", "", nil)
+	defer common.CloseResponseBody(response)
+	assert.Equal(t, response.StatusCode, http.StatusUnauthorized)
+
+	response = sendSessionsRequest(t, http.MethodGet, s.URL+server.AuthSessionsPath, userID, currentToken, nil)
+	defer common.CloseResponseBody(response)
+	assert.Equal(t, response.StatusCode, http.StatusOK)
+	sessions := []server.ResponseSession{}
+	err = json.NewDecoder(response.Body).Decode(&sessions)
+	assert.NoError(t, err)
+
+	type sessionInfo struct {
+		id        string
+		userAgent string
+		ipAddress string
+		current   bool
+	}
+	result := []sessionInfo{}
+	for _, session := range sessions {
+		assert.False(t, session.StartedAt.IsZero())
+		assert.False(t, session.LastUsedAt.IsZero())
+		result = append(result, sessionInfo{id: session.ID, userAgent: session.UserAgent, ipAddress: session.IPAddress, current: session.Current})
+	}
+	assert.ElementsMatch(t, result, []sessionInfo{
+		{id: currentFamily.String(), userAgent: "Firefox", ipAddress: "10.0.0.1", current: true},
+		{id: laptopFamily.String(), userAgent: "Chrome", ipAddress: "10.0.0.2", current: false},
+	})
+}
+
+func TestLoginStoresSession(t *testing.T) {
+	db, err := common.SetupDBByURL("../.env", "TEST_DB_URL")
+	assert.NoError(t, err)
+	defer common.CloseDB(db)
+	cleanupDB(db)
+	hashedPassword, _ := auth.HashPassword("password")
+	userID := addDBUser(db, User{loginName: "login", email: "some_email@email.com", hashedPassword: hashedPassword})
+
+	s := setupTestServer(db)
+	defer s.Close()
+
+	body, _ := json.Marshal(server.RequestLogin{Email: "some_email@email.com", Password: "password"})
+	request, err := http.NewRequest(http.MethodPost, s.URL+server.AuthLoginPath, bytes.NewBuffer(body))
+	assert.NoError(t, err)
+	request.Header.Set("User-Agent", "Test browser")
+	response, err := http.DefaultClient.Do(request)
+	assert.NoError(t, err)
+	defer common.CloseResponseBody(response)
+	assert.Equal(t, response.StatusCode, http.StatusOK)
+
+	response = sendSessionsRequest(t, http.MethodGet, s.URL+server.AuthSessionsPath, userID, response.Cookies()[0].Value, nil)
+	defer common.CloseResponseBody(response)
+	sessions := []server.ResponseSession{}
+	err = json.NewDecoder(response.Body).Decode(&sessions)
+	assert.NoError(t, err)
+	assert.Equal(t, len(sessions), 1)
+	assert.Equal(t, sessions[0].UserAgent, "Test browser")
+	assert.Equal(t, sessions[0].IPAddress, "127.0.0.1")
+	assert.True(t, sessions[0].Current)
+}
+
+func TestDeleteSession(t *testing.T) {
+	type testCase struct {
+		name               string
+		sessionID          func(own uuid.UUID, another uuid.UUID) string
+		expectedStatusCode int
+		expectedRevoked    bool
+	}
+	testCases := []testCase{
+		{
+			name:               "success",
+			sessionID:          func(own uuid.UUID, another uuid.UUID) string { return own.String() },
+			expectedStatusCode: http.StatusNoContent,
+			expectedRevoked:    true,
+		},
+		{
+			name:               "another_user_session",
+			sessionID:          func(own uuid.UUID, another uuid.UUID) string { return another.String() },
+			expectedStatusCode: http.StatusNotFound,
+		},
+		{
+			name:               "unknown_session",
+			sessionID:          func(own uuid.UUID, another uuid.UUID) string { return uuid.NewString() },
+			expectedStatusCode: http.StatusNotFound,
+		},
+		{
+			name:               "invalid_session_id",
+			sessionID:          func(own uuid.UUID, another uuid.UUID) string { return "invalid" },
+			expectedStatusCode: http.StatusBadRequest,
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			db, err := common.SetupDBByURL("../.env", "TEST_DB_URL")
+			assert.NoError(t, err)
+			defer common.CloseDB(db)
+			cleanupDB(db)
+			userID := addDBUser(db, User{loginName: "login", email: "some_email@email.com", hashedPassword: "304854e2e79de0f96dc5477fef38a18f"})
+			anotherUserID := addDBUser(db, User{loginName: "another", email: "another@email.com", hashedPassword: "304854e2e79de0f96dc5477fef38a18f"})
+			ownFamily := uuid.New()
+			anotherFamily := uuid.New()
+			ownToken := addDBSession(db, Session{userID: userID, familyID: ownFamily})
+			anotherToken := addDBSession(db, Session{userID: anotherUserID, familyID: anotherFamily})
+
+			s := setupTestServer(db)
+			defer s.Close()
+
+			url := fmt.Sprintf("%v%v/%v", s.URL, server.AuthSessionsPath, tc.sessionID(ownFamily, anotherFamily))
+			response := sendSessionsRequest(t, http.MethodDelete, url, userID, "", nil)
+			defer common.CloseResponseBody(response)
+			assert.Equal(t, response.StatusCode, tc.expectedStatusCode)
+
+			assert.Equal(t, getDBToken(db, ownToken).revokedAt.Valid, tc.expectedRevoked)
+			assert.False(t, getDBToken(db, anotherToken).revokedAt.Valid)
+		})
+	}
+}
+
+func TestLogoutAll(t *testing.T) {
+	db, err := common.SetupDBByURL("../.env", "TEST_DB_URL")
+	assert.NoError(t, err)
+	defer common.CloseDB(db)
+	cleanupDB(db)
+	userID := addDBUser(db, User{loginName: "login", email: "some_email@email.com", hashedPassword: "304854e2e79de0f96dc5477fef38a18f"})
+	anotherUserID := addDBUser(db, User{loginName: "another", email: "another@email.com", hashedPassword: "304854e2e79de0f96dc5477fef38a18f"})
+	currentToken := addDBSession(db, Session{userID: userID, familyID: uuid.New()})
+	otherToken := addDBSession(db, Session{userID: userID, familyID: uuid.New()})
+	anotherUserToken := addDBSession(db, Session{userID: anotherUserID, familyID: uuid.New()})
+
+	s := setupTestServer(db)
+	defer s.Close()
+
+	response := sendSessionsRequest(t, http.MethodPost, s.URL+server.AuthLogoutAllPath, "", currentToken, nil)
+	defer common.CloseResponseBody(response)
+	assert.Equal(t, response.StatusCode, http.StatusUnauthorized)
+
+	response = sendSessionsRequest(t, http.MethodPost, s.URL+server.AuthLogoutAllPath, userID, currentToken, nil)
+	defer common.CloseResponseBody(response)
+	assert.Equal(t, response.StatusCode, http.StatusNoContent)
+
+	assert.True(t, getDBToken(db, currentToken).revokedAt.Valid)
+	assert.True(t, getDBToken(db, otherToken).revokedAt.Valid)
+	assert.False(t, getDBToken(db, anotherUserToken).revokedAt.Valid)
+}
+
+func TestPasswordChangeRevokesOtherSessions(t *testing.T) {
+	const password = "password"
+	type testCase struct {
+		name                 string
+		newPassword          string
+		expectedOtherRevoked bool
+	}
+	testCases := []testCase{
+		{
+			name:                 "password_changed",
+			newPassword:          "new_password",
+			expectedOtherRevoked: true,
+		},
+		{
+			name:                 "same_password",
+			newPassword:          password,
+			expectedOtherRevoked: false,
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			db, err := common.SetupDBByURL("../.env", "TEST_DB_URL")
+			assert.NoError(t, err)
+			defer common.CloseDB(db)
+			cleanupDB(db)
+			hashedPassword, _ := auth.HashPassword(password)
+			userID := addDBUser(db, User{loginName: "login", email: "some_email@email.com", hashedPassword: hashedPassword})
+			currentToken := addDBSession(db, Session{userID: userID, familyID: uuid.New()})
+			otherToken := addDBSession(db, Session{userID: userID, familyID: uuid.New()})
+
+			s := setupTestServer(db)
+			defer s.Close()
+
+			body, _ := json.Marshal(server.RequestUser{LoginName: "new_login", Email: "new_email@email.com", Password: tc.newPassword})
+			response := sendSessionsRequest(t, http.MethodPut, s.URL+server.ApiUsersPath, userID, currentToken, body)
+			defer common.CloseResponseBody(response)
+			assert.Equal(t, response.StatusCode, http.StatusNoContent)
+
+			assert.False(t, getDBToken(db, currentToken).revokedAt.Valid)
+			assert.Equal(t, getDBToken(db, otherToken).revokedAt.Valid, tc.expectedOtherRevoked)
+		})
+	}
+}
@@ -0,0 +1,187 @@
+package tests
+
+import (
+	"database/sql"
+	"encoding/json"
+	"log"
+	"net/http"
+	"net/url"
+	"testing"
+	"time"
+
+	common "github.com/bakurvik/mylib-common"
+	"github.com/bakurvik/mylib/users/internal/auth"
+	"github.com/bakurvik/mylib/users/internal/server"
+	"github.com/bakurvik/mylib/users/internal/totp"
+	"github.com/stretchr/testify/assert"
+)
+
+const (
+	selectTOTPEnabled  = "SELECT totp_enabled FROM users WHERE id = $1"
+	insertRecoveryCode = "INSERT INTO recovery_codes(user_id, code_hash) VALUES ($1, $2)"
+)
+
+func currentTOTPCode(t *testing.T, secret string) string {
+	code, err := totp.Code(secret, totp.Step(time.Now()))
+	assert.NoError(t, err)
+	return code
+}
+
+// wrongTOTPCode returns a code that doesn't match any of the currently valid ones.
+func wrongTOTPCode(t *testing.T, secret string) string {
+	code := currentTOTPCode(t, secret)
+	for digit := '0'; digit <= '9'; digit++ {
+		wrong := code[:len(code)-1] + string(digit)
+		if _, ok := totp.Validate(secret, wrong, time.Now()); !ok {
+			return wrong
+		}
+	}
+	return "abcdef"
+}
+
+func isDBTOTPEnabled(db *sql.DB, userID string) bool {
+	enabled := false
+	err := db.QueryRow(selectTOTPEnabled, userID).Scan(&enabled)
+	if err != nil {
+		log.Print("Failed to get user: ", err)
+	}
+	return enabled
+}
+
+// addDBTwoFactorUser adds user with password "password" and enabled 2FA with testTOTPSecret.
+func addDBTwoFactorUser(t *testing.T, db *sql.DB, email string) string {
+	hashedPassword, _ := auth.HashPassword("password")
+	userID := addDBUser(db, User{loginName: "login", email: email, hashedPassword: hashedPassword})
+	_, err := db.Exec(enableUserTOTP, userID, testTOTPSecret)
+	assert.NoError(t, err)
+	return userID
+}
+
+func loginWithChallenge(t *testing.T, url string, email string) string {
+	response := postLogin(t, url, email, "password")
+	defer common.CloseResponseBody(response)
+	assert.Equal(t, response.StatusCode, http.StatusAccepted)
+	assert.Equal(t, len(response.Cookies()), 0)
+	challenge := server.ResponseTwoFactorChallenge{}
+	err := json.NewDecoder(response.Body).Decode(&challenge)
+	assert.NoError(t, err)
+	assert.NotEqual(t, challenge.ChallengeToken, "")
+	return challenge.ChallengeToken
+}
+
+func TestTwoFactorSetupAndEnable(t *testing.T) {
+	db, err := common.SetupDBByURL("../.env", "TEST_DB_URL")
+	assert.NoError(t, err)
+	defer common.CloseDB(db)
+	cleanupDB(db)
+	userID := addDBUser(db, User{loginName: "login", email: "some_email@email.com", hashedPassword: "304854e2e79de0f96dc5477fef38a18f"})
+
+	s := setupTestServer(db)
+	defer s.Close()
+
+	response := sendSessionsRequest(t, http.MethodPost, s.URL+server.AuthTwoFactorSetupPath, "", "", nil)
+	defer common.CloseResponseBody(response)
+	assert.Equal(t, response.StatusCode, http.StatusUnauthorized)
+
+	response = sendSessionsRequest(t, http.MethodPost, s.URL+server.AuthTwoFactorEnablePath, userID, "", []byte(`{"code": "123456"}`))
+	defer common.CloseResponseBody(response)
+	assert.Equal(t, response.StatusCode, http.StatusBadRequest)
+
+	response = sendSessionsRequest(t, http.MethodPost, s.URL+server.AuthTwoFactorSetupPath, userID, "", nil)
+	defer common.CloseResponseBody(response)
+	assert.Equal(t, response.StatusCode, http.StatusOK)
+	setup := server.ResponseTwoFactorSetup{}
+	err = json.NewDecoder(response.Body).Decode(&setup)
+	assert.NoError(t, err)
+	uri, err := url.Parse(setup.URI)
+	assert.NoError(t, err)
+	assert.Equal(t, uri.Scheme, "otpauth")
+	assert.Equal(t, uri.Query().Get("secret"), setup.Secret)
+	assert.Contains(t, uri.Path, "some_email@email.com")
+	assert.False(t, isDBTOTPEnabled(db, userID))
+
+	body, _ := json.Marshal(server.RequestTwoFactorCode{Code: wrongTOTPCode(t, setup.Secret)})
+	response = sendSessionsRequest(t, http.MethodPost, s.URL+server.AuthTwoFactorEnablePath, userID, "", body)
+	defer common.CloseResponseBody(response)
+	assert.Equal(t, response.StatusCode, http.StatusBadRequest)
+	assert.False(t, isDBTOTPEnabled(db, userID))
+
+	body, _ = json.Marshal(server.RequestTwoFactorCode{Code: currentTOTPCode(t, setup.Secret)})
+	response = sendSessionsRequest(t, http.MethodPost, s.URL+server.AuthTwoFactorEnablePath, userID, "", body)
+	defer common.CloseResponseBody(response)
+	assert.Equal(t, response.StatusCode, http.StatusOK)
+	codes := server.ResponseRecoveryCodes{}
+	err = json.NewDecoder(response.Body).Decode(&codes)
+	assert.NoError(t, err)
+	assert.Equal(t, len(codes.Codes), 10)
+	assert.True(t, isDBTOTPEnabled(db, userID))
+
+	response = sendSessionsRequest(t, http.MethodPost, s.URL+server.AuthTwoFactorSetupPath, userID, "", nil)
+	defer common.CloseResponseBody(response)
+	assert.Equal(t, response.StatusCode, http.StatusConflict)
+}
+
+func TestLoginWithTwoFactor(t *testing.T) {
+	db, err := common.SetupDBByURL("../.env", "TEST_DB_URL")
+	assert.NoError(t, err)
+	defer common.CloseDB(db)
+	cleanupDB(db)
+	userID := addDBTwoFactorUser(t, db, "some_email@email.com")
+
+	s := setupTestServer(db)
+	defer s.Close()
+
+	challenge := loginWithChallenge(t, s.URL, "some_email@email.com")
+
+	response := postJSON(t, s.URL+server.AuthTwoFactorVerifyPath, server.RequestTwoFactorVerify{ChallengeToken: challenge, Code: wrongTOTPCode(t, testTOTPSecret)})
+	defer common.CloseResponseBody(response)
+	assert.Equal(t, response.StatusCode, http.StatusUnauthorized)
+
+	response = postJSON(t, s.URL+server.AuthTwoFactorVerifyPath, server.RequestTwoFactorVerify{ChallengeToken: "unknown", Code: currentTOTPCode(t, testTOTPSecret)})
+	defer common.CloseResponseBody(response)
+	assert.Equal(t, response.StatusCode, http.StatusUnauthorized)
+
+	code := currentTOTPCode(t, testTOTPSecret)
+	response = postJSON(t, s.URL+server.AuthTwoFactorVerifyPath, server.RequestTwoFactorVerify{ChallengeToken: challenge, Code: code})
+	defer common.CloseResponseBody(response)
+	assert.Equal(t, response.StatusCode, http.StatusOK)
+	responseToken := server.ResponseToken{}
+	err = json.NewDecoder(response.Body).Decode(&responseToken)
+	assert.NoError(t, err)
+	assert.Equal(t, responseToken.ID, userID)
+	assert.Equal(t, len(response.Cookies()), 1)
+
+	// The challenge can be used once.
+	response = postJSON(t, s.URL+server.AuthTwoFactorVerifyPath, server.RequestTwoFactorVerify{ChallengeToken: challenge, Code: code})
+	defer common.CloseResponseBody(response)
+	assert.Equal(t, response.StatusCode, http.StatusUnauthorized)
+
+	// The code can't be replayed with a new challenge.
+	challenge = loginWithChallenge(t, s.URL, "some_email@email.com")
+	response = postJSON(t, s.URL+server.AuthTwoFactorVerifyPath, server.RequestTwoFactorVerify{ChallengeToken: challenge, Code: code})
+	defer common.CloseResponseBody(response)
+	assert.Equal(t, response.StatusCode, http.StatusUnauthorized)
+}
+
+func TestLoginWithRecoveryCode(t *testing.T) {
+	db, err := common.SetupDBByURL("../.env", "TEST_DB_URL")
+	assert.NoError(t, err)
+	defer common.CloseDB(db)
+	cleanupDB(db)
+	userID := addDBTwoFactorUser(t, db, "some_email@email.com")
+	_, err = db.Exec(insertRecoveryCode, userID, auth.HashToken("abcdefghij"))
+	assert.NoError(t, err)
+
+	s := setupTestServer(db)
+	defer s.Close()
+
+	challenge := loginWithChallenge(t, s.URL, "some_email@email.com")
+	response := postJSON(t, s.URL+server.AuthTwoFactorVerifyPath, server.RequestTwoFactorVerify{ChallengeToken: challenge, Code: "ABCDE-FGHIJ"})
+	defer common.CloseResponseBody(response)
+	assert.Equal(t, response.StatusCode, http.StatusOK)
+
+	challenge = loginWithChallenge(t, s.URL, "some_email@email.com")
+	response = postJSON(t, s.URL+server.AuthTwoFactorVerifyPath, server.RequestTwoFactorVerify{ChallengeToken: challenge, Code: "abcde-fghij"})
+	defer common.CloseResponseBody(response)
+	assert.Equal(t, response.StatusCode, http.StatusUnauthorized)
+}
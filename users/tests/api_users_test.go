@@ -21,7 +21,8 @@ import (
 )
 
 const (
-	selectUsers = "SELECT login_name, email, birth_date, hashed_password FROM users WHERE id = $1"
+	selectUsers  = "SELECT login_name, email, birth_date, hashed_password FROM users WHERE id = $1"
+	selectOutbox = "SELECT topic, message_key, payload FROM outbox ORDER BY id"
 )
 
 func getDBUser(db *sql.DB, id string) *User {
@@ -37,6 +38,32 @@ func getDBUser(db *sql.DB, id string) *User {
 	return &user
 }
 
+type outboxMessage struct {
+	topic   string
+	key     string
+	payload server.UserMessage
+}
+
+func getDBOutboxMessages(t *testing.T, db *sql.DB) []outboxMessage {
+	rows, err := db.Query(selectOutbox)
+	if err != nil {
+		t.Fatalf("Error while selecting outbox: %v", err)
+	}
+	defer common.CloseRows(rows)
+	messages := []outboxMessage{}
+	for rows.Next() {
+		message := outboxMessage{}
+		payload := []byte{}
+		err := rows.Scan(&message.topic, &message.key, &payload)
+		if err != nil {
+			t.Fatalf("Error while scanning outbox: %v", err)
+		}
+		json.Unmarshal(payload, &message.payload)
+		messages = append(messages, message)
+	}
+	return messages
+}
+
 func getUserFromResponse(response *http.Response) server.ResponseUser {
 	body, _ := io.ReadAll(response.Body)
 	responseData := server.ResponseUser{}
@@ -162,7 +189,7 @@ func TestUpdateUser(t *testing.T) {
 			request, requestErr := http.NewRequest(http.MethodPut, s.URL+server.ApiUsersPath, bytes.NewBuffer(requestJson))
 			assert.NoError(t, requestErr)
 			uuid, _ := uuid.Parse(userID)
-			accessToken, _ := auth.MakeJWT(uuid, authSecretKey, time.Hour)
+			accessToken, _ := testKeys.MakeJWT(uuid, auth.RoleReader, time.Hour)
 			if tc.requestToken != "" {
 				accessToken = tc.requestToken
 			}
@@ -194,7 +221,7 @@ func TestUpdateUser(t *testing.T) {
 func TestGetUser(t *testing.T) {
 	anotherUserID := "4fc40366-ff15-4653-be30-1bba21f016c1"
 	anotherUseruuid, _ := uuid.Parse(anotherUserID)
-	accessToken, _ := auth.MakeJWT(anotherUseruuid, authSecretKey, time.Hour)
+	accessToken, _ := testKeys.MakeJWT(anotherUseruuid, auth.RoleReader, time.Hour)
 	type testCase struct {
 		name               string
 		token              string
@@ -209,7 +236,7 @@ func TestGetUser(t *testing.T) {
 			token:              "",
 			dbUser:             User{loginName: "login", email: "some_email@email.com", birthDate: toSqlNullTime("09.05.1956"), hashedPassword: "304854e2e79de0f96dc5477fef38a18f"},
 			expectedStatusCode: http.StatusOK,
-			expectedUser:       server.ResponseUser{LoginName: "login", Email: "some_email@email.com", BirthDate: "09.05.1956"},
+			expectedUser:       server.ResponseUser{LoginName: "login"},
 		},
 		{
 			name:               "authorized_as_another_user",
@@ -225,13 +252,6 @@ func TestGetUser(t *testing.T) {
 			expectedStatusCode: http.StatusOK,
 			expectedUser:       server.ResponseUser{LoginName: "login"},
 		},
-		{
-			name:               "authorized_as_request_user_no_date",
-			token:              "",
-			dbUser:             User{loginName: "login", email: "some_email@email.com", birthDate: sql.NullTime{}, hashedPassword: "304854e2e79de0f96dc5477fef38a18f"},
-			expectedStatusCode: http.StatusOK,
-			expectedUser:       server.ResponseUser{LoginName: "login", Email: "some_email@email.com"},
-		},
 		{
 			name:               "user_not_found",
 			token:              "",
@@ -254,10 +274,10 @@ func TestGetUser(t *testing.T) {
 			s := setupTestServer(db)
 			defer s.Close()
 
-			request, requestErr := http.NewRequest(http.MethodGet, fmt.Sprintf("%v%v/{%v}", s.URL, server.ApiUsersPath, userID), nil)
+			request, requestErr := http.NewRequest(http.MethodGet, fmt.Sprintf("%v%v/%v", s.URL, server.ApiUsersPath, userID), nil)
 			assert.NoError(t, requestErr)
 			uuid, _ := uuid.Parse(userID)
-			accessToken, _ := auth.MakeJWT(uuid, authSecretKey, time.Hour)
+			accessToken, _ := testKeys.MakeJWT(uuid, auth.RoleReader, time.Hour)
 			if tc.token != "" {
 				accessToken = tc.token
 			}
@@ -270,6 +290,7 @@ func TestGetUser(t *testing.T) {
 			assert.Equal(t, tc.expectedStatusCode, response.StatusCode)
 
 			if tc.expectedStatusCode == http.StatusOK {
+				tc.expectedUser.ID = userID
 				responseUser := getUserFromResponse(response)
 				assert.Equal(t, responseUser, tc.expectedUser)
 			}
@@ -277,6 +298,150 @@ func TestGetUser(t *testing.T) {
 	}
 }
 
+func TestGetUserMe(t *testing.T) {
+	type testCase struct {
+		name               string
+		token              string
+		dbUser             User
+		expectedStatusCode int
+		expectedUser       server.ResponseUserMe
+	}
+	testCases := []testCase{
+		{
+			name:               "authorized",
+			dbUser:             User{loginName: "login", email: "some_email@email.com", birthDate: toSqlNullTime("09.05.1956"), hashedPassword: "304854e2e79de0f96dc5477fef38a18f"},
+			expectedStatusCode: http.StatusOK,
+			expectedUser:       server.ResponseUserMe{ResponseUser: server.ResponseUser{LoginName: "login"}, Email: "some_email@email.com", BirthDate: "09.05.1956", Locale: "en"},
+		},
+		{
+			name:               "no_birth_date",
+			dbUser:             User{loginName: "login", email: "some_email@email.com", birthDate: sql.NullTime{}, hashedPassword: "304854e2e79de0f96dc5477fef38a18f"},
+			expectedStatusCode: http.StatusOK,
+			expectedUser:       server.ResponseUserMe{ResponseUser: server.ResponseUser{LoginName: "login"}, Email: "some_email@email.com", Locale: "en"},
+		},
+		{
+			name:               "unauthorized",
+			token:              "invalid_token",
+			dbUser:             User{loginName: "login", email: "some_email@email.com", birthDate: sql.NullTime{}, hashedPassword: "304854e2e79de0f96dc5477fef38a18f"},
+			expectedStatusCode: http.StatusUnauthorized,
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			db, err := common.SetupDBByURL("../.env", "TEST_DB_URL")
+			assert.NoError(t, err)
+			defer common.CloseDB(db)
+			cleanupDB(db)
+			userID := addDBUser(db, tc.dbUser)
+
+			s := setupTestServer(db)
+			defer s.Close()
+
+			request, requestErr := http.NewRequest(http.MethodGet, s.URL+server.ApiUsersMePath, nil)
+			assert.NoError(t, requestErr)
+			uuid, _ := uuid.Parse(userID)
+			accessToken, _ := testKeys.MakeJWT(uuid, auth.RoleReader, time.Hour)
+			if tc.token != "" {
+				accessToken = tc.token
+			}
+			request.Header.Add("Authorization", "Bearer "+accessToken)
+
+			client := &http.Client{}
+			response, err := client.Do(request)
+			assert.NoError(t, err)
+			defer common.CloseResponseBody(response)
+			assert.Equal(t, tc.expectedStatusCode, response.StatusCode)
+
+			if tc.expectedStatusCode == http.StatusOK {
+				responseUser := server.ResponseUserMe{}
+				err = json.NewDecoder(response.Body).Decode(&responseUser)
+				assert.NoError(t, err)
+				assert.False(t, responseUser.CreatedAt.IsZero())
+				tc.expectedUser.ID = userID
+				tc.expectedUser.CreatedAt = responseUser.CreatedAt
+				assert.Equal(t, responseUser, tc.expectedUser)
+			}
+		})
+	}
+}
+
+func TestUpdateUserProfile(t *testing.T) {
+	type testCase struct {
+		name               string
+		token              string
+		request            server.RequestUserProfile
+		expectedStatusCode int
+		expectedUser       server.ResponseUser
+	}
+	testCases := []testCase{
+		{
+			name:               "success",
+			request:            server.RequestUserProfile{DisplayName: "Name", Bio: "Reader", AvatarURL: "https://example.com/avatar.png", Locale: "pt-BR", ReadingListPublic: true},
+			expectedStatusCode: http.StatusNoContent,
+			expectedUser:       server.ResponseUser{LoginName: "login", DisplayName: "Name", Bio: "Reader", AvatarURL: "https://example.com/avatar.png", ReadingListPublic: true},
+		},
+		{
+			name:               "invalid_avatar_url",
+			request:            server.RequestUserProfile{AvatarURL: "javascript:alert(1)"},
+			expectedStatusCode: http.StatusBadRequest,
+		},
+		{
+			name:               "invalid_locale",
+			request:            server.RequestUserProfile{Locale: "english"},
+			expectedStatusCode: http.StatusBadRequest,
+		},
+		{
+			name:               "unauthorized",
+			token:              "invalid_token",
+			request:            server.RequestUserProfile{DisplayName: "Name"},
+			expectedStatusCode: http.StatusUnauthorized,
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			db, err := common.SetupDBByURL("../.env", "TEST_DB_URL")
+			assert.NoError(t, err)
+			defer common.CloseDB(db)
+			cleanupDB(db)
+			userID := addDBUser(db, User{loginName: "login", email: "some_email@email.com", hashedPassword: "304854e2e79de0f96dc5477fef38a18f"})
+
+			s := setupTestServer(db)
+			defer s.Close()
+
+			requestJson, _ := json.Marshal(tc.request)
+			request, requestErr := http.NewRequest(http.MethodPut, s.URL+server.ApiUsersMeProfilePath, bytes.NewBuffer(requestJson))
+			assert.NoError(t, requestErr)
+			uuid, _ := uuid.Parse(userID)
+			accessToken, _ := testKeys.MakeJWT(uuid, auth.RoleReader, time.Hour)
+			if tc.token != "" {
+				accessToken = tc.token
+			}
+			request.Header.Add("Authorization", "Bearer "+accessToken)
+
+			client := &http.Client{}
+			response, err := client.Do(request)
+			assert.NoError(t, err)
+			defer common.CloseResponseBody(response)
+			assert.Equal(t, tc.expectedStatusCode, response.StatusCode)
+
+			if tc.expectedStatusCode == http.StatusNoContent {
+				response, err := http.Get(fmt.Sprintf("%v%v/%v", s.URL, server.ApiUsersPath, userID))
+				assert.NoError(t, err)
+				defer common.CloseResponseBody(response)
+				tc.expectedUser.ID = userID
+				assert.Equal(t, getUserFromResponse(response), tc.expectedUser)
+				assert.Equal(t, getDBOutboxMessages(t, db), []outboxMessage{{
+					topic:   server.UsersTopic,
+					key:     userID,
+					payload: server.UserMessage{ID: userID, Action: "updated", DisplayName: tc.request.DisplayName, ReadingListPublic: tc.request.ReadingListPublic},
+				}})
+			} else {
+				assert.Empty(t, getDBOutboxMessages(t, db))
+			}
+		})
+	}
+}
+
 func TestDeleteUser(t *testing.T) {
 	type testCase struct {
 		name               string
@@ -290,7 +455,7 @@ func TestDeleteUser(t *testing.T) {
 			expectedStatusCode: http.StatusNoContent,
 		},
 		{
-			name:               "success",
+			name:               "unauthorized",
 			hasToken:           false,
 			expectedStatusCode: http.StatusUnauthorized,
 		},
@@ -310,7 +475,7 @@ func TestDeleteUser(t *testing.T) {
 			request, requestErr := http.NewRequest(http.MethodDelete, s.URL+server.ApiUsersPath, nil)
 			assert.NoError(t, requestErr)
 			uuid, _ := uuid.Parse(userID)
-			accessToken, _ := auth.MakeJWT(uuid, authSecretKey, time.Hour)
+			accessToken, _ := testKeys.MakeJWT(uuid, auth.RoleReader, time.Hour)
 			if tc.hasToken {
 				request.Header.Add("Authorization", "Bearer "+accessToken)
 			}
@@ -324,9 +489,13 @@ func TestDeleteUser(t *testing.T) {
 			dbUser := getDBUser(db, uuid.String())
 			if tc.expectedStatusCode == http.StatusNoContent {
 				assert.Nil(t, dbUser)
+				assert.Equal(t, getDBOutboxMessages(t, db), []outboxMessage{
+					{topic: server.UsersTopic, key: userID, payload: server.UserMessage{ID: userID, Action: "deleted"}},
+				})
 			} else {
 				assert.NotNil(t, dbUser)
 				assert.Equal(t, *dbUser, user)
+				assert.Empty(t, getDBOutboxMessages(t, db))
 			}
 		})
 	}
@@ -5,21 +5,30 @@ import (
 	"log"
 	"net/http"
 	"net/http/httptest"
+	"sync"
 	"time"
 
+	"github.com/bakurvik/mylib/users/internal/auth"
 	"github.com/bakurvik/mylib/users/internal/database"
+	"github.com/bakurvik/mylib/users/internal/mailer"
 	"github.com/bakurvik/mylib/users/internal/server"
 )
 
 const (
-	cookieRefreshToken = "refresh_token"
-	insertUser         = "INSERT INTO users(id, login_name, email, birth_date, hashed_password) VALUES (gen_random_uuid(), $1, $2, $3, $4) RETURNING id"
-	selectRefreshToken = "SELECT user_id, expires_at, revoked_at FROM refresh_tokens WHERE token = $1"
-	deleteUsers        = "DELETE FROM users"
-	authSecretKey      = "secret_key"
-	timeFormat         = "02.01.2006"
+	cookieRefreshToken  = "refresh_token"
+	insertUser          = "INSERT INTO users(id, login_name, email, birth_date, hashed_password) VALUES (gen_random_uuid(), $1, $2, $3, $4) RETURNING id"
+	selectRefreshToken  = "SELECT user_id, expires_at, revoked_at, family_id, parent_token FROM refresh_tokens WHERE token = $1"
+	deleteUsers         = "DELETE FROM users"
+	deleteLoginAttempts = "DELETE FROM login_attempts"
+	deleteOutbox        = "DELETE FROM outbox"
+	testPublicURL       = "http://users.test"
+	testResetURL        = "http://frontend.test/reset-password"
+	timeFormat          = "02.01.2006"
 )
 
+// testKeys sign and validate access tokens in tests.
+var testKeys = makeTestKeySet()
+
 type User struct {
 	loginName      string
 	email          string
@@ -28,9 +37,11 @@ type User struct {
 }
 
 type RefreshToken struct {
-	userID    string
-	expiresAt time.Time
-	revokedAt sql.NullTime
+	userID      string
+	expiresAt   time.Time
+	revokedAt   sql.NullTime
+	familyID    string
+	parentToken sql.NullString
 }
 
 func addDBUser(db *sql.DB, user User) string {
@@ -46,14 +57,60 @@ func addDBUser(db *sql.DB, user User) string {
 }
 
 func cleanupDB(db *sql.DB) {
-	_, err := db.Query(deleteUsers)
+	for _, query := range []string{deleteUsers, deleteLoginAttempts, deleteOutbox} {
+		_, err := db.Exec(query)
+		if err != nil {
+			log.Print("Failed to cleanup db: ", err)
+		}
+	}
+}
+
+func makeTestKeySet() *auth.KeySet {
+	key, err := auth.GenerateSigningKey()
 	if err != nil {
-		log.Print("Failed to cleanup db: ", err)
+		log.Fatal("Failed to generate signing key: ", err)
 	}
+	keys, err := auth.NewKeySet(key)
+	if err != nil {
+		log.Fatal("Failed to make key set: ", err)
+	}
+	return keys
+}
+
+// testMailer keeps sent messages in memory, they are sent in background, so tests wait for them.
+type testMailer struct {
+	mu       sync.Mutex
+	messages []mailer.Message
+}
+
+func (m *testMailer) Send(message mailer.Message) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.messages = append(m.messages, message)
+	return nil
+}
+
+func (m *testMailer) Messages() []mailer.Message {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return append([]mailer.Message{}, m.messages...)
+}
+
+func newTestConfig(db *sql.DB) server.ApiConfig {
+	return server.ApiConfig{DB: database.New(db), Keys: testKeys, Mailer: &testMailer{}, PublicURL: testPublicURL, PasswordResetURL: testResetURL}
 }
 
 func setupTestServer(db *sql.DB) *httptest.Server {
-	apiCfg := server.ApiConfig{DB: database.New(db), AuthSecretKey: authSecretKey}
+	return startTestServer(newTestConfig(db))
+}
+
+func setupTestServerWithMailer(db *sql.DB, m mailer.Mailer) *httptest.Server {
+	apiCfg := newTestConfig(db)
+	apiCfg.Mailer = m
+	return startTestServer(apiCfg)
+}
+
+func startTestServer(apiCfg server.ApiConfig) *httptest.Server {
 	sm := http.NewServeMux()
 	server.Handle(sm, &apiCfg)
 	return httptest.NewServer(sm)
@@ -62,7 +119,7 @@ func setupTestServer(db *sql.DB) *httptest.Server {
 func getDBToken(db *sql.DB, token string) *RefreshToken {
 	row := db.QueryRow(selectRefreshToken, token)
 	dbToken := RefreshToken{}
-	err := row.Scan(&dbToken.userID, &dbToken.expiresAt, &dbToken.revokedAt)
+	err := row.Scan(&dbToken.userID, &dbToken.expiresAt, &dbToken.revokedAt, &dbToken.familyID, &dbToken.parentToken)
 	if err != nil {
 		return nil
 	}